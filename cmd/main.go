@@ -2,8 +2,12 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -11,6 +15,27 @@ import (
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 
+	attachmentbusiness "github.com/khoaphungnguyen/go-openai/internal/attachment/business"
+	attachmentstorage "github.com/khoaphungnguyen/go-openai/internal/attachment/storage"
+	attachmenttransport "github.com/khoaphungnguyen/go-openai/internal/attachment/transport"
+	auditbusiness "github.com/khoaphungnguyen/go-openai/internal/audit/business"
+	auditstorage "github.com/khoaphungnguyen/go-openai/internal/audit/storage"
+	audittransport "github.com/khoaphungnguyen/go-openai/internal/audit/transport"
+	"github.com/khoaphungnguyen/go-openai/internal/blobstore"
+	coderunbusiness "github.com/khoaphungnguyen/go-openai/internal/coderun/business"
+	coderunstorage "github.com/khoaphungnguyen/go-openai/internal/coderun/storage"
+	coderuntransport "github.com/khoaphungnguyen/go-openai/internal/coderun/transport"
+	documentbusiness "github.com/khoaphungnguyen/go-openai/internal/document/business"
+	documentstorage "github.com/khoaphungnguyen/go-openai/internal/document/storage"
+	documenttransport "github.com/khoaphungnguyen/go-openai/internal/document/transport"
+	embeddingbusiness "github.com/khoaphungnguyen/go-openai/internal/embedding/business"
+	embeddingmodel "github.com/khoaphungnguyen/go-openai/internal/embedding/model"
+	embeddingstorage "github.com/khoaphungnguyen/go-openai/internal/embedding/storage"
+	embeddingtransport "github.com/khoaphungnguyen/go-openai/internal/embedding/transport"
+	feedbackbusiness "github.com/khoaphungnguyen/go-openai/internal/feedback/business"
+	feedbackstorage "github.com/khoaphungnguyen/go-openai/internal/feedback/storage"
+	feedbacktransport "github.com/khoaphungnguyen/go-openai/internal/feedback/transport"
+	"github.com/khoaphungnguyen/go-openai/internal/mailer"
 	messagebusiness "github.com/khoaphungnguyen/go-openai/internal/message/business"
 	messagestorage "github.com/khoaphungnguyen/go-openai/internal/message/storage"
 	messagetransport "github.com/khoaphungnguyen/go-openai/internal/message/transport"
@@ -18,12 +43,28 @@ import (
 	notebusiness "github.com/khoaphungnguyen/go-openai/internal/note/business"
 	notestorage "github.com/khoaphungnguyen/go-openai/internal/note/storage"
 	notetransport "github.com/khoaphungnguyen/go-openai/internal/note/transport"
+	"github.com/khoaphungnguyen/go-openai/internal/oidc"
 	openaibusiness "github.com/khoaphungnguyen/go-openai/internal/openai/business"
 	openaistorage "github.com/khoaphungnguyen/go-openai/internal/openai/storage"
 	openaitransport "github.com/khoaphungnguyen/go-openai/internal/openai/transport"
+	"github.com/khoaphungnguyen/go-openai/internal/provider"
+	quizbusiness "github.com/khoaphungnguyen/go-openai/internal/quiz/business"
+	quizstorage "github.com/khoaphungnguyen/go-openai/internal/quiz/storage"
+	quiztransport "github.com/khoaphungnguyen/go-openai/internal/quiz/transport"
+	"github.com/khoaphungnguyen/go-openai/internal/retention"
+	"github.com/khoaphungnguyen/go-openai/internal/sandbox"
+	sharebusiness "github.com/khoaphungnguyen/go-openai/internal/share/business"
+	sharestorage "github.com/khoaphungnguyen/go-openai/internal/share/storage"
+	sharetransport "github.com/khoaphungnguyen/go-openai/internal/share/transport"
+	"github.com/khoaphungnguyen/go-openai/internal/trash"
+	userauth "github.com/khoaphungnguyen/go-openai/internal/user/auth"
 	userbusiness "github.com/khoaphungnguyen/go-openai/internal/user/business"
+	usermodel "github.com/khoaphungnguyen/go-openai/internal/user/model"
 	userstorage "github.com/khoaphungnguyen/go-openai/internal/user/storage"
 	usertransport "github.com/khoaphungnguyen/go-openai/internal/user/transport"
+	workspacebusiness "github.com/khoaphungnguyen/go-openai/internal/workspace/business"
+	workspacestorage "github.com/khoaphungnguyen/go-openai/internal/workspace/storage"
+	workspacetransport "github.com/khoaphungnguyen/go-openai/internal/workspace/transport"
 )
 
 func main() {
@@ -55,22 +96,179 @@ func main() {
 	}
 	openaiClient := openai.NewClient(apiKey)
 
+	ollamaURL := os.Getenv("OLLAMA_URL")
+	if ollamaURL == "" {
+		ollamaURL = provider.DefaultOllamaURL
+	}
+
+	// Uploaded files are kept on the local filesystem unless configured otherwise
+	storageDir := os.Getenv("ATTACHMENT_STORAGE_DIR")
+	if storageDir == "" {
+		storageDir = "uploads"
+	}
+	blobStore, err := blobstore.NewLocalStore(storageDir)
+	if err != nil {
+		log.Fatalf("Failed to initialize attachment storage: %v", err)
+	}
+
+	embeddingModel := os.Getenv("EMBEDDING_MODEL")
+	if embeddingModel == "" {
+		embeddingModel = provider.DefaultEmbeddingModel
+	}
+	chunking := embeddingmodel.DefaultChunkConfig
+	if size, err := strconv.Atoi(os.Getenv("EMBEDDING_CHUNK_SIZE")); err == nil {
+		chunking.Size = size
+	}
+	if overlap, err := strconv.Atoi(os.Getenv("EMBEDDING_CHUNK_OVERLAP")); err == nil {
+		chunking.Overlap = overlap
+	}
+
+	// Account emails go through SMTP when configured, otherwise to files or the log
+	var mail mailer.Mailer
+	switch {
+	case os.Getenv("SMTP_HOST") != "":
+		port, err := strconv.Atoi(os.Getenv("SMTP_PORT"))
+		if err != nil {
+			port = 587
+		}
+		mail = mailer.NewSMTPMailer(mailer.SMTPConfig{
+			Host:     os.Getenv("SMTP_HOST"),
+			Port:     port,
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     os.Getenv("MAIL_FROM"),
+		})
+	case os.Getenv("MAIL_DIR") != "":
+		mail, err = mailer.NewFileMailer(os.Getenv("MAIL_DIR"))
+		if err != nil {
+			log.Fatalf("Failed to initialize mailer: %v", err)
+		}
+	default:
+		mail = mailer.NewLogMailer()
+	}
+
+	appURL := os.Getenv("APP_URL")
+	if appURL == "" {
+		appURL = "http://localhost:3000"
+	}
+	requireVerifiedEmail, _ := strconv.ParseBool(os.Getenv("REQUIRE_EMAIL_VERIFICATION"))
+	requireAdminMFA, _ := strconv.ParseBool(os.Getenv("REQUIRE_ADMIN_MFA"))
+
+	auditService := auditbusiness.NewAuditService(auditstorage.NewAuditStore(db))
+	auditHandler := audittransport.NewAuditHandler(auditService)
+
 	// User and Chat service setup
-	userService := userbusiness.NewUserService(userstorage.NewUserStore(db))
-	userHandler := usertransport.NewUserHandler(userService, jwtKey)
+	jwtWrapper := &userauth.JwtWrapper{
+		SecretKey:              jwtKey,
+		Issuer:                 "AuthService",
+		AccessTokenExpiration:  userauth.DefaultAccessTokenDuration,
+		RefreshTokenExpiration: userauth.DefaultRefreshTokenDuration,
+	}
+	userService := userbusiness.NewUserService(userstorage.NewUserStore(db), jwtWrapper, mail, appURL, requireAdminMFA)
 
-	messageService := messagebusiness.NewMessageService(messagestorage.NewMessageStore(db))
+	// Users can also sign in with any OpenID Connect provider listed in OIDC_PROVIDERS
+	var identityProviders []userbusiness.IdentityProvider
+	for _, name := range strings.Split(os.Getenv("OIDC_PROVIDERS"), ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		config := oidc.Config{
+			Name:         name,
+			IssuerURL:    os.Getenv(prefix + "ISSUER_URL"),
+			ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			RedirectURL:  os.Getenv(prefix + "REDIRECT_URL"),
+			Scopes:       strings.Fields(os.Getenv(prefix + "SCOPES")),
+		}
+		if config.IssuerURL == "" || config.ClientID == "" || config.RedirectURL == "" {
+			log.Fatalf("OIDC provider %q needs %sISSUER_URL, %sCLIENT_ID and %sREDIRECT_URL", name, prefix, prefix, prefix)
+		}
+		identityProviders = append(identityProviders, oidc.NewProvider(config, nil))
+	}
+	ssoService := userbusiness.NewSSOService(userService, identityProviders...)
+	userHandler := usertransport.NewUserHandler(userService, ssoService, auditService)
+
+	workspaceService := workspacebusiness.NewWorkspaceService(workspacestorage.NewWorkspaceStore(db), userService)
+	workspaceHandler := workspacetransport.NewWorkspaceHandler(workspaceService)
+
+	// Notes and messages are embedded in the background as they change
+	llm := provider.NewRouter(openaiClient, ollamaURL)
+	embeddingStore := embeddingstorage.NewEmbeddingStore(db)
+	embeddingService, err := embeddingbusiness.NewEmbeddingService(embeddingStore, llm, embeddingModel, chunking)
+	if err != nil {
+		log.Fatalf("Invalid embedding configuration: %v", err)
+	}
+	embeddingHandler := embeddingtransport.NewEmbeddingHandler(embeddingService)
+	if embeddingStore.UsesPgvector() {
+		log.Println("Semantic search uses pgvector")
+	} else {
+		log.Println("pgvector not installed, semantic search runs in process")
+	}
+	embeddingService.Start(context.Background())
+
+	messageService := messagebusiness.NewMessageService(messagestorage.NewMessageStore(db), workspaceService, embeddingService)
 	messageHandler := messagetransport.NewMessageHandler(messageService)
 
-	noteService := notebusiness.NewNoteService(notestorage.NewNoteStore(db))
-	noteHandler := notetransport.NewNoteHandler(noteService)
+	noteService := notebusiness.NewNoteService(notestorage.NewNoteStore(db), workspaceService, embeddingService)
+	noteGenerator := notebusiness.NewNoteGenerator(noteService, messageService, llm)
+	noteHandler := notetransport.NewNoteHandler(noteService, noteGenerator, auditService)
+
+	shareService := sharebusiness.NewShareService(sharestorage.NewShareStore(db), messageService)
+	shareHandler := sharetransport.NewShareHandler(shareService)
 
-	openaiService := openaibusiness.NewOpenAIService(openaistorage.NewOpenAIStore(db), messageService)
-	chatHandler := openaitransport.NewOpenAIHandler(openaiService)
+	attachmentService := attachmentbusiness.NewAttachmentService(attachmentstorage.NewAttachmentStore(db), blobStore, messageService, embeddingService)
+	attachmentHandler := attachmenttransport.NewAttachmentHandler(attachmentService)
+
+	// Reference documents are extracted, chunked and embedded in the background
+	documentService := documentbusiness.NewDocumentService(documentstorage.NewDocumentStore(db), blobStore, embeddingService, chunking)
+	documentHandler := documenttransport.NewDocumentHandler(documentService)
+	documentService.Start(context.Background())
+
+	openaiService := openaibusiness.NewOpenAIService(openaistorage.NewOpenAIStore(db), messageService, llm, embeddingService)
+	chatHandler := openaitransport.NewOpenAIHandler(openaiService, attachmentService)
+
+	// Note code runs in a bubblewrap sandbox; the toolchains can be pointed elsewhere
+	runner := sandbox.NewRunner(sandbox.Config{
+		Limits:       sandbox.DefaultLimits,
+		GoBinary:     os.Getenv("SANDBOX_GO_BINARY"),
+		PythonBinary: os.Getenv("SANDBOX_PYTHON_BINARY"),
+		BwrapBinary:  os.Getenv("SANDBOX_BWRAP_BINARY"),
+	})
+	codeRunService := coderunbusiness.NewCodeRunService(coderunstorage.NewCodeRunStore(db), noteService, runner, llm)
+	codeRunHandler := coderuntransport.NewCodeRunHandler(codeRunService)
+
+	quizService := quizbusiness.NewQuizService(quizstorage.NewQuizStore(db), noteService, openaiService, llm)
+	quizHandler := quiztransport.NewQuizHandler(quizService)
+
+	feedbackService := feedbackbusiness.NewFeedbackService(feedbackstorage.NewFeedbackStore(db), messageService, openaiService)
+	feedbackHandler := feedbacktransport.NewFeedbackHandler(feedbackService, auditService)
+
+	// Permanently remove records kept past their retention period: trashed threads
+	// and notes after TRASH_RETENTION_DAYS, expired credentials after
+	// CREDENTIAL_RETENTION_DAYS and security events after AUDIT_RETENTION_DAYS
+	credentialRetention := retentionFromEnv("CREDENTIAL_RETENTION_DAYS", userbusiness.ExpiredCredentialRetention)
+	tasks := trash.Tasks(retentionFromEnv("TRASH_RETENTION_DAYS", trash.DefaultRetention),
+		messageService.PurgeDeletedBefore, noteService.PurgeDeletedBefore)
+	tasks = append(tasks,
+		retention.Task{Name: "orphaned attachments", Purge: func(time.Time) (int64, error) {
+			return attachmentService.PurgeOrphaned(context.Background())
+		}},
+		retention.Task{Name: "expired refresh tokens", Retention: credentialRetention, Purge: userService.PurgeExpiredRefreshTokens},
+		retention.Task{Name: "expired sessions", Retention: credentialRetention, Purge: userService.PurgeExpiredSessions},
+		retention.Task{Name: "expired password reset tokens", Retention: credentialRetention, Purge: userService.PurgeExpiredPasswordResets},
+		retention.Task{Name: "expired oidc login states", Retention: credentialRetention, Purge: ssoService.PurgeExpiredLoginStates},
+		retention.Task{Name: "expired api keys", Retention: credentialRetention, Purge: userService.PurgeExpiredAPIKeys},
+		retention.Task{Name: "login attempts", Retention: userbusiness.LoginAttemptRetention, Purge: userService.PurgeLoginAttempts},
+		retention.Task{Name: "audit log entries", Retention: retentionFromEnv("AUDIT_RETENTION_DAYS", auditbusiness.DefaultRetention),
+			Purge: auditService.PurgeEntriesBefore},
+	)
+	retention.NewPurger(retention.DefaultInterval, tasks...).Start(context.Background())
 
 	router := gin.Default()
-	router.Use(middleware.CORSMiddleware([]string{"http://localhost:3000"}))
-	setupRoutes(router, userHandler, messageHandler, noteHandler, chatHandler, jwtKey, openaiClient)
+	router.Use(middleware.CORSMiddleware([]string{appURL}))
+	setupRoutes(router, userService, userHandler, messageHandler, noteHandler, shareHandler, workspaceHandler, feedbackHandler, attachmentHandler, quizHandler, codeRunHandler, embeddingHandler, documentHandler, chatHandler, auditHandler, jwtKey, openaiClient, requireVerifiedEmail)
 
 	if err := router.Run(":8000"); err != nil {
 		log.Fatalf("Failed to run server: %v", err)
@@ -78,51 +276,233 @@ func main() {
 }
 
 // setupRoutes defines the HTTP routes for the application.
-func setupRoutes(router *gin.Engine, userHandler *usertransport.UserHandler, messageHandler *messagetransport.MessageHandler, noteHandler *notetransport.NoteHandler,
-	openAIHandler *openaitransport.OpenAIHandler, jwtKey string, openaiClient *openai.Client) {
+func setupRoutes(router *gin.Engine, userService *userbusiness.UserService, userHandler *usertransport.UserHandler, messageHandler *messagetransport.MessageHandler, noteHandler *notetransport.NoteHandler,
+	shareHandler *sharetransport.ShareHandler, workspaceHandler *workspacetransport.WorkspaceHandler,
+	feedbackHandler *feedbacktransport.FeedbackHandler, attachmentHandler *attachmenttransport.AttachmentHandler,
+	quizHandler *quiztransport.QuizHandler, codeRunHandler *coderuntransport.CodeRunHandler,
+	embeddingHandler *embeddingtransport.EmbeddingHandler, documentHandler *documenttransport.DocumentHandler, openAIHandler *openaitransport.OpenAIHandler, auditHandler *audittransport.AuditHandler, jwtKey string, openaiClient *openai.Client, requireVerifiedEmail bool) {
 
 	auth := router.Group("/auth")
 	{
 		auth.POST("/login", userHandler.Login)
 		auth.POST("/signup", userHandler.Signup)
 		auth.POST("/refresh", userHandler.RenewAccessToken)
+		auth.POST("/logout", userHandler.Logout)
+		auth.POST("/verify-email", userHandler.VerifyEmail)
+		auth.POST("/forgot-password", userHandler.ForgotPassword)
+		auth.POST("/reset-password", userHandler.ResetPassword)
+		auth.POST("/mfa/verify", userHandler.VerifyMFALogin)
+		auth.GET("/oidc/providers", userHandler.GetIdentityProviders)
+		auth.GET("/oidc/:provider/login", userHandler.StartOIDCLogin)
+		auth.POST("/oidc/:provider/callback", userHandler.CompleteOIDCLogin)
 	}
 
-	protected := router.Group("/protected").Use(middleware.AuthMiddleware(jwtKey))
+	// Public, read-only access to shared thread snapshots
+	shared := router.Group("/shared")
 	{
-		protected.GET("/users", userHandler.GetAllUsers)
-		protected.GET("/profile", userHandler.Profile)
-		protected.PUT("/profile", userHandler.UpdateProfile)
-		protected.PUT("/profile/restore", userHandler.RestoreProfile)
-		protected.DELETE("/profile", userHandler.DeleteProfile)
+		shared.GET("/:token", shareHandler.GetSharedThread)
+	}
+
+	// Admin routes are open to staff roles; each group needs a permission of the role
+	admin := router.Group("/admin")
+	admin.Use(middleware.AuthMiddleware(jwtKey, userService), middleware.RequireSession(),
+		middleware.RequireRole(usermodel.AdminRole, usermodel.SupportRole), middleware.AdminMFAMiddleware(userService))
+	{
+		feedback := admin.Group("", middleware.RequirePermission(usermodel.PermissionReadFeedback))
+		feedback.GET("/feedback/stats", feedbackHandler.GetFeedbackStats)
+		feedback.GET("/feedback/export", feedbackHandler.ExportFeedback)
+
+		readUsers := admin.Group("", middleware.RequirePermission(usermodel.PermissionReadUsers))
+		readUsers.GET("/users", userHandler.ListUsers)
+		readUsers.GET("/users/:userID/sessions", userHandler.GetUserSessions)
+		readUsers.GET("/lockouts", userHandler.ListLockouts)
+
+		manageUsers := admin.Group("", middleware.RequirePermission(usermodel.PermissionManageUsers))
+		manageUsers.PUT("/users/:userID/role", userHandler.ChangeUserRole)
+		manageUsers.POST("/users/:userID/suspend", userHandler.SuspendUser)
+		manageUsers.POST("/users/:userID/unsuspend", userHandler.UnsuspendUser)
+		manageUsers.POST("/users/:userID/password-reset", userHandler.ForcePasswordReset)
+		manageUsers.DELETE("/users/:userID/sessions", userHandler.RevokeUserSessions)
+		manageUsers.POST("/lockouts/:id/unlock", userHandler.Unlock)
+
+		usage := admin.Group("", middleware.RequirePermission(usermodel.PermissionReadUsage))
+		usage.GET("/users/:userID/usage", openAIHandler.GetUserUsage)
+
+		auditLog := admin.Group("", middleware.RequirePermission(usermodel.PermissionReadAuditLog))
+		auditLog.GET("/audit-logs", auditHandler.GetAuditLogs)
+	}
+
+	// Protected routes accept access tokens and API keys; an API key can only
+	// call the route groups its scopes name
+	protected := router.Group("/protected")
+	protected.Use(middleware.AuthMiddleware(jwtKey, userService))
+	{
+		profile := protected.Group("", middleware.RequireScope(usermodel.ScopeProfile))
+		profile.GET("/profile", userHandler.Profile)
+
+		// Account security routes are limited to interactive logins
+		account := protected.Group("", middleware.RequireSession())
+		account.GET("/users", middleware.RequirePermission(usermodel.PermissionReadUsers), userHandler.GetAllUsers)
+		account.PUT("/profile", userHandler.UpdateProfile)
+		account.PUT("/profile/restore", userHandler.RestoreProfile)
+		account.DELETE("/profile", userHandler.DeleteProfile)
+		account.POST("/logout/all", userHandler.LogoutEverywhere)
+		account.GET("/sessions", userHandler.GetSessions)
+		account.DELETE("/sessions/:id", userHandler.RevokeSession)
+		account.POST("/verify-email/resend", userHandler.ResendVerificationEmail)
+		account.PUT("/password", userHandler.ChangePassword)
+		account.GET("/mfa", userHandler.GetMFAStatus)
+		account.POST("/mfa/enroll", userHandler.EnrollMFA)
+		account.POST("/mfa/confirm", userHandler.ConfirmMFA)
+		account.POST("/mfa/recovery-codes", userHandler.RegenerateRecoveryCodes)
+		account.DELETE("/mfa", userHandler.DisableMFA)
+		account.POST("/api-keys", userHandler.CreateAPIKey)
+		account.GET("/api-keys", userHandler.GetAPIKeys)
+		account.DELETE("/api-keys/:id", userHandler.RevokeAPIKey)
+
+		chat := protected.Group("", middleware.RequireScope(usermodel.ScopeChat))
 
 		// ChatMessage routes under protected group
-		protected.POST("/thread", messageHandler.CreateThread)
-		protected.GET("/thread/:id", messageHandler.GetThreadByID)
-		protected.GET("/threads", messageHandler.GetAllThreads)
-		protected.DELETE("/thread/:id", messageHandler.DeleteThread)
-		protected.POST("/message", messageHandler.CreateMessage)
-		protected.GET("/threads/:threadID", messageHandler.GetMessagesByThreadID)
+		chat.POST("/thread", messageHandler.CreateThread)
+		chat.GET("/thread/:id", messageHandler.GetThreadByID)
+		chat.GET("/threads", messageHandler.GetAllThreads)
+		chat.DELETE("/thread/:id", messageHandler.DeleteThread)
+		chat.POST("/message", messageHandler.CreateMessage)
+		chat.GET("/threads/:threadID", messageHandler.GetMessagesByThreadID)
+		chat.PUT("/thread/:id/workspace", messageHandler.MoveThreadToWorkspace)
+		chat.PUT("/thread/:id/rag", messageHandler.SetThreadRAG)
+
+		// Trash routes under protected group
+		chat.GET("/trash/threads", messageHandler.GetDeletedThreads)
+		chat.POST("/trash/threads/:id/restore", messageHandler.RestoreThread)
+		chat.DELETE("/trash/threads/:id", messageHandler.PurgeThread)
+
+		// Message feedback routes under protected group
+		chat.PUT("/messages/:id/feedback", feedbackHandler.RateMessage)
+		chat.GET("/messages/:id/feedback", feedbackHandler.GetFeedback)
+		chat.DELETE("/messages/:id/feedback", feedbackHandler.DeleteFeedback)
+
+		// Attachment routes under protected group
+		chat.POST("/attachments", attachmentHandler.UploadAttachment)
+		chat.GET("/attachments/:id", attachmentHandler.GetAttachment)
+		chat.GET("/attachments/:id/content", attachmentHandler.DownloadAttachment)
+		chat.DELETE("/attachments/:id", attachmentHandler.DeleteAttachment)
+		chat.GET("/thread/:id/attachments", attachmentHandler.GetThreadAttachments)
+
+		// Share link routes under protected group
+		chat.POST("/thread/:id/shares", shareHandler.CreateShare)
+		chat.GET("/thread/:id/shares", shareHandler.GetThreadShares)
+		chat.DELETE("/shares/:shareID", shareHandler.RevokeShare)
+		chat.POST("/shared/:token/fork", shareHandler.ForkSharedThread)
+
+		notes := protected.Group("", middleware.RequireScope(usermodel.ScopeNotes))
 
 		// Note routes under protected group
-		protected.POST("/notes", noteHandler.CreateNote)
-		protected.GET("/notes", noteHandler.GetAllNoteByUserID)
-		protected.GET("/notes/:id", noteHandler.GetNoteByID)
-		protected.PUT("/notes/:id", noteHandler.UpdateNote)
-		protected.DELETE("/notes/:id", noteHandler.DeleteNote)
+		notes.POST("/notes", noteHandler.CreateNote)
+		notes.GET("/notes", noteHandler.GetAllNoteByUserID)
+		notes.GET("/notes/:id", noteHandler.GetNoteByID)
+		notes.PUT("/notes/:id", noteHandler.UpdateNote)
+		notes.DELETE("/notes/:id", noteHandler.DeleteNote)
+		notes.GET("/trash/notes", noteHandler.GetDeletedNotes)
+		notes.POST("/trash/notes/:id/restore", noteHandler.RestoreNote)
+		notes.DELETE("/trash/notes/:id", noteHandler.PurgeNote)
+
+		// Note revision routes under protected group
+		notes.GET("/notes/:id/revisions", noteHandler.GetRevisions)
+		notes.GET("/notes/:id/revisions/diff", noteHandler.DiffRevisions)
+		notes.GET("/notes/:id/revisions/:version", noteHandler.GetRevision)
+		notes.POST("/notes/:id/revisions/:version/restore", noteHandler.RestoreRevision)
+
+		// Spaced-repetition review routes under protected group
+		notes.GET("/reviews/due", noteHandler.GetDueNotes)
+		notes.GET("/reviews/stats", noteHandler.GetReviewStats)
+		notes.GET("/notes/:id/review", noteHandler.GetReviewSchedule)
+		notes.POST("/notes/:id/review", noteHandler.GradeNote)
+
+		// Note taxonomy, search and statistics routes under protected group
+		notes.GET("/notes/search", noteHandler.SearchNotes)
+		notes.GET("/notes/stats", noteHandler.GetNoteStats)
+		notes.PUT("/notes/:id/tags", noteHandler.SetNoteTags)
+		notes.GET("/tags", noteHandler.GetTags)
+		notes.GET("/note-categories", noteHandler.GetCategories)
+
+		// Note export and import routes under protected group
+		notes.GET("/notes/export", noteHandler.ExportNotes)
+		notes.POST("/notes/import", noteHandler.ImportNotes)
+
+		// Note code run routes under protected group
+		notes.POST("/notes/:id/runs", codeRunHandler.RunNoteCode)
+		notes.GET("/notes/:id/runs", codeRunHandler.GetNoteRuns)
+		notes.GET("/notes/:id/runs/:runID", codeRunHandler.GetNoteRun)
+
+		// Document knowledge base routes under protected group
+		documents := protected.Group("", middleware.RequireScope(usermodel.ScopeDocuments))
+		documents.POST("/collections", documentHandler.CreateCollection)
+		documents.GET("/collections", documentHandler.GetCollections)
+		documents.GET("/collections/:id", documentHandler.GetCollection)
+		documents.PUT("/collections/:id", documentHandler.UpdateCollection)
+		documents.DELETE("/collections/:id", documentHandler.DeleteCollection)
+		documents.POST("/collections/:id/documents", documentHandler.UploadDocument)
+		documents.GET("/documents/:id", documentHandler.GetDocument)
+		documents.POST("/documents/:id/reindex", documentHandler.ReindexDocument)
+		documents.DELETE("/documents/:id", documentHandler.DeleteDocument)
+
+		// Workspace routes under protected group
+		workspaces := protected.Group("", middleware.RequireScope(usermodel.ScopeWorkspaces))
+		workspaces.POST("/workspaces", workspaceHandler.CreateWorkspace)
+		workspaces.GET("/workspaces", workspaceHandler.GetWorkspaces)
+		workspaces.GET("/workspaces/:id", workspaceHandler.GetWorkspace)
+		workspaces.DELETE("/workspaces/:id", workspaceHandler.DeleteWorkspace)
+		workspaces.POST("/workspaces/:id/members", workspaceHandler.AddMember)
+		workspaces.PUT("/workspaces/:id/members/:userID", workspaceHandler.UpdateMemberRole)
+		workspaces.DELETE("/workspaces/:id/members/:userID", workspaceHandler.RemoveMember)
+		workspaces.GET("/workspaces/:id/threads", messageHandler.GetWorkspaceThreads)
+		workspaces.GET("/workspaces/:id/notes", noteHandler.GetWorkspaceNotes)
 
-		// Apply OpenAIClientMiddleware to the protected group that requires OpenAI client
+		// Apply OpenAIClientMiddleware to the protected group that requires OpenAI client.
+		// Route groups are created after it so their routes include it.
 		protected.Use(middleware.OpenAIClientMiddleware(openaiClient))
-		protected.POST("/suggestions", openAIHandler.FetchSuggestion)
-		protected.POST("/hints", openAIHandler.GenerateHint)
-		protected.POST("/drawings", openAIHandler.FetchDrawing)
-		protected.POST("/transactions", openAIHandler.CreateTransaction)
-		protected.GET("/transactions/user/:userID", openAIHandler.GetTransactionsByUserID)
-		protected.PUT("/transactions", openAIHandler.UpdateTransaction)
-		protected.DELETE("/transactions/:transactionID", openAIHandler.DeleteTransaction)
-		protected.GET("/transactions/:transactionID", openAIHandler.GetTransactionByID)
-		protected.GET("/chat/:threadID", openAIHandler.WebSocketHandler)
-		protected.GET("/chat/stream/:threadID", openAIHandler.SSEHandler)
-		protected.POST("/chat/ask/:threadID", openAIHandler.MessageHanlder)
+		if requireVerifiedEmail {
+			protected.Use(middleware.VerifiedEmailMiddleware(userService))
+		}
+		assistant := protected.Group("", middleware.RequireScope(usermodel.ScopeChat))
+		assistant.POST("/suggestions", openAIHandler.FetchSuggestion)
+		assistant.POST("/hints", openAIHandler.GenerateHint)
+		assistant.POST("/drawings", openAIHandler.FetchDrawing)
+		assistant.POST("/transactions", openAIHandler.CreateTransaction)
+		assistant.GET("/transactions/user/:userID", openAIHandler.GetTransactionsByUserID)
+		assistant.PUT("/transactions", openAIHandler.UpdateTransaction)
+		assistant.DELETE("/transactions/:transactionID", openAIHandler.DeleteTransaction)
+		assistant.GET("/transactions/:transactionID", openAIHandler.GetTransactionByID)
+		assistant.GET("/chat/:threadID", openAIHandler.WebSocketHandler)
+		assistant.GET("/chat/stream/:threadID", openAIHandler.SSEHandler)
+		assistant.POST("/chat/ask/:threadID", openAIHandler.MessageHanlder)
+
+		study := protected.Group("", middleware.RequireScope(usermodel.ScopeNotes))
+		study.POST("/thread/:id/note/generate", noteHandler.GenerateNote)
+
+		// Quiz routes under protected group
+		study.POST("/quiz/start", quizHandler.StartQuiz)
+		study.POST("/quiz/:noteID/hint", quizHandler.GetHint)
+		study.POST("/quiz/:noteID/answer", quizHandler.SubmitAnswer)
+		study.GET("/quiz/attempts", quizHandler.GetAttempts)
+		study.GET("/quiz/scores", quizHandler.GetNoteScores)
+
+		// Test case proposals for note code runs
+		study.POST("/notes/:id/testcases/propose", codeRunHandler.ProposeTestCases)
+
+		// Semantic search routes under protected group
+		search := protected.Group("", middleware.RequireScope(usermodel.ScopeSearch))
+		search.GET("/search/semantic", embeddingHandler.Search)
+		search.GET("/notes/:id/similar", embeddingHandler.SimilarNotes)
+	}
+}
+
+// retentionFromEnv reads a retention period in days from the environment,
+// falling back to the default when it is unset or not a positive number.
+func retentionFromEnv(name string, fallback time.Duration) time.Duration {
+	if days, err := strconv.Atoi(os.Getenv(name)); err == nil && days > 0 {
+		return time.Duration(days) * 24 * time.Hour
 	}
+	return fallback
 }
@@ -0,0 +1,43 @@
+// attachmentbusiness contains the business logic for chat message attachments.
+package attachmentbusiness
+
+import (
+	"github.com/google/uuid"
+	attachmentstorage "github.com/khoaphungnguyen/go-openai/internal/attachment/storage"
+	"github.com/khoaphungnguyen/go-openai/internal/blobstore"
+	messagebusiness "github.com/khoaphungnguyen/go-openai/internal/message/business"
+)
+
+const (
+	// MaxUploadSize is the largest file accepted as an attachment.
+	MaxUploadSize = 10 << 20
+	// DefaultInlineTokenBudget bounds the tokens of text attachments inlined into one message.
+	DefaultInlineTokenBudget = 3000
+	// MaxAttachmentsPerMessage bounds how many files can be sent with one message.
+	MaxAttachmentsPerMessage = 5
+)
+
+// AttachmentIndexer makes the text of an uploaded file searchable.
+type AttachmentIndexer interface {
+	IndexAttachment(attachmentID uuid.UUID)
+}
+
+// AttachmentService provides methods for attachment operations.
+type AttachmentService struct {
+	attachmentStore   attachmentstorage.AttachmentStore
+	blobStore         blobstore.Store
+	messageService    *messagebusiness.MessageService
+	indexer           AttachmentIndexer
+	inlineTokenBudget int
+}
+
+// NewAttachmentService creates a new AttachmentService. The indexer may be nil.
+func NewAttachmentService(attachmentStore attachmentstorage.AttachmentStore, blobStore blobstore.Store, messageService *messagebusiness.MessageService, indexer AttachmentIndexer) *AttachmentService {
+	return &AttachmentService{
+		attachmentStore:   attachmentStore,
+		blobStore:         blobStore,
+		messageService:    messageService,
+		indexer:           indexer,
+		inlineTokenBudget: DefaultInlineTokenBudget,
+	}
+}
@@ -0,0 +1,271 @@
+package attachmentbusiness
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+	attachmentmodel "github.com/khoaphungnguyen/go-openai/internal/attachment/model"
+	attachmentstorage "github.com/khoaphungnguyen/go-openai/internal/attachment/storage"
+	"github.com/khoaphungnguyen/go-openai/internal/blobstore"
+	"github.com/khoaphungnguyen/go-openai/internal/extract"
+	"github.com/pkoukk/tiktoken-go"
+)
+
+var (
+	// ErrAttachmentNotFound is returned when an attachment does not exist or is not visible to the user.
+	ErrAttachmentNotFound = attachmentstorage.ErrAttachmentNotFound
+	// ErrThreadAccessDenied is returned when the user cannot post to the thread.
+	ErrThreadAccessDenied = errors.New("thread does not exist or user lacks permission")
+	// ErrFileTooLarge is returned when an upload exceeds MaxUploadSize.
+	ErrFileTooLarge = fmt.Errorf("file exceeds the %d MB limit", MaxUploadSize>>20)
+	// ErrUnsupportedFile is returned for files that are neither images nor text.
+	ErrUnsupportedFile = errors.New("unsupported file type; upload an image, a text or source file, or a PDF")
+	// ErrVisionUnsupported is returned when sending images to a model without vision support.
+	ErrVisionUnsupported = errors.New("the selected model does not accept image attachments")
+	// ErrInvalidAttachments is returned when attachments cannot be sent with a message.
+	ErrInvalidAttachments = errors.New("attachments must belong to the thread and not be sent already")
+)
+
+// visionModelPrefixes lists model families that accept image input.
+var visionModelPrefixes = []string{
+	"gpt-4-vision", "gpt-4-turbo", "gpt-4o", "gpt-4.1",
+	"llava", "bakllava", "llama3.2-vision", "moondream", "minicpm-v",
+}
+
+// IsVisionModel reports whether the model accepts image parts.
+func IsVisionModel(model string) bool {
+	for _, prefix := range visionModelPrefixes {
+		if strings.HasPrefix(model, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Upload stores a file for a thread the user can post to.
+func (as *AttachmentService) Upload(ctx context.Context, userID, threadID uuid.UUID, fileName string, r io.Reader) (*attachmentmodel.Attachment, error) {
+	if !as.messageService.CanWriteThread(threadID, userID) {
+		return nil, ErrThreadAccessDenied
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, MaxUploadSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxUploadSize {
+		return nil, ErrFileTooLarge
+	}
+
+	fileName = filepath.Base(fileName)
+	contentType := extract.DetectContentType(fileName, data)
+	kind := extract.DetectKind(fileName, contentType)
+	if kind == extract.KindUnsupported {
+		return nil, ErrUnsupportedFile
+	}
+
+	var text string
+	if kind != extract.KindImage {
+		text, err = extract.Text(kind, data)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrUnsupportedFile, err)
+		}
+	}
+
+	attachmentID := uuid.New()
+	attachment := &attachmentmodel.Attachment{
+		ID:            attachmentID,
+		UserID:        userID,
+		ThreadID:      threadID,
+		FileName:      fileName,
+		ContentType:   contentType,
+		Kind:          kind,
+		Size:          int64(len(data)),
+		StorageKey:    fmt.Sprintf("attachments/%s/%s", userID, attachmentID),
+		ExtractedText: text,
+	}
+
+	if err := as.blobStore.Put(ctx, attachment.StorageKey, bytes.NewReader(data), attachment.Size, contentType); err != nil {
+		return nil, fmt.Errorf("failed to store file: %w", err)
+	}
+	if err := as.attachmentStore.CreateAttachment(attachment); err != nil {
+		_ = as.blobStore.Delete(ctx, attachment.StorageKey)
+		return nil, err
+	}
+	if text != "" && as.indexer != nil {
+		as.indexer.IndexAttachment(attachment.ID)
+	}
+	return attachment, nil
+}
+
+// GetAttachment retrieves an attachment if the user can read its thread.
+func (as *AttachmentService) GetAttachment(userID, attachmentID uuid.UUID) (*attachmentmodel.Attachment, error) {
+	attachment, err := as.attachmentStore.GetAttachmentByID(attachmentID)
+	if err != nil {
+		return nil, err
+	}
+	if !as.messageService.CanReadThread(attachment.ThreadID, userID) {
+		return nil, ErrAttachmentNotFound
+	}
+	return attachment, nil
+}
+
+// OpenContent returns the stored file of an attachment the user can read.
+func (as *AttachmentService) OpenContent(ctx context.Context, userID, attachmentID uuid.UUID) (*attachmentmodel.Attachment, io.ReadCloser, error) {
+	attachment, err := as.GetAttachment(userID, attachmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	content, err := as.blobStore.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return attachment, content, nil
+}
+
+// GetAttachmentsByThreadID lists the attachments of a thread the user can read.
+func (as *AttachmentService) GetAttachmentsByThreadID(userID, threadID uuid.UUID) ([]attachmentmodel.Attachment, error) {
+	if !as.messageService.CanReadThread(threadID, userID) {
+		return nil, ErrThreadAccessDenied
+	}
+	return as.attachmentStore.GetAttachmentsByThreadID(threadID)
+}
+
+// DeleteAttachment removes an attachment uploaded by the user together with its file.
+func (as *AttachmentService) DeleteAttachment(ctx context.Context, userID, attachmentID uuid.UUID) error {
+	attachment, err := as.attachmentStore.GetAttachmentByID(attachmentID)
+	if err != nil {
+		return err
+	}
+	if attachment.UserID != userID {
+		return ErrAttachmentNotFound
+	}
+	if err := as.attachmentStore.DeleteAttachment(attachmentID); err != nil {
+		return err
+	}
+	return as.blobStore.Delete(ctx, attachment.StorageKey)
+}
+
+// PrepareForPrompt loads unsent attachments of a thread for a model request: images
+// are encoded for vision models and text content is inlined within the token budget.
+func (as *AttachmentService) PrepareForPrompt(ctx context.Context, userID, threadID uuid.UUID, attachmentIDs []uuid.UUID, model string) (*attachmentmodel.PromptAttachments, error) {
+	if len(attachmentIDs) > MaxAttachmentsPerMessage {
+		return nil, fmt.Errorf("%w: at most %d files per message", ErrInvalidAttachments, MaxAttachmentsPerMessage)
+	}
+	attachments, err := as.attachmentStore.GetAttachmentsByIDs(attachmentIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(attachments) != len(attachmentIDs) {
+		return nil, ErrInvalidAttachments
+	}
+
+	prepared := &attachmentmodel.PromptAttachments{}
+	var texts []*attachmentmodel.Attachment
+	for i := range attachments {
+		attachment := &attachments[i]
+		if attachment.ThreadID != threadID || attachment.UserID != userID || attachment.MessageID != nil {
+			return nil, ErrInvalidAttachments
+		}
+		if attachment.Kind != extract.KindImage {
+			texts = append(texts, attachment)
+			continue
+		}
+		if !IsVisionModel(model) {
+			return nil, ErrVisionUnsupported
+		}
+		image, err := as.loadImage(ctx, attachment)
+		if err != nil {
+			return nil, err
+		}
+		prepared.Images = append(prepared.Images, image)
+	}
+
+	if len(texts) > 0 {
+		inline, err := as.inlineText(texts)
+		if err != nil {
+			return nil, err
+		}
+		prepared.Text = inline
+	}
+	return prepared, nil
+}
+
+// LinkToMessage records which message the attachments were sent with.
+func (as *AttachmentService) LinkToMessage(attachmentIDs []uuid.UUID, messageID uuid.UUID) error {
+	if len(attachmentIDs) == 0 {
+		return nil
+	}
+	return as.attachmentStore.LinkToMessage(attachmentIDs, messageID)
+}
+
+// loadImage reads an image attachment and encodes it as base64.
+func (as *AttachmentService) loadImage(ctx context.Context, attachment *attachmentmodel.Attachment) (attachmentmodel.ImagePart, error) {
+	content, err := as.blobStore.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		return attachmentmodel.ImagePart{}, fmt.Errorf("failed to read %s: %w", attachment.FileName, err)
+	}
+	defer content.Close()
+
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return attachmentmodel.ImagePart{}, err
+	}
+	return attachmentmodel.ImagePart{
+		ContentType: attachment.ContentType,
+		Base64:      base64.StdEncoding.EncodeToString(data),
+	}, nil
+}
+
+// inlineText formats text attachments as fenced blocks, splitting the token
+// budget evenly and truncating files that do not fit.
+func (as *AttachmentService) inlineText(attachments []*attachmentmodel.Attachment) (string, error) {
+	tke, err := tiktoken.GetEncoding("cl100k_base")
+	if err != nil {
+		return "", fmt.Errorf("getEncoding: %v", err)
+	}
+
+	perFile := as.inlineTokenBudget / len(attachments)
+	var b strings.Builder
+	for _, attachment := range attachments {
+		tokens := tke.Encode(attachment.ExtractedText, nil, nil)
+		content := attachment.ExtractedText
+		truncated := false
+		if len(tokens) > perFile {
+			content = tke.Decode(tokens[:perFile])
+			truncated = true
+		}
+
+		fmt.Fprintf(&b, "\n\nAttached file: %s\n```\n%s\n```", attachment.FileName, content)
+		if truncated {
+			fmt.Fprintf(&b, "\n(%s was truncated to fit the context window.)", attachment.FileName)
+		}
+	}
+	return b.String(), nil
+}
+
+// PurgeOrphaned removes the files and records of attachments whose thread was purged.
+func (as *AttachmentService) PurgeOrphaned(ctx context.Context) (int64, error) {
+	attachments, err := as.attachmentStore.GetOrphanedAttachments()
+	if err != nil {
+		return 0, err
+	}
+
+	var purged int64
+	for _, attachment := range attachments {
+		if err := as.blobStore.Delete(ctx, attachment.StorageKey); err != nil && !errors.Is(err, blobstore.ErrBlobNotFound) {
+			return purged, err
+		}
+		if err := as.attachmentStore.DeleteAttachment(attachment.ID); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
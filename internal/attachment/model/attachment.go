@@ -0,0 +1,73 @@
+// attachmentmodel defines the data structures used for chat message attachments.
+package attachmentmodel
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/khoaphungnguyen/go-openai/internal/extract"
+)
+
+// Attachment is a file uploaded to a thread and optionally linked to the message it was sent with.
+type Attachment struct {
+	ID            uuid.UUID    `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	UserID        uuid.UUID    `gorm:"type:uuid;not null"`
+	ThreadID      uuid.UUID    `gorm:"type:uuid;index"` // cleared when the thread is purged
+	MessageID     *uuid.UUID   `gorm:"type:uuid;index"`
+	FileName      string       `gorm:"type:varchar(255);not null"`
+	ContentType   string       `gorm:"type:varchar(255);not null"`
+	Kind          extract.Kind `gorm:"type:varchar(20);not null"`
+	Size          int64        `gorm:"not null"`
+	StorageKey    string       `gorm:"type:varchar(512);not null"`
+	ExtractedText string       `gorm:"type:text"`
+	CreatedAt     time.Time    `gorm:"default:now()"`
+}
+
+// TableName overrides the table name used by Attachment.
+func (Attachment) TableName() string {
+	return "message_attachment"
+}
+
+// AttachmentResponse is the client-facing representation of an attachment.
+type AttachmentResponse struct {
+	ID          uuid.UUID    `json:"id"`
+	ThreadID    uuid.UUID    `json:"threadId"`
+	MessageID   *uuid.UUID   `json:"messageId"`
+	FileName    string       `json:"fileName"`
+	ContentType string       `json:"contentType"`
+	Kind        extract.Kind `json:"kind"`
+	Size        int64        `json:"size"`
+	CreatedAt   time.Time    `json:"createdAt"`
+}
+
+// ToAttachmentResponse converts an Attachment to its client-facing representation.
+func (a *Attachment) ToAttachmentResponse() AttachmentResponse {
+	return AttachmentResponse{
+		ID:          a.ID,
+		ThreadID:    a.ThreadID,
+		MessageID:   a.MessageID,
+		FileName:    a.FileName,
+		ContentType: a.ContentType,
+		Kind:        a.Kind,
+		Size:        a.Size,
+		CreatedAt:   a.CreatedAt,
+	}
+}
+
+// ImagePart is an image attachment encoded for a model request.
+type ImagePart struct {
+	ContentType string
+	Base64      string
+}
+
+// DataURL returns the image as a data URL, as accepted by OpenAI vision models.
+func (p ImagePart) DataURL() string {
+	return "data:" + p.ContentType + ";base64," + p.Base64
+}
+
+// PromptAttachments holds the attachment content to send along with a user message.
+type PromptAttachments struct {
+	// Text is the inlined content of text and PDF attachments, already truncated to the token budget.
+	Text   string
+	Images []ImagePart
+}
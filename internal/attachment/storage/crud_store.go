@@ -0,0 +1,64 @@
+package attachmentstorage
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	attachmentmodel "github.com/khoaphungnguyen/go-openai/internal/attachment/model"
+	"gorm.io/gorm"
+)
+
+// ErrAttachmentNotFound is the error returned when an attachment cannot be found.
+var ErrAttachmentNotFound = errors.New("attachment not found")
+
+// CreateAttachment adds a new attachment record to the database.
+func (as *attachmentStore) CreateAttachment(attachment *attachmentmodel.Attachment) error {
+	return as.db.Create(attachment).Error
+}
+
+// GetAttachmentByID retrieves an attachment by its ID.
+func (as *attachmentStore) GetAttachmentByID(attachmentID uuid.UUID) (*attachmentmodel.Attachment, error) {
+	var attachment attachmentmodel.Attachment
+	err := as.db.First(&attachment, "id = ?", attachmentID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrAttachmentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve attachment: %w", err)
+	}
+	return &attachment, nil
+}
+
+// GetAttachmentsByIDs retrieves the attachments with the given IDs in upload order.
+func (as *attachmentStore) GetAttachmentsByIDs(attachmentIDs []uuid.UUID) ([]attachmentmodel.Attachment, error) {
+	var attachments []attachmentmodel.Attachment
+	err := as.db.Where("id IN ?", attachmentIDs).Order("created_at ASC").Find(&attachments).Error
+	return attachments, err
+}
+
+// GetAttachmentsByThreadID retrieves all attachments uploaded to a thread.
+func (as *attachmentStore) GetAttachmentsByThreadID(threadID uuid.UUID) ([]attachmentmodel.Attachment, error) {
+	var attachments []attachmentmodel.Attachment
+	err := as.db.Where("thread_id = ?", threadID).Order("created_at ASC").Find(&attachments).Error
+	return attachments, err
+}
+
+// LinkToMessage associates attachments with the message they were sent with.
+func (as *attachmentStore) LinkToMessage(attachmentIDs []uuid.UUID, messageID uuid.UUID) error {
+	return as.db.Model(&attachmentmodel.Attachment{}).
+		Where("id IN ? AND message_id IS NULL", attachmentIDs).
+		Update("message_id", messageID).Error
+}
+
+// DeleteAttachment removes an attachment record.
+func (as *attachmentStore) DeleteAttachment(attachmentID uuid.UUID) error {
+	return as.db.Where("id = ?", attachmentID).Delete(&attachmentmodel.Attachment{}).Error
+}
+
+// GetOrphanedAttachments retrieves attachments whose thread has been permanently deleted.
+func (as *attachmentStore) GetOrphanedAttachments() ([]attachmentmodel.Attachment, error) {
+	var attachments []attachmentmodel.Attachment
+	err := as.db.Where("thread_id IS NULL").Find(&attachments).Error
+	return attachments, err
+}
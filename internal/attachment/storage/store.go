@@ -0,0 +1,29 @@
+// attachmentstorage provides data persistence logic for message attachments.
+package attachmentstorage
+
+import (
+	"github.com/google/uuid"
+	attachmentmodel "github.com/khoaphungnguyen/go-openai/internal/attachment/model"
+	"gorm.io/gorm"
+)
+
+// AttachmentStore provides methods for attachment operations.
+type AttachmentStore interface {
+	CreateAttachment(attachment *attachmentmodel.Attachment) error
+	GetAttachmentByID(attachmentID uuid.UUID) (*attachmentmodel.Attachment, error)
+	GetAttachmentsByIDs(attachmentIDs []uuid.UUID) ([]attachmentmodel.Attachment, error)
+	GetAttachmentsByThreadID(threadID uuid.UUID) ([]attachmentmodel.Attachment, error)
+	LinkToMessage(attachmentIDs []uuid.UUID, messageID uuid.UUID) error
+	DeleteAttachment(attachmentID uuid.UUID) error
+	GetOrphanedAttachments() ([]attachmentmodel.Attachment, error)
+}
+
+// attachmentStore encapsulates the logic for storing and retrieving attachment records.
+type attachmentStore struct {
+	db *gorm.DB
+}
+
+// NewAttachmentStore creates a new instance of attachmentStore.
+func NewAttachmentStore(db *gorm.DB) AttachmentStore {
+	return &attachmentStore{db: db}
+}
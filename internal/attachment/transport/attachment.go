@@ -0,0 +1,14 @@
+// attachmenttransport handles HTTP requests and responses for message attachments.
+package attachmenttransport
+
+import attachmentbusiness "github.com/khoaphungnguyen/go-openai/internal/attachment/business"
+
+// AttachmentHandler handles attachment-related HTTP requests.
+type AttachmentHandler struct {
+	attachmentService *attachmentbusiness.AttachmentService
+}
+
+// NewAttachmentHandler creates a new AttachmentHandler.
+func NewAttachmentHandler(attachmentService *attachmentbusiness.AttachmentService) *AttachmentHandler {
+	return &AttachmentHandler{attachmentService: attachmentService}
+}
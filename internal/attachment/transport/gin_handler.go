@@ -0,0 +1,190 @@
+package attachmenttransport
+
+import (
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	attachmentbusiness "github.com/khoaphungnguyen/go-openai/internal/attachment/business"
+	attachmentmodel "github.com/khoaphungnguyen/go-openai/internal/attachment/model"
+	"github.com/khoaphungnguyen/go-openai/internal/common"
+	"github.com/khoaphungnguyen/go-openai/internal/extract"
+)
+
+// UploadAttachment handles a multipart upload of a file to a thread.
+// The form must contain a "file" part and a "threadID" field.
+func (ah *AttachmentHandler) UploadAttachment(c *gin.Context) {
+	userID, err := common.GetUserIDFromContext(c)
+	if err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.LimitMultipartBody(c, attachmentbusiness.MaxUploadSize)
+
+	threadID, err := uuid.Parse(c.PostForm("threadID"))
+	if err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, "Invalid thread ID")
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, "File is required")
+		return
+	}
+	if fileHeader.Size > attachmentbusiness.MaxUploadSize {
+		common.RespondWithError(c, http.StatusRequestEntityTooLarge, attachmentbusiness.ErrFileTooLarge.Error())
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, "Failed to read file")
+		return
+	}
+	defer file.Close()
+
+	attachment, err := ah.attachmentService.Upload(c.Request.Context(), userID, threadID, fileHeader.Filename, file)
+	if err != nil {
+		respondWithAttachmentError(c, err, "Failed to upload attachment")
+		return
+	}
+
+	common.RespondWithJSON(c, http.StatusCreated, attachment.ToAttachmentResponse())
+}
+
+// GetAttachment handles retrieving the metadata of an attachment.
+func (ah *AttachmentHandler) GetAttachment(c *gin.Context) {
+	userID, attachmentID, ok := parseAttachmentRequest(c)
+	if !ok {
+		return
+	}
+
+	attachment, err := ah.attachmentService.GetAttachment(userID, attachmentID)
+	if err != nil {
+		respondWithAttachmentError(c, err, "Failed to retrieve attachment")
+		return
+	}
+
+	common.RespondWithJSON(c, http.StatusOK, attachment.ToAttachmentResponse())
+}
+
+// DownloadAttachment streams the stored file of an attachment.
+func (ah *AttachmentHandler) DownloadAttachment(c *gin.Context) {
+	userID, attachmentID, ok := parseAttachmentRequest(c)
+	if !ok {
+		return
+	}
+
+	attachment, content, err := ah.attachmentService.OpenContent(c.Request.Context(), userID, attachmentID)
+	if err != nil {
+		respondWithAttachmentError(c, err, "Failed to retrieve attachment")
+		return
+	}
+	defer content.Close()
+
+	contentType, disposition := downloadType(attachment)
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": attachment.FileName}))
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Content-Security-Policy", "sandbox")
+	c.Status(http.StatusOK)
+	if _, err := io.Copy(c.Writer, content); err != nil {
+		log.Printf("Error streaming attachment %s: %v", attachmentID, err)
+	}
+}
+
+// downloadType returns the content type and disposition an attachment is served
+// with. Only raster images display inline; everything else is downloaded, and
+// text is always plain so uploaded HTML or SVG never renders on the API origin.
+func downloadType(attachment *attachmentmodel.Attachment) (string, string) {
+	switch attachment.Kind {
+	case extract.KindImage:
+		return attachment.ContentType, "inline"
+	case extract.KindText:
+		return "text/plain; charset=utf-8", "attachment"
+	case extract.KindPDF:
+		return "application/pdf", "attachment"
+	default:
+		return "application/octet-stream", "attachment"
+	}
+}
+
+// GetThreadAttachments handles listing the attachments of a thread.
+func (ah *AttachmentHandler) GetThreadAttachments(c *gin.Context) {
+	userID, err := common.GetUserIDFromContext(c)
+	if err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	threadID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, "Invalid thread ID")
+		return
+	}
+
+	attachments, err := ah.attachmentService.GetAttachmentsByThreadID(userID, threadID)
+	if err != nil {
+		respondWithAttachmentError(c, err, "Failed to retrieve attachments")
+		return
+	}
+
+	responses := make([]attachmentmodel.AttachmentResponse, 0, len(attachments))
+	for _, attachment := range attachments {
+		responses = append(responses, attachment.ToAttachmentResponse())
+	}
+	common.RespondWithJSON(c, http.StatusOK, responses)
+}
+
+// DeleteAttachment handles deleting an attachment uploaded by the caller.
+func (ah *AttachmentHandler) DeleteAttachment(c *gin.Context) {
+	userID, attachmentID, ok := parseAttachmentRequest(c)
+	if !ok {
+		return
+	}
+
+	if err := ah.attachmentService.DeleteAttachment(c.Request.Context(), userID, attachmentID); err != nil {
+		respondWithAttachmentError(c, err, "Failed to delete attachment")
+		return
+	}
+
+	common.RespondWithJSON(c, http.StatusOK, gin.H{"message": "Attachment deleted successfully"})
+}
+
+// parseAttachmentRequest extracts the caller and attachment IDs, responding on failure.
+func parseAttachmentRequest(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userID, err := common.GetUserIDFromContext(c)
+	if err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, err.Error())
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	attachmentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, "Invalid attachment ID")
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, attachmentID, true
+}
+
+// respondWithAttachmentError maps attachment errors to HTTP responses.
+func respondWithAttachmentError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, attachmentbusiness.ErrAttachmentNotFound):
+		common.RespondWithError(c, http.StatusNotFound, "Attachment not found")
+	case errors.Is(err, attachmentbusiness.ErrThreadAccessDenied):
+		common.RespondWithError(c, http.StatusForbidden, "Access denied")
+	case errors.Is(err, attachmentbusiness.ErrFileTooLarge):
+		common.RespondWithError(c, http.StatusRequestEntityTooLarge, err.Error())
+	case errors.Is(err, attachmentbusiness.ErrUnsupportedFile):
+		common.RespondWithError(c, http.StatusUnsupportedMediaType, err.Error())
+	default:
+		common.RespondWithError(c, http.StatusInternalServerError, fallback)
+	}
+}
@@ -0,0 +1,60 @@
+// auditbusiness contains the business logic for the security audit log.
+package auditbusiness
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	auditmodel "github.com/khoaphungnguyen/go-openai/internal/audit/model"
+	auditstorage "github.com/khoaphungnguyen/go-openai/internal/audit/storage"
+	"github.com/khoaphungnguyen/go-openai/internal/common"
+)
+
+const (
+	// DefaultRetention is how long audit log entries are kept.
+	DefaultRetention = 365 * 24 * time.Hour
+	// DefaultPageSize and MaxPageSize bound the pages of audit log searches.
+	DefaultPageSize = 50
+	MaxPageSize     = 200
+)
+
+// ErrInvalidFilter is returned for audit log searches with an empty or reversed time range.
+var ErrInvalidFilter = errors.New("invalid audit log filter")
+
+// AuditService records security events and lets administrators search them.
+type AuditService struct {
+	auditStore auditstorage.AuditStore
+}
+
+// NewAuditService creates a new AuditService.
+func NewAuditService(auditStore auditstorage.AuditStore) *AuditService {
+	return &AuditService{auditStore: auditStore}
+}
+
+// Record appends an entry to the audit log. A failure to record is logged
+// rather than returned, so it never undoes the action being audited.
+func (s *AuditService) Record(ctx context.Context, entry *auditmodel.AuditLog) {
+	entry.CreatedAt = time.Now()
+	entry.UserAgent = common.Truncate(entry.UserAgent, common.MaxUserAgentLength)
+	entry.IPAddress = common.Truncate(entry.IPAddress, common.MaxIPAddressLength)
+	if err := s.auditStore.CreateEntry(entry); err != nil {
+		log.Printf("Failed to record audit event %s: %v", entry.Action, err)
+	}
+}
+
+// Search returns a page of the entries matching the filter and the number of matching entries.
+func (s *AuditService) Search(filter auditmodel.AuditFilter) ([]auditmodel.AuditLog, int64, error) {
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, 0, ErrInvalidFilter
+	}
+	filter.Action = strings.TrimSpace(filter.Action)
+	return s.auditStore.SearchEntries(filter)
+}
+
+// PurgeEntriesBefore permanently removes entries recorded before the cutoff.
+func (s *AuditService) PurgeEntriesBefore(cutoff time.Time) (int64, error) {
+	return s.auditStore.DeleteEntriesBefore(cutoff)
+}
@@ -0,0 +1,90 @@
+// auditmodel defines the data structures of the security audit log.
+package auditmodel
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Action names an audited event. Actions are grouped by the prefix before the dot.
+type Action string
+
+const (
+	ActionSignup                 Action = "auth.signup"
+	ActionLogin                  Action = "auth.login"
+	ActionLoginFailed            Action = "auth.login_failed"
+	ActionLoginBlocked           Action = "auth.login_blocked"
+	ActionLogout                 Action = "auth.logout"
+	ActionLogoutAll              Action = "auth.logout_all"
+	ActionSessionRevoked         Action = "auth.session_revoked"
+	ActionEmailVerified          Action = "auth.email_verified"
+	ActionPasswordResetRequested Action = "auth.password_reset_requested"
+	ActionPasswordReset          Action = "auth.password_reset"
+	ActionPasswordChanged        Action = "auth.password_changed"
+	ActionMFAEnabled             Action = "auth.mfa_enabled"
+	ActionMFADisabled            Action = "auth.mfa_disabled"
+	ActionRecoveryCodesReset     Action = "auth.recovery_codes_regenerated"
+	ActionAPIKeyCreated          Action = "auth.api_key_created"
+	ActionAPIKeyRevoked          Action = "auth.api_key_revoked"
+
+	ActionProfileUpdated  Action = "profile.updated"
+	ActionProfileDeleted  Action = "profile.deleted"
+	ActionProfileRestored Action = "profile.restored"
+
+	ActionRoleChanged         Action = "admin.role_changed"
+	ActionUserSuspended       Action = "admin.user_suspended"
+	ActionUserUnsuspended     Action = "admin.user_unsuspended"
+	ActionPasswordResetForced Action = "admin.password_reset_forced"
+	ActionUserSessionsRevoked Action = "admin.sessions_revoked"
+	ActionLockoutUnlocked     Action = "admin.lockout_unlocked"
+
+	ActionNotesExported    Action = "export.notes"
+	ActionFeedbackExported Action = "export.feedback"
+)
+
+// Kinds of audit log targets.
+const (
+	TargetUser    = "user"
+	TargetSession = "session"
+	TargetAPIKey  = "api_key"
+	TargetLockout = "lockout"
+)
+
+// AuditLog is one entry of the append-only security audit log. The actor is
+// nil for events of anonymous requests, such as a login with an unknown email.
+type AuditLog struct {
+	ID         uuid.UUID              `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
+	ActorID    *uuid.UUID             `gorm:"column:actor_id;type:uuid" json:"actorId"`
+	Action     Action                 `gorm:"column:action;type:varchar(64);not null" json:"action"`
+	TargetType string                 `gorm:"column:target_type;type:varchar(32)" json:"targetType,omitempty"`
+	TargetID   *uuid.UUID             `gorm:"column:target_id;type:uuid" json:"targetId,omitempty"`
+	IPAddress  string                 `gorm:"column:ip_address;type:varchar(45);not null" json:"ipAddress"`
+	UserAgent  string                 `gorm:"column:user_agent;type:varchar(512);not null" json:"userAgent"`
+	Metadata   map[string]interface{} `gorm:"column:metadata;type:jsonb;serializer:json" json:"metadata,omitempty"`
+	CreatedAt  time.Time              `gorm:"column:created_at;not null" json:"createdAt"`
+}
+
+// TableName overrides the table name used by AuditLog.
+func (AuditLog) TableName() string {
+	return "audit_log"
+}
+
+// Set adds a metadata value to the entry.
+func (e *AuditLog) Set(key string, value interface{}) *AuditLog {
+	if e.Metadata == nil {
+		e.Metadata = map[string]interface{}{}
+	}
+	e.Metadata[key] = value
+	return e
+}
+
+// AuditFilter narrows an audit log search. Empty fields do not filter.
+type AuditFilter struct {
+	UserID *uuid.UUID // Matches entries the user acted in or was the target of
+	Action string     // An exact action, or a prefix ending in "*" such as "admin.*"
+	From   *time.Time // Inclusive
+	To     *time.Time // Exclusive
+	Limit  int
+	Offset int
+}
@@ -0,0 +1,48 @@
+package auditstorage
+
+import (
+	"strings"
+	"time"
+
+	auditmodel "github.com/khoaphungnguyen/go-openai/internal/audit/model"
+	"github.com/khoaphungnguyen/go-openai/internal/common"
+)
+
+// CreateEntry appends an entry to the audit log.
+func (s *auditStore) CreateEntry(entry *auditmodel.AuditLog) error {
+	return s.db.Create(entry).Error
+}
+
+// SearchEntries returns a page of the entries matching the filter, newest
+// first, along with the number of matching entries.
+func (s *auditStore) SearchEntries(filter auditmodel.AuditFilter) ([]auditmodel.AuditLog, int64, error) {
+	query := s.db.Model(&auditmodel.AuditLog{})
+	if filter.UserID != nil {
+		query = query.Where("(actor_id = ? OR (target_type = ? AND target_id = ?))", *filter.UserID, auditmodel.TargetUser, *filter.UserID)
+	}
+	if prefix, ok := strings.CutSuffix(filter.Action, "*"); ok {
+		query = query.Where("action LIKE ?", common.EscapeLike(prefix)+"%")
+	} else if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+
+	total, page, err := common.Paginate(query, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	var entries []auditmodel.AuditLog
+	err = page.Order("created_at DESC").Find(&entries).Error
+	return entries, total, err
+}
+
+// DeleteEntriesBefore permanently removes entries recorded before the cutoff.
+func (s *auditStore) DeleteEntriesBefore(cutoff time.Time) (int64, error) {
+	result := s.db.Where("created_at < ?", cutoff).Delete(&auditmodel.AuditLog{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,27 @@
+// auditstorage provides data persistence logic for the security audit log.
+package auditstorage
+
+import (
+	"time"
+
+	auditmodel "github.com/khoaphungnguyen/go-openai/internal/audit/model"
+	"gorm.io/gorm"
+)
+
+// AuditStore provides methods for audit log operations. Entries can only be
+// added and, once past the retention period, deleted.
+type AuditStore interface {
+	CreateEntry(entry *auditmodel.AuditLog) error
+	SearchEntries(filter auditmodel.AuditFilter) ([]auditmodel.AuditLog, int64, error)
+	DeleteEntriesBefore(cutoff time.Time) (int64, error)
+}
+
+// auditStore encapsulates the logic for storing and retrieving audit log entries.
+type auditStore struct {
+	db *gorm.DB
+}
+
+// NewAuditStore creates a new instance of auditStore.
+func NewAuditStore(db *gorm.DB) AuditStore {
+	return &auditStore{db: db}
+}
@@ -0,0 +1,14 @@
+// audittransport handles HTTP requests and responses for the security audit log.
+package audittransport
+
+import auditbusiness "github.com/khoaphungnguyen/go-openai/internal/audit/business"
+
+// AuditHandler handles audit log HTTP requests.
+type AuditHandler struct {
+	auditService *auditbusiness.AuditService
+}
+
+// NewAuditHandler creates a new AuditHandler.
+func NewAuditHandler(auditService *auditbusiness.AuditService) *AuditHandler {
+	return &AuditHandler{auditService: auditService}
+}
@@ -0,0 +1,28 @@
+package audittransport
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	auditmodel "github.com/khoaphungnguyen/go-openai/internal/audit/model"
+)
+
+// NewEntry starts an audit log entry for the request. The actor is the
+// authenticated user, if any, and requests made with an API key note the key.
+// A nil target ID leaves the entry without a target.
+func NewEntry(c *gin.Context, action auditmodel.Action, targetType string, targetID uuid.UUID) *auditmodel.AuditLog {
+	entry := &auditmodel.AuditLog{
+		Action:    action,
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+	if actorID, err := uuid.Parse(c.GetString("userID")); err == nil {
+		entry.ActorID = &actorID
+	}
+	if targetID != uuid.Nil {
+		entry.TargetType, entry.TargetID = targetType, &targetID
+	}
+	if apiKeyID := c.GetString("apiKeyID"); apiKeyID != "" {
+		entry.Set("apiKeyId", apiKeyID)
+	}
+	return entry
+}
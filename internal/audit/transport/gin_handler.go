@@ -0,0 +1,79 @@
+package audittransport
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	auditbusiness "github.com/khoaphungnguyen/go-openai/internal/audit/business"
+	auditmodel "github.com/khoaphungnguyen/go-openai/internal/audit/model"
+	"github.com/khoaphungnguyen/go-openai/internal/common"
+)
+
+// GetAuditLogs handles searching the audit log by user, action and time, newest first. Admin only.
+func (ah *AuditHandler) GetAuditLogs(c *gin.Context) {
+	filter, err := parseAuditFilter(c)
+	if err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	entries, total, err := ah.auditService.Search(filter)
+	if errors.Is(err, auditbusiness.ErrInvalidFilter) {
+		common.RespondWithError(c, http.StatusBadRequest, "The from date must be before the to date")
+		return
+	}
+	if err != nil {
+		common.RespondWithError(c, http.StatusInternalServerError, "Failed to retrieve audit log")
+		return
+	}
+	common.RespondWithJSON(c, http.StatusOK, gin.H{"total": total, "entries": entries})
+}
+
+// parseAuditFilter reads the userId, action, from, to, limit and offset query
+// parameters. Dates accept either YYYY-MM-DD or RFC 3339; "to" is exclusive.
+func parseAuditFilter(c *gin.Context) (auditmodel.AuditFilter, error) {
+	filter := auditmodel.AuditFilter{Action: c.Query("action")}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(auditbusiness.DefaultPageSize)))
+	if err != nil || limit <= 0 || limit > auditbusiness.MaxPageSize {
+		return filter, errors.New("invalid limit")
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		return filter, errors.New("invalid offset")
+	}
+	filter.Limit, filter.Offset = limit, offset
+
+	if value := c.Query("userId"); value != "" {
+		userID, err := uuid.Parse(value)
+		if err != nil {
+			return filter, errors.New("invalid user ID")
+		}
+		filter.UserID = &userID
+	}
+	for param, target := range map[string]**time.Time{"from": &filter.From, "to": &filter.To} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		t, err := parseDate(value)
+		if err != nil {
+			return filter, fmt.Errorf("invalid %s date", param)
+		}
+		*target = &t
+	}
+	return filter, nil
+}
+
+// parseDate parses a date in YYYY-MM-DD or RFC 3339 format.
+func parseDate(value string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
@@ -0,0 +1,60 @@
+package blobstore
+
+import (
+	"context"
+	"io"
+	"strings"
+)
+
+// S3API is the subset of an S3-compatible client used by the blob store. It can be
+// satisfied by a thin adapter over the AWS SDK, MinIO or any other compatible client.
+type S3API interface {
+	PutObject(ctx context.Context, bucket, key string, r io.Reader, size int64, contentType string) error
+	GetObject(ctx context.Context, bucket, key string) (io.ReadCloser, error)
+	DeleteObject(ctx context.Context, bucket, key string) error
+	// IsNotFound reports whether err means the object does not exist.
+	IsNotFound(err error) bool
+}
+
+// s3Store keeps blobs in a bucket of an S3-compatible object store.
+type s3Store struct {
+	client S3API
+	bucket string
+	prefix string
+}
+
+// NewS3Store creates a Store backed by an S3-compatible bucket. Keys are stored below prefix.
+func NewS3Store(client S3API, bucket, prefix string) Store {
+	return &s3Store{client: client, bucket: bucket, prefix: strings.Trim(prefix, "/")}
+}
+
+// Put uploads the blob.
+func (s *s3Store) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	return s.client.PutObject(ctx, s.bucket, s.objectKey(key), r, size, contentType)
+}
+
+// Get downloads the blob.
+func (s *s3Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	body, err := s.client.GetObject(ctx, s.bucket, s.objectKey(key))
+	if err != nil && s.client.IsNotFound(err) {
+		return nil, ErrBlobNotFound
+	}
+	return body, err
+}
+
+// Delete removes the blob; deleting a missing blob is not an error.
+func (s *s3Store) Delete(ctx context.Context, key string) error {
+	err := s.client.DeleteObject(ctx, s.bucket, s.objectKey(key))
+	if err != nil && s.client.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// objectKey prefixes the key with the configured folder.
+func (s *s3Store) objectKey(key string) string {
+	if s.prefix == "" {
+		return key
+	}
+	return s.prefix + "/" + key
+}
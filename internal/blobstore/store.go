@@ -0,0 +1,18 @@
+// blobstore provides pluggable storage for uploaded file contents.
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrBlobNotFound is the error returned when no blob exists under a key.
+var ErrBlobNotFound = errors.New("blob not found")
+
+// Store persists opaque binary objects under string keys.
+type Store interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
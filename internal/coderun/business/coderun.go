@@ -0,0 +1,35 @@
+// coderunbusiness contains the business logic for running note code in the sandbox.
+package coderunbusiness
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	coderunstorage "github.com/khoaphungnguyen/go-openai/internal/coderun/storage"
+	notemodel "github.com/khoaphungnguyen/go-openai/internal/note/model"
+	"github.com/khoaphungnguyen/go-openai/internal/provider"
+	"github.com/khoaphungnguyen/go-openai/internal/sandbox"
+)
+
+// NoteReader gives code runs read access to notes.
+type NoteReader interface {
+	GetNoteByID(userID, noteID uuid.UUID) (*notemodel.Note, error)
+}
+
+// CodeRunner executes programs against test cases.
+type CodeRunner interface {
+	Run(ctx context.Context, language sandbox.Language, source string, cases []sandbox.TestCase) (*sandbox.Report, error)
+}
+
+// CodeRunService provides methods for code run operations.
+type CodeRunService struct {
+	runStore coderunstorage.CodeRunStore
+	notes    NoteReader
+	runner   CodeRunner
+	llm      provider.Provider
+}
+
+// NewCodeRunService creates a new CodeRunService.
+func NewCodeRunService(runStore coderunstorage.CodeRunStore, notes NoteReader, runner CodeRunner, llm provider.Provider) *CodeRunService {
+	return &CodeRunService{runStore: runStore, notes: notes, runner: runner, llm: llm}
+}
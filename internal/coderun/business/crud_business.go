@@ -0,0 +1,175 @@
+package coderunbusiness
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	coderunmodel "github.com/khoaphungnguyen/go-openai/internal/coderun/model"
+	notemodel "github.com/khoaphungnguyen/go-openai/internal/note/model"
+	"github.com/khoaphungnguyen/go-openai/internal/provider"
+	"github.com/khoaphungnguyen/go-openai/internal/sandbox"
+)
+
+const (
+	// DefaultRunLimit bounds the runs returned in a note's history.
+	DefaultRunLimit = 20
+	// DefaultProposalCount is how many test cases are proposed when none is requested.
+	DefaultProposalCount = 5
+	// proposeMaxTokens bounds the length of the proposed test cases.
+	proposeMaxTokens = 1500
+)
+
+var (
+	// ErrNoteNotFound is returned when the note does not exist or the user cannot read it.
+	ErrNoteNotFound = errors.New("note not found")
+	// ErrRunNotFound is returned when a run does not belong to the note.
+	ErrRunNotFound = errors.New("run not found")
+	// ErrNoCode is returned when neither the request nor the note has code to run.
+	ErrNoCode = errors.New("note has no code to run")
+	// ErrUnknownLanguage is returned when the language is not given and cannot be detected.
+	ErrUnknownLanguage = errors.New("language could not be detected, set it to go or python")
+	// ErrNoProblem is returned when test cases are requested for a note without a problem statement.
+	ErrNoProblem = errors.New("note has no problem statement")
+	// ErrInvalidTestCases is returned when the model output does not match the test case schema.
+	ErrInvalidTestCases = errors.New("model did not return valid test cases")
+)
+
+const proposeSystemPrompt = `You write test cases for a program that solves the problem below.
+The program reads its input from stdin and writes its answer to stdout.
+Use the input format of the reference code when it is given; otherwise choose a simple line-based format and state it in the first test case's name.
+Cover typical inputs and edge cases. Respond with a single JSON object and nothing else, using exactly this schema:
+{
+  "testCases": [
+    {"name": "short description", "input": "exact stdin", "expected": "exact stdout"}
+  ]
+}`
+
+// RunNote runs code for a note against the test cases and records the result.
+// The note's own code is used when code is empty, and the language is
+// detected from the code when it is not given.
+func (cs *CodeRunService) RunNote(ctx context.Context, userID, noteID uuid.UUID, language sandbox.Language, code string, cases []sandbox.TestCase) (*coderunmodel.CodeRun, error) {
+	note, err := cs.getNote(userID, noteID)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(code) == "" {
+		code = note.Code
+	}
+	if strings.TrimSpace(code) == "" {
+		return nil, ErrNoCode
+	}
+	if language == "" {
+		if language = sandbox.DetectLanguage(code); language == "" {
+			return nil, ErrUnknownLanguage
+		}
+	}
+
+	report, err := cs.runner.Run(ctx, language, code, cases)
+	if err != nil {
+		return nil, err
+	}
+
+	run := coderunmodel.NewCodeRun(noteID, userID, language, code, report)
+	if err := cs.runStore.CreateRun(run); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// GetRuns retrieves the recent runs of a note the user can read.
+func (cs *CodeRunService) GetRuns(userID, noteID uuid.UUID) ([]coderunmodel.CodeRun, error) {
+	if _, err := cs.getNote(userID, noteID); err != nil {
+		return nil, err
+	}
+	return cs.runStore.GetRuns(noteID, DefaultRunLimit)
+}
+
+// GetRun retrieves a single run of a note the user can read.
+func (cs *CodeRunService) GetRun(userID, noteID, runID uuid.UUID) (*coderunmodel.CodeRun, error) {
+	if _, err := cs.getNote(userID, noteID); err != nil {
+		return nil, err
+	}
+	run, err := cs.runStore.GetRun(noteID, runID)
+	if err != nil {
+		return nil, err
+	}
+	if run == nil {
+		return nil, ErrRunNotFound
+	}
+	return run, nil
+}
+
+// ProposeTestCases asks the model for test cases derived from the note's problem.
+// The proposals are returned for the user to review and are not saved.
+func (cs *CodeRunService) ProposeTestCases(ctx context.Context, userID, noteID uuid.UUID, model string, count int) ([]sandbox.TestCase, error) {
+	note, err := cs.getNote(userID, noteID)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(note.Problem) == "" {
+		return nil, ErrNoProblem
+	}
+	if count <= 0 {
+		count = DefaultProposalCount
+	}
+	if count > sandbox.MaxTestCases {
+		count = sandbox.MaxTestCases
+	}
+
+	input := fmt.Sprintf("Write %d test cases.\n\nProblem:\n%s\n\nReference code:\n%s", count, note.Problem, provider.OrNone(note.Code))
+	output, err := cs.llm.Complete(ctx, provider.Request{
+		Model: model,
+		Messages: []provider.Message{
+			{Role: "system", Content: proposeSystemPrompt},
+			{Role: "user", Content: input},
+		},
+		MaxTokens: proposeMaxTokens,
+		JSON:      true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cases, err := parseTestCases(output)
+	if err != nil {
+		return nil, err
+	}
+	if len(cases) > count {
+		cases = cases[:count]
+	}
+	return cases, nil
+}
+
+// getNote loads a note the user can read.
+func (cs *CodeRunService) getNote(userID, noteID uuid.UUID) (*notemodel.Note, error) {
+	note, err := cs.notes.GetNoteByID(userID, noteID)
+	if err != nil || note == nil {
+		return nil, ErrNoteNotFound
+	}
+	return note, nil
+}
+
+// parseTestCases decodes the model output and drops test cases without input or expected output.
+func parseTestCases(output string) ([]sandbox.TestCase, error) {
+	var proposal struct {
+		TestCases []sandbox.TestCase `json:"testCases"`
+	}
+	if err := provider.DecodeJSONObject(output, &proposal); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTestCases, err)
+	}
+
+	cases := make([]sandbox.TestCase, 0, len(proposal.TestCases))
+	for _, tc := range proposal.TestCases {
+		if strings.TrimSpace(tc.Expected) == "" || len(tc.Input) > sandbox.MaxInputSize || len(tc.Expected) > sandbox.MaxInputSize {
+			continue
+		}
+		cases = append(cases, tc)
+	}
+	if len(cases) == 0 {
+		return nil, ErrInvalidTestCases
+	}
+	return cases, nil
+}
@@ -0,0 +1,64 @@
+// coderunmodel defines the data structures used for running note code.
+package coderunmodel
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/khoaphungnguyen/go-openai/internal/sandbox"
+)
+
+// Status summarizes the outcome of a run.
+type Status string
+
+const (
+	StatusPassed       Status = "passed"
+	StatusFailed       Status = "failed"
+	StatusCompileError Status = "compile_error"
+)
+
+// CodeRun is the result of running a note's code against a set of test cases.
+type CodeRun struct {
+	ID            uuid.UUID            `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
+	NoteID        uuid.UUID            `gorm:"type:uuid;not null;index" json:"noteId"`
+	UserID        uuid.UUID            `gorm:"type:uuid;not null" json:"userId"`
+	Language      sandbox.Language     `gorm:"type:varchar(20);not null" json:"language"`
+	Code          string               `gorm:"type:text;not null" json:"code"`
+	Status        Status               `gorm:"type:varchar(20);not null" json:"status"`
+	Passed        int                  `gorm:"not null" json:"passed"`
+	Total         int                  `gorm:"not null" json:"total"`
+	CompileOutput string               `gorm:"type:text" json:"compileOutput,omitempty"`
+	Results       []sandbox.CaseResult `gorm:"type:jsonb;serializer:json;not null" json:"results"`
+	CreatedAt     time.Time            `gorm:"default:now()" json:"createdAt"`
+}
+
+// TableName overrides the table name used by CodeRun.
+func (CodeRun) TableName() string {
+	return "note_code_run"
+}
+
+// NewCodeRun records a sandbox report as a run of code on a note.
+func NewCodeRun(noteID, userID uuid.UUID, language sandbox.Language, code string, report *sandbox.Report) *CodeRun {
+	run := &CodeRun{
+		NoteID:        noteID,
+		UserID:        userID,
+		Language:      language,
+		Code:          code,
+		Passed:        report.Passed,
+		Total:         report.Total,
+		CompileOutput: report.CompileOutput,
+		Results:       report.Cases,
+	}
+	switch {
+	case report.CompileFailed:
+		run.Status = StatusCompileError
+	case report.Passed == report.Total:
+		run.Status = StatusPassed
+	default:
+		run.Status = StatusFailed
+	}
+	if run.Results == nil {
+		run.Results = []sandbox.CaseResult{}
+	}
+	return run
+}
@@ -0,0 +1,34 @@
+package coderunstorage
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	coderunmodel "github.com/khoaphungnguyen/go-openai/internal/coderun/model"
+	"gorm.io/gorm"
+)
+
+// CreateRun adds a code run to the database.
+func (cs *codeRunStore) CreateRun(run *coderunmodel.CodeRun) error {
+	return cs.db.Create(run).Error
+}
+
+// GetRuns retrieves the most recent runs of a note.
+func (cs *codeRunStore) GetRuns(noteID uuid.UUID, limit int) ([]coderunmodel.CodeRun, error) {
+	var runs []coderunmodel.CodeRun
+	err := cs.db.Where("note_id = ?", noteID).Order("created_at DESC").Limit(limit).Find(&runs).Error
+	return runs, err
+}
+
+// GetRun retrieves a run of a note, returning nil if it does not exist.
+func (cs *codeRunStore) GetRun(noteID, runID uuid.UUID) (*coderunmodel.CodeRun, error) {
+	var run coderunmodel.CodeRun
+	err := cs.db.Where("note_id = ?", noteID).First(&run, "id = ?", runID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
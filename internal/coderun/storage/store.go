@@ -0,0 +1,25 @@
+// coderunstorage provides data persistence logic for note code runs.
+package coderunstorage
+
+import (
+	"github.com/google/uuid"
+	coderunmodel "github.com/khoaphungnguyen/go-openai/internal/coderun/model"
+	"gorm.io/gorm"
+)
+
+// CodeRunStore provides methods for code run operations.
+type CodeRunStore interface {
+	CreateRun(run *coderunmodel.CodeRun) error
+	GetRuns(noteID uuid.UUID, limit int) ([]coderunmodel.CodeRun, error)
+	GetRun(noteID, runID uuid.UUID) (*coderunmodel.CodeRun, error)
+}
+
+// codeRunStore encapsulates the logic for storing and retrieving code runs.
+type codeRunStore struct {
+	db *gorm.DB
+}
+
+// NewCodeRunStore creates a new instance of codeRunStore.
+func NewCodeRunStore(db *gorm.DB) CodeRunStore {
+	return &codeRunStore{db: db}
+}
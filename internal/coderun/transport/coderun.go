@@ -0,0 +1,14 @@
+// coderuntransport handles HTTP requests and responses for running note code.
+package coderuntransport
+
+import coderunbusiness "github.com/khoaphungnguyen/go-openai/internal/coderun/business"
+
+// CodeRunHandler handles code run HTTP requests.
+type CodeRunHandler struct {
+	codeRunService *coderunbusiness.CodeRunService
+}
+
+// NewCodeRunHandler creates a new CodeRunHandler.
+func NewCodeRunHandler(codeRunService *coderunbusiness.CodeRunService) *CodeRunHandler {
+	return &CodeRunHandler{codeRunService: codeRunService}
+}
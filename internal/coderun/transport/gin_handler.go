@@ -0,0 +1,146 @@
+package coderuntransport
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	coderunbusiness "github.com/khoaphungnguyen/go-openai/internal/coderun/business"
+	"github.com/khoaphungnguyen/go-openai/internal/common"
+	"github.com/khoaphungnguyen/go-openai/internal/sandbox"
+)
+
+type RunPayload struct {
+	// Language and Code default to the language detected from and the code of the note.
+	Language  sandbox.Language   `json:"language"`
+	Code      string             `json:"code"`
+	TestCases []sandbox.TestCase `json:"testCases" binding:"required"`
+}
+
+type ProposePayload struct {
+	Model string `json:"model" binding:"required"`
+	Count int    `json:"count"`
+}
+
+// RunNoteCode handles running a note's code against test cases in the sandbox.
+func (ch *CodeRunHandler) RunNoteCode(c *gin.Context) {
+	userID, noteID, ok := parseNoteRequest(c)
+	if !ok {
+		return
+	}
+
+	var payload RunPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	run, err := ch.codeRunService.RunNote(c.Request.Context(), userID, noteID, payload.Language, payload.Code, payload.TestCases)
+	if err != nil {
+		respondWithCodeRunError(c, err, "Failed to run code")
+		return
+	}
+
+	common.RespondWithJSON(c, http.StatusCreated, run)
+}
+
+// GetNoteRuns handles listing the recent runs of a note.
+func (ch *CodeRunHandler) GetNoteRuns(c *gin.Context) {
+	userID, noteID, ok := parseNoteRequest(c)
+	if !ok {
+		return
+	}
+
+	runs, err := ch.codeRunService.GetRuns(userID, noteID)
+	if err != nil {
+		respondWithCodeRunError(c, err, "Failed to retrieve runs")
+		return
+	}
+
+	common.RespondWithJSON(c, http.StatusOK, runs)
+}
+
+// GetNoteRun handles retrieving a single run of a note.
+func (ch *CodeRunHandler) GetNoteRun(c *gin.Context) {
+	userID, noteID, ok := parseNoteRequest(c)
+	if !ok {
+		return
+	}
+
+	runID, err := uuid.Parse(c.Param("runID"))
+	if err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, "Invalid run ID")
+		return
+	}
+
+	run, err := ch.codeRunService.GetRun(userID, noteID, runID)
+	if err != nil {
+		respondWithCodeRunError(c, err, "Failed to retrieve run")
+		return
+	}
+
+	common.RespondWithJSON(c, http.StatusOK, run)
+}
+
+// ProposeTestCases handles asking the model for test cases derived from a note's problem.
+func (ch *CodeRunHandler) ProposeTestCases(c *gin.Context) {
+	userID, noteID, ok := parseNoteRequest(c)
+	if !ok {
+		return
+	}
+
+	var payload ProposePayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	cases, err := ch.codeRunService.ProposeTestCases(c.Request.Context(), userID, noteID, payload.Model, payload.Count)
+	if err != nil {
+		respondWithCodeRunError(c, err, "Failed to propose test cases")
+		return
+	}
+
+	common.RespondWithJSON(c, http.StatusOK, gin.H{"testCases": cases})
+}
+
+// parseNoteRequest extracts the caller and note IDs, responding on failure.
+func parseNoteRequest(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userID, err := common.GetUserIDFromContext(c)
+	if err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, err.Error())
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	noteID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, "Invalid note ID")
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, noteID, true
+}
+
+// respondWithCodeRunError maps code run errors to HTTP responses.
+func respondWithCodeRunError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, coderunbusiness.ErrNoteNotFound):
+		common.RespondWithError(c, http.StatusNotFound, "Note not found")
+	case errors.Is(err, coderunbusiness.ErrRunNotFound):
+		common.RespondWithError(c, http.StatusNotFound, "Run not found")
+	case errors.Is(err, coderunbusiness.ErrNoCode),
+		errors.Is(err, coderunbusiness.ErrUnknownLanguage),
+		errors.Is(err, coderunbusiness.ErrNoProblem),
+		errors.Is(err, sandbox.ErrUnsupportedLanguage),
+		errors.Is(err, sandbox.ErrInvalidProgram):
+		common.RespondWithError(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, coderunbusiness.ErrInvalidTestCases):
+		common.RespondWithError(c, http.StatusBadGateway, err.Error())
+	case errors.Is(err, sandbox.ErrUnavailable):
+		log.Printf("Code sandbox error: %v", err)
+		common.RespondWithError(c, http.StatusServiceUnavailable, "Code runner is unavailable")
+	default:
+		common.RespondWithError(c, http.StatusInternalServerError, fallback)
+	}
+}
@@ -0,0 +1,15 @@
+package common
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// multipartOverhead leaves room for form fields and boundaries on top of the file size limit.
+const multipartOverhead = 1 << 20
+
+// LimitMultipartBody caps the body of a multipart upload whose file may be up to maxFileSize bytes.
+func LimitMultipartBody(c *gin.Context, maxFileSize int64) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxFileSize+multipartOverhead)
+}
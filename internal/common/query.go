@@ -0,0 +1,25 @@
+package common
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Paginate counts the rows matching query and returns the total with the query
+// for the page at offset, which the caller orders and loads.
+func Paginate(query *gorm.DB, limit, offset int) (int64, *gorm.DB, error) {
+	// Share the conditions between the count and the page query
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return 0, nil, err
+	}
+	return total, query.Limit(limit).Offset(offset), nil
+}
+
+// EscapeLike escapes the wildcards of a LIKE pattern so user input matches literally.
+func EscapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
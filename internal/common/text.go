@@ -0,0 +1,18 @@
+package common
+
+import "strings"
+
+const (
+	// MaxUserAgentLength and MaxIPAddressLength match the columns recording the
+	// client of a session or an audit log entry.
+	MaxUserAgentLength = 512
+	MaxIPAddressLength = 45
+)
+
+// Truncate cuts s to at most n bytes without splitting a UTF-8 sequence.
+func Truncate(s string, n int) string {
+	if len(s) > n {
+		return strings.ToValidUTF8(s[:n], "")
+	}
+	return s
+}
@@ -0,0 +1,245 @@
+package documentbusiness
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+	documentmodel "github.com/khoaphungnguyen/go-openai/internal/document/model"
+	embeddingmodel "github.com/khoaphungnguyen/go-openai/internal/embedding/model"
+	"github.com/khoaphungnguyen/go-openai/internal/extract"
+)
+
+const (
+	// MaxDocumentSize is the largest file accepted into a collection.
+	MaxDocumentSize = 20 << 20
+	// maxCollectionNameLength bounds collection names.
+	maxCollectionNameLength = 255
+)
+
+var (
+	// ErrCollectionNotFound is returned when the collection does not exist or belongs to another user.
+	ErrCollectionNotFound = errors.New("collection not found")
+	// ErrDocumentNotFound is returned when the document does not exist or belongs to another user.
+	ErrDocumentNotFound = errors.New("document not found")
+	// ErrInvalidCollectionName is returned for empty or overly long collection names.
+	ErrInvalidCollectionName = fmt.Errorf("collection name must be between 1 and %d characters", maxCollectionNameLength)
+	// ErrDuplicateCollection is returned when the user already has a collection with the name.
+	ErrDuplicateCollection = errors.New("a collection with this name already exists")
+	// ErrInvalidChunking is returned for chunk settings that cannot split documents.
+	ErrInvalidChunking = errors.New("invalid chunk settings")
+	// ErrFileTooLarge is returned when an upload exceeds MaxDocumentSize.
+	ErrFileTooLarge = fmt.Errorf("file exceeds the %d MB limit", MaxDocumentSize>>20)
+	// ErrUnsupportedFile is returned for files text cannot be extracted from.
+	ErrUnsupportedFile = errors.New("unsupported file type; upload a Markdown, text or source file, or a PDF")
+	// ErrDocumentProcessing is returned when reindexing a document that is being ingested.
+	ErrDocumentProcessing = errors.New("document is being processed")
+)
+
+// CreateCollection creates a collection for the user. A zero chunk size uses the default chunk settings.
+func (ds *DocumentService) CreateCollection(userID uuid.UUID, name, description string, chunking embeddingmodel.ChunkConfig) (*documentmodel.Collection, error) {
+	name, err := ds.validateName(userID, uuid.Nil, name)
+	if err != nil {
+		return nil, err
+	}
+	if chunking.Size == 0 {
+		chunking = ds.chunking
+	}
+	if err := chunking.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidChunking, err)
+	}
+
+	collection := &documentmodel.Collection{
+		UserID:       userID,
+		Name:         name,
+		Description:  strings.TrimSpace(description),
+		ChunkSize:    chunking.Size,
+		ChunkOverlap: chunking.Overlap,
+	}
+	if err := ds.documentStore.CreateCollection(collection); err != nil {
+		return nil, err
+	}
+	return collection, nil
+}
+
+// GetCollections lists the user's collections.
+func (ds *DocumentService) GetCollections(userID uuid.UUID) ([]documentmodel.Collection, error) {
+	return ds.documentStore.GetCollectionsByUserID(userID)
+}
+
+// GetCollection retrieves a collection of the user with its documents.
+func (ds *DocumentService) GetCollection(userID, collectionID uuid.UUID) (*documentmodel.CollectionDetail, error) {
+	collection, err := ds.getCollection(userID, collectionID)
+	if err != nil {
+		return nil, err
+	}
+	documents, err := ds.documentStore.GetDocumentsByCollectionID(collectionID)
+	if err != nil {
+		return nil, err
+	}
+	return &documentmodel.CollectionDetail{Collection: *collection, Documents: documents}, nil
+}
+
+// UpdateCollection renames a collection of the user and replaces its description.
+// Chunk settings cannot change once documents may have been split with them.
+func (ds *DocumentService) UpdateCollection(userID, collectionID uuid.UUID, name, description string) (*documentmodel.Collection, error) {
+	if _, err := ds.getCollection(userID, collectionID); err != nil {
+		return nil, err
+	}
+	name, err := ds.validateName(userID, collectionID, name)
+	if err != nil {
+		return nil, err
+	}
+	if err := ds.documentStore.UpdateCollection(collectionID, name, strings.TrimSpace(description)); err != nil {
+		return nil, err
+	}
+	return ds.documentStore.GetCollectionByID(collectionID)
+}
+
+// DeleteCollection removes a collection of the user with all its documents and their chunks.
+func (ds *DocumentService) DeleteCollection(ctx context.Context, userID, collectionID uuid.UUID) error {
+	if _, err := ds.getCollection(userID, collectionID); err != nil {
+		return err
+	}
+	documents, err := ds.documentStore.GetDocumentsByCollectionID(collectionID)
+	if err != nil {
+		return err
+	}
+	for _, document := range documents {
+		if err := ds.removeDocument(ctx, &document); err != nil {
+			return err
+		}
+	}
+	return ds.documentStore.DeleteCollection(collectionID)
+}
+
+// Upload stores a file in a collection of the user and queues it for ingestion.
+func (ds *DocumentService) Upload(ctx context.Context, userID, collectionID uuid.UUID, fileName string, r io.Reader) (*documentmodel.Document, error) {
+	if _, err := ds.getCollection(userID, collectionID); err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, MaxDocumentSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxDocumentSize {
+		return nil, ErrFileTooLarge
+	}
+
+	fileName = filepath.Base(fileName)
+	contentType := extract.DetectContentType(fileName, data)
+	kind := extract.DetectKind(fileName, contentType)
+	if kind != extract.KindText && kind != extract.KindPDF {
+		return nil, ErrUnsupportedFile
+	}
+
+	documentID := uuid.New()
+	document := &documentmodel.Document{
+		ID:           documentID,
+		UserID:       userID,
+		CollectionID: collectionID,
+		FileName:     fileName,
+		ContentType:  contentType,
+		Kind:         kind,
+		Size:         int64(len(data)),
+		StorageKey:   fmt.Sprintf("documents/%s/%s", userID, documentID),
+		Status:       documentmodel.StatusPending,
+	}
+
+	if err := ds.blobStore.Put(ctx, document.StorageKey, bytes.NewReader(data), document.Size, contentType); err != nil {
+		return nil, fmt.Errorf("failed to store file: %w", err)
+	}
+	if err := ds.documentStore.CreateDocument(document); err != nil {
+		_ = ds.blobStore.Delete(ctx, document.StorageKey)
+		return nil, err
+	}
+	ds.enqueue(document.ID)
+	return document, nil
+}
+
+// GetDocument retrieves a document of the user, including its ingestion status.
+func (ds *DocumentService) GetDocument(userID, documentID uuid.UUID) (*documentmodel.Document, error) {
+	return ds.getDocument(userID, documentID)
+}
+
+// ReindexDocument queues a document of the user for ingestion again, such as after it failed.
+func (ds *DocumentService) ReindexDocument(userID, documentID uuid.UUID) (*documentmodel.Document, error) {
+	if _, err := ds.getDocument(userID, documentID); err != nil {
+		return nil, err
+	}
+	reset, err := ds.documentStore.ResetDocument(documentID)
+	if err != nil {
+		return nil, err
+	}
+	if !reset {
+		return nil, ErrDocumentProcessing
+	}
+	ds.enqueue(documentID)
+	return ds.documentStore.GetDocumentByID(documentID)
+}
+
+// DeleteDocument removes a document of the user together with its file and chunks.
+func (ds *DocumentService) DeleteDocument(ctx context.Context, userID, documentID uuid.UUID) error {
+	document, err := ds.getDocument(userID, documentID)
+	if err != nil {
+		return err
+	}
+	return ds.removeDocument(ctx, document)
+}
+
+// removeDocument deletes the chunks, file and record of a document.
+func (ds *DocumentService) removeDocument(ctx context.Context, document *documentmodel.Document) error {
+	if err := ds.indexer.RemoveDocument(document.ID); err != nil {
+		return err
+	}
+	if err := ds.documentStore.DeleteDocument(document.ID); err != nil {
+		return err
+	}
+	return ds.blobStore.Delete(ctx, document.StorageKey)
+}
+
+// getCollection loads a collection, mapping missing and foreign ones to ErrCollectionNotFound.
+func (ds *DocumentService) getCollection(userID, collectionID uuid.UUID) (*documentmodel.Collection, error) {
+	collection, err := ds.documentStore.GetCollectionByID(collectionID)
+	if err != nil {
+		return nil, err
+	}
+	if collection == nil || collection.UserID != userID {
+		return nil, ErrCollectionNotFound
+	}
+	return collection, nil
+}
+
+// getDocument loads a document, mapping missing and foreign ones to ErrDocumentNotFound.
+func (ds *DocumentService) getDocument(userID, documentID uuid.UUID) (*documentmodel.Document, error) {
+	document, err := ds.documentStore.GetDocumentByID(documentID)
+	if err != nil {
+		return nil, err
+	}
+	if document == nil || document.UserID != userID {
+		return nil, ErrDocumentNotFound
+	}
+	return document, nil
+}
+
+// validateName trims a collection name and checks it is valid and unique for the user.
+func (ds *DocumentService) validateName(userID, collectionID uuid.UUID, name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len([]rune(name)) > maxCollectionNameLength {
+		return "", ErrInvalidCollectionName
+	}
+	exists, err := ds.documentStore.CollectionNameExists(userID, name, collectionID)
+	if err != nil {
+		return "", err
+	}
+	if exists {
+		return "", ErrDuplicateCollection
+	}
+	return name, nil
+}
@@ -0,0 +1,38 @@
+// documentbusiness contains the business logic for the document knowledge base.
+package documentbusiness
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/khoaphungnguyen/go-openai/internal/blobstore"
+	documentstorage "github.com/khoaphungnguyen/go-openai/internal/document/storage"
+	embeddingmodel "github.com/khoaphungnguyen/go-openai/internal/embedding/model"
+)
+
+// DocumentIndexer embeds the chunks of documents for search.
+type DocumentIndexer interface {
+	IndexDocument(ctx context.Context, documentID uuid.UUID) (int64, error)
+	RemoveDocument(documentID uuid.UUID) error
+}
+
+// DocumentService provides methods for collection and document operations.
+type DocumentService struct {
+	documentStore documentstorage.DocumentStore
+	blobStore     blobstore.Store
+	indexer       DocumentIndexer
+	chunking      embeddingmodel.ChunkConfig
+	queue         chan uuid.UUID
+}
+
+// NewDocumentService creates a new DocumentService. New collections split
+// documents as configured by chunking unless they set their own chunk settings.
+func NewDocumentService(documentStore documentstorage.DocumentStore, blobStore blobstore.Store, indexer DocumentIndexer, chunking embeddingmodel.ChunkConfig) *DocumentService {
+	return &DocumentService{
+		documentStore: documentStore,
+		blobStore:     blobStore,
+		indexer:       indexer,
+		chunking:      chunking,
+		queue:         make(chan uuid.UUID, ingestQueueSize),
+	}
+}
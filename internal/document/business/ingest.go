@@ -0,0 +1,140 @@
+package documentbusiness
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	documentmodel "github.com/khoaphungnguyen/go-openai/internal/document/model"
+	"github.com/khoaphungnguyen/go-openai/internal/extract"
+	"github.com/pkoukk/tiktoken-go"
+)
+
+const (
+	// ingestQueueSize bounds the documents waiting for the worker; the rest are
+	// picked up by the next sweep.
+	ingestQueueSize = 100
+	// sweepInterval is how often pending documents missing from the queue are picked up.
+	sweepInterval = time.Minute
+	// ingestTimeout bounds the ingestion of a single document.
+	ingestTimeout = 10 * time.Minute
+)
+
+// enqueue schedules a document for ingestion without blocking.
+func (ds *DocumentService) enqueue(documentID uuid.UUID) {
+	select {
+	case ds.queue <- documentID:
+	default:
+		log.Printf("Document queue full, leaving %s to the next sweep", documentID)
+	}
+}
+
+// Start runs the ingestion worker until ctx is cancelled. Documents whose
+// ingestion was interrupted by a restart are queued again.
+func (ds *DocumentService) Start(ctx context.Context) {
+	if count, err := ds.documentStore.ResetProcessing(); err != nil {
+		log.Printf("Error resetting interrupted documents: %v", err)
+	} else if count > 0 {
+		log.Printf("Resuming ingestion of %d documents", count)
+	}
+
+	go func() {
+		ticker := time.NewTicker(sweepInterval)
+		defer ticker.Stop()
+		ds.sweep()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case documentID := <-ds.queue:
+				ds.ingest(ctx, documentID)
+			case <-ticker.C:
+				ds.sweep()
+			}
+		}
+	}()
+}
+
+// sweep queues pending documents, such as those dropped from a full queue.
+func (ds *DocumentService) sweep() {
+	ids, err := ds.documentStore.GetDocumentIDsByStatus(documentmodel.StatusPending)
+	if err != nil {
+		log.Printf("Error listing pending documents: %v", err)
+		return
+	}
+	for _, id := range ids {
+		ds.enqueue(id)
+	}
+}
+
+// ingest extracts, chunks and embeds a pending document, recording the outcome
+// in its status. Documents already claimed or removed are skipped.
+func (ds *DocumentService) ingest(ctx context.Context, documentID uuid.UUID) {
+	claimed, err := ds.documentStore.ClaimDocument(documentID)
+	if err != nil {
+		log.Printf("Error claiming document %s: %v", documentID, err)
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, ingestTimeout)
+	defer cancel()
+
+	if reason, err := ds.process(ctx, documentID); err != nil {
+		log.Printf("Error ingesting document %s: %v", documentID, err)
+		if err := ds.documentStore.MarkFailed(documentID, reason); err != nil {
+			log.Printf("Error recording failed document %s: %v", documentID, err)
+		}
+	}
+}
+
+// process runs the ingestion steps of a claimed document. On failure it
+// returns the reason shown to the user along with the error.
+func (ds *DocumentService) process(ctx context.Context, documentID uuid.UUID) (string, error) {
+	document, err := ds.documentStore.GetDocumentByID(documentID)
+	if err != nil || document == nil {
+		return "Document could not be loaded", fmt.Errorf("load document: %v", err)
+	}
+
+	reader, err := ds.blobStore.Get(ctx, document.StorageKey)
+	if err != nil {
+		return "Stored file could not be read", err
+	}
+	data, err := io.ReadAll(reader)
+	reader.Close()
+	if err != nil {
+		return "Stored file could not be read", err
+	}
+
+	text, err := extract.Text(document.Kind, data)
+	if err != nil {
+		return "Text could not be extracted from the file", err
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "The file contains no text", fmt.Errorf("no text in %s", document.FileName)
+	}
+
+	tke, err := tiktoken.GetEncoding("cl100k_base")
+	if err != nil {
+		return "Text could not be tokenized", fmt.Errorf("getEncoding: %v", err)
+	}
+	if err := ds.documentStore.SaveContent(documentID, text, len(tke.Encode(text, nil, nil))); err != nil {
+		return "Extracted text could not be saved", err
+	}
+
+	chunks, err := ds.indexer.IndexDocument(ctx, documentID)
+	if err != nil {
+		return "Chunks could not be embedded", err
+	}
+	if err := ds.documentStore.MarkReady(documentID, chunks); err != nil {
+		return "Document could not be marked as ready", err
+	}
+	return "", nil
+}
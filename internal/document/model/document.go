@@ -0,0 +1,74 @@
+// documentmodel defines the data structures used for the document knowledge base.
+package documentmodel
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/khoaphungnguyen/go-openai/internal/extract"
+)
+
+// Status tracks the ingestion of a document.
+type Status string
+
+const (
+	// StatusPending documents are waiting for the ingestion worker.
+	StatusPending Status = "pending"
+	// StatusProcessing documents are being extracted, chunked and embedded.
+	StatusProcessing Status = "processing"
+	// StatusReady documents are searchable.
+	StatusReady Status = "ready"
+	// StatusFailed documents could not be ingested; Error tells why.
+	StatusFailed Status = "failed"
+)
+
+// Collection groups the reference documents of a user. Its chunk settings
+// apply to every document uploaded to it.
+type Collection struct {
+	ID           uuid.UUID `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
+	UserID       uuid.UUID `gorm:"type:uuid;not null" json:"-"`
+	Name         string    `gorm:"type:varchar(255);not null" json:"name"`
+	Description  string    `gorm:"type:text" json:"description"`
+	ChunkSize    int       `gorm:"not null" json:"chunkSize"`
+	ChunkOverlap int       `gorm:"not null" json:"chunkOverlap"`
+	// DocumentCount is filled in when listing collections.
+	DocumentCount int64     `gorm:"->" json:"documentCount"`
+	CreatedAt     time.Time `gorm:"default:now()" json:"createdAt"`
+	UpdatedAt     time.Time `gorm:"default:now()" json:"updatedAt"`
+}
+
+// TableName overrides the table name used by Collection.
+func (Collection) TableName() string {
+	return "document_collection"
+}
+
+// Document is a file uploaded to a collection, together with its extracted text.
+type Document struct {
+	ID           uuid.UUID    `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
+	UserID       uuid.UUID    `gorm:"type:uuid;not null" json:"-"`
+	CollectionID uuid.UUID    `gorm:"type:uuid;not null;index" json:"collectionId"`
+	FileName     string       `gorm:"type:varchar(255);not null" json:"fileName"`
+	ContentType  string       `gorm:"type:varchar(255);not null" json:"contentType"`
+	Kind         extract.Kind `gorm:"type:varchar(20);not null" json:"kind"`
+	Size         int64        `gorm:"not null" json:"size"`
+	StorageKey   string       `gorm:"type:varchar(512);not null" json:"-"`
+	Content      string       `gorm:"type:text" json:"-"`
+	Status       Status       `gorm:"type:varchar(20);not null" json:"status"`
+	Error        string       `gorm:"type:text" json:"error,omitempty"`
+	Tokens       int          `gorm:"not null;default:0" json:"tokens"`
+	ChunkCount   int64        `gorm:"not null;default:0" json:"chunkCount"`
+	IndexedAt    *time.Time   `json:"indexedAt"`
+	CreatedAt    time.Time    `gorm:"default:now()" json:"createdAt"`
+	UpdatedAt    time.Time    `gorm:"default:now()" json:"updatedAt"`
+}
+
+// TableName overrides the table name used by Document.
+func (Document) TableName() string {
+	return "document"
+}
+
+// CollectionDetail is a collection with its documents.
+type CollectionDetail struct {
+	Collection
+	Documents []Document `json:"documents"`
+}
@@ -0,0 +1,163 @@
+package documentstorage
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	documentmodel "github.com/khoaphungnguyen/go-openai/internal/document/model"
+	"gorm.io/gorm"
+)
+
+// documentCountColumn selects the number of documents of each collection.
+const documentCountColumn = "(SELECT count(*) FROM document d WHERE d.collection_id = document_collection.id) AS document_count"
+
+// CreateCollection adds a new collection to the database.
+func (ds *documentStore) CreateCollection(collection *documentmodel.Collection) error {
+	return ds.db.Create(collection).Error
+}
+
+// GetCollectionByID retrieves a collection with its document count, returning nil if it does not exist.
+func (ds *documentStore) GetCollectionByID(collectionID uuid.UUID) (*documentmodel.Collection, error) {
+	var collection documentmodel.Collection
+	err := ds.db.Select("document_collection.*, "+documentCountColumn).
+		Where("id = ?", collectionID).
+		First(&collection).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &collection, nil
+}
+
+// GetCollectionsByUserID retrieves the collections of a user with their document counts, by name.
+func (ds *documentStore) GetCollectionsByUserID(userID uuid.UUID) ([]documentmodel.Collection, error) {
+	var collections []documentmodel.Collection
+	err := ds.db.Select("document_collection.*, "+documentCountColumn).
+		Where("user_id = ?", userID).
+		Order("name ASC").
+		Find(&collections).Error
+	return collections, err
+}
+
+// CollectionNameExists reports whether the user has another collection with the name, ignoring case.
+func (ds *documentStore) CollectionNameExists(userID uuid.UUID, name string, exclude uuid.UUID) (bool, error) {
+	var count int64
+	err := ds.db.Model(&documentmodel.Collection{}).
+		Where("user_id = ? AND lower(name) = lower(?) AND id <> ?", userID, name, exclude).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// UpdateCollection renames a collection and replaces its description.
+func (ds *documentStore) UpdateCollection(collectionID uuid.UUID, name, description string) error {
+	return ds.db.Model(&documentmodel.Collection{}).Where("id = ?", collectionID).Updates(map[string]interface{}{
+		"name":        name,
+		"description": description,
+		"updated_at":  time.Now(),
+	}).Error
+}
+
+// DeleteCollection removes a collection; its documents are removed by cascade.
+func (ds *documentStore) DeleteCollection(collectionID uuid.UUID) error {
+	return ds.db.Delete(&documentmodel.Collection{}, "id = ?", collectionID).Error
+}
+
+// CreateDocument adds a new document to the database.
+func (ds *documentStore) CreateDocument(document *documentmodel.Document) error {
+	return ds.db.Create(document).Error
+}
+
+// GetDocumentByID retrieves a document, returning nil if it does not exist.
+func (ds *documentStore) GetDocumentByID(documentID uuid.UUID) (*documentmodel.Document, error) {
+	var document documentmodel.Document
+	err := ds.db.First(&document, "id = ?", documentID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &document, nil
+}
+
+// GetDocumentsByCollectionID retrieves the documents of a collection, newest first, without their text.
+func (ds *documentStore) GetDocumentsByCollectionID(collectionID uuid.UUID) ([]documentmodel.Document, error) {
+	var documents []documentmodel.Document
+	err := ds.db.Omit("content").
+		Where("collection_id = ?", collectionID).
+		Order("created_at DESC").
+		Find(&documents).Error
+	return documents, err
+}
+
+// GetDocumentIDsByStatus retrieves the documents in a status, oldest first.
+func (ds *documentStore) GetDocumentIDsByStatus(status documentmodel.Status) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := ds.db.Model(&documentmodel.Document{}).
+		Where("status = ?", status).
+		Order("created_at ASC").
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// ClaimDocument moves a pending document to processing, reporting whether it was pending.
+func (ds *documentStore) ClaimDocument(documentID uuid.UUID) (bool, error) {
+	result := ds.db.Model(&documentmodel.Document{}).
+		Where("id = ? AND status = ?", documentID, documentmodel.StatusPending).
+		Updates(map[string]interface{}{"status": documentmodel.StatusProcessing, "error": "", "updated_at": time.Now()})
+	return result.RowsAffected > 0, result.Error
+}
+
+// SaveContent stores the text extracted from a document.
+func (ds *documentStore) SaveContent(documentID uuid.UUID, content string, tokens int) error {
+	return ds.db.Model(&documentmodel.Document{}).Where("id = ?", documentID).Updates(map[string]interface{}{
+		"content":    content,
+		"tokens":     tokens,
+		"updated_at": time.Now(),
+	}).Error
+}
+
+// MarkReady records that a document was indexed into chunkCount chunks.
+func (ds *documentStore) MarkReady(documentID uuid.UUID, chunkCount int64) error {
+	now := time.Now()
+	return ds.db.Model(&documentmodel.Document{}).Where("id = ?", documentID).Updates(map[string]interface{}{
+		"status":      documentmodel.StatusReady,
+		"chunk_count": chunkCount,
+		"indexed_at":  now,
+		"updated_at":  now,
+	}).Error
+}
+
+// MarkFailed records why a document could not be ingested.
+func (ds *documentStore) MarkFailed(documentID uuid.UUID, reason string) error {
+	return ds.db.Model(&documentmodel.Document{}).Where("id = ?", documentID).Updates(map[string]interface{}{
+		"status":     documentmodel.StatusFailed,
+		"error":      reason,
+		"updated_at": time.Now(),
+	}).Error
+}
+
+// ResetDocument queues a document that is not being processed for ingestion
+// again, reporting whether it was reset.
+func (ds *documentStore) ResetDocument(documentID uuid.UUID) (bool, error) {
+	result := ds.db.Model(&documentmodel.Document{}).
+		Where("id = ? AND status <> ?", documentID, documentmodel.StatusProcessing).
+		Updates(map[string]interface{}{"status": documentmodel.StatusPending, "error": "", "updated_at": time.Now()})
+	return result.RowsAffected > 0, result.Error
+}
+
+// ResetProcessing queues documents whose ingestion was interrupted again.
+func (ds *documentStore) ResetProcessing() (int64, error) {
+	result := ds.db.Model(&documentmodel.Document{}).
+		Where("status = ?", documentmodel.StatusProcessing).
+		Update("status", documentmodel.StatusPending)
+	return result.RowsAffected, result.Error
+}
+
+// DeleteDocument removes a document.
+func (ds *documentStore) DeleteDocument(documentID uuid.UUID) error {
+	return ds.db.Delete(&documentmodel.Document{}, "id = ?", documentID).Error
+}
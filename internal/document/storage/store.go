@@ -0,0 +1,40 @@
+// documentstorage provides data persistence logic for collections and documents.
+package documentstorage
+
+import (
+	"github.com/google/uuid"
+	documentmodel "github.com/khoaphungnguyen/go-openai/internal/document/model"
+	"gorm.io/gorm"
+)
+
+// DocumentStore provides methods for collection and document operations.
+type DocumentStore interface {
+	CreateCollection(collection *documentmodel.Collection) error
+	GetCollectionByID(collectionID uuid.UUID) (*documentmodel.Collection, error)
+	GetCollectionsByUserID(userID uuid.UUID) ([]documentmodel.Collection, error)
+	CollectionNameExists(userID uuid.UUID, name string, exclude uuid.UUID) (bool, error)
+	UpdateCollection(collectionID uuid.UUID, name, description string) error
+	DeleteCollection(collectionID uuid.UUID) error
+
+	CreateDocument(document *documentmodel.Document) error
+	GetDocumentByID(documentID uuid.UUID) (*documentmodel.Document, error)
+	GetDocumentsByCollectionID(collectionID uuid.UUID) ([]documentmodel.Document, error)
+	GetDocumentIDsByStatus(status documentmodel.Status) ([]uuid.UUID, error)
+	ClaimDocument(documentID uuid.UUID) (bool, error)
+	SaveContent(documentID uuid.UUID, content string, tokens int) error
+	MarkReady(documentID uuid.UUID, chunkCount int64) error
+	MarkFailed(documentID uuid.UUID, reason string) error
+	ResetDocument(documentID uuid.UUID) (bool, error)
+	ResetProcessing() (int64, error)
+	DeleteDocument(documentID uuid.UUID) error
+}
+
+// documentStore encapsulates the logic for storing and retrieving collections and documents.
+type documentStore struct {
+	db *gorm.DB
+}
+
+// NewDocumentStore creates a new instance of documentStore.
+func NewDocumentStore(db *gorm.DB) DocumentStore {
+	return &documentStore{db: db}
+}
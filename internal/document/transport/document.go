@@ -0,0 +1,14 @@
+// documenttransport handles HTTP requests and responses for the document knowledge base.
+package documenttransport
+
+import documentbusiness "github.com/khoaphungnguyen/go-openai/internal/document/business"
+
+// DocumentHandler handles collection and document HTTP requests.
+type DocumentHandler struct {
+	documentService *documentbusiness.DocumentService
+}
+
+// NewDocumentHandler creates a new DocumentHandler.
+func NewDocumentHandler(documentService *documentbusiness.DocumentService) *DocumentHandler {
+	return &DocumentHandler{documentService: documentService}
+}
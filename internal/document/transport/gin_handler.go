@@ -0,0 +1,240 @@
+package documenttransport
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/khoaphungnguyen/go-openai/internal/common"
+	documentbusiness "github.com/khoaphungnguyen/go-openai/internal/document/business"
+	embeddingmodel "github.com/khoaphungnguyen/go-openai/internal/embedding/model"
+)
+
+type CollectionPayload struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+	// ChunkSize and ChunkOverlap are counted in tokens; a zero size uses the server defaults.
+	ChunkSize    int `json:"chunkSize"`
+	ChunkOverlap int `json:"chunkOverlap"`
+}
+
+type CollectionUpdatePayload struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+}
+
+// CreateCollection handles creating a document collection.
+func (dh *DocumentHandler) CreateCollection(c *gin.Context) {
+	userID, err := common.GetUserIDFromContext(c)
+	if err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var payload CollectionPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	chunking := embeddingmodel.ChunkConfig{Size: payload.ChunkSize, Overlap: payload.ChunkOverlap}
+	collection, err := dh.documentService.CreateCollection(userID, payload.Name, payload.Description, chunking)
+	if err != nil {
+		respondWithDocumentError(c, err, "Failed to create collection")
+		return
+	}
+
+	common.RespondWithJSON(c, http.StatusCreated, collection)
+}
+
+// GetCollections handles listing the caller's collections.
+func (dh *DocumentHandler) GetCollections(c *gin.Context) {
+	userID, err := common.GetUserIDFromContext(c)
+	if err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	collections, err := dh.documentService.GetCollections(userID)
+	if err != nil {
+		respondWithDocumentError(c, err, "Failed to retrieve collections")
+		return
+	}
+
+	common.RespondWithJSON(c, http.StatusOK, collections)
+}
+
+// GetCollection handles retrieving a collection with its documents.
+func (dh *DocumentHandler) GetCollection(c *gin.Context) {
+	userID, collectionID, ok := parseRequest(c, "Invalid collection ID")
+	if !ok {
+		return
+	}
+
+	collection, err := dh.documentService.GetCollection(userID, collectionID)
+	if err != nil {
+		respondWithDocumentError(c, err, "Failed to retrieve collection")
+		return
+	}
+
+	common.RespondWithJSON(c, http.StatusOK, collection)
+}
+
+// UpdateCollection handles renaming a collection.
+func (dh *DocumentHandler) UpdateCollection(c *gin.Context) {
+	userID, collectionID, ok := parseRequest(c, "Invalid collection ID")
+	if !ok {
+		return
+	}
+
+	var payload CollectionUpdatePayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	collection, err := dh.documentService.UpdateCollection(userID, collectionID, payload.Name, payload.Description)
+	if err != nil {
+		respondWithDocumentError(c, err, "Failed to update collection")
+		return
+	}
+
+	common.RespondWithJSON(c, http.StatusOK, collection)
+}
+
+// DeleteCollection handles deleting a collection with all its documents.
+func (dh *DocumentHandler) DeleteCollection(c *gin.Context) {
+	userID, collectionID, ok := parseRequest(c, "Invalid collection ID")
+	if !ok {
+		return
+	}
+
+	if err := dh.documentService.DeleteCollection(c.Request.Context(), userID, collectionID); err != nil {
+		respondWithDocumentError(c, err, "Failed to delete collection")
+		return
+	}
+
+	common.RespondWithJSON(c, http.StatusOK, gin.H{"message": "Collection deleted successfully"})
+}
+
+// UploadDocument handles a multipart upload of a file to a collection. The
+// form must contain a "file" part; the document is ingested in the background.
+func (dh *DocumentHandler) UploadDocument(c *gin.Context) {
+	userID, collectionID, ok := parseRequest(c, "Invalid collection ID")
+	if !ok {
+		return
+	}
+
+	common.LimitMultipartBody(c, documentbusiness.MaxDocumentSize)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, "File is required")
+		return
+	}
+	if fileHeader.Size > documentbusiness.MaxDocumentSize {
+		common.RespondWithError(c, http.StatusRequestEntityTooLarge, documentbusiness.ErrFileTooLarge.Error())
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, "Failed to read file")
+		return
+	}
+	defer file.Close()
+
+	document, err := dh.documentService.Upload(c.Request.Context(), userID, collectionID, fileHeader.Filename, file)
+	if err != nil {
+		respondWithDocumentError(c, err, "Failed to upload document")
+		return
+	}
+
+	common.RespondWithJSON(c, http.StatusAccepted, document)
+}
+
+// GetDocument handles retrieving a document and its ingestion status.
+func (dh *DocumentHandler) GetDocument(c *gin.Context) {
+	userID, documentID, ok := parseRequest(c, "Invalid document ID")
+	if !ok {
+		return
+	}
+
+	document, err := dh.documentService.GetDocument(userID, documentID)
+	if err != nil {
+		respondWithDocumentError(c, err, "Failed to retrieve document")
+		return
+	}
+
+	common.RespondWithJSON(c, http.StatusOK, document)
+}
+
+// ReindexDocument handles queueing a document for ingestion again.
+func (dh *DocumentHandler) ReindexDocument(c *gin.Context) {
+	userID, documentID, ok := parseRequest(c, "Invalid document ID")
+	if !ok {
+		return
+	}
+
+	document, err := dh.documentService.ReindexDocument(userID, documentID)
+	if err != nil {
+		respondWithDocumentError(c, err, "Failed to reindex document")
+		return
+	}
+
+	common.RespondWithJSON(c, http.StatusAccepted, document)
+}
+
+// DeleteDocument handles deleting a document with its file and chunks.
+func (dh *DocumentHandler) DeleteDocument(c *gin.Context) {
+	userID, documentID, ok := parseRequest(c, "Invalid document ID")
+	if !ok {
+		return
+	}
+
+	if err := dh.documentService.DeleteDocument(c.Request.Context(), userID, documentID); err != nil {
+		respondWithDocumentError(c, err, "Failed to delete document")
+		return
+	}
+
+	common.RespondWithJSON(c, http.StatusOK, gin.H{"message": "Document deleted successfully"})
+}
+
+// parseRequest extracts the caller and the ID path parameter, responding on failure.
+func parseRequest(c *gin.Context, invalidID string) (uuid.UUID, uuid.UUID, bool) {
+	userID, err := common.GetUserIDFromContext(c)
+	if err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, err.Error())
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, invalidID)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, id, true
+}
+
+// respondWithDocumentError maps knowledge base errors to HTTP responses.
+func respondWithDocumentError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, documentbusiness.ErrCollectionNotFound):
+		common.RespondWithError(c, http.StatusNotFound, "Collection not found")
+	case errors.Is(err, documentbusiness.ErrDocumentNotFound):
+		common.RespondWithError(c, http.StatusNotFound, "Document not found")
+	case errors.Is(err, documentbusiness.ErrInvalidCollectionName),
+		errors.Is(err, documentbusiness.ErrInvalidChunking):
+		common.RespondWithError(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, documentbusiness.ErrDuplicateCollection),
+		errors.Is(err, documentbusiness.ErrDocumentProcessing):
+		common.RespondWithError(c, http.StatusConflict, err.Error())
+	case errors.Is(err, documentbusiness.ErrFileTooLarge):
+		common.RespondWithError(c, http.StatusRequestEntityTooLarge, err.Error())
+	case errors.Is(err, documentbusiness.ErrUnsupportedFile):
+		common.RespondWithError(c, http.StatusUnsupportedMediaType, err.Error())
+	default:
+		common.RespondWithError(c, http.StatusInternalServerError, fallback)
+	}
+}
@@ -0,0 +1,29 @@
+package embeddingbusiness
+
+import (
+	embeddingmodel "github.com/khoaphungnguyen/go-openai/internal/embedding/model"
+	"github.com/pkoukk/tiktoken-go"
+)
+
+// maxChunksPerSource bounds the chunks embedded for a single source.
+const maxChunksPerSource = 500
+
+// splitChunks splits text into windows of at most Size tokens, each starting
+// Overlap tokens before the end of the previous one.
+func splitChunks(tke *tiktoken.Tiktoken, text string, config embeddingmodel.ChunkConfig) []string {
+	tokens := tke.Encode(text, nil, nil)
+	if len(tokens) <= config.Size {
+		return []string{text}
+	}
+
+	var chunks []string
+	step := config.Size - config.Overlap
+	for start := 0; start < len(tokens) && len(chunks) < maxChunksPerSource; start += step {
+		end := min(start+config.Size, len(tokens))
+		chunks = append(chunks, tke.Decode(tokens[start:end]))
+		if end == len(tokens) {
+			break
+		}
+	}
+	return chunks
+}
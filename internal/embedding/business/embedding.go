@@ -0,0 +1,44 @@
+// embeddingbusiness contains the business logic for computing embeddings and semantic search.
+package embeddingbusiness
+
+import (
+	"github.com/google/uuid"
+	embeddingmodel "github.com/khoaphungnguyen/go-openai/internal/embedding/model"
+	embeddingstorage "github.com/khoaphungnguyen/go-openai/internal/embedding/storage"
+	"github.com/khoaphungnguyen/go-openai/internal/provider"
+)
+
+// job asks the worker to refresh the embedding of a source.
+type job struct {
+	sourceType embeddingmodel.SourceType
+	sourceID   uuid.UUID
+}
+
+// EmbeddingService provides methods for embedding operations.
+type EmbeddingService struct {
+	embeddingStore embeddingstorage.EmbeddingStore
+	embedder       provider.Embedder
+	model          string
+	chunking       embeddingmodel.ChunkConfig
+	queue          chan job
+}
+
+// NewEmbeddingService creates a new EmbeddingService computing vectors with the
+// given embedding model over chunks of content split as configured.
+func NewEmbeddingService(embeddingStore embeddingstorage.EmbeddingStore, embedder provider.Embedder, model string, chunking embeddingmodel.ChunkConfig) (*EmbeddingService, error) {
+	if err := chunking.Validate(); err != nil {
+		return nil, err
+	}
+	return &EmbeddingService{
+		embeddingStore: embeddingStore,
+		embedder:       embedder,
+		model:          model,
+		chunking:       chunking,
+		queue:          make(chan job, queueSize),
+	}, nil
+}
+
+// Model returns the embedding model used by the service.
+func (es *EmbeddingService) Model() string {
+	return es.model
+}
@@ -0,0 +1,223 @@
+package embeddingbusiness
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	embeddingmodel "github.com/khoaphungnguyen/go-openai/internal/embedding/model"
+	"github.com/pkoukk/tiktoken-go"
+)
+
+const (
+	// BackfillInterval is how often the worker looks for content without an up-to-date embedding.
+	BackfillInterval = 5 * time.Minute
+	// queueSize bounds the pending jobs; content dropped from a full queue is picked up by the backfill.
+	queueSize = 1000
+	// backfillBatch is how many sources are loaded at a time during a backfill.
+	backfillBatch = 50
+	// embedBatchSize is how many chunks are embedded per request.
+	embedBatchSize = 100
+	// jobTimeout bounds the time spent embedding a single batch.
+	jobTimeout = time.Minute
+)
+
+// Enqueue schedules the embedding of a source to be refreshed in the background.
+func (es *EmbeddingService) Enqueue(sourceType embeddingmodel.SourceType, sourceID uuid.UUID) {
+	select {
+	case es.queue <- job{sourceType: sourceType, sourceID: sourceID}:
+	default:
+		log.Printf("Embedding queue full, leaving %s %s to the backfill", sourceType, sourceID)
+	}
+}
+
+// IndexNote schedules the embedding of a created or updated note.
+func (es *EmbeddingService) IndexNote(noteID uuid.UUID) {
+	es.Enqueue(embeddingmodel.SourceNote, noteID)
+}
+
+// IndexAttachment schedules the embedding of the text extracted from an uploaded file.
+func (es *EmbeddingService) IndexAttachment(attachmentID uuid.UUID) {
+	es.Enqueue(embeddingmodel.SourceAttachment, attachmentID)
+}
+
+// IndexDocument embeds an uploaded document right away and returns the number of chunks indexed.
+func (es *EmbeddingService) IndexDocument(ctx context.Context, documentID uuid.UUID) (int64, error) {
+	if _, err := es.IndexSources(ctx, embeddingmodel.SourceDocument, []uuid.UUID{documentID}); err != nil {
+		return 0, err
+	}
+	return es.embeddingStore.CountChunks(embeddingmodel.SourceDocument, documentID, es.model)
+}
+
+// RemoveDocument deletes the embeddings of every chunk of a document.
+func (es *EmbeddingService) RemoveDocument(documentID uuid.UUID) error {
+	return es.embeddingStore.DeleteEmbeddings(embeddingmodel.SourceDocument, []uuid.UUID{documentID})
+}
+
+// IndexMessage schedules the embedding of a new chat message.
+func (es *EmbeddingService) IndexMessage(messageID uuid.UUID) {
+	es.Enqueue(embeddingmodel.SourceMessage, messageID)
+}
+
+// Start runs the worker that processes queued jobs, and a backfill every
+// BackfillInterval that embeds content missed by the queue, until ctx is cancelled.
+func (es *EmbeddingService) Start(ctx context.Context) {
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case j := <-es.queue:
+				jobCtx, cancel := context.WithTimeout(ctx, jobTimeout)
+				if _, err := es.IndexSources(jobCtx, j.sourceType, []uuid.UUID{j.sourceID}); err != nil {
+					log.Printf("Error embedding %s %s: %v", j.sourceType, j.sourceID, err)
+				}
+				cancel()
+			}
+		}
+	}()
+
+	go func() {
+		ticker := time.NewTicker(BackfillInterval)
+		defer ticker.Stop()
+		for {
+			if count, err := es.Backfill(ctx); err != nil {
+				log.Printf("Error backfilling embeddings: %v", err)
+			} else if count > 0 {
+				log.Printf("Backfilled embeddings of %d sources", count)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Backfill embeds all content that has no up-to-date embedding and removes
+// embeddings whose content was purged. It returns how many sources were indexed.
+func (es *EmbeddingService) Backfill(ctx context.Context) (int, error) {
+	total := 0
+	for _, sourceType := range embeddingmodel.SourceTypes {
+		for ctx.Err() == nil {
+			ids, err := es.embeddingStore.GetStaleSourceIDs(sourceType, es.model, backfillBatch)
+			if err != nil {
+				return total, err
+			}
+			if len(ids) == 0 {
+				break
+			}
+			jobCtx, cancel := context.WithTimeout(ctx, jobTimeout)
+			count, err := es.IndexSources(jobCtx, sourceType, ids)
+			cancel()
+			total += count
+			if err != nil {
+				return total, err
+			}
+			// Stop once a batch makes no progress, such as sources with no text
+			if len(ids) < backfillBatch || count == 0 {
+				break
+			}
+		}
+		if _, err := es.embeddingStore.DeleteOrphanedEmbeddings(sourceType); err != nil {
+			return total, err
+		}
+	}
+	return total, ctx.Err()
+}
+
+// IndexSources refreshes the embeddings of the given sources chunk by chunk,
+// calling the model only for chunks that changed. It returns how many sources were indexed.
+func (es *EmbeddingService) IndexSources(ctx context.Context, sourceType embeddingmodel.SourceType, ids []uuid.UUID) (int, error) {
+	sources, err := es.embeddingStore.LoadSources(sourceType, ids)
+	if err != nil {
+		return 0, err
+	}
+	existing, err := es.embeddingStore.GetEmbeddings(sourceType, ids, es.model)
+	if err != nil {
+		return 0, err
+	}
+	current := make(map[uuid.UUID]map[int]embeddingmodel.Embedding, len(sources))
+	for _, embedding := range existing {
+		if current[embedding.SourceID] == nil {
+			current[embedding.SourceID] = make(map[int]embeddingmodel.Embedding)
+		}
+		current[embedding.SourceID][embedding.ChunkIndex] = embedding
+	}
+
+	tke, err := tiktoken.GetEncoding("cl100k_base")
+	if err != nil {
+		return 0, fmt.Errorf("getEncoding: %v", err)
+	}
+
+	indexed := 0
+	for _, source := range sources {
+		text := strings.TrimSpace(source.Text)
+		if text == "" {
+			continue
+		}
+		chunking := es.chunking
+		if source.Chunking.Size > 0 {
+			chunking = source.Chunking
+		}
+		chunks := splitChunks(tke, text, chunking)
+
+		var pending []*embeddingmodel.Embedding
+		for i, chunk := range chunks {
+			embedding := &embeddingmodel.Embedding{
+				UserID:      source.UserID,
+				SourceType:  sourceType,
+				SourceID:    source.ID,
+				ChunkIndex:  i,
+				Model:       es.model,
+				Content:     chunk,
+				ContentHash: contentHash(chunk),
+			}
+			if previous, ok := current[source.ID][i]; ok && previous.ContentHash == embedding.ContentHash {
+				// Unchanged chunks only need their timestamp refreshed
+				embedding.Vector = previous.Vector
+				if err := es.embeddingStore.UpsertEmbedding(embedding); err != nil {
+					return indexed, err
+				}
+				continue
+			}
+			pending = append(pending, embedding)
+		}
+
+		for start := 0; start < len(pending); start += embedBatchSize {
+			batch := pending[start:min(start+embedBatchSize, len(pending))]
+			inputs := make([]string, len(batch))
+			for i, embedding := range batch {
+				inputs[i] = embedding.Content
+			}
+			vectors, err := es.embedder.Embed(ctx, es.model, inputs)
+			if err != nil {
+				return indexed, err
+			}
+			for i, embedding := range batch {
+				embedding.Vector = vectors[i]
+				if err := es.embeddingStore.UpsertEmbedding(embedding); err != nil {
+					return indexed, err
+				}
+			}
+		}
+
+		if err := es.embeddingStore.DeleteChunksFrom(sourceType, source.ID, es.model, len(chunks)); err != nil {
+			return indexed, err
+		}
+		indexed++
+	}
+	return indexed, nil
+}
+
+// contentHash fingerprints embedded text so unchanged content is not re-embedded.
+func contentHash(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,66 @@
+package embeddingbusiness
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	embeddingmodel "github.com/khoaphungnguyen/go-openai/internal/embedding/model"
+	"github.com/pkoukk/tiktoken-go"
+)
+
+// Retrieve returns up to k chunks of the user's notes and documents most
+// relevant to the query, best first, whose combined content fits in tokenBudget tokens.
+func (es *EmbeddingService) Retrieve(ctx context.Context, userID uuid.UUID, query string, k, tokenBudget int) ([]embeddingmodel.Passage, error) {
+	query = strings.TrimSpace(query)
+	if query == "" || k <= 0 || tokenBudget <= 0 {
+		return nil, nil
+	}
+
+	vectors, err := es.embedder.Embed(ctx, es.model, []string{query})
+	if err != nil {
+		return nil, err
+	}
+	matches, err := es.embeddingStore.Search(userID, es.model, vectors[0], embeddingmodel.KnowledgeSourceTypes, nil, k*searchOverfetch)
+	if err != nil {
+		return nil, err
+	}
+	sources, err := es.loadMatchedSources(matches)
+	if err != nil {
+		return nil, err
+	}
+
+	tke, err := tiktoken.GetEncoding("cl100k_base")
+	if err != nil {
+		return nil, fmt.Errorf("getEncoding: %v", err)
+	}
+
+	var passages []embeddingmodel.Passage
+	remaining := tokenBudget
+	for _, match := range matches {
+		source, ok := sources[match.SourceID]
+		if !ok {
+			continue
+		}
+		// Skip chunks that do not fit so smaller, less relevant ones can still be used
+		tokens := len(tke.Encode(match.Content, nil, nil))
+		if tokens > remaining {
+			continue
+		}
+		remaining -= tokens
+		passages = append(passages, embeddingmodel.Passage{
+			SourceType: source.Type,
+			SourceID:   source.ID,
+			ParentID:   source.ParentID,
+			Title:      source.Title,
+			ChunkIndex: match.ChunkIndex,
+			Content:    match.Content,
+			Score:      match.Score,
+		})
+		if len(passages) == k {
+			break
+		}
+	}
+	return passages, nil
+}
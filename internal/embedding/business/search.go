@@ -0,0 +1,183 @@
+package embeddingbusiness
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	embeddingmodel "github.com/khoaphungnguyen/go-openai/internal/embedding/model"
+)
+
+const (
+	// DefaultSearchLimit is the number of results returned when none is requested.
+	DefaultSearchLimit = 10
+	// MaxSearchLimit caps the number of results of a single search.
+	MaxSearchLimit = 50
+	// snippetLength is the number of characters of content shown with a result.
+	snippetLength = 240
+	// searchOverfetch multiplies the matches fetched per requested result.
+	searchOverfetch = 4
+)
+
+var (
+	// ErrEmptyQuery is returned when a search has no query text.
+	ErrEmptyQuery = errors.New("search query cannot be empty")
+	// ErrInvalidSourceType is returned when a search names an unknown source type.
+	ErrInvalidSourceType = errors.New("invalid source type")
+	// ErrNoteNotFound is returned when the note does not exist or belongs to another user.
+	ErrNoteNotFound = errors.New("note not found")
+	// ErrNotIndexed is returned when a note has no content to compare with.
+	ErrNotIndexed = errors.New("note has no content to compare")
+)
+
+// Search returns the user's content of the given types closest in meaning to the query.
+// All source types are searched when none is given.
+func (es *EmbeddingService) Search(ctx context.Context, userID uuid.UUID, query string, types []embeddingmodel.SourceType, limit int) ([]embeddingmodel.SearchResult, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, ErrEmptyQuery
+	}
+	if len(types) == 0 {
+		types = embeddingmodel.SourceTypes
+	}
+	for _, sourceType := range types {
+		if !sourceType.IsValid() {
+			return nil, ErrInvalidSourceType
+		}
+	}
+	limit = clampLimit(limit)
+
+	vectors, err := es.embedder.Embed(ctx, es.model, []string{query})
+	if err != nil {
+		return nil, err
+	}
+	// Fetch extra matches since several chunks of a source may match and trashed content is dropped
+	matches, err := es.embeddingStore.Search(userID, es.model, vectors[0], types, nil, limit*searchOverfetch)
+	if err != nil {
+		return nil, err
+	}
+	return es.hydrate(matches, limit)
+}
+
+// SimilarNotes returns the user's notes closest in meaning to the given note,
+// compared by the mean of its chunk vectors. A note that has not been embedded
+// yet is embedded on demand.
+func (es *EmbeddingService) SimilarNotes(ctx context.Context, userID, noteID uuid.UUID, limit int) ([]embeddingmodel.SearchResult, error) {
+	sources, err := es.embeddingStore.LoadSources(embeddingmodel.SourceNote, []uuid.UUID{noteID})
+	if err != nil {
+		return nil, err
+	}
+	if len(sources) == 0 || sources[0].UserID != userID {
+		return nil, ErrNoteNotFound
+	}
+	limit = clampLimit(limit)
+
+	ids := []uuid.UUID{noteID}
+	embeddings, err := es.embeddingStore.GetEmbeddings(embeddingmodel.SourceNote, ids, es.model)
+	if err != nil {
+		return nil, err
+	}
+	if len(embeddings) == 0 {
+		if _, err := es.IndexSources(ctx, embeddingmodel.SourceNote, ids); err != nil {
+			return nil, err
+		}
+		if embeddings, err = es.embeddingStore.GetEmbeddings(embeddingmodel.SourceNote, ids, es.model); err != nil {
+			return nil, err
+		}
+		if len(embeddings) == 0 {
+			return nil, ErrNotIndexed
+		}
+	}
+
+	types := []embeddingmodel.SourceType{embeddingmodel.SourceNote}
+	matches, err := es.embeddingStore.Search(userID, es.model, meanVector(embeddings), types, &noteID, limit*searchOverfetch)
+	if err != nil {
+		return nil, err
+	}
+	return es.hydrate(matches, limit)
+}
+
+// hydrate keeps the best matching chunk of each source whose content still
+// exists and is not trashed, up to limit results in score order.
+func (es *EmbeddingService) hydrate(matches []embeddingmodel.Match, limit int) ([]embeddingmodel.SearchResult, error) {
+	sources, err := es.loadMatchedSources(matches)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]embeddingmodel.SearchResult, 0, limit)
+	seen := make(map[uuid.UUID]bool)
+	for _, match := range matches {
+		source, ok := sources[match.SourceID]
+		if !ok || seen[match.SourceID] {
+			continue
+		}
+		seen[match.SourceID] = true
+		results = append(results, embeddingmodel.SearchResult{
+			Type:     source.Type,
+			ID:       source.ID,
+			ParentID: source.ParentID,
+			Title:    source.Title,
+			Snippet:  snippet(match.Content, source.Title),
+			Score:    match.Score,
+		})
+		if len(results) == limit {
+			break
+		}
+	}
+	return results, nil
+}
+
+// loadMatchedSources loads the sources of matches by ID, omitting missing and trashed ones.
+func (es *EmbeddingService) loadMatchedSources(matches []embeddingmodel.Match) (map[uuid.UUID]embeddingmodel.Source, error) {
+	idsByType := make(map[embeddingmodel.SourceType][]uuid.UUID)
+	for _, match := range matches {
+		idsByType[match.SourceType] = append(idsByType[match.SourceType], match.SourceID)
+	}
+	sources := make(map[uuid.UUID]embeddingmodel.Source)
+	for sourceType, ids := range idsByType {
+		loaded, err := es.embeddingStore.LoadSources(sourceType, ids)
+		if err != nil {
+			return nil, err
+		}
+		for _, source := range loaded {
+			sources[source.ID] = source
+		}
+	}
+	return sources, nil
+}
+
+// meanVector averages the chunk vectors of a source.
+func meanVector(embeddings []embeddingmodel.Embedding) embeddingmodel.Vector {
+	mean := make(embeddingmodel.Vector, len(embeddings[0].Vector))
+	for _, embedding := range embeddings {
+		for i := range mean {
+			if i < len(embedding.Vector) {
+				mean[i] += embedding.Vector[i] / float32(len(embeddings))
+			}
+		}
+	}
+	return mean
+}
+
+// snippet returns the start of a matched chunk, without a leading repeat of the source title.
+func snippet(content, title string) string {
+	text := strings.TrimSpace(content)
+	if title != "" {
+		text = strings.TrimSpace(strings.TrimPrefix(text, title))
+	}
+	text = strings.Join(strings.Fields(text), " ")
+	if runes := []rune(text); len(runes) > snippetLength {
+		return string(runes[:snippetLength]) + "…"
+	}
+	return text
+}
+
+// clampLimit applies the default and maximum number of search results.
+func clampLimit(limit int) int {
+	if limit <= 0 {
+		return DefaultSearchLimit
+	}
+	return min(limit, MaxSearchLimit)
+}
@@ -0,0 +1,197 @@
+// embeddingmodel defines the data structures used for semantic search.
+package embeddingmodel
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SourceType identifies the kind of content an embedding was computed from.
+type SourceType string
+
+const (
+	SourceNote       SourceType = "note"
+	SourceMessage    SourceType = "message"
+	SourceAttachment SourceType = "attachment"
+	SourceDocument   SourceType = "document"
+)
+
+// SourceTypes lists the searchable source types.
+var SourceTypes = []SourceType{SourceNote, SourceMessage, SourceAttachment, SourceDocument}
+
+// KnowledgeSourceTypes lists the source types chat answers can be grounded in.
+var KnowledgeSourceTypes = []SourceType{SourceNote, SourceAttachment, SourceDocument}
+
+// MaxChunkTokens is the largest chunk embedding models accept.
+const MaxChunkTokens = 8000
+
+// ChunkConfig sets how content is split into overlapping chunks of tokens before embedding.
+type ChunkConfig struct {
+	Size    int `json:"size"`
+	Overlap int `json:"overlap"`
+}
+
+// DefaultChunkConfig suits notes and documents of a few pages.
+var DefaultChunkConfig = ChunkConfig{Size: 500, Overlap: 50}
+
+// Validate checks that chunks make progress and fit the embedding model.
+func (c ChunkConfig) Validate() error {
+	if c.Size <= 0 || c.Size > MaxChunkTokens {
+		return fmt.Errorf("chunk size must be between 1 and %d tokens", MaxChunkTokens)
+	}
+	if c.Overlap < 0 || c.Overlap >= c.Size {
+		return errors.New("chunk overlap must be at least 0 and less than the chunk size")
+	}
+	return nil
+}
+
+// IsValid reports whether t is a known source type.
+func (t SourceType) IsValid() bool {
+	for _, known := range SourceTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Embedding is the vector of a chunk of content under an embedding model.
+// Long content is split into overlapping chunks numbered from zero.
+type Embedding struct {
+	ID          uuid.UUID  `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	UserID      uuid.UUID  `gorm:"type:uuid;not null;index"`
+	SourceType  SourceType `gorm:"type:varchar(20);not null"`
+	SourceID    uuid.UUID  `gorm:"type:uuid;not null"`
+	ChunkIndex  int        `gorm:"not null;default:0"`
+	Model       string     `gorm:"type:varchar(255);not null"`
+	Content     string     `gorm:"type:text;not null"`
+	ContentHash string     `gorm:"type:varchar(64);not null"`
+	Vector      Vector     `gorm:"type:real[];not null"`
+	UpdatedAt   time.Time  `gorm:"autoUpdateTime"`
+}
+
+// TableName overrides the table name used by Embedding.
+func (Embedding) TableName() string {
+	return "embedding"
+}
+
+// Source is content that can be embedded, loaded from the table of its type.
+type Source struct {
+	Type     SourceType
+	ID       uuid.UUID
+	UserID   uuid.UUID
+	ParentID *uuid.UUID
+	Title    string
+	Text     string
+	// Chunking overrides how the source is split; the zero value uses the service default.
+	Chunking ChunkConfig
+}
+
+// Match is a chunk whose embedding is close to a query vector.
+type Match struct {
+	SourceType SourceType
+	SourceID   uuid.UUID
+	ChunkIndex int
+	Content    string
+	Score      float64
+}
+
+// Passage is a chunk of the user's content retrieved to ground a chat answer.
+type Passage struct {
+	SourceType SourceType
+	SourceID   uuid.UUID
+	ParentID   *uuid.UUID
+	Title      string
+	ChunkIndex int
+	Content    string
+	Score      float64
+}
+
+// SearchResult is a match with the content it points to.
+type SearchResult struct {
+	Type     SourceType `json:"type"`
+	ID       uuid.UUID  `json:"id"`
+	ParentID *uuid.UUID `json:"parentId,omitempty"`
+	Title    string     `json:"title"`
+	Snippet  string     `json:"snippet"`
+	Score    float64    `json:"score"`
+}
+
+// Vector is an embedding vector stored as a Postgres real[] array.
+type Vector []float32
+
+// Value implements driver.Valuer.
+func (v Vector) Value() (driver.Value, error) {
+	return "{" + v.join() + "}", nil
+}
+
+// Scan implements sql.Scanner.
+func (v *Vector) Scan(src interface{}) error {
+	var text string
+	switch value := src.(type) {
+	case string:
+		text = value
+	case []byte:
+		text = string(value)
+	case nil:
+		*v = nil
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into Vector", src)
+	}
+
+	text = strings.Trim(text, "{}[]")
+	if text == "" {
+		*v = Vector{}
+		return nil
+	}
+	parts := strings.Split(text, ",")
+	vector := make(Vector, len(parts))
+	for i, part := range parts {
+		f, err := strconv.ParseFloat(strings.TrimSpace(part), 32)
+		if err != nil {
+			return fmt.Errorf("invalid vector component %q: %w", part, err)
+		}
+		vector[i] = float32(f)
+	}
+	*v = vector
+	return nil
+}
+
+// Literal formats the vector as a pgvector literal.
+func (v Vector) Literal() string {
+	return "[" + v.join() + "]"
+}
+
+func (v Vector) join() string {
+	parts := make([]string, len(v))
+	for i, f := range v {
+		parts[i] = strconv.FormatFloat(float64(f), 'g', -1, 32)
+	}
+	return strings.Join(parts, ",")
+}
+
+// Cosine returns the cosine similarity of two vectors, or 0 when their
+// dimensions differ or either is zero.
+func Cosine(a, b Vector) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
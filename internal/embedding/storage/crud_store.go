@@ -0,0 +1,109 @@
+package embeddingstorage
+
+import (
+	"sort"
+
+	"github.com/google/uuid"
+	embeddingmodel "github.com/khoaphungnguyen/go-openai/internal/embedding/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// UpsertEmbedding saves an embedding, replacing the one of the same source chunk and model.
+func (es *embeddingStore) UpsertEmbedding(embedding *embeddingmodel.Embedding) error {
+	return es.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "source_type"}, {Name: "source_id"}, {Name: "model"}, {Name: "chunk_index"}},
+		DoUpdates: clause.AssignmentColumns([]string{"user_id", "content", "content_hash", "vector", "updated_at"}),
+	}).Create(embedding).Error
+}
+
+// GetEmbeddings retrieves the embeddings of every chunk of several sources under a model.
+func (es *embeddingStore) GetEmbeddings(sourceType embeddingmodel.SourceType, sourceIDs []uuid.UUID, model string) ([]embeddingmodel.Embedding, error) {
+	var embeddings []embeddingmodel.Embedding
+	if len(sourceIDs) == 0 {
+		return embeddings, nil
+	}
+	err := es.db.Where("source_type = ? AND source_id IN ? AND model = ?", sourceType, sourceIDs, model).Find(&embeddings).Error
+	return embeddings, err
+}
+
+// DeleteEmbeddings removes the embeddings of the given sources under every model.
+func (es *embeddingStore) DeleteEmbeddings(sourceType embeddingmodel.SourceType, sourceIDs []uuid.UUID) error {
+	if len(sourceIDs) == 0 {
+		return nil
+	}
+	return es.db.Where("source_type = ? AND source_id IN ?", sourceType, sourceIDs).Delete(&embeddingmodel.Embedding{}).Error
+}
+
+// DeleteChunksFrom removes the embeddings of a source's chunks numbered from
+// from onwards, left over after its content got shorter.
+func (es *embeddingStore) DeleteChunksFrom(sourceType embeddingmodel.SourceType, sourceID uuid.UUID, model string, from int) error {
+	return es.db.Where("source_type = ? AND source_id = ? AND model = ? AND chunk_index >= ?", sourceType, sourceID, model, from).
+		Delete(&embeddingmodel.Embedding{}).Error
+}
+
+// CountChunks counts the embedded chunks of a source under a model.
+func (es *embeddingStore) CountChunks(sourceType embeddingmodel.SourceType, sourceID uuid.UUID, model string) (int64, error) {
+	var count int64
+	err := es.db.Model(&embeddingmodel.Embedding{}).
+		Where("source_type = ? AND source_id = ? AND model = ?", sourceType, sourceID, model).
+		Count(&count).Error
+	return count, err
+}
+
+// UsesPgvector reports whether similarity search runs in Postgres.
+func (es *embeddingStore) UsesPgvector() bool {
+	return es.pgvector
+}
+
+// Search finds the user's chunks whose embeddings under the model are closest
+// to vector by cosine similarity, best first.
+func (es *embeddingStore) Search(userID uuid.UUID, model string, vector embeddingmodel.Vector, types []embeddingmodel.SourceType, exclude *uuid.UUID, limit int) ([]embeddingmodel.Match, error) {
+	query := es.db.Model(&embeddingmodel.Embedding{}).
+		Where("user_id = ? AND model = ? AND source_type IN ?", userID, model, types)
+	if exclude != nil {
+		query = query.Where("source_id <> ?", *exclude)
+	}
+
+	if es.pgvector {
+		var matches []embeddingmodel.Match
+		literal := vector.Literal()
+		err := query.
+			Select("source_type, source_id, chunk_index, content, 1 - (vector::vector <=> ?::vector) AS score", literal).
+			Order(gorm.Expr("vector::vector <=> ?::vector", literal)).
+			Limit(limit).
+			Scan(&matches).Error
+		return matches, err
+	}
+
+	// Without pgvector, score every candidate in process
+	rows, err := query.Select("source_type, source_id, chunk_index, content, vector").Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var matches []embeddingmodel.Match
+	for rows.Next() {
+		var candidate embeddingmodel.Embedding
+		if err := rows.Scan(&candidate.SourceType, &candidate.SourceID, &candidate.ChunkIndex, &candidate.Content, &candidate.Vector); err != nil {
+			return nil, err
+		}
+		matches = append(matches, embeddingmodel.Match{
+			SourceType: candidate.SourceType,
+			SourceID:   candidate.SourceID,
+			ChunkIndex: candidate.ChunkIndex,
+			Content:    candidate.Content,
+			Score:      embeddingmodel.Cosine(vector, candidate.Vector),
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
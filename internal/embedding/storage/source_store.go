@@ -0,0 +1,131 @@
+package embeddingstorage
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	embeddingmodel "github.com/khoaphungnguyen/go-openai/internal/embedding/model"
+)
+
+// sourceQuery describes where the content of a source type lives.
+type sourceQuery struct {
+	// rows selects id, user_id, parent_id, title, text and updated_at of every
+	// embeddable source, and optionally chunk_size and chunk_overlap; trashed
+	// content is left out.
+	rows string
+	// exists matches the source of an embedding row, including trashed content,
+	// so embeddings survive until the source is purged.
+	exists string
+}
+
+var sourceQueries = map[embeddingmodel.SourceType]sourceQuery{
+	embeddingmodel.SourceNote: {
+		rows: `SELECT n.id, n.user_id, n.thread_id AS parent_id, n.title,
+				concat_ws(E'\n\n', n.title, n.type, n.problem, n.approach, n.solution, n.code) AS text,
+				n.updated_at
+			FROM notes n
+			WHERE n.deleted_at IS NULL AND n.user_id IS NOT NULL`,
+		exists: `SELECT 1 FROM notes n WHERE n.id = embedding.source_id`,
+	},
+	embeddingmodel.SourceAttachment: {
+		rows: `SELECT a.id, a.user_id, a.thread_id AS parent_id, a.file_name AS title, a.extracted_text AS text, a.created_at AS updated_at
+			FROM message_attachment a
+			JOIN chat_thread t ON t.id = a.thread_id
+			WHERE t.deleted_at IS NULL AND length(trim(a.extracted_text)) > 0`,
+		exists: `SELECT 1 FROM message_attachment a WHERE a.id = embedding.source_id`,
+	},
+	embeddingmodel.SourceDocument: {
+		rows: `SELECT d.id, d.user_id, d.collection_id AS parent_id, d.file_name AS title, d.content AS text, d.updated_at,
+				c.chunk_size, c.chunk_overlap
+			FROM document d
+			JOIN document_collection c ON c.id = d.collection_id
+			WHERE d.status IN ('processing', 'ready')`,
+		exists: `SELECT 1 FROM document d WHERE d.id = embedding.source_id`,
+	},
+	embeddingmodel.SourceMessage: {
+		rows: `SELECT m.id, t.user_id, m.thread_id AS parent_id, t.title, m.content AS text, m.created_at AS updated_at
+			FROM chat_message m
+			JOIN chat_thread t ON t.id = m.thread_id
+			WHERE t.deleted_at IS NULL AND t.user_id IS NOT NULL AND length(trim(m.content)) > 0`,
+		exists: `SELECT 1 FROM chat_message m WHERE m.id = embedding.source_id`,
+	},
+}
+
+// sourceRow is a row of a source query.
+type sourceRow struct {
+	ID           uuid.UUID
+	UserID       uuid.UUID
+	ParentID     *uuid.UUID
+	Title        string
+	Text         string
+	ChunkSize    int
+	ChunkOverlap int
+}
+
+// querySource returns the query of a source type.
+func querySource(sourceType embeddingmodel.SourceType) (sourceQuery, error) {
+	query, ok := sourceQueries[sourceType]
+	if !ok {
+		return sourceQuery{}, fmt.Errorf("unknown source type %q", sourceType)
+	}
+	return query, nil
+}
+
+// LoadSources retrieves the content of the given sources. Missing and trashed sources are omitted.
+func (es *embeddingStore) LoadSources(sourceType embeddingmodel.SourceType, ids []uuid.UUID) ([]embeddingmodel.Source, error) {
+	query, err := querySource(sourceType)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	var rows []sourceRow
+	if err := es.db.Raw("SELECT * FROM ("+query.rows+") s WHERE s.id IN ?", ids).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	sources := make([]embeddingmodel.Source, 0, len(rows))
+	for _, row := range rows {
+		sources = append(sources, embeddingmodel.Source{
+			Type:     sourceType,
+			ID:       row.ID,
+			UserID:   row.UserID,
+			ParentID: row.ParentID,
+			Title:    row.Title,
+			Text:     row.Text,
+			Chunking: embeddingmodel.ChunkConfig{Size: row.ChunkSize, Overlap: row.ChunkOverlap},
+		})
+	}
+	return sources, nil
+}
+
+// GetStaleSourceIDs retrieves sources that have no embeddings under the model
+// or changed after they were computed, most recently changed first.
+func (es *embeddingStore) GetStaleSourceIDs(sourceType embeddingmodel.SourceType, model string, limit int) ([]uuid.UUID, error) {
+	query, err := querySource(sourceType)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []uuid.UUID
+	err = es.db.Raw(`SELECT s.id FROM (`+query.rows+`) s
+		WHERE NOT EXISTS (
+			SELECT 1 FROM embedding e
+			WHERE e.source_type = ? AND e.source_id = s.id AND e.model = ? AND e.updated_at >= s.updated_at
+		)
+		ORDER BY s.updated_at DESC
+		LIMIT ?`, sourceType, model, limit).Scan(&ids).Error
+	return ids, err
+}
+
+// DeleteOrphanedEmbeddings removes embeddings of a source type whose source was purged.
+func (es *embeddingStore) DeleteOrphanedEmbeddings(sourceType embeddingmodel.SourceType) (int64, error) {
+	query, err := querySource(sourceType)
+	if err != nil {
+		return 0, err
+	}
+	result := es.db.Where("source_type = ? AND NOT EXISTS ("+query.exists+")", sourceType).
+		Delete(&embeddingmodel.Embedding{})
+	return result.RowsAffected, result.Error
+}
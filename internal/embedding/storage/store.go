@@ -0,0 +1,40 @@
+// embeddingstorage provides data persistence logic for embeddings.
+package embeddingstorage
+
+import (
+	"github.com/google/uuid"
+	embeddingmodel "github.com/khoaphungnguyen/go-openai/internal/embedding/model"
+	"gorm.io/gorm"
+)
+
+// EmbeddingStore provides methods for embedding operations.
+type EmbeddingStore interface {
+	UpsertEmbedding(embedding *embeddingmodel.Embedding) error
+	GetEmbeddings(sourceType embeddingmodel.SourceType, sourceIDs []uuid.UUID, model string) ([]embeddingmodel.Embedding, error)
+	DeleteEmbeddings(sourceType embeddingmodel.SourceType, sourceIDs []uuid.UUID) error
+	DeleteChunksFrom(sourceType embeddingmodel.SourceType, sourceID uuid.UUID, model string, from int) error
+	CountChunks(sourceType embeddingmodel.SourceType, sourceID uuid.UUID, model string) (int64, error)
+	DeleteOrphanedEmbeddings(sourceType embeddingmodel.SourceType) (int64, error)
+
+	LoadSources(sourceType embeddingmodel.SourceType, ids []uuid.UUID) ([]embeddingmodel.Source, error)
+	GetStaleSourceIDs(sourceType embeddingmodel.SourceType, model string, limit int) ([]uuid.UUID, error)
+
+	Search(userID uuid.UUID, model string, vector embeddingmodel.Vector, types []embeddingmodel.SourceType, exclude *uuid.UUID, limit int) ([]embeddingmodel.Match, error)
+	UsesPgvector() bool
+}
+
+// embeddingStore encapsulates the logic for storing and searching embeddings.
+type embeddingStore struct {
+	db       *gorm.DB
+	pgvector bool
+}
+
+// NewEmbeddingStore creates a new instance of embeddingStore. Similarity search
+// runs in Postgres when the pgvector extension is installed and in process otherwise.
+func NewEmbeddingStore(db *gorm.DB) EmbeddingStore {
+	var pgvector bool
+	if err := db.Raw("SELECT EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'vector')").Scan(&pgvector).Error; err != nil {
+		pgvector = false
+	}
+	return &embeddingStore{db: db, pgvector: pgvector}
+}
@@ -0,0 +1,14 @@
+// embeddingtransport handles HTTP requests and responses for semantic search.
+package embeddingtransport
+
+import embeddingbusiness "github.com/khoaphungnguyen/go-openai/internal/embedding/business"
+
+// EmbeddingHandler handles semantic search HTTP requests.
+type EmbeddingHandler struct {
+	embeddingService *embeddingbusiness.EmbeddingService
+}
+
+// NewEmbeddingHandler creates a new EmbeddingHandler.
+func NewEmbeddingHandler(embeddingService *embeddingbusiness.EmbeddingService) *EmbeddingHandler {
+	return &EmbeddingHandler{embeddingService: embeddingService}
+}
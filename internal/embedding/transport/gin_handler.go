@@ -0,0 +1,94 @@
+package embeddingtransport
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/khoaphungnguyen/go-openai/internal/common"
+	embeddingbusiness "github.com/khoaphungnguyen/go-openai/internal/embedding/business"
+	embeddingmodel "github.com/khoaphungnguyen/go-openai/internal/embedding/model"
+	"github.com/khoaphungnguyen/go-openai/internal/provider"
+)
+
+// Search handles searching the user's notes and messages by meaning.
+// Query parameters: q, type (repeatable: note, message) and limit.
+func (eh *EmbeddingHandler) Search(c *gin.Context) {
+	userID, err := common.GetUserIDFromContext(c)
+	if err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	limit, ok := parseLimit(c)
+	if !ok {
+		return
+	}
+	var types []embeddingmodel.SourceType
+	for _, sourceType := range c.QueryArray("type") {
+		types = append(types, embeddingmodel.SourceType(sourceType))
+	}
+
+	results, err := eh.embeddingService.Search(c.Request.Context(), userID, c.Query("q"), types, limit)
+	if err != nil {
+		respondWithEmbeddingError(c, err, "Failed to search")
+		return
+	}
+
+	common.RespondWithJSON(c, http.StatusOK, results)
+}
+
+// SimilarNotes handles listing the notes closest in meaning to a note.
+func (eh *EmbeddingHandler) SimilarNotes(c *gin.Context) {
+	userID, err := common.GetUserIDFromContext(c)
+	if err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	noteID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, "Invalid note ID")
+		return
+	}
+	limit, ok := parseLimit(c)
+	if !ok {
+		return
+	}
+
+	results, err := eh.embeddingService.SimilarNotes(c.Request.Context(), userID, noteID, limit)
+	if err != nil {
+		respondWithEmbeddingError(c, err, "Failed to find similar notes")
+		return
+	}
+
+	common.RespondWithJSON(c, http.StatusOK, results)
+}
+
+// parseLimit reads the optional limit query parameter, responding on failure.
+func parseLimit(c *gin.Context) (int, bool) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(embeddingbusiness.DefaultSearchLimit)))
+	if err != nil || limit <= 0 || limit > embeddingbusiness.MaxSearchLimit {
+		common.RespondWithError(c, http.StatusBadRequest, "Invalid limit")
+		return 0, false
+	}
+	return limit, true
+}
+
+// respondWithEmbeddingError maps semantic search errors to HTTP responses.
+func respondWithEmbeddingError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, embeddingbusiness.ErrNoteNotFound):
+		common.RespondWithError(c, http.StatusNotFound, "Note not found")
+	case errors.Is(err, embeddingbusiness.ErrEmptyQuery),
+		errors.Is(err, embeddingbusiness.ErrInvalidSourceType),
+		errors.Is(err, embeddingbusiness.ErrNotIndexed):
+		common.RespondWithError(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, provider.ErrUnsupportedEmbeddingModel):
+		common.RespondWithError(c, http.StatusServiceUnavailable, "Embedding model is not supported")
+	default:
+		common.RespondWithError(c, http.StatusInternalServerError, fallback)
+	}
+}
@@ -0,0 +1,85 @@
+// extract classifies uploaded files and pulls plain text out of them.
+package extract
+
+import (
+	"errors"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// Kind classifies an uploaded file by how it can be passed to a model.
+type Kind string
+
+const (
+	KindImage       Kind = "image"
+	KindText        Kind = "text"
+	KindPDF         Kind = "pdf"
+	KindUnsupported Kind = "unsupported"
+)
+
+// ErrUnsupportedFile is returned when no text can be extracted from a file.
+var ErrUnsupportedFile = errors.New("unsupported file type")
+
+// imageTypes lists the image formats accepted by vision-capable models.
+var imageTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// textExtensions lists extensions of plain-text documents and source files.
+var textExtensions = map[string]bool{
+	".txt": true, ".md": true, ".markdown": true, ".csv": true, ".tsv": true, ".json": true,
+	".yaml": true, ".yml": true, ".toml": true, ".xml": true, ".html": true, ".css": true,
+	".go": true, ".py": true, ".js": true, ".jsx": true, ".ts": true, ".tsx": true,
+	".java": true, ".kt": true, ".swift": true, ".c": true, ".h": true, ".cpp": true,
+	".hpp": true, ".cc": true, ".cs": true, ".rs": true, ".rb": true, ".php": true,
+	".scala": true, ".sql": true, ".sh": true, ".r": true, ".lua": true, ".dart": true,
+}
+
+// DetectContentType determines the content type from the file contents,
+// falling back to the file extension for text formats sniffing cannot tell apart.
+func DetectContentType(fileName string, data []byte) string {
+	contentType := http.DetectContentType(data)
+	if strings.HasPrefix(contentType, "text/plain") || contentType == "application/octet-stream" {
+		if textExtensions[strings.ToLower(filepath.Ext(fileName))] && utf8.Valid(data) {
+			return "text/plain; charset=utf-8"
+		}
+	}
+	return contentType
+}
+
+// DetectKind classifies a file from its name and sniffed content type.
+func DetectKind(fileName, contentType string) Kind {
+	mediaType := strings.TrimSpace(strings.Split(contentType, ";")[0])
+	switch {
+	case imageTypes[mediaType]:
+		return KindImage
+	case mediaType == "application/pdf":
+		return KindPDF
+	case strings.HasPrefix(mediaType, "text/"), mediaType == "application/json":
+		return KindText
+	case textExtensions[strings.ToLower(filepath.Ext(fileName))]:
+		return KindText
+	default:
+		return KindUnsupported
+	}
+}
+
+// Text extracts plain text from a text or PDF file.
+func Text(kind Kind, data []byte) (string, error) {
+	switch kind {
+	case KindText:
+		if !utf8.Valid(data) {
+			return "", ErrUnsupportedFile
+		}
+		return string(data), nil
+	case KindPDF:
+		return PDFText(data)
+	default:
+		return "", ErrUnsupportedFile
+	}
+}
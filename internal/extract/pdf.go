@@ -0,0 +1,446 @@
+package extract
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ErrNoPDFText is returned for PDFs without an extractable text layer, such as scans.
+var ErrNoPDFText = errors.New("pdf has no extractable text")
+
+const (
+	// maxPDFStreamSize bounds the size of a single decompressed content stream.
+	maxPDFStreamSize = 16 << 20
+	// maxPDFArrayDepth bounds the nesting of arrays, which are parsed
+	// recursively; deeper arrays are skipped without being read.
+	maxPDFArrayDepth = 64
+)
+
+// PDFText extracts the text drawn by the content streams of a PDF. It handles
+// uncompressed and Flate-compressed streams with simple font encodings, which
+// covers most PDFs exported from editors; scanned documents yield ErrNoPDFText.
+func PDFText(data []byte) (string, error) {
+	if !bytes.HasPrefix(data, []byte("%PDF")) {
+		return "", ErrUnsupportedFile
+	}
+
+	var out strings.Builder
+	for _, stream := range pdfStreams(data) {
+		if !bytes.Contains(stream, []byte("BT")) {
+			continue
+		}
+		extractContentText(stream, &out)
+	}
+
+	text := normalizeWhitespace(out.String())
+	if text == "" {
+		return "", ErrNoPDFText
+	}
+	return text, nil
+}
+
+// pdfStreams returns the decoded bodies of every stream object in the file.
+func pdfStreams(data []byte) [][]byte {
+	var streams [][]byte
+	offset := 0
+	for {
+		start := bytes.Index(data[offset:], []byte("stream"))
+		if start < 0 {
+			break
+		}
+		start += offset
+		// Skip the "endstream" keyword itself
+		if start >= 3 && string(data[start-3:start]) == "end" {
+			offset = start + len("stream")
+			continue
+		}
+
+		bodyStart := start + len("stream")
+		if bodyStart < len(data) && data[bodyStart] == '\r' {
+			bodyStart++
+		}
+		if bodyStart < len(data) && data[bodyStart] == '\n' {
+			bodyStart++
+		}
+		end := bytes.Index(data[bodyStart:], []byte("endstream"))
+		if end < 0 {
+			break
+		}
+		body := data[bodyStart : bodyStart+end]
+		offset = bodyStart + end + len("endstream")
+
+		dict := streamDictionary(data[:start])
+		if bytes.Contains(dict, []byte("/Image")) {
+			continue
+		}
+		switch {
+		case bytes.Contains(dict, []byte("/FlateDecode")):
+			decoded, err := inflate(body)
+			if err != nil {
+				continue
+			}
+			streams = append(streams, decoded)
+		case bytes.Contains(dict, []byte("/Filter")):
+			// Other filters (DCT, LZW, ...) do not carry text we can read
+			continue
+		default:
+			streams = append(streams, body)
+		}
+	}
+	return streams
+}
+
+// streamDictionary returns the dictionary that precedes a stream keyword.
+func streamDictionary(before []byte) []byte {
+	objStart := bytes.LastIndex(before, []byte("obj"))
+	if objStart < 0 {
+		return nil
+	}
+	return before[objStart:]
+}
+
+// inflate decompresses a Flate stream, tolerating truncated trailing data.
+func inflate(body []byte) ([]byte, error) {
+	r, err := zlib.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	decoded, err := io.ReadAll(io.LimitReader(r, maxPDFStreamSize))
+	if err != nil && len(decoded) == 0 {
+		return nil, err
+	}
+	return decoded, nil
+}
+
+// extractContentText interprets the text operators of a content stream.
+func extractContentText(content []byte, out *strings.Builder) {
+	lexer := &pdfLexer{data: content}
+	var operands []pdfToken
+	for {
+		tok, ok := lexer.next()
+		if !ok {
+			return
+		}
+		if tok.kind != tokenOperator {
+			operands = append(operands, tok)
+			continue
+		}
+
+		switch tok.value {
+		case "Tj":
+			writeLastString(operands, out)
+		case "'", "\"":
+			out.WriteByte('\n')
+			writeLastString(operands, out)
+		case "TJ":
+			if n := len(operands); n > 0 && operands[n-1].kind == tokenArray {
+				for _, item := range operands[n-1].items {
+					switch item.kind {
+					case tokenString:
+						out.WriteString(item.value)
+					case tokenNumber:
+						// Large negative kerning usually separates words
+						if v, err := strconv.ParseFloat(item.value, 64); err == nil && v < -200 {
+							out.WriteByte(' ')
+						}
+					}
+				}
+			}
+		case "T*", "ET", "Tm":
+			out.WriteByte('\n')
+		case "Td", "TD":
+			if n := len(operands); n >= 2 && operands[n-1].value != "0" {
+				out.WriteByte('\n')
+			} else {
+				out.WriteByte(' ')
+			}
+		case "BI":
+			lexer.skipInlineImage()
+		}
+		operands = operands[:0]
+	}
+}
+
+// writeLastString appends the last string operand, if any.
+func writeLastString(operands []pdfToken, out *strings.Builder) {
+	if n := len(operands); n > 0 && operands[n-1].kind == tokenString {
+		out.WriteString(operands[n-1].value)
+	}
+}
+
+type tokenKind int
+
+const (
+	tokenOperator tokenKind = iota
+	tokenString
+	tokenNumber
+	tokenName
+	tokenArray
+	tokenOther
+)
+
+type pdfToken struct {
+	kind  tokenKind
+	value string
+	items []pdfToken
+}
+
+// pdfLexer tokenizes PDF content streams.
+type pdfLexer struct {
+	data []byte
+	pos  int
+}
+
+func (l *pdfLexer) next() (pdfToken, bool) {
+	return l.token(0)
+}
+
+// token reads the next token inside depth enclosing arrays.
+func (l *pdfLexer) token(depth int) (pdfToken, bool) {
+	l.skipSpace()
+	if l.pos >= len(l.data) {
+		return pdfToken{}, false
+	}
+	c := l.data[l.pos]
+	switch {
+	case c == '(':
+		return pdfToken{kind: tokenString, value: l.literalString()}, true
+	case c == '<' && l.pos+1 < len(l.data) && l.data[l.pos+1] == '<':
+		l.pos += 2
+		return pdfToken{kind: tokenOther, value: "<<"}, true
+	case c == '>' && l.pos+1 < len(l.data) && l.data[l.pos+1] == '>':
+		l.pos += 2
+		return pdfToken{kind: tokenOther, value: ">>"}, true
+	case c == '<':
+		return pdfToken{kind: tokenString, value: l.hexString()}, true
+	case c == '[':
+		return pdfToken{kind: tokenArray, items: l.array(depth + 1)}, true
+	case c == '/':
+		start := l.pos
+		l.pos++
+		for l.pos < len(l.data) && !isDelimiter(l.data[l.pos]) {
+			l.pos++
+		}
+		return pdfToken{kind: tokenName, value: string(l.data[start:l.pos])}, true
+	case c == ']' || c == ')' || c == '>' || c == '{' || c == '}':
+		l.pos++
+		return pdfToken{kind: tokenOther, value: string(c)}, true
+	}
+
+	start := l.pos
+	for l.pos < len(l.data) && !isDelimiter(l.data[l.pos]) {
+		l.pos++
+	}
+	if l.pos == start {
+		l.pos++
+	}
+	word := string(l.data[start:l.pos])
+	if _, err := strconv.ParseFloat(word, 64); err == nil {
+		return pdfToken{kind: tokenNumber, value: word}, true
+	}
+	return pdfToken{kind: tokenOperator, value: word}, true
+}
+
+// array reads the items of an array at the given nesting depth up to its
+// closing bracket. Arrays nested too deeply are skipped and read as empty.
+func (l *pdfLexer) array(depth int) []pdfToken {
+	l.pos++ // opening bracket
+	if depth > maxPDFArrayDepth {
+		l.skipArray()
+		return nil
+	}
+	var items []pdfToken
+	for {
+		l.skipSpace()
+		if l.pos >= len(l.data) {
+			return items
+		}
+		if l.data[l.pos] == ']' {
+			l.pos++
+			return items
+		}
+		item, ok := l.token(depth)
+		if !ok {
+			return items
+		}
+		items = append(items, item)
+	}
+}
+
+// skipArray moves past the rest of an array, including any arrays nested in
+// it, without recursing.
+func (l *pdfLexer) skipArray() {
+	open := 1
+	for l.pos < len(l.data) && open > 0 {
+		switch l.data[l.pos] {
+		case '(':
+			l.literalString()
+		case '<':
+			if l.pos+1 < len(l.data) && l.data[l.pos+1] == '<' {
+				l.pos += 2
+			} else {
+				l.hexString()
+			}
+		case '%':
+			l.skipSpace()
+		case '[':
+			open++
+			l.pos++
+		case ']':
+			open--
+			l.pos++
+		default:
+			l.pos++
+		}
+	}
+}
+
+func (l *pdfLexer) skipSpace() {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		if c == '%' {
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+			continue
+		}
+		if !isSpace(c) {
+			return
+		}
+		l.pos++
+	}
+}
+
+// literalString reads a parenthesised string, resolving escapes and nesting.
+func (l *pdfLexer) literalString() string {
+	var b strings.Builder
+	depth := 0
+	l.pos++ // opening parenthesis
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		switch c {
+		case '\\':
+			if l.pos >= len(l.data) {
+				return b.String()
+			}
+			e := l.data[l.pos]
+			l.pos++
+			switch e {
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case 'b', 'f':
+			case '\r', '\n':
+				// Line continuation
+			default:
+				if e >= '0' && e <= '7' {
+					v := int(e - '0')
+					for i := 0; i < 2 && l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; i++ {
+						v = v*8 + int(l.data[l.pos]-'0')
+						l.pos++
+					}
+					b.WriteByte(byte(v))
+				} else {
+					b.WriteByte(e)
+				}
+			}
+		case '(':
+			depth++
+			b.WriteByte(c)
+		case ')':
+			if depth == 0 {
+				return b.String()
+			}
+			depth--
+			b.WriteByte(c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// hexString reads a <...> string; two-byte glyph codes are decoded as UTF-16BE.
+func (l *pdfLexer) hexString() string {
+	l.pos++ // opening angle bracket
+	var digits []byte
+	for l.pos < len(l.data) && l.data[l.pos] != '>' {
+		if c := l.data[l.pos]; !isSpace(c) {
+			digits = append(digits, c)
+		}
+		l.pos++
+	}
+	l.pos++ // closing angle bracket
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	raw := make([]byte, 0, len(digits)/2)
+	for i := 0; i+1 < len(digits); i += 2 {
+		v, err := strconv.ParseUint(string(digits[i:i+2]), 16, 8)
+		if err != nil {
+			return ""
+		}
+		raw = append(raw, byte(v))
+	}
+	if len(raw) >= 2 && len(raw)%2 == 0 && raw[0] == 0 {
+		var b strings.Builder
+		for i := 0; i+1 < len(raw); i += 2 {
+			b.WriteRune(rune(uint16(raw[i])<<8 | uint16(raw[i+1])))
+		}
+		return b.String()
+	}
+	return string(raw)
+}
+
+// skipInlineImage skips the binary payload between ID and EI.
+func (l *pdfLexer) skipInlineImage() {
+	idx := bytes.Index(l.data[l.pos:], []byte("ID"))
+	if idx < 0 {
+		l.pos = len(l.data)
+		return
+	}
+	l.pos += idx + 2
+	end := bytes.Index(l.data[l.pos:], []byte("EI"))
+	if end < 0 {
+		l.pos = len(l.data)
+		return
+	}
+	l.pos += end + 2
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\r' || c == '\t' || c == '\f' || c == 0
+}
+
+func isDelimiter(c byte) bool {
+	return isSpace(c) || strings.IndexByte("()<>[]{}/%", c) >= 0
+}
+
+// normalizeWhitespace trims each line and collapses runs of blank lines.
+func normalizeWhitespace(text string) string {
+	lines := strings.Split(text, "\n")
+	var out []string
+	blank := false
+	for _, line := range lines {
+		line = strings.Join(strings.Fields(line), " ")
+		if line == "" {
+			if !blank && len(out) > 0 {
+				out = append(out, "")
+			}
+			blank = true
+			continue
+		}
+		blank = false
+		out = append(out, line)
+	}
+	return strings.TrimSpace(strings.Join(out, "\n"))
+}
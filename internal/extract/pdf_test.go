@@ -0,0 +1,111 @@
+package extract
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// pdfWithStream wraps a content stream in a minimal PDF object.
+func pdfWithStream(dict string, body []byte) []byte {
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n1 0 obj\n<< " + dict + " >>\nstream\n")
+	b.Write(body)
+	b.WriteString("\nendstream\nendobj\n%%EOF\n")
+	return b.Bytes()
+}
+
+func TestPDFText(t *testing.T) {
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	zw.Write([]byte("BT /F1 12 Tf (Compressed text) Tj ET"))
+	zw.Close()
+
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{
+			name: "simple text",
+			data: pdfWithStream("/Length 44", []byte("BT /F1 12 Tf 72 712 Td (Hello, world) Tj ET")),
+			want: "Hello, world",
+		},
+		{
+			name: "flate stream",
+			data: pdfWithStream("/Filter /FlateDecode", compressed.Bytes()),
+			want: "Compressed text",
+		},
+		{
+			name: "kerned array and escapes",
+			data: pdfWithStream("", []byte(`BT [(Two) -300 (words)] TJ T* (a \(nested\) paren) Tj ET`)),
+			want: "Two words\na (nested) paren",
+		},
+		{
+			name: "hex strings",
+			data: pdfWithStream("", []byte("BT <48656C6C6F> Tj T* <00480069> Tj ET")),
+			want: "Hello\nHi",
+		},
+		{
+			name: "inline image",
+			data: pdfWithStream("", []byte("BT (Before) Tj ET\nBI /W 2 /H 2 ID \x00(Tj)\x01 EI\nBT (After) Tj ET")),
+			want: "Before\nAfter",
+		},
+		{
+			name: "truncated file",
+			data: []byte("%PDF-1.4\n1 0 obj\n<< >>\nstream\nBT (Cut off) Tj ET\nendstream\n2 0 obj\n<< >>\nstream\nBT (Never ends"),
+			want: "Cut off",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PDFText(tt.data)
+			if err != nil {
+				t.Fatalf("PDFText: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("PDFText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPDFTextErrors(t *testing.T) {
+	if _, err := PDFText([]byte("not a pdf")); !errors.Is(err, ErrUnsupportedFile) {
+		t.Errorf("PDFText of plain text = %v, want ErrUnsupportedFile", err)
+	}
+	scan := pdfWithStream("/Subtype /Image /Filter /DCTDecode", []byte("BT (pixels) Tj ET"))
+	if _, err := PDFText(scan); !errors.Is(err, ErrNoPDFText) {
+		t.Errorf("PDFText of an image-only PDF = %v, want ErrNoPDFText", err)
+	}
+}
+
+func TestPDFTextDeepNesting(t *testing.T) {
+	const depth = 10 << 20
+	content := "BT " + strings.Repeat("[", depth) + "(hidden) ] ( ignored ) " + strings.Repeat("]", depth) +
+		" (Visible) Tj ET"
+	got, err := PDFText(pdfWithStream("", []byte(content)))
+	if err != nil {
+		t.Fatalf("PDFText: %v", err)
+	}
+	if got != "Visible" {
+		t.Errorf("PDFText() = %q, want %q", got, "Visible")
+	}
+
+	// Nesting up to the limit is still read
+	content = "BT " + strings.Repeat("[", maxPDFArrayDepth-1) + "[(Kept)] TJ" + strings.Repeat("]", maxPDFArrayDepth-1) + " ET"
+	lexer := &pdfLexer{data: []byte(content)}
+	lexer.next()
+	tok, _ := lexer.next()
+	for i := 0; i < maxPDFArrayDepth-1; i++ {
+		if tok.kind != tokenArray || len(tok.items) == 0 {
+			t.Fatalf("array at depth %d was not read", i+1)
+		}
+		tok = tok.items[0]
+	}
+	if tok.kind != tokenArray || len(tok.items) != 1 || tok.items[0].value != "Kept" {
+		t.Errorf("innermost array = %+v, want [(Kept)]", tok)
+	}
+}
@@ -0,0 +1,135 @@
+package feedbackbusiness
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	feedbackmodel "github.com/khoaphungnguyen/go-openai/internal/feedback/model"
+	feedbackstorage "github.com/khoaphungnguyen/go-openai/internal/feedback/storage"
+)
+
+const (
+	maxReasons       = 10
+	maxReasonLength  = 100
+	maxCommentLength = 2000
+)
+
+var (
+	// ErrFeedbackNotFound is returned when the user has not rated the message.
+	ErrFeedbackNotFound = feedbackstorage.ErrFeedbackNotFound
+	// ErrMessageNotFound is returned when the message does not exist or is not visible to the user.
+	ErrMessageNotFound = errors.New("message not found")
+	// ErrNotAssistantMessage is returned when rating a message that was not produced by the model.
+	ErrNotAssistantMessage = errors.New("only assistant messages can be rated")
+	// ErrInvalidFeedback is returned for malformed ratings, reasons or comments.
+	ErrInvalidFeedback = errors.New("invalid feedback")
+)
+
+// RateMessage records or replaces the user's rating of an assistant message.
+func (fs *FeedbackService) RateMessage(userID, messageID uuid.UUID, rating feedbackmodel.Rating, reasons []string, comment string) (*feedbackmodel.MessageFeedback, error) {
+	if !rating.IsValid() {
+		return nil, ErrInvalidFeedback
+	}
+	cleanReasons, err := normalizeReasons(reasons)
+	if err != nil {
+		return nil, err
+	}
+	comment = strings.TrimSpace(comment)
+	if len(comment) > maxCommentLength {
+		return nil, ErrInvalidFeedback
+	}
+
+	message, err := fs.messageService.GetMessageByID(userID, messageID)
+	if err != nil {
+		return nil, ErrMessageNotFound
+	}
+	if message.Role != "assistant" {
+		return nil, ErrNotAssistantMessage
+	}
+
+	feedback := &feedbackmodel.MessageFeedback{
+		MessageID: messageID,
+		UserID:    userID,
+		ThreadID:  message.ThreadID,
+		Rating:    rating,
+		Reasons:   cleanReasons,
+		Comment:   comment,
+	}
+
+	// Attach the model and prompt version that produced the message, when recorded
+	if transaction, err := fs.openAIService.GetTransactionByMessageID(messageID); err == nil {
+		feedback.TransactionID = &transaction.ID
+		feedback.Model = transaction.Model
+		feedback.PromptVersion = transaction.PromptVersion
+	}
+
+	if err := fs.feedbackStore.UpsertFeedback(feedback); err != nil {
+		return nil, err
+	}
+	return fs.feedbackStore.GetFeedback(messageID, userID)
+}
+
+// GetFeedback retrieves the user's rating of a message.
+func (fs *FeedbackService) GetFeedback(userID, messageID uuid.UUID) (*feedbackmodel.MessageFeedback, error) {
+	return fs.feedbackStore.GetFeedback(messageID, userID)
+}
+
+// DeleteFeedback removes the user's rating of a message.
+func (fs *FeedbackService) DeleteFeedback(userID, messageID uuid.UUID) error {
+	return fs.feedbackStore.DeleteFeedback(messageID, userID)
+}
+
+// GetStats aggregates ratings by day, model and prompt version for admins.
+func (fs *FeedbackService) GetStats(filter feedbackmodel.FeedbackFilter) ([]feedbackmodel.FeedbackStat, error) {
+	if filter.Rating != "" && !filter.Rating.IsValid() {
+		return nil, ErrInvalidFeedback
+	}
+	return fs.feedbackStore.GetStats(filter)
+}
+
+// ExportRatedPairs calls fn for every rated prompt/response pair in fine-tuning format.
+// Pairs without a preceding user prompt are skipped.
+func (fs *FeedbackService) ExportRatedPairs(filter feedbackmodel.FeedbackFilter, fn func(example *feedbackmodel.RatedExample) error) error {
+	if filter.Rating != "" && !filter.Rating.IsValid() {
+		return ErrInvalidFeedback
+	}
+	return fs.feedbackStore.EachRatedPair(filter, func(pair *feedbackmodel.RatedPair) error {
+		if pair.Prompt == "" {
+			return nil
+		}
+		return fn(&feedbackmodel.RatedExample{
+			Messages: []feedbackmodel.ExampleMessage{
+				{Role: "user", Content: pair.Prompt},
+				{Role: "assistant", Content: pair.Response},
+			},
+			Rating:        pair.Rating,
+			Reasons:       pair.Reasons,
+			Comment:       pair.Comment,
+			Model:         pair.Model,
+			PromptVersion: pair.PromptVersion,
+			MessageID:     pair.MessageID,
+		})
+	})
+}
+
+// normalizeReasons trims, de-duplicates and validates the reason tags.
+func normalizeReasons(reasons []string) (feedbackmodel.StringList, error) {
+	if len(reasons) > maxReasons {
+		return nil, ErrInvalidFeedback
+	}
+	seen := make(map[string]bool, len(reasons))
+	clean := make(feedbackmodel.StringList, 0, len(reasons))
+	for _, reason := range reasons {
+		reason = strings.TrimSpace(reason)
+		if reason == "" || seen[reason] {
+			continue
+		}
+		if len(reason) > maxReasonLength {
+			return nil, ErrInvalidFeedback
+		}
+		seen[reason] = true
+		clean = append(clean, reason)
+	}
+	return clean, nil
+}
@@ -0,0 +1,24 @@
+// feedbackbusiness contains the business logic for rating assistant messages.
+package feedbackbusiness
+
+import (
+	feedbackstorage "github.com/khoaphungnguyen/go-openai/internal/feedback/storage"
+	messagebusiness "github.com/khoaphungnguyen/go-openai/internal/message/business"
+	openaibusiness "github.com/khoaphungnguyen/go-openai/internal/openai/business"
+)
+
+// FeedbackService provides methods for message feedback operations.
+type FeedbackService struct {
+	feedbackStore  feedbackstorage.FeedbackStore
+	messageService *messagebusiness.MessageService
+	openAIService  *openaibusiness.OpenAIService
+}
+
+// NewFeedbackService creates a new FeedbackService.
+func NewFeedbackService(feedbackStore feedbackstorage.FeedbackStore, messageService *messagebusiness.MessageService, openAIService *openaibusiness.OpenAIService) *FeedbackService {
+	return &FeedbackService{
+		feedbackStore:  feedbackStore,
+		messageService: messageService,
+		openAIService:  openAIService,
+	}
+}
@@ -0,0 +1,151 @@
+// feedbackmodel defines the data structures used for rating assistant messages.
+package feedbackmodel
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Rating is a thumbs up or thumbs down verdict on an assistant message.
+type Rating string
+
+const (
+	RatingUp   Rating = "up"
+	RatingDown Rating = "down"
+)
+
+// IsValid reports whether the rating is one of the known values.
+func (r Rating) IsValid() bool {
+	return r == RatingUp || r == RatingDown
+}
+
+// StringList is a list of strings persisted as a JSONB array.
+type StringList []string
+
+// Value implements driver.Valuer.
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal([]string(l))
+	return string(b), err
+}
+
+// Scan implements sql.Scanner.
+func (l *StringList) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*l = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, l)
+	case string:
+		return json.Unmarshal([]byte(v), l)
+	default:
+		return errors.New("unsupported type for StringList")
+	}
+}
+
+// MessageFeedback is a user's rating of an assistant message, linked to the
+// transaction that produced it so ratings can be compared per model and prompt.
+type MessageFeedback struct {
+	ID            uuid.UUID  `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	MessageID     uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_message_feedback_message_user"`
+	UserID        uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_message_feedback_message_user"`
+	ThreadID      uuid.UUID  `gorm:"type:uuid;index"`
+	TransactionID *uuid.UUID `gorm:"type:uuid"`
+	Model         string     `gorm:"type:varchar(255)"`
+	PromptVersion string     `gorm:"type:varchar(50)"`
+	Rating        Rating     `gorm:"type:varchar(10);not null"`
+	Reasons       StringList `gorm:"type:jsonb"`
+	Comment       string     `gorm:"type:text"`
+	CreatedAt     time.Time  `gorm:"default:now()"`
+	UpdatedAt     time.Time  `gorm:"autoUpdateTime"`
+}
+
+// TableName overrides the table name used by MessageFeedback.
+func (MessageFeedback) TableName() string {
+	return "message_feedback"
+}
+
+// FeedbackResponse is the client-facing representation of a rating.
+type FeedbackResponse struct {
+	ID        uuid.UUID `json:"id"`
+	MessageID uuid.UUID `json:"messageId"`
+	Rating    Rating    `json:"rating"`
+	Reasons   []string  `json:"reasons"`
+	Comment   string    `json:"comment"`
+	Model     string    `json:"model"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// ToFeedbackResponse converts a MessageFeedback to its client-facing representation.
+func (f *MessageFeedback) ToFeedbackResponse() FeedbackResponse {
+	reasons := []string(f.Reasons)
+	if reasons == nil {
+		reasons = []string{}
+	}
+	return FeedbackResponse{
+		ID:        f.ID,
+		MessageID: f.MessageID,
+		Rating:    f.Rating,
+		Reasons:   reasons,
+		Comment:   f.Comment,
+		Model:     f.Model,
+		CreatedAt: f.CreatedAt,
+		UpdatedAt: f.UpdatedAt,
+	}
+}
+
+// FeedbackStat aggregates ratings for one model on one day.
+type FeedbackStat struct {
+	Day           time.Time `json:"day"`
+	Model         string    `json:"model"`
+	PromptVersion string    `json:"promptVersion"`
+	Up            int64     `json:"up"`
+	Down          int64     `json:"down"`
+	Total         int64     `json:"total"`
+}
+
+// FeedbackFilter restricts admin queries over feedback.
+type FeedbackFilter struct {
+	From   *time.Time
+	To     *time.Time
+	Model  string
+	Rating Rating
+}
+
+// RatedPair is a prompt/response pair together with its rating, used to build fine-tuning datasets.
+type RatedPair struct {
+	MessageID     uuid.UUID
+	Prompt        string
+	Response      string
+	Model         string
+	PromptVersion string
+	Rating        Rating
+	Reasons       StringList
+	Comment       string
+	CreatedAt     time.Time
+}
+
+// ExampleMessage is a chat message in the fine-tuning JSONL format.
+type ExampleMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// RatedExample is one line of the JSONL export.
+type RatedExample struct {
+	Messages      []ExampleMessage `json:"messages"`
+	Rating        Rating           `json:"rating"`
+	Reasons       []string         `json:"reasons,omitempty"`
+	Comment       string           `json:"comment,omitempty"`
+	Model         string           `json:"model"`
+	PromptVersion string           `json:"promptVersion"`
+	MessageID     uuid.UUID        `json:"messageId"`
+}
@@ -0,0 +1,107 @@
+package feedbackstorage
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	feedbackmodel "github.com/khoaphungnguyen/go-openai/internal/feedback/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrFeedbackNotFound is the error returned when no feedback exists for a message.
+var ErrFeedbackNotFound = errors.New("feedback not found")
+
+// UpsertFeedback creates the user's feedback on a message or replaces the existing one.
+func (fs *feedbackStore) UpsertFeedback(feedback *feedbackmodel.MessageFeedback) error {
+	return fs.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "message_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rating", "reasons", "comment", "transaction_id", "model", "prompt_version", "updated_at"}),
+	}).Create(feedback).Error
+}
+
+// GetFeedback retrieves the user's feedback on a message.
+func (fs *feedbackStore) GetFeedback(messageID, userID uuid.UUID) (*feedbackmodel.MessageFeedback, error) {
+	var feedback feedbackmodel.MessageFeedback
+	err := fs.db.Where("message_id = ? AND user_id = ?", messageID, userID).First(&feedback).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrFeedbackNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve feedback: %w", err)
+	}
+	return &feedback, nil
+}
+
+// DeleteFeedback removes the user's feedback on a message.
+func (fs *feedbackStore) DeleteFeedback(messageID, userID uuid.UUID) error {
+	result := fs.db.Where("message_id = ? AND user_id = ?", messageID, userID).Delete(&feedbackmodel.MessageFeedback{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrFeedbackNotFound
+	}
+	return nil
+}
+
+// GetStats aggregates ratings per day, model and prompt version.
+func (fs *feedbackStore) GetStats(filter feedbackmodel.FeedbackFilter) ([]feedbackmodel.FeedbackStat, error) {
+	var stats []feedbackmodel.FeedbackStat
+	query := applyFilter(fs.db.Table("message_feedback AS f"), filter).
+		Select(`date_trunc('day', f.created_at) AS day, f.model, f.prompt_version,
+			COUNT(*) FILTER (WHERE f.rating = 'up') AS up,
+			COUNT(*) FILTER (WHERE f.rating = 'down') AS down,
+			COUNT(*) AS total`).
+		Group("day, f.model, f.prompt_version").
+		Order("day DESC, f.model")
+	err := query.Scan(&stats).Error
+	return stats, err
+}
+
+// EachRatedPair streams every rated assistant message together with the user
+// message that preceded it in the thread.
+func (fs *feedbackStore) EachRatedPair(filter feedbackmodel.FeedbackFilter, fn func(pair *feedbackmodel.RatedPair) error) error {
+	query := applyFilter(fs.db.Table("message_feedback AS f"), filter).
+		Select(`f.message_id, m.content AS response, f.model, f.prompt_version, f.rating, f.reasons, COALESCE(f.comment, '') AS comment, f.created_at,
+			COALESCE((SELECT p.content FROM chat_message AS p
+			  WHERE p.thread_id = m.thread_id AND p.role = 'user' AND p.created_at <= m.created_at
+			  ORDER BY p.created_at DESC LIMIT 1), '') AS prompt`).
+		Joins("JOIN chat_message AS m ON m.id = f.message_id").
+		Order("f.created_at ASC")
+
+	rows, err := query.Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var pair feedbackmodel.RatedPair
+		if err := fs.db.ScanRows(rows, &pair); err != nil {
+			return err
+		}
+		if err := fn(&pair); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// applyFilter adds the optional filter conditions to a feedback query.
+func applyFilter(query *gorm.DB, filter feedbackmodel.FeedbackFilter) *gorm.DB {
+	if filter.From != nil {
+		query = query.Where("f.created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("f.created_at < ?", *filter.To)
+	}
+	if filter.Model != "" {
+		query = query.Where("f.model = ?", filter.Model)
+	}
+	if filter.Rating != "" {
+		query = query.Where("f.rating = ?", filter.Rating)
+	}
+	return query
+}
@@ -0,0 +1,27 @@
+// feedbackstorage provides data persistence logic for message feedback.
+package feedbackstorage
+
+import (
+	"github.com/google/uuid"
+	feedbackmodel "github.com/khoaphungnguyen/go-openai/internal/feedback/model"
+	"gorm.io/gorm"
+)
+
+// FeedbackStore provides methods for message feedback operations.
+type FeedbackStore interface {
+	UpsertFeedback(feedback *feedbackmodel.MessageFeedback) error
+	GetFeedback(messageID, userID uuid.UUID) (*feedbackmodel.MessageFeedback, error)
+	DeleteFeedback(messageID, userID uuid.UUID) error
+	GetStats(filter feedbackmodel.FeedbackFilter) ([]feedbackmodel.FeedbackStat, error)
+	EachRatedPair(filter feedbackmodel.FeedbackFilter, fn func(pair *feedbackmodel.RatedPair) error) error
+}
+
+// feedbackStore encapsulates the logic for storing and retrieving feedback.
+type feedbackStore struct {
+	db *gorm.DB
+}
+
+// NewFeedbackStore creates a new instance of feedbackStore.
+func NewFeedbackStore(db *gorm.DB) FeedbackStore {
+	return &feedbackStore{db: db}
+}
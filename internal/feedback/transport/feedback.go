@@ -0,0 +1,18 @@
+// feedbacktransport handles HTTP requests and responses for message feedback.
+package feedbacktransport
+
+import (
+	auditbusiness "github.com/khoaphungnguyen/go-openai/internal/audit/business"
+	feedbackbusiness "github.com/khoaphungnguyen/go-openai/internal/feedback/business"
+)
+
+// FeedbackHandler handles feedback-related HTTP requests.
+type FeedbackHandler struct {
+	feedbackService *feedbackbusiness.FeedbackService
+	auditService    *auditbusiness.AuditService
+}
+
+// NewFeedbackHandler creates a new FeedbackHandler.
+func NewFeedbackHandler(feedbackService *feedbackbusiness.FeedbackService, auditService *auditbusiness.AuditService) *FeedbackHandler {
+	return &FeedbackHandler{feedbackService: feedbackService, auditService: auditService}
+}
@@ -0,0 +1,190 @@
+package feedbacktransport
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	auditmodel "github.com/khoaphungnguyen/go-openai/internal/audit/model"
+	audittransport "github.com/khoaphungnguyen/go-openai/internal/audit/transport"
+	"github.com/khoaphungnguyen/go-openai/internal/common"
+	feedbackbusiness "github.com/khoaphungnguyen/go-openai/internal/feedback/business"
+	feedbackmodel "github.com/khoaphungnguyen/go-openai/internal/feedback/model"
+)
+
+type FeedbackPayload struct {
+	Rating  feedbackmodel.Rating `json:"rating" binding:"required"`
+	Reasons []string             `json:"reasons"`
+	Comment string               `json:"comment"`
+}
+
+// RateMessage handles rating an assistant message with thumbs up or down.
+func (fh *FeedbackHandler) RateMessage(c *gin.Context) {
+	userID, messageID, ok := parseMessageRequest(c)
+	if !ok {
+		return
+	}
+
+	var payload FeedbackPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	feedback, err := fh.feedbackService.RateMessage(userID, messageID, payload.Rating, payload.Reasons, payload.Comment)
+	if err != nil {
+		respondWithFeedbackError(c, err, "Failed to save feedback")
+		return
+	}
+
+	common.RespondWithJSON(c, http.StatusOK, feedback.ToFeedbackResponse())
+}
+
+// GetFeedback handles retrieving the caller's rating of a message.
+func (fh *FeedbackHandler) GetFeedback(c *gin.Context) {
+	userID, messageID, ok := parseMessageRequest(c)
+	if !ok {
+		return
+	}
+
+	feedback, err := fh.feedbackService.GetFeedback(userID, messageID)
+	if err != nil {
+		respondWithFeedbackError(c, err, "Failed to retrieve feedback")
+		return
+	}
+
+	common.RespondWithJSON(c, http.StatusOK, feedback.ToFeedbackResponse())
+}
+
+// DeleteFeedback handles removing the caller's rating of a message.
+func (fh *FeedbackHandler) DeleteFeedback(c *gin.Context) {
+	userID, messageID, ok := parseMessageRequest(c)
+	if !ok {
+		return
+	}
+
+	if err := fh.feedbackService.DeleteFeedback(userID, messageID); err != nil {
+		respondWithFeedbackError(c, err, "Failed to delete feedback")
+		return
+	}
+
+	common.RespondWithJSON(c, http.StatusOK, gin.H{"message": "Feedback deleted successfully"})
+}
+
+// GetFeedbackStats handles the admin aggregate of ratings by model and date.
+func (fh *FeedbackHandler) GetFeedbackStats(c *gin.Context) {
+	filter, err := parseFeedbackFilter(c)
+	if err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	stats, err := fh.feedbackService.GetStats(filter)
+	if err != nil {
+		respondWithFeedbackError(c, err, "Failed to retrieve feedback statistics")
+		return
+	}
+
+	common.RespondWithJSON(c, http.StatusOK, stats)
+}
+
+// ExportFeedback streams rated prompt/response pairs as JSONL for fine-tuning datasets.
+func (fh *FeedbackHandler) ExportFeedback(c *gin.Context) {
+	filter, err := parseFeedbackFilter(c)
+	if err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	entry := audittransport.NewEntry(c, auditmodel.ActionFeedbackExported, "", uuid.Nil)
+	if filter.Model != "" {
+		entry.Set("model", filter.Model)
+	}
+	if filter.Rating != "" {
+		entry.Set("rating", filter.Rating)
+	}
+	if filter.From != nil {
+		entry.Set("from", filter.From)
+	}
+	if filter.To != nil {
+		entry.Set("to", filter.To)
+	}
+	fh.auditService.Record(c.Request.Context(), entry)
+
+	c.Header("Content-Type", "application/x-ndjson")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="feedback-%s.jsonl"`, time.Now().Format("20060102")))
+	c.Status(http.StatusOK)
+
+	encoder := json.NewEncoder(c.Writer)
+	err = fh.feedbackService.ExportRatedPairs(filter, func(example *feedbackmodel.RatedExample) error {
+		return encoder.Encode(example)
+	})
+	if err != nil {
+		// Headers are already sent, so the partial export can only be logged
+		log.Printf("Error exporting feedback: %v", err)
+	}
+}
+
+// parseMessageRequest extracts the caller and message IDs, responding on failure.
+func parseMessageRequest(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userID, err := common.GetUserIDFromContext(c)
+	if err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, err.Error())
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	messageID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, "Invalid message ID")
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, messageID, true
+}
+
+// parseFeedbackFilter reads the from, to, model and rating query parameters.
+// Dates accept either YYYY-MM-DD or RFC 3339; "to" is exclusive.
+func parseFeedbackFilter(c *gin.Context) (feedbackmodel.FeedbackFilter, error) {
+	filter := feedbackmodel.FeedbackFilter{
+		Model:  c.Query("model"),
+		Rating: feedbackmodel.Rating(c.Query("rating")),
+	}
+	for param, target := range map[string]**time.Time{"from": &filter.From, "to": &filter.To} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		t, err := parseDate(value)
+		if err != nil {
+			return filter, fmt.Errorf("invalid %s date", param)
+		}
+		*target = &t
+	}
+	return filter, nil
+}
+
+// parseDate parses a date in YYYY-MM-DD or RFC 3339 format.
+func parseDate(value string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// respondWithFeedbackError maps feedback errors to HTTP responses.
+func respondWithFeedbackError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, feedbackbusiness.ErrMessageNotFound):
+		common.RespondWithError(c, http.StatusNotFound, "Message not found")
+	case errors.Is(err, feedbackbusiness.ErrFeedbackNotFound):
+		common.RespondWithError(c, http.StatusNotFound, "Feedback not found")
+	case errors.Is(err, feedbackbusiness.ErrNotAssistantMessage), errors.Is(err, feedbackbusiness.ErrInvalidFeedback):
+		common.RespondWithError(c, http.StatusBadRequest, err.Error())
+	default:
+		common.RespondWithError(c, http.StatusInternalServerError, fallback)
+	}
+}
@@ -0,0 +1,54 @@
+package mailer
+
+import (
+	"context"
+	"sync"
+)
+
+// CapturingMailer keeps sent messages in memory so tests can inspect them.
+// Setting Err makes every send fail with it.
+type CapturingMailer struct {
+	mu   sync.Mutex
+	sent []Message
+	Err  error
+}
+
+// NewCapturingMailer creates an empty CapturingMailer.
+func NewCapturingMailer() *CapturingMailer {
+	return &CapturingMailer{}
+}
+
+// Send records the message, or fails with Err when it is set.
+func (m *CapturingMailer) Send(ctx context.Context, msg Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return m.Err
+	}
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+// Sent returns a copy of the messages sent so far, oldest first.
+func (m *CapturingMailer) Sent() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Message(nil), m.sent...)
+}
+
+// Last returns the most recently sent message.
+func (m *CapturingMailer) Last() (Message, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.sent) == 0 {
+		return Message{}, false
+	}
+	return m.sent[len(m.sent)-1], true
+}
+
+// Reset forgets the messages sent so far.
+func (m *CapturingMailer) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = nil
+}
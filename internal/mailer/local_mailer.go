@@ -0,0 +1,61 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// localFrom is the sender of messages that never leave the machine.
+const localFrom = "no-reply@localhost"
+
+// fileMailer writes every message to a .eml file instead of sending it, for
+// local development.
+type fileMailer struct {
+	dir string
+}
+
+// NewFileMailer creates a Mailer that writes messages as .eml files to dir.
+func NewFileMailer(dir string) (Mailer, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create mail directory: %w", err)
+	}
+	return &fileMailer{dir: dir}, nil
+}
+
+// Send writes the message to a new file named after the time it was sent.
+func (m *fileMailer) Send(ctx context.Context, msg Message) error {
+	if _, err := address(msg.To); err != nil {
+		return err
+	}
+	now := time.Now()
+	name := fmt.Sprintf("%s-%s.eml", now.UTC().Format("20060102T150405"), uuid.NewString()[:8])
+	path := filepath.Join(m.dir, name)
+	if err := os.WriteFile(path, format(localFrom, msg, now), 0o640); err != nil {
+		return err
+	}
+	log.Printf("Mail to %s written to %s", msg.To, path)
+	return nil
+}
+
+// logMailer prints every message to the application log instead of sending it.
+type logMailer struct{}
+
+// NewLogMailer creates a Mailer that logs messages, for local development.
+func NewLogMailer() Mailer {
+	return logMailer{}
+}
+
+// Send logs the message.
+func (logMailer) Send(ctx context.Context, msg Message) error {
+	if _, err := address(msg.To); err != nil {
+		return err
+	}
+	log.Printf("Mail to %s\nSubject: %s\n\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
@@ -3,9 +3,20 @@ package messagebusiness
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	messagemodel "github.com/khoaphungnguyen/go-openai/internal/message/model"
+	workspacemodel "github.com/khoaphungnguyen/go-openai/internal/workspace/model"
+)
+
+var (
+	// ErrThreadAccessDenied is returned when a thread does not exist or the user lacks permission.
+	ErrThreadAccessDenied = errors.New("thread does not exist or user lacks permission")
+	// ErrWorkspaceAccessDenied is returned when the user cannot write to the target workspace.
+	ErrWorkspaceAccessDenied = errors.New("user lacks permission in workspace")
+	// ErrThreadNotInTrash is returned when a thread is not in the user's trash.
+	ErrThreadNotInTrash = errors.New("thread not found in trash")
 )
 
 // CreateThread handles the creation of a new chat thread.
@@ -13,6 +24,9 @@ func (ms *MessageService) CreateThread(thread *messagemodel.ChatThread) error {
 	if thread == nil {
 		return errors.New("thread cannot be nil")
 	}
+	if thread.WorkspaceID != nil && !ms.canWriteWorkspace(*thread.WorkspaceID, thread.UserID) {
+		return ErrWorkspaceAccessDenied
+	}
 	return ms.messageStore.CreateThread(thread)
 }
 
@@ -37,14 +51,16 @@ func (ms *MessageService) CreateMessage(userID uuid.UUID, message *messagemodel.
 	if message == nil {
 		return errors.New("message cannot be nil")
 	}
-	exists, err := ms.messageStore.CheckThreadExistsAndBelongsToUser(message.ThreadID, userID)
-	if err != nil {
-		return fmt.Errorf("failed to verify thread ownership: %w", err)
+	if !ms.CanWriteThread(message.ThreadID, userID) {
+		return ErrThreadAccessDenied
+	}
+	if err := ms.messageStore.CreateMessage(message); err != nil {
+		return err
 	}
-	if !exists {
-		return errors.New("thread does not exist or user lacks permission")
+	if ms.indexer != nil {
+		ms.indexer.IndexMessage(message.ID)
 	}
-	return ms.messageStore.CreateMessage(message)
+	return nil
 }
 
 // GetAllThreads retrieves all chat threads for a specific user.
@@ -77,7 +93,7 @@ func (ms *MessageService) GetMessagesByThreadID(threadID uuid.UUID, limit, offse
 	return responseMessages, nil
 }
 
-// DeleteThread deletes a chat thread.
+// DeleteThread moves a chat thread to the trash.
 func (ms *MessageService) DeleteThread(threadID uuid.UUID, userID uuid.UUID) error {
 	if threadID == uuid.Nil {
 		return errors.New("invalid thread ID")
@@ -99,3 +115,177 @@ func (ms *MessageService) CheckThreadExists(threadID uuid.UUID) (bool, error) {
 func (ms *MessageService) IsUserThreadOwner(threadID, userID uuid.UUID) bool {
 	return ms.messageStore.IsUserThreadOwner(threadID, userID)
 }
+
+// CanReadThread reports whether the user owns the thread or is a member of its workspace.
+func (ms *MessageService) CanReadThread(threadID, userID uuid.UUID) bool {
+	role, ok := ms.threadRole(threadID, userID)
+	return ok && role.CanRead()
+}
+
+// CanWriteThread reports whether the user owns the thread or is an editor of its workspace.
+func (ms *MessageService) CanWriteThread(threadID, userID uuid.UUID) bool {
+	role, ok := ms.threadRole(threadID, userID)
+	return ok && role.CanWrite()
+}
+
+// GetThreadsByWorkspaceID retrieves the threads of a workspace the user is a member of.
+func (ms *MessageService) GetThreadsByWorkspaceID(userID, workspaceID uuid.UUID) ([]messagemodel.ChatThread, error) {
+	if ms.workspaceAccess == nil {
+		return nil, ErrWorkspaceAccessDenied
+	}
+	role, err := ms.workspaceAccess.MemberRole(workspaceID, userID)
+	if err != nil || !role.CanRead() {
+		return nil, ErrWorkspaceAccessDenied
+	}
+	return ms.messageStore.GetThreadsByWorkspaceID(workspaceID)
+}
+
+// MoveThreadToWorkspace moves a thread owned by the user into a workspace where the
+// user can write, or back to the user's personal threads when workspaceID is nil.
+func (ms *MessageService) MoveThreadToWorkspace(userID, threadID uuid.UUID, workspaceID *uuid.UUID) error {
+	if !ms.messageStore.IsUserThreadOwner(threadID, userID) {
+		return ErrThreadAccessDenied
+	}
+	if workspaceID != nil && !ms.canWriteWorkspace(*workspaceID, userID) {
+		return ErrWorkspaceAccessDenied
+	}
+	return ms.messageStore.UpdateThreadWorkspace(threadID, workspaceID)
+}
+
+// SetThreadRAG turns grounding answers in the user's notes and documents on or
+// off for a thread the user can post to.
+func (ms *MessageService) SetThreadRAG(userID, threadID uuid.UUID, enabled bool) error {
+	if !ms.CanWriteThread(threadID, userID) {
+		return ErrThreadAccessDenied
+	}
+	return ms.messageStore.UpdateThreadRAG(threadID, enabled)
+}
+
+// threadRole resolves the effective role of a user on a thread. Thread owners
+// are treated as workspace owners; other users inherit their workspace role.
+func (ms *MessageService) threadRole(threadID, userID uuid.UUID) (workspacemodel.Role, bool) {
+	if threadID == uuid.Nil || userID == uuid.Nil {
+		return "", false
+	}
+	thread, err := ms.messageStore.GetThreadByID(threadID)
+	if err != nil || thread == nil {
+		return "", false
+	}
+	if thread.UserID == userID {
+		return workspacemodel.OwnerRole, true
+	}
+	if thread.WorkspaceID == nil || ms.workspaceAccess == nil {
+		return "", false
+	}
+	role, err := ms.workspaceAccess.MemberRole(*thread.WorkspaceID, userID)
+	if err != nil {
+		return "", false
+	}
+	return role, true
+}
+
+// canWriteWorkspace reports whether the user may add content to the workspace.
+func (ms *MessageService) canWriteWorkspace(workspaceID, userID uuid.UUID) bool {
+	if ms.workspaceAccess == nil {
+		return false
+	}
+	role, err := ms.workspaceAccess.MemberRole(workspaceID, userID)
+	return err == nil && role.CanWrite()
+}
+
+// GetMessagesByThreadIDUntil retrieves the messages of a thread created up to the given time.
+func (ms *MessageService) GetMessagesByThreadIDUntil(threadID uuid.UUID, until time.Time) ([]messagemodel.ChatMessageResponse, error) {
+	if threadID == uuid.Nil {
+		return nil, errors.New("invalid thread ID")
+	}
+	messages, err := ms.messageStore.GetMessagesByThreadIDUntil(threadID, until)
+	if err != nil {
+		return nil, err
+	}
+	responseMessages := make([]messagemodel.ChatMessageResponse, 0, len(messages))
+	for _, msg := range messages {
+		responseMessages = append(responseMessages, messagemodel.ChatMessageResponse{
+			ID:        msg.ID,
+			Content:   msg.Content,
+			Role:      msg.Role,
+			CreatedAt: msg.CreatedAt,
+		})
+	}
+	return responseMessages, nil
+}
+
+// ForkThread copies the messages of a thread created up to the given time into a new thread owned by userID.
+func (ms *MessageService) ForkThread(userID uuid.UUID, source *messagemodel.ChatThread, until time.Time) (*messagemodel.ChatThread, error) {
+	if userID == uuid.Nil {
+		return nil, errors.New("invalid user ID")
+	}
+	if source == nil {
+		return nil, errors.New("thread cannot be nil")
+	}
+	messages, err := ms.messageStore.GetMessagesByThreadIDUntil(source.ID, until)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load messages: %w", err)
+	}
+	for i := range messages {
+		messages[i].UserID = userID
+	}
+	fork := &messagemodel.ChatThread{
+		UserID: userID,
+		Title:  source.Title,
+		Model:  source.Model,
+	}
+	if err := ms.messageStore.CreateThreadWithMessages(fork, messages); err != nil {
+		return nil, fmt.Errorf("failed to fork thread: %w", err)
+	}
+	return fork, nil
+}
+
+// GetMessageByID retrieves a message if the user can read its thread.
+func (ms *MessageService) GetMessageByID(userID, messageID uuid.UUID) (*messagemodel.ChatMessage, error) {
+	if messageID == uuid.Nil {
+		return nil, errors.New("invalid message ID")
+	}
+	message, err := ms.messageStore.GetMessageByID(messageID)
+	if err != nil {
+		return nil, err
+	}
+	if message == nil || !ms.CanReadThread(message.ThreadID, userID) {
+		return nil, ErrThreadAccessDenied
+	}
+	return message, nil
+}
+
+// GetDeletedThreads retrieves the threads in the user's trash.
+func (ms *MessageService) GetDeletedThreads(userID uuid.UUID) ([]messagemodel.ChatThread, error) {
+	return ms.messageStore.GetDeletedThreadsByUserID(userID)
+}
+
+// RestoreThread moves a thread out of the user's trash.
+func (ms *MessageService) RestoreThread(userID, threadID uuid.UUID) error {
+	restored, err := ms.messageStore.RestoreThread(threadID, userID)
+	if err != nil {
+		return err
+	}
+	if !restored {
+		return ErrThreadNotInTrash
+	}
+	return nil
+}
+
+// PurgeThread permanently deletes a thread from the user's trash. Usage
+// records of the thread are kept with their thread reference cleared.
+func (ms *MessageService) PurgeThread(userID, threadID uuid.UUID) error {
+	purged, err := ms.messageStore.PurgeThread(threadID, userID)
+	if err != nil {
+		return err
+	}
+	if !purged {
+		return ErrThreadNotInTrash
+	}
+	return nil
+}
+
+// PurgeDeletedBefore permanently deletes all threads trashed before the cutoff.
+func (ms *MessageService) PurgeDeletedBefore(cutoff time.Time) (int64, error) {
+	return ms.messageStore.PurgeThreadsDeletedBefore(cutoff)
+}
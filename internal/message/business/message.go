@@ -1,14 +1,30 @@
 // messagebusiness contains the business logic for message operations.
 package messagebusiness
 
-import messagestorage "github.com/khoaphungnguyen/go-openai/internal/message/storage"
+import (
+	"github.com/google/uuid"
+	messagestorage "github.com/khoaphungnguyen/go-openai/internal/message/storage"
+	workspacemodel "github.com/khoaphungnguyen/go-openai/internal/workspace/model"
+)
+
+// WorkspaceAccess resolves the role a user holds in a workspace.
+type WorkspaceAccess interface {
+	MemberRole(workspaceID, userID uuid.UUID) (workspacemodel.Role, error)
+}
+
+// MessageIndexer refreshes the search index of a new message.
+type MessageIndexer interface {
+	IndexMessage(messageID uuid.UUID)
+}
 
 // MessageService provides methods for message operations.
 type MessageService struct {
-	messageStore messagestorage.MessageStore
+	messageStore    messagestorage.MessageStore
+	workspaceAccess WorkspaceAccess
+	indexer         MessageIndexer
 }
 
-// NewMessageService creates a new MessageService.
-func NewMessageService(messageStore messagestorage.MessageStore) *MessageService {
-	return &MessageService{messageStore: messageStore}
+// NewMessageService creates a new MessageService. The indexer may be nil.
+func NewMessageService(messageStore messagestorage.MessageStore, workspaceAccess WorkspaceAccess, indexer MessageIndexer) *MessageService {
+	return &MessageService{messageStore: messageStore, workspaceAccess: workspaceAccess, indexer: indexer}
 }
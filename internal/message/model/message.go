@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 // ChatMessage represents a single message in a chat thread.
@@ -24,12 +25,18 @@ func (ChatMessage) TableName() string {
 
 // ChatThread represents a thread of chat messages.
 type ChatThread struct {
-	ID        uuid.UUID `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
-	UserID    uuid.UUID `gorm:"type:uuid"`
-	Title     string    `gorm:"type:varchar(255)"`
-	Model     string    `gorm:"type:varchar(255)"`
-	CreatedAt time.Time `gorm:"default:now()"`
-	UpdatedAt time.Time `gorm:"default:now()"`
+	ID          uuid.UUID  `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	UserID      uuid.UUID  `gorm:"type:uuid"`
+	WorkspaceID *uuid.UUID `gorm:"type:uuid;index"`
+	Title       string     `gorm:"type:varchar(255)"`
+	Model       string     `gorm:"type:varchar(255)"`
+	CreatedAt   time.Time  `gorm:"default:now()"`
+	UpdatedAt   time.Time  `gorm:"default:now()"`
+	// RAGEnabled grounds answers in the thread on the user's own notes and documents.
+	RAGEnabled bool `gorm:"column:rag_enabled;not null;default:false"`
+	// DeletedAt marks a thread as moved to the trash; trashed threads are
+	// hidden from all regular queries until restored or purged.
+	DeletedAt gorm.DeletedAt `gorm:"index"`
 }
 
 // TableName overrides the table name used by ChatThread.
@@ -2,6 +2,7 @@ package messagestorage
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	messagemodel "github.com/khoaphungnguyen/go-openai/internal/message/model"
@@ -57,7 +58,7 @@ func (ms *messageStore) GetMessagesByThreadID(threadID uuid.UUID, offset, limit
 	return messages, err
 }
 
-// DeleteThread deletes a chat thread.
+// DeleteThread moves a chat thread to the trash.
 func (ms *messageStore) DeleteThread(threadID uuid.UUID, userID uuid.UUID) error {
 	return ms.db.Where("id = ? AND user_id = ?", threadID, userID).Delete(&messagemodel.ChatThread{}).Error
 }
@@ -81,3 +82,87 @@ func (ms *messageStore) CheckThreadExistsAndBelongsToUser(threadID, userID uuid.
 	}
 	return count > 0, nil
 }
+
+// GetMessagesByThreadIDUntil retrieves the messages of a thread created up to the given time, oldest first.
+func (ms *messageStore) GetMessagesByThreadIDUntil(threadID uuid.UUID, until time.Time) ([]messagemodel.ChatMessage, error) {
+	var messages []messagemodel.ChatMessage
+	err := ms.db.Where("thread_id = ? AND created_at <= ?", threadID, until).Order("created_at ASC").Find(&messages).Error
+	return messages, err
+}
+
+// CreateThreadWithMessages creates a thread and its messages in a single transaction.
+// The thread title is restored afterwards because the insert trigger overwrites it.
+func (ms *messageStore) CreateThreadWithMessages(thread *messagemodel.ChatThread, messages []messagemodel.ChatMessage) error {
+	return ms.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(thread).Error; err != nil {
+			return err
+		}
+		for i := range messages {
+			messages[i].ID = uuid.Nil
+			messages[i].ThreadID = thread.ID
+			if err := tx.Create(&messages[i]).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(thread).Update("title", thread.Title).Error
+	})
+}
+
+// GetThreadsByWorkspaceID retrieves all chat threads that belong to a workspace.
+func (ms *messageStore) GetThreadsByWorkspaceID(workspaceID uuid.UUID) ([]messagemodel.ChatThread, error) {
+	var threads []messagemodel.ChatThread
+	err := ms.db.Where("workspace_id = ?", workspaceID).Order("updated_at DESC").Find(&threads).Error
+	return threads, err
+}
+
+// UpdateThreadWorkspace moves a thread into a workspace, or back to personal when workspaceID is nil.
+func (ms *messageStore) UpdateThreadWorkspace(threadID uuid.UUID, workspaceID *uuid.UUID) error {
+	return ms.db.Model(&messagemodel.ChatThread{}).Where("id = ?", threadID).Update("workspace_id", workspaceID).Error
+}
+
+// UpdateThreadRAG turns retrieval-augmented answers on or off for a thread.
+func (ms *messageStore) UpdateThreadRAG(threadID uuid.UUID, enabled bool) error {
+	return ms.db.Model(&messagemodel.ChatThread{}).Where("id = ?", threadID).Update("rag_enabled", enabled).Error
+}
+
+// GetMessageByID retrieves a chat message by its ID.
+func (ms *messageStore) GetMessageByID(messageID uuid.UUID) (*messagemodel.ChatMessage, error) {
+	var message messagemodel.ChatMessage
+	err := ms.db.First(&message, "id = ?", messageID).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil // Message does not exist
+		}
+		return nil, fmt.Errorf("failed to retrieve message: %w", err)
+	}
+	return &message, nil
+}
+
+// GetDeletedThreadsByUserID retrieves the trashed threads of a user, most recently deleted first.
+func (ms *messageStore) GetDeletedThreadsByUserID(userID uuid.UUID) ([]messagemodel.ChatThread, error) {
+	var threads []messagemodel.ChatThread
+	err := ms.db.Unscoped().Where("user_id = ? AND deleted_at IS NOT NULL", userID).Order("deleted_at DESC").Find(&threads).Error
+	return threads, err
+}
+
+// RestoreThread moves a trashed thread back out of the trash. It reports whether a thread was restored.
+func (ms *messageStore) RestoreThread(threadID, userID uuid.UUID) (bool, error) {
+	result := ms.db.Unscoped().Model(&messagemodel.ChatThread{}).
+		Where("id = ? AND user_id = ? AND deleted_at IS NOT NULL", threadID, userID).
+		Update("deleted_at", gorm.Expr("NULL"))
+	return result.RowsAffected > 0, result.Error
+}
+
+// PurgeThread permanently deletes a trashed thread. It reports whether a thread was purged.
+func (ms *messageStore) PurgeThread(threadID, userID uuid.UUID) (bool, error) {
+	result := ms.db.Unscoped().
+		Where("id = ? AND user_id = ? AND deleted_at IS NOT NULL", threadID, userID).
+		Delete(&messagemodel.ChatThread{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// PurgeThreadsDeletedBefore permanently deletes all threads trashed before the cutoff.
+func (ms *messageStore) PurgeThreadsDeletedBefore(cutoff time.Time) (int64, error) {
+	result := ms.db.Unscoped().Where("deleted_at < ?", cutoff).Delete(&messagemodel.ChatThread{})
+	return result.RowsAffected, result.Error
+}
@@ -2,6 +2,8 @@
 package messagestorage
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	messagemodel "github.com/khoaphungnguyen/go-openai/internal/message/model"
 	"gorm.io/gorm"
@@ -17,9 +19,20 @@ type MessageStore interface {
 	IsUserThreadOwner(threadID, userID uuid.UUID) bool
 
 	CreateMessage(message *messagemodel.ChatMessage) error
+	GetMessageByID(messageID uuid.UUID) (*messagemodel.ChatMessage, error)
 	GetMessagesByThreadID(threadID uuid.UUID, limit, offset int) ([]messagemodel.ChatMessage, error)
 	DeleteThread(threadID uuid.UUID, userID uuid.UUID) error
 	CheckThreadExistsAndBelongsToUser(threadID, userID uuid.UUID) (bool, error)
+	GetMessagesByThreadIDUntil(threadID uuid.UUID, until time.Time) ([]messagemodel.ChatMessage, error)
+	CreateThreadWithMessages(thread *messagemodel.ChatThread, messages []messagemodel.ChatMessage) error
+	GetThreadsByWorkspaceID(workspaceID uuid.UUID) ([]messagemodel.ChatThread, error)
+	UpdateThreadWorkspace(threadID uuid.UUID, workspaceID *uuid.UUID) error
+	UpdateThreadRAG(threadID uuid.UUID, enabled bool) error
+
+	GetDeletedThreadsByUserID(userID uuid.UUID) ([]messagemodel.ChatThread, error)
+	RestoreThread(threadID, userID uuid.UUID) (bool, error)
+	PurgeThread(threadID, userID uuid.UUID) (bool, error)
+	PurgeThreadsDeletedBefore(cutoff time.Time) (int64, error)
 }
 
 // messageStore encapsulates the logic for storing and retrieving message data.
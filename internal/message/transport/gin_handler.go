@@ -9,20 +9,35 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	messagebusiness "github.com/khoaphungnguyen/go-openai/internal/message/business"
 	messagemodel "github.com/khoaphungnguyen/go-openai/internal/message/model"
 )
 
 type ThreadPayload struct {
-	Title string `json:"title"`
-	Model string `json:"model"`
+	Title       string     `json:"title"`
+	Model       string     `json:"model"`
+	WorkspaceID *uuid.UUID `json:"workspaceId"`
+	RAGEnabled  bool       `json:"ragEnabled"`
+}
+
+type ThreadWorkspacePayload struct {
+	// WorkspaceID moves the thread into a workspace; null moves it back to personal threads.
+	WorkspaceID *uuid.UUID `json:"workspaceId"`
+}
+
+type ThreadRAGPayload struct {
+	Enabled *bool `json:"enabled" binding:"required"`
 }
 
 type ThreadResponse struct {
-	ID        uuid.UUID `json:"id"`
-	Title     string    `json:"title"`
-	Model     string    `json:"model"`
-	CreatedAt time.Time `json:"createdAt"`
-	UpdatedAt time.Time `json:"updatedAt"`
+	ID          uuid.UUID  `json:"id"`
+	WorkspaceID *uuid.UUID `json:"workspaceId"`
+	Title       string     `json:"title"`
+	Model       string     `json:"model"`
+	RAGEnabled  bool       `json:"ragEnabled"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+	DeletedAt   *time.Time `json:"deletedAt,omitempty"`
 }
 
 type ChatMessageResponse struct {
@@ -48,12 +63,18 @@ func (mh *MessageHandler) CreateThread(c *gin.Context) {
 	}
 
 	thread := &messagemodel.ChatThread{
-		Title:  payload.Title,
-		Model:  payload.Model,
-		UserID: userID,
+		Title:       payload.Title,
+		Model:       payload.Model,
+		UserID:      userID,
+		WorkspaceID: payload.WorkspaceID,
+		RAGEnabled:  payload.RAGEnabled,
 	}
 
 	if err := mh.messsageService.CreateThread(thread); err != nil {
+		if errors.Is(err, messagebusiness.ErrWorkspaceAccessDenied) {
+			respondWithError(c, http.StatusForbidden, "Access denied")
+			return
+		}
 		respondWithError(c, http.StatusInternalServerError, "Failed to create thread")
 		return
 	}
@@ -110,7 +131,7 @@ func (mh *MessageHandler) GetThreadByID(c *gin.Context) {
 	}
 
 	// Check if the user is authorized to view the thread
-	if thread.UserID != userID {
+	if !mh.messsageService.CanReadThread(threadID, userID) {
 		respondWithError(c, http.StatusForbidden, "Access denied")
 		return
 	}
@@ -134,6 +155,10 @@ func (mh *MessageHandler) CreateMessage(c *gin.Context) {
 
 	message.UserID = userID
 	if err := mh.messsageService.CreateMessage(userID, &message); err != nil {
+		if errors.Is(err, messagebusiness.ErrThreadAccessDenied) {
+			respondWithError(c, http.StatusForbidden, err.Error())
+			return
+		}
 		respondWithError(c, http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -156,7 +181,7 @@ func (mh *MessageHandler) GetMessagesByThreadID(c *gin.Context) {
 	}
 
 	// Check if the user is authorized to access the thread
-	if !mh.messsageService.IsUserThreadOwner(threadID, userID) {
+	if !mh.messsageService.CanReadThread(threadID, userID) {
 		respondWithError(c, http.StatusForbidden, "Unauthorized access to thread")
 		return
 	}
@@ -189,7 +214,176 @@ func (mh *MessageHandler) DeleteThread(c *gin.Context) {
 		return
 	}
 
-	respondWithJSON(c, http.StatusOK, gin.H{"message": "Thread deleted successfully"})
+	respondWithJSON(c, http.StatusOK, gin.H{"message": "Thread moved to trash"})
+}
+
+// GetDeletedThreads handles listing the threads in the user's trash.
+func (mh *MessageHandler) GetDeletedThreads(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	threads, err := mh.messsageService.GetDeletedThreads(userID)
+	if err != nil {
+		respondWithError(c, http.StatusInternalServerError, "Failed to retrieve trash")
+		return
+	}
+
+	responseThreads := make([]ThreadResponse, 0, len(threads))
+	for _, thread := range threads {
+		responseThreads = append(responseThreads, convertToThreadResponse(&thread))
+	}
+	respondWithJSON(c, http.StatusOK, responseThreads)
+}
+
+// RestoreThread handles moving a thread out of the trash.
+func (mh *MessageHandler) RestoreThread(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	threadID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, "Invalid thread ID")
+		return
+	}
+
+	if err := mh.messsageService.RestoreThread(userID, threadID); err != nil {
+		if errors.Is(err, messagebusiness.ErrThreadNotInTrash) {
+			respondWithError(c, http.StatusNotFound, "Thread not found in trash")
+			return
+		}
+		respondWithError(c, http.StatusInternalServerError, "Failed to restore thread")
+		return
+	}
+
+	respondWithJSON(c, http.StatusOK, gin.H{"message": "Thread restored successfully"})
+}
+
+// PurgeThread handles permanently deleting a thread from the trash.
+func (mh *MessageHandler) PurgeThread(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	threadID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, "Invalid thread ID")
+		return
+	}
+
+	if err := mh.messsageService.PurgeThread(userID, threadID); err != nil {
+		if errors.Is(err, messagebusiness.ErrThreadNotInTrash) {
+			respondWithError(c, http.StatusNotFound, "Thread not found in trash")
+			return
+		}
+		respondWithError(c, http.StatusInternalServerError, "Failed to delete thread")
+		return
+	}
+
+	respondWithJSON(c, http.StatusOK, gin.H{"message": "Thread deleted permanently"})
+}
+
+// GetWorkspaceThreads handles listing the threads shared in a workspace.
+func (mh *MessageHandler) GetWorkspaceThreads(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	workspaceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, "Invalid workspace ID")
+		return
+	}
+
+	threads, err := mh.messsageService.GetThreadsByWorkspaceID(userID, workspaceID)
+	if err != nil {
+		if errors.Is(err, messagebusiness.ErrWorkspaceAccessDenied) {
+			respondWithError(c, http.StatusNotFound, "Workspace not found")
+			return
+		}
+		respondWithError(c, http.StatusInternalServerError, "Failed to retrieve threads")
+		return
+	}
+
+	responseThreads := make([]ThreadResponse, 0, len(threads))
+	for _, thread := range threads {
+		responseThreads = append(responseThreads, convertToThreadResponse(&thread))
+	}
+
+	respondWithJSON(c, http.StatusOK, responseThreads)
+}
+
+// MoveThreadToWorkspace handles moving a thread owned by the caller into or out of a workspace.
+func (mh *MessageHandler) MoveThreadToWorkspace(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	threadID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, "Invalid thread ID")
+		return
+	}
+
+	var payload ThreadWorkspacePayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		respondWithError(c, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	if err := mh.messsageService.MoveThreadToWorkspace(userID, threadID, payload.WorkspaceID); err != nil {
+		if errors.Is(err, messagebusiness.ErrThreadAccessDenied) || errors.Is(err, messagebusiness.ErrWorkspaceAccessDenied) {
+			respondWithError(c, http.StatusForbidden, "Access denied")
+			return
+		}
+		respondWithError(c, http.StatusInternalServerError, "Failed to move thread")
+		return
+	}
+
+	respondWithJSON(c, http.StatusOK, gin.H{"message": "Thread moved successfully"})
+}
+
+// SetThreadRAG handles turning answers grounded in the caller's notes and documents on or off for a thread.
+func (mh *MessageHandler) SetThreadRAG(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	threadID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, "Invalid thread ID")
+		return
+	}
+
+	var payload ThreadRAGPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		respondWithError(c, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	if err := mh.messsageService.SetThreadRAG(userID, threadID, *payload.Enabled); err != nil {
+		if errors.Is(err, messagebusiness.ErrThreadAccessDenied) {
+			respondWithError(c, http.StatusForbidden, "Access denied")
+			return
+		}
+		respondWithError(c, http.StatusInternalServerError, "Failed to update thread")
+		return
+	}
+
+	respondWithJSON(c, http.StatusOK, gin.H{"ragEnabled": *payload.Enabled})
 }
 
 // Helper functions
@@ -216,13 +410,19 @@ func respondWithJSON(c *gin.Context, code int, payload interface{}) {
 }
 
 func convertToThreadResponse(thread *messagemodel.ChatThread) ThreadResponse {
-	return ThreadResponse{
-		ID:        thread.ID,
-		Title:     thread.Title,
-		Model:     thread.Model,
-		CreatedAt: thread.CreatedAt,
-		UpdatedAt: thread.UpdatedAt,
+	response := ThreadResponse{
+		ID:          thread.ID,
+		WorkspaceID: thread.WorkspaceID,
+		Title:       thread.Title,
+		Model:       thread.Model,
+		RAGEnabled:  thread.RAGEnabled,
+		CreatedAt:   thread.CreatedAt,
+		UpdatedAt:   thread.UpdatedAt,
+	}
+	if thread.DeletedAt.Valid {
+		response.DeletedAt = &thread.DeletedAt.Time
 	}
+	return response
 }
 
 // convertToChatMessageResponse converts a ChatMessage model to a ChatMessageResponse for the API.
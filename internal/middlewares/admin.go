@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	userbusiness "github.com/khoaphungnguyen/go-openai/internal/user/business"
+)
+
+// AdminMFAMiddleware refuses staff sessions opened without a second factor
+// when two-factor authentication is required for their role. It must run
+// after AuthMiddleware.
+func AdminMFAMiddleware(userService *userbusiness.UserService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if userService.MFARequiredForRole(roleFromContext(c)) && !c.GetBool("mfa") {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Enable two-factor authentication and log in again to perform this action"})
+			return
+		}
+		c.Next()
+	}
+}
@@ -1,17 +1,24 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	userauth "github.com/khoaphungnguyen/go-openai/internal/user/auth"
+	userbusiness "github.com/khoaphungnguyen/go-openai/internal/user/business"
 )
 
 const bearerSchema = "Bearer "
 
 // AuthMiddleware is a middleware that validates JWT tokens and authorizes users.
-func AuthMiddleware(secretKey string) gin.HandlerFunc {
+// Access tokens are only accepted while their session is active and their
+// user is not suspended, checked at most every AccessCheckInterval.
+// A personal API key is accepted in place of a token; the scopes of the key
+// are then checked by RequireScope on the routes it calls.
+func AuthMiddleware(secretKey string, userService *userbusiness.UserService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -25,16 +32,72 @@ func AuthMiddleware(secretKey string) gin.HandlerFunc {
 		}
 
 		tokenString := strings.TrimPrefix(authHeader, bearerSchema)
+		if userbusiness.IsAPIKey(tokenString) {
+			authenticateAPIKey(c, userService, tokenString)
+			return
+		}
 		jwtWrapper := userauth.JwtWrapper{SecretKey: secretKey}
 
-		claims, err := jwtWrapper.ValidateToken(tokenString)
+		claims, err := jwtWrapper.ValidateToken(tokenString, userauth.AccessToken)
 		if err != nil {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
 			return
 		}
 
-		// Add userID to Gin context for downstream handlers
+		// The token dies with its session, and with its user's access when suspended
+		userID, err := uuid.Parse(claims.UserID)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+			return
+		}
+		sessionID, err := uuid.Parse(claims.SessionID)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+			return
+		}
+		switch err := userService.CheckAccess(userID, sessionID); {
+		case errors.Is(err, userbusiness.ErrSessionRevoked):
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Session has ended. Please log in again."})
+			return
+		case errors.Is(err, userbusiness.ErrAccountSuspended):
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Account is suspended"})
+			return
+		case err != nil:
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify session"})
+			return
+		}
+
+		// Add userID, role, sessionID and mfa to Gin context for downstream handlers
 		c.Set("userID", claims.UserID)
+		c.Set("role", claims.Role)
+		c.Set("sessionID", claims.SessionID)
+		c.Set("mfa", claims.MFA)
 		c.Next()
 	}
 }
+
+// authenticateAPIKey authorizes a request made with a personal API key.
+func authenticateAPIKey(c *gin.Context, userService *userbusiness.UserService, value string) {
+	key, err := userService.AuthenticateAPIKey(value)
+	switch {
+	case errors.Is(err, userbusiness.ErrInvalidAPIKey):
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired API key"})
+		return
+	case errors.Is(err, userbusiness.ErrAccountInactive):
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Account is inactive"})
+		return
+	case errors.Is(err, userbusiness.ErrAccountSuspended):
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Account is suspended"})
+		return
+	case err != nil:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify API key"})
+		return
+	}
+
+	// API keys carry no session; downstream middleware checks their scopes
+	c.Set("userID", key.UserID.String())
+	c.Set("apiKeyID", key.ID.String())
+	c.Set("apiKeyScopes", key.Scopes)
+	c.Set("mfa", false)
+	c.Next()
+}
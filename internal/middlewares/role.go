@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	usermodel "github.com/khoaphungnguyen/go-openai/internal/user/model"
+)
+
+// RequireRole only lets users with one of the roles through. The role is read
+// from the access token, so it must run after AuthMiddleware. Requests made
+// with an API key carry no role and are refused.
+func RequireRole(roles ...usermodel.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := roleFromContext(c)
+		for _, allowed := range roles {
+			if role == allowed {
+				c.Next()
+				return
+			}
+		}
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "You are not authorized to perform this action"})
+	}
+}
+
+// RequirePermission only lets users whose role grants the permission through.
+// It must run after AuthMiddleware.
+func RequirePermission(permission usermodel.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !roleFromContext(c).Can(permission) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "You are not authorized to perform this action"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// roleFromContext returns the role AuthMiddleware took from the access token.
+func roleFromContext(c *gin.Context) usermodel.Role {
+	return usermodel.Role(c.GetString("role"))
+}
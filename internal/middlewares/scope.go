@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireScope only lets requests made with an API key through when the key
+// has the scope. Requests made with an access token are not restricted. It
+// must run after AuthMiddleware.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, ok := c.Get("apiKeyScopes")
+		if !ok {
+			c.Next()
+			return
+		}
+		scopes, _ := value.([]string)
+		for _, granted := range scopes {
+			if granted == scope {
+				c.Next()
+				return
+			}
+		}
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "API key is missing the " + scope + " scope"})
+	}
+}
+
+// RequireSession rejects requests made with an API key, keeping account
+// security and administration to interactive logins. It must run after
+// AuthMiddleware.
+func RequireSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := c.Get("apiKeyScopes"); ok {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "This action cannot be performed with an API key"})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/khoaphungnguyen/go-openai/internal/common"
+	userbusiness "github.com/khoaphungnguyen/go-openai/internal/user/business"
+)
+
+// VerifiedEmailMiddleware only lets users with a verified email address through. It must run after AuthMiddleware.
+func VerifiedEmailMiddleware(userService *userbusiness.UserService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := common.GetUserIDFromContext(c)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization required"})
+			return
+		}
+
+		verified, err := userService.IsEmailVerified(userID)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization required"})
+			return
+		}
+		if !verified {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Please verify your email address to use this feature"})
+			return
+		}
+		c.Next()
+	}
+}
@@ -2,9 +2,18 @@ package notebusiness
 
 import (
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	notemodel "github.com/khoaphungnguyen/go-openai/internal/note/model"
+	workspacemodel "github.com/khoaphungnguyen/go-openai/internal/workspace/model"
+)
+
+var (
+	// ErrWorkspaceAccessDenied is returned when the user cannot access the target workspace.
+	ErrWorkspaceAccessDenied = errors.New("user lacks permission in workspace")
+	// ErrNoteNotInTrash is returned when a note is not in the user's trash.
+	ErrNoteNotInTrash = errors.New("note not found in trash")
 )
 
 // CreateNote handles the creation of a new note.
@@ -12,7 +21,17 @@ func (ns *NoteService) CreateNote(note *notemodel.Note) error {
 	if note == nil {
 		return errors.New("note cannot be nil")
 	}
-	return ns.notestorage.CreateNote(note)
+	if note.WorkspaceID != nil && !ns.workspaceRole(*note.WorkspaceID, note.UserID).CanWrite() {
+		return ErrWorkspaceAccessDenied
+	}
+	if err := applyTaxonomy(note); err != nil {
+		return err
+	}
+	if err := ns.notestorage.CreateNote(note); err != nil {
+		return err
+	}
+	ns.index(note.ID)
+	return nil
 }
 
 // GetNotesByUserID retrieves all notes for a specific user
@@ -23,18 +42,24 @@ func (ns *NoteService) GetNotesByUserID(userID uuid.UUID, limit, offset int) ([]
 	return ns.notestorage.GetNotesByUserID(userID, limit, offset)
 }
 
+// GetNotesByWorkspaceID retrieves the notes of a workspace the user is a member of.
+func (ns *NoteService) GetNotesByWorkspaceID(userID, workspaceID uuid.UUID) ([]*notemodel.Note, error) {
+	if !ns.workspaceRole(workspaceID, userID).CanRead() {
+		return nil, ErrWorkspaceAccessDenied
+	}
+	return ns.notestorage.GetNotesByWorkspaceID(workspaceID)
+}
+
 // GetNoteByID retrieves a note by its ID.
 func (ns *NoteService) GetNoteByID(userID, noteID uuid.UUID) (*notemodel.Note, error) {
-
-	onwer := ns.notestorage.IsUserNoteOwner(noteID, userID)
-	if !onwer {
+	if !ns.noteRole(noteID, userID).CanRead() {
 		return nil, errors.New("user is not owner")
 	}
 
 	return ns.notestorage.GetNoteByID(noteID)
 }
 
-// DeleteNote deletes a note.
+// DeleteNote moves a note to the trash.
 func (ns *NoteService) DeleteNote(userID, noteID uuid.UUID) error {
 	onwer := ns.notestorage.IsUserNoteOwner(noteID, userID)
 	if !onwer {
@@ -45,9 +70,75 @@ func (ns *NoteService) DeleteNote(userID, noteID uuid.UUID) error {
 
 // UpdateNote updates a note.
 func (ns *NoteService) UpdateNoteByID(userID, noteID uuid.UUID, note *notemodel.Note) error {
-	onwer := ns.notestorage.IsUserNoteOwner(noteID, userID)
-	if !onwer {
+	if !ns.noteRole(noteID, userID).CanWrite() {
 		return errors.New("user is not owner")
 	}
-	return ns.notestorage.UpdateNoteByID(noteID, note)
+	if note.Category != "" && !note.Category.IsValid() {
+		return ErrInvalidCategory
+	}
+	if err := ns.notestorage.UpdateNoteByID(noteID, userID, note); err != nil {
+		return err
+	}
+	ns.index(noteID)
+	return nil
+}
+
+// noteRole resolves the effective role of a user on a note. Note owners are
+// treated as workspace owners; other users inherit their workspace role.
+func (ns *NoteService) noteRole(noteID, userID uuid.UUID) workspacemodel.Role {
+	if ns.notestorage.IsUserNoteOwner(noteID, userID) {
+		return workspacemodel.OwnerRole
+	}
+	note, err := ns.notestorage.GetNoteByID(noteID)
+	if err != nil || note == nil || note.WorkspaceID == nil {
+		return ""
+	}
+	return ns.workspaceRole(*note.WorkspaceID, userID)
+}
+
+// workspaceRole returns the user's role in the workspace, or an empty role for non-members.
+func (ns *NoteService) workspaceRole(workspaceID, userID uuid.UUID) workspacemodel.Role {
+	if ns.workspaceAccess == nil {
+		return ""
+	}
+	role, err := ns.workspaceAccess.MemberRole(workspaceID, userID)
+	if err != nil {
+		return ""
+	}
+	return role
+}
+
+// GetDeletedNotes retrieves the notes in the user's trash.
+func (ns *NoteService) GetDeletedNotes(userID uuid.UUID) ([]*notemodel.Note, error) {
+	return ns.notestorage.GetDeletedNotesByUserID(userID)
+}
+
+// RestoreNote moves a note out of the user's trash.
+func (ns *NoteService) RestoreNote(userID, noteID uuid.UUID) error {
+	restored, err := ns.notestorage.RestoreNote(noteID, userID)
+	if err != nil {
+		return err
+	}
+	if !restored {
+		return ErrNoteNotInTrash
+	}
+	ns.index(noteID)
+	return nil
+}
+
+// PurgeNote permanently deletes a note from the user's trash.
+func (ns *NoteService) PurgeNote(userID, noteID uuid.UUID) error {
+	purged, err := ns.notestorage.PurgeNote(noteID, userID)
+	if err != nil {
+		return err
+	}
+	if !purged {
+		return ErrNoteNotInTrash
+	}
+	return nil
+}
+
+// PurgeDeletedBefore permanently deletes all notes trashed before the cutoff.
+func (ns *NoteService) PurgeDeletedBefore(cutoff time.Time) (int64, error) {
+	return ns.notestorage.PurgeNotesDeletedBefore(cutoff)
 }
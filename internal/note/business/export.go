@@ -0,0 +1,155 @@
+package notebusiness
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/google/uuid"
+	notemodel "github.com/khoaphungnguyen/go-openai/internal/note/model"
+)
+
+// ErrInvalidExportFormat is returned for unsupported export formats.
+var ErrInvalidExportFormat = errors.New("format must be anki-csv, anki-tsv, markdown or json")
+
+// ExportNotes writes all of the user's notes to w in the given format.
+func (ns *NoteService) ExportNotes(userID uuid.UUID, format notemodel.ExportFormat, w io.Writer) error {
+	if !format.IsValid() {
+		return ErrInvalidExportFormat
+	}
+	notes, err := ns.notestorage.GetAllNotes(userID)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case notemodel.ExportAnkiCSV:
+		return writeAnkiDeck(w, notes, ',')
+	case notemodel.ExportAnkiTSV:
+		return writeAnkiDeck(w, notes, '\t')
+	case notemodel.ExportMarkdown:
+		return writeMarkdownVault(w, notes)
+	default:
+		exported := make([]notemodel.ExportedNote, 0, len(notes))
+		for _, note := range notes {
+			exported = append(exported, notemodel.NewExportedNote(note))
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(exported)
+	}
+}
+
+// writeAnkiDeck writes notes as Anki cards with the problem on the front and
+// the approach, solution and code on the back. The header lines let Anki pick
+// the separator, HTML rendering and tags column without manual mapping.
+func writeAnkiDeck(w io.Writer, notes []*notemodel.Note, separator rune) error {
+	separatorName := "Comma"
+	if separator == '\t' {
+		separatorName = "Tab"
+	}
+	if _, err := fmt.Fprintf(w, "#separator:%s\n#html:true\n#tags column:3\n", separatorName); err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	writer.Comma = separator
+	for _, note := range notes {
+		if err := writer.Write([]string{ankiFront(note), ankiBack(note), ankiTags(note)}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// ankiFront renders the question side of a card.
+func ankiFront(note *notemodel.Note) string {
+	front := "<b>" + html.EscapeString(note.Title) + "</b>"
+	if note.Problem != "" {
+		front += "<br><br>" + ankiText(note.Problem)
+	}
+	return front
+}
+
+// ankiBack renders the answer side of a card.
+func ankiBack(note *notemodel.Note) string {
+	var sections []string
+	if note.Approach != "" {
+		sections = append(sections, "<b>Approach</b><br>"+ankiText(note.Approach))
+	}
+	if note.Solution != "" {
+		sections = append(sections, "<b>Solution</b><br>"+ankiText(note.Solution))
+	}
+	if note.Code != "" {
+		sections = append(sections, "<pre><code>"+html.EscapeString(note.Code)+"</code></pre>")
+	}
+	return strings.Join(sections, "<br><br>")
+}
+
+// ankiText escapes plain text for an HTML card field, keeping line breaks.
+func ankiText(text string) string {
+	return strings.ReplaceAll(html.EscapeString(text), "\n", "<br>")
+}
+
+// ankiTags lists the note's tags, category and level as space-separated Anki tags.
+func ankiTags(note *notemodel.Note) string {
+	tags := make([]string, 0, len(note.Tags)+2)
+	for _, tag := range note.Tags {
+		tags = append(tags, tag.Tag)
+	}
+	if note.Category != "" {
+		tags = append(tags, "category::"+string(note.Category))
+	}
+	if note.Level != "" {
+		tags = append(tags, "level::"+strings.ToLower(note.Level))
+	}
+	return strings.Join(tags, " ")
+}
+
+// writeMarkdownVault writes notes as a zip of Markdown files, one per note.
+func writeMarkdownVault(w io.Writer, notes []*notemodel.Note) error {
+	archive := zip.NewWriter(w)
+	names := make(map[string]bool, len(notes))
+	for _, note := range notes {
+		name := markdownFileName(note, names)
+		file, err := archive.CreateHeader(&zip.FileHeader{
+			Name:     name,
+			Method:   zip.Deflate,
+			Modified: note.UpdatedAt,
+		})
+		if err != nil {
+			return err
+		}
+		content, err := MarshalMarkdown(notemodel.NewExportedNote(note))
+		if err != nil {
+			return err
+		}
+		if _, err := file.Write(content); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+// markdownFileName derives a file name from the note title that is unique within the archive.
+func markdownFileName(note *notemodel.Note, used map[string]bool) string {
+	base := notemodel.Slugify(note.Title)
+	if len(base) > 80 {
+		base = strings.Trim(base[:80], "-")
+	}
+	if base == "" {
+		base = note.ID.String()
+	}
+	name := base
+	for n := 2; used[name]; n++ {
+		name = fmt.Sprintf("%s-%d", base, n)
+	}
+	used[name] = true
+	return name + ".md"
+}
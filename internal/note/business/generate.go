@@ -0,0 +1,240 @@
+package notebusiness
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	messagemodel "github.com/khoaphungnguyen/go-openai/internal/message/model"
+	notemodel "github.com/khoaphungnguyen/go-openai/internal/note/model"
+	"github.com/khoaphungnguyen/go-openai/internal/provider"
+	"github.com/pkoukk/tiktoken-go"
+)
+
+const (
+	// generateTranscriptBudget bounds the tokens of thread messages sent to the model.
+	generateTranscriptBudget = 6000
+	// generateMaxTokens bounds the length of the generated note.
+	generateMaxTokens = 1500
+)
+
+var (
+	// ErrThreadAccessDenied is returned when the user cannot write to the thread.
+	ErrThreadAccessDenied = errors.New("thread does not exist or user lacks permission")
+	// ErrEmptyThread is returned when the thread has no messages to summarize.
+	ErrEmptyThread = errors.New("thread has no messages")
+	// ErrInvalidGeneratedNote is returned when the model output does not match the note schema.
+	ErrInvalidGeneratedNote = errors.New("model did not return a valid note")
+)
+
+// generateNoteSystemPrompt constrains the model to the note schema.
+const generateNoteSystemPrompt = `You turn a conversation about a programming problem into a study note.
+Respond with a single JSON object and nothing else, using exactly this schema:
+{
+  "title": "short name of the problem",
+  "problem": "the problem statement in your own words",
+  "approach": "the key insight and step-by-step approach",
+  "solution": "explanation of the final solution including time and space complexity",
+  "code": "the final solution code, or an empty string if there is none",
+  "level": "Easy" | "Medium" | "Hard",
+  "type": "the main topic, for example Array, Graph or Dynamic Programming"
+}
+Only use information from the conversation.`
+
+// ThreadReader gives note generation read access to chat threads.
+type ThreadReader interface {
+	CanWriteThread(threadID, userID uuid.UUID) bool
+	GetThreadByID(threadID uuid.UUID) (*messagemodel.ChatThread, error)
+	GetMessagesByThreadIDUntil(threadID uuid.UUID, until time.Time) ([]messagemodel.ChatMessageResponse, error)
+}
+
+// GenerateStage names a step of note generation reported to the caller.
+type GenerateStage string
+
+const (
+	StageReading    GenerateStage = "reading"
+	StageGenerating GenerateStage = "generating"
+	StageSaving     GenerateStage = "saving"
+)
+
+// GenerateProgress reports the progress of note generation. Delta carries
+// model output while generating.
+type GenerateProgress struct {
+	Stage GenerateStage `json:"stage"`
+	Delta string        `json:"delta,omitempty"`
+}
+
+// GeneratedNote is the structured note extracted by the model.
+type GeneratedNote struct {
+	Title    string `json:"title"`
+	Problem  string `json:"problem"`
+	Approach string `json:"approach"`
+	Solution string `json:"solution"`
+	Code     string `json:"code"`
+	Level    string `json:"level"`
+	Type     string `json:"type"`
+}
+
+// NoteGenerator creates notes from chat threads with a language model.
+type NoteGenerator struct {
+	noteService *NoteService
+	threads     ThreadReader
+	llm         provider.Provider
+}
+
+// NewNoteGenerator creates a new NoteGenerator.
+func NewNoteGenerator(noteService *NoteService, threads ThreadReader, llm provider.Provider) *NoteGenerator {
+	return &NoteGenerator{noteService: noteService, threads: threads, llm: llm}
+}
+
+// GenerateFromThread extracts a structured note from the thread's messages and
+// stores it in the note linked to the thread, creating the note if none exists.
+// It reports whether a new note was created.
+func (g *NoteGenerator) GenerateFromThread(ctx context.Context, userID, threadID uuid.UUID, model string, progress func(GenerateProgress) error) (*notemodel.Note, bool, error) {
+	if !g.threads.CanWriteThread(threadID, userID) {
+		return nil, false, ErrThreadAccessDenied
+	}
+	if err := progress(GenerateProgress{Stage: StageReading}); err != nil {
+		return nil, false, err
+	}
+
+	thread, err := g.threads.GetThreadByID(threadID)
+	if err != nil {
+		return nil, false, err
+	}
+	if thread == nil {
+		return nil, false, ErrThreadAccessDenied
+	}
+	messages, err := g.threads.GetMessagesByThreadIDUntil(threadID, time.Now())
+	if err != nil {
+		return nil, false, err
+	}
+	transcript, err := buildTranscript(messages, generateTranscriptBudget)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if err := progress(GenerateProgress{Stage: StageGenerating}); err != nil {
+		return nil, false, err
+	}
+	output, err := g.llm.Stream(ctx, provider.Request{
+		Model: model,
+		Messages: []provider.Message{
+			{Role: "system", Content: generateNoteSystemPrompt},
+			{Role: "user", Content: transcript},
+		},
+		MaxTokens: generateMaxTokens,
+		JSON:      true,
+	}, func(delta string) error {
+		return progress(GenerateProgress{Stage: StageGenerating, Delta: delta})
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	generated, err := parseGeneratedNote(output)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if err := progress(GenerateProgress{Stage: StageSaving}); err != nil {
+		return nil, false, err
+	}
+	return g.save(userID, thread, generated)
+}
+
+// save writes the generated fields to the note linked to the thread.
+func (g *NoteGenerator) save(userID uuid.UUID, thread *messagemodel.ChatThread, generated *GeneratedNote) (*notemodel.Note, bool, error) {
+	fields := notemodel.Note{
+		Title:    generated.Title,
+		Problem:  generated.Problem,
+		Approach: generated.Approach,
+		Solution: generated.Solution,
+		Code:     generated.Code,
+		Level:    generated.Level,
+		Type:     generated.Type,
+		Category: notemodel.NormalizeCategory(generated.Type),
+	}
+
+	existing, err := g.noteService.notestorage.GetNoteByThreadID(thread.ID)
+	if err != nil {
+		return nil, false, err
+	}
+	if existing != nil {
+		// A thread has one note; a caller who cannot edit it does not get a second one
+		if !g.noteService.noteRole(existing.ID, userID).CanWrite() {
+			return nil, false, ErrThreadAccessDenied
+		}
+		if err := g.noteService.UpdateNoteByID(userID, existing.ID, &fields); err != nil {
+			return nil, false, err
+		}
+		note, err := g.noteService.notestorage.GetNoteByID(existing.ID)
+		return note, false, err
+	}
+
+	note := &fields
+	note.UserID = userID
+	note.ThreadID = thread.ID
+	note.WorkspaceID = thread.WorkspaceID
+	if note.Title == "" {
+		note.Title = thread.Title
+	}
+	if err := g.noteService.CreateNote(note); err != nil {
+		return nil, false, err
+	}
+	return note, true, nil
+}
+
+// buildTranscript formats the most recent messages that fit in the token budget, oldest first.
+func buildTranscript(messages []messagemodel.ChatMessageResponse, budget int) (string, error) {
+	if len(messages) == 0 {
+		return "", ErrEmptyThread
+	}
+	tke, err := tiktoken.GetEncoding("cl100k_base")
+	if err != nil {
+		return "", fmt.Errorf("getEncoding: %v", err)
+	}
+
+	var parts []string
+	used := 0
+	for i := len(messages) - 1; i >= 0; i-- {
+		part := fmt.Sprintf("%s: %s", messages[i].Role, messages[i].Content)
+		tokens := len(tke.Encode(part, nil, nil))
+		if used+tokens > budget && len(parts) > 0 {
+			break
+		}
+		parts = append(parts, part)
+		used += tokens
+	}
+	for i, j := 0, len(parts)-1; i < j; i, j = i+1, j-1 {
+		parts[i], parts[j] = parts[j], parts[i]
+	}
+	return strings.Join(parts, "\n\n"), nil
+}
+
+// parseGeneratedNote decodes the model output and validates it against the note schema.
+func parseGeneratedNote(output string) (*GeneratedNote, error) {
+	var generated GeneratedNote
+	if err := provider.DecodeJSONObject(output, &generated); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidGeneratedNote, err)
+	}
+	if strings.TrimSpace(generated.Problem) == "" {
+		return nil, fmt.Errorf("%w: missing problem", ErrInvalidGeneratedNote)
+	}
+
+	if title := []rune(strings.TrimSpace(generated.Title)); len(title) > 255 {
+		generated.Title = string(title[:255])
+	}
+
+	switch strings.ToLower(strings.TrimSpace(generated.Level)) {
+	case "easy":
+		generated.Level = "Easy"
+	case "hard":
+		generated.Level = "Hard"
+	default:
+		generated.Level = "Medium"
+	}
+	return &generated, nil
+}
@@ -0,0 +1,316 @@
+package notebusiness
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/google/uuid"
+	notemodel "github.com/khoaphungnguyen/go-openai/internal/note/model"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// MaxImportSize bounds the size of an uploaded Markdown file or zip archive.
+	MaxImportSize = 20 << 20
+	// MaxImportFiles bounds the number of notes imported from one archive.
+	MaxImportFiles = 500
+	// maxMarkdownSize bounds the size of a single Markdown file.
+	maxMarkdownSize = 1 << 20
+	// defaultImportLevel is used for imported notes without a valid level.
+	defaultImportLevel = "Medium"
+)
+
+var (
+	// ErrUnsupportedImport is returned for uploads that are neither Markdown nor zip.
+	ErrUnsupportedImport = errors.New("import must be a .md file or a .zip of .md files")
+	// ErrTooManyImportFiles is returned for archives holding more than MaxImportFiles notes.
+	ErrTooManyImportFiles = fmt.Errorf("an archive can hold at most %d notes", MaxImportFiles)
+	// ErrInvalidMarkdown is returned for Markdown files that cannot be parsed into a note.
+	ErrInvalidMarkdown = errors.New("invalid markdown note")
+)
+
+// Note sections recognized as level-two headings, in the order they are written.
+const (
+	sectionProblem  = "Problem"
+	sectionApproach = "Approach"
+	sectionSolution = "Solution"
+	sectionCode     = "Code"
+)
+
+// frontMatter is the YAML header of an imported Markdown note. Tags may be a
+// list or a single comma- or space-separated string, as Obsidian accepts both.
+type frontMatter struct {
+	Title    string      `yaml:"title"`
+	Level    string      `yaml:"level"`
+	Type     string      `yaml:"type"`
+	Category string      `yaml:"category"`
+	Tags     interface{} `yaml:"tags"`
+}
+
+// MarshalMarkdown renders a note as Markdown with YAML front matter.
+func MarshalMarkdown(note notemodel.ExportedNote) ([]byte, error) {
+	header, err := yaml.Marshal(note)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+	buf.Write(header)
+	buf.WriteString("---\n\n# ")
+	buf.WriteString(note.Title)
+	buf.WriteString("\n")
+	for _, section := range []struct{ heading, content string }{
+		{sectionProblem, note.Problem},
+		{sectionApproach, note.Approach},
+		{sectionSolution, note.Solution},
+	} {
+		if strings.TrimSpace(section.content) == "" {
+			continue
+		}
+		fmt.Fprintf(&buf, "\n## %s\n\n%s\n", section.heading, strings.TrimSpace(section.content))
+	}
+	if strings.TrimSpace(note.Code) != "" {
+		fence := "```"
+		for strings.Contains(note.Code, fence) {
+			fence += "`"
+		}
+		fmt.Fprintf(&buf, "\n## %s\n\n%s\n%s\n%s\n", sectionCode, fence, strings.Trim(note.Code, "\n"), fence)
+	}
+	return buf.Bytes(), nil
+}
+
+// ParseMarkdown reads a note written by MarshalMarkdown or by hand. The front
+// matter is optional; the title falls back to the first level-one heading and
+// then to fallbackTitle. Text outside the known sections becomes the problem.
+func ParseMarkdown(content []byte, fallbackTitle string) (*notemodel.Note, error) {
+	text := strings.ReplaceAll(string(content), "\r\n", "\n")
+
+	var meta frontMatter
+	if rest, ok := strings.CutPrefix(text, "---\n"); ok {
+		end := strings.Index("\n"+rest, "\n---\n")
+		if end < 0 {
+			return nil, fmt.Errorf("%w: unterminated front matter", ErrInvalidMarkdown)
+		}
+		header := rest[:max(end-1, 0)]
+		if err := yaml.Unmarshal([]byte(header), &meta); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidMarkdown, err)
+		}
+		text = rest[end+len("---\n"):]
+	}
+
+	title, sections := splitSections(text)
+	if meta.Title != "" {
+		title = meta.Title
+	}
+	if title == "" {
+		title = fallbackTitle
+	}
+	if len(title) > 255 {
+		title = title[:255]
+	}
+
+	note := &notemodel.Note{
+		Title:    title,
+		Problem:  sections[sectionProblem],
+		Approach: sections[sectionApproach],
+		Solution: sections[sectionSolution],
+		Code:     unfenceCode(sections[sectionCode]),
+		Level:    normalizeLevel(meta.Level),
+		Type:     meta.Type,
+	}
+	if meta.Category != "" {
+		note.Category = notemodel.NormalizeCategory(meta.Category)
+	}
+	for _, tag := range frontMatterTags(meta.Tags) {
+		note.Tags = append(note.Tags, notemodel.NoteTag{Tag: tag})
+	}
+	if note.Problem == "" && note.Approach == "" && note.Solution == "" && note.Code == "" {
+		return nil, fmt.Errorf("%w: note has no content", ErrInvalidMarkdown)
+	}
+	return note, nil
+}
+
+// splitSections returns the first level-one heading and the content of the
+// known level-two sections. Headings inside fenced code blocks are ignored and
+// unknown sections stay part of the section before them.
+func splitSections(text string) (string, map[string]string) {
+	var title string
+	current := sectionProblem
+	contents := make(map[string][]string)
+	var fence string
+
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+		} else if marker := fenceMarker(trimmed); marker != "" {
+			fence = marker
+		} else if title == "" && strings.HasPrefix(line, "# ") {
+			title = strings.TrimSpace(line[2:])
+			continue
+		} else if strings.HasPrefix(line, "## ") {
+			if section := knownSection(line[3:]); section != "" {
+				current = section
+				continue
+			}
+		}
+		contents[current] = append(contents[current], line)
+	}
+
+	sections := make(map[string]string, len(contents))
+	for section, lines := range contents {
+		sections[section] = strings.TrimSpace(strings.Join(lines, "\n"))
+	}
+	return title, sections
+}
+
+// fenceMarker returns the backtick or tilde run opening a fenced code block.
+func fenceMarker(line string) string {
+	for _, char := range []string{"`", "~"} {
+		if strings.HasPrefix(line, strings.Repeat(char, 3)) {
+			return line[:len(line)-len(strings.TrimLeft(line, char))]
+		}
+	}
+	return ""
+}
+
+// knownSection matches a level-two heading to a note section.
+func knownSection(heading string) string {
+	heading = strings.TrimSpace(heading)
+	for _, section := range []string{sectionProblem, sectionApproach, sectionSolution, sectionCode} {
+		if strings.EqualFold(heading, section) {
+			return section
+		}
+	}
+	return ""
+}
+
+// unfenceCode strips the fence from a code section made of a single fenced block.
+func unfenceCode(code string) string {
+	lines := strings.Split(code, "\n")
+	if len(lines) < 2 {
+		return code
+	}
+	marker := fenceMarker(lines[0])
+	last := strings.TrimSpace(lines[len(lines)-1])
+	if marker == "" || !strings.HasPrefix(last, marker) || strings.Trim(last, marker[:1]) != "" {
+		return code
+	}
+	return strings.Join(lines[1:len(lines)-1], "\n")
+}
+
+// normalizeLevel maps a level in any letter case to its canonical spelling,
+// defaulting to Medium for missing or unknown levels.
+func normalizeLevel(level string) string {
+	for _, valid := range []string{"Easy", "Medium", "Hard"} {
+		if strings.EqualFold(strings.TrimSpace(level), valid) {
+			return valid
+		}
+	}
+	return defaultImportLevel
+}
+
+// frontMatterTags reads tags given either as a YAML list or as a string.
+func frontMatterTags(value interface{}) []string {
+	switch tags := value.(type) {
+	case string:
+		return strings.FieldsFunc(tags, func(r rune) bool { return r == ',' || r == ' ' })
+	case []interface{}:
+		names := make([]string, 0, len(tags))
+		for _, tag := range tags {
+			names = append(names, fmt.Sprint(tag))
+		}
+		return names
+	}
+	return nil
+}
+
+// ImportMarkdown creates notes for the user from a Markdown file or a zip of
+// Markdown files. Files that fail to import are reported without aborting the rest.
+func (ns *NoteService) ImportMarkdown(userID uuid.UUID, fileName string, file io.ReaderAt, size int64) (*notemodel.ImportResult, error) {
+	result := &notemodel.ImportResult{Imported: []uuid.UUID{}, Failed: []notemodel.ImportError{}}
+
+	switch strings.ToLower(path.Ext(fileName)) {
+	case ".md", ".markdown":
+		ns.importMarkdownFile(userID, fileName, io.NewSectionReader(file, 0, size), result)
+	case ".zip":
+		archive, err := zip.NewReader(file, size)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrUnsupportedImport, err)
+		}
+		var entries []*zip.File
+		for _, entry := range archive.File {
+			if isMarkdownEntry(entry) {
+				entries = append(entries, entry)
+			}
+		}
+		if len(entries) > MaxImportFiles {
+			return nil, ErrTooManyImportFiles
+		}
+		for _, entry := range entries {
+			reader, err := entry.Open()
+			if err != nil {
+				result.Failed = append(result.Failed, notemodel.ImportError{File: entry.Name, Error: err.Error()})
+				continue
+			}
+			ns.importMarkdownFile(userID, entry.Name, reader, result)
+			reader.Close()
+		}
+	default:
+		return nil, ErrUnsupportedImport
+	}
+	return result, nil
+}
+
+// importMarkdownFile parses and saves one Markdown note, recording the outcome in result.
+func (ns *NoteService) importMarkdownFile(userID uuid.UUID, name string, r io.Reader, result *notemodel.ImportResult) {
+	fail := func(err error) {
+		result.Failed = append(result.Failed, notemodel.ImportError{File: name, Error: err.Error()})
+	}
+
+	// Read one byte past the limit so oversized (or zip-bomb) entries are rejected
+	content, err := io.ReadAll(io.LimitReader(r, maxMarkdownSize+1))
+	if err != nil {
+		fail(err)
+		return
+	}
+	if len(content) > maxMarkdownSize {
+		fail(fmt.Errorf("file exceeds %d bytes", maxMarkdownSize))
+		return
+	}
+
+	base := path.Base(name)
+	note, err := ParseMarkdown(content, strings.TrimSuffix(base, path.Ext(base)))
+	if err != nil {
+		fail(err)
+		return
+	}
+	note.UserID = userID
+	if err := ns.CreateNote(note); err != nil {
+		fail(err)
+		return
+	}
+	result.Imported = append(result.Imported, note.ID)
+}
+
+// isMarkdownEntry reports whether a zip entry is a Markdown file worth importing,
+// skipping directories and metadata such as __MACOSX and dot files.
+func isMarkdownEntry(entry *zip.File) bool {
+	if entry.FileInfo().IsDir() {
+		return false
+	}
+	name := entry.Name
+	if strings.HasPrefix(name, "__MACOSX/") || strings.HasPrefix(path.Base(name), ".") {
+		return false
+	}
+	ext := strings.ToLower(path.Ext(name))
+	return ext == ".md" || ext == ".markdown"
+}
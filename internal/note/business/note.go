@@ -2,15 +2,36 @@
 package notebusiness
 
 import (
+	"github.com/google/uuid"
 	notestorage "github.com/khoaphungnguyen/go-openai/internal/note/storage"
+	workspacemodel "github.com/khoaphungnguyen/go-openai/internal/workspace/model"
 )
 
+// WorkspaceAccess resolves the role a user holds in a workspace.
+type WorkspaceAccess interface {
+	MemberRole(workspaceID, userID uuid.UUID) (workspacemodel.Role, error)
+}
+
+// NoteIndexer refreshes the search index of a note after its content changed.
+type NoteIndexer interface {
+	IndexNote(noteID uuid.UUID)
+}
+
 // NoteService provides methods for message operations.
 type NoteService struct {
-	notestorage notestorage.NoteStore
+	notestorage     notestorage.NoteStore
+	workspaceAccess WorkspaceAccess
+	indexer         NoteIndexer
+}
+
+// NewNoteService creates a new NoteService. The indexer may be nil.
+func NewNoteService(notestorage notestorage.NoteStore, workspaceAccess WorkspaceAccess, indexer NoteIndexer) *NoteService {
+	return &NoteService{notestorage: notestorage, workspaceAccess: workspaceAccess, indexer: indexer}
 }
 
-// NewNoteService creates a new NoteService.
-func NewNoteService(notestorage notestorage.NoteStore) *NoteService {
-	return &NoteService{notestorage: notestorage}
+// index schedules a note for reindexing when an indexer is configured.
+func (ns *NoteService) index(noteID uuid.UUID) {
+	if ns.indexer != nil {
+		ns.indexer.IndexNote(noteID)
+	}
 }
@@ -0,0 +1,151 @@
+package notebusiness
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	notemodel "github.com/khoaphungnguyen/go-openai/internal/note/model"
+)
+
+const (
+	// DefaultDueLimit bounds the size of the review queue.
+	DefaultDueLimit = 20
+	// retentionWindowDays is the period over which the retention rate is measured.
+	retentionWindowDays = 30
+	// reviewHistoryLimit bounds the reviews returned with a schedule.
+	reviewHistoryLimit = 50
+)
+
+var (
+	// ErrNoteNotFound is returned when a note does not exist or the user cannot read it.
+	ErrNoteNotFound = errors.New("note not found")
+	// ErrInvalidGrade is returned for grades outside 0 to 5.
+	ErrInvalidGrade = errors.New("grade must be between 0 and 5")
+)
+
+// GetDueNotes returns the notes due for review today, most overdue first.
+func (ns *NoteService) GetDueNotes(userID uuid.UUID, limit int) ([]notemodel.DueNote, error) {
+	if limit <= 0 {
+		limit = DefaultDueLimit
+	}
+	return ns.notestorage.GetDueNotes(userID, endOfToday(time.Now()), limit)
+}
+
+// GradeNote records a review of a note and reschedules it with SM-2.
+func (ns *NoteService) GradeNote(userID, noteID uuid.UUID, grade int) (*notemodel.ReviewSchedule, error) {
+	if grade < 0 || grade > notemodel.MaxGrade {
+		return nil, ErrInvalidGrade
+	}
+	if !ns.noteRole(noteID, userID).CanRead() {
+		return nil, ErrNoteNotFound
+	}
+
+	now := time.Now()
+	schedule, err := ns.notestorage.GetReviewSchedule(noteID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if schedule == nil {
+		schedule = notemodel.NewReviewSchedule(noteID, userID, now)
+	}
+	schedule.Apply(grade, now)
+
+	log := &notemodel.ReviewLog{
+		NoteID:       noteID,
+		UserID:       userID,
+		Grade:        grade,
+		IntervalDays: schedule.IntervalDays,
+		EaseFactor:   schedule.EaseFactor,
+		ReviewedAt:   now,
+	}
+	if err := ns.notestorage.SaveReview(schedule, log); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// GetReviewSchedule returns the user's schedule for a note, nil if it was never
+// reviewed, together with its most recent reviews.
+func (ns *NoteService) GetReviewSchedule(userID, noteID uuid.UUID) (*notemodel.ReviewSchedule, []notemodel.ReviewLog, error) {
+	if !ns.noteRole(noteID, userID).CanRead() {
+		return nil, nil, ErrNoteNotFound
+	}
+	schedule, err := ns.notestorage.GetReviewSchedule(noteID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	logs, err := ns.notestorage.GetReviewLogs(noteID, userID, reviewHistoryLimit)
+	if err != nil {
+		return nil, nil, err
+	}
+	return schedule, logs, nil
+}
+
+// GetReviewStats summarizes the user's queue, retention and review streaks.
+func (ns *NoteService) GetReviewStats(userID uuid.UUID) (*notemodel.ReviewStats, error) {
+	now := time.Now()
+	stats := &notemodel.ReviewStats{RetentionDays: retentionWindowDays}
+
+	var err error
+	if stats.DueToday, err = ns.notestorage.CountDueNotes(userID, endOfToday(now)); err != nil {
+		return nil, err
+	}
+	if stats.Scheduled, stats.Mature, err = ns.notestorage.CountReviewSchedules(userID); err != nil {
+		return nil, err
+	}
+	if stats.TotalReviews, _, err = ns.notestorage.CountReviews(userID, time.Time{}); err != nil {
+		return nil, err
+	}
+
+	recent, passed, err := ns.notestorage.CountReviews(userID, now.AddDate(0, 0, -retentionWindowDays))
+	if err != nil {
+		return nil, err
+	}
+	stats.RecentReviews = recent
+	if recent > 0 {
+		stats.RetentionRate = float64(passed) / float64(recent)
+	}
+
+	days, err := ns.notestorage.GetReviewDays(userID)
+	if err != nil {
+		return nil, err
+	}
+	stats.CurrentStreak, stats.LongestStreak, stats.ReviewedToday = reviewStreaks(days, now)
+	return stats, nil
+}
+
+// reviewStreaks computes the current and longest runs of consecutive review
+// days from distinct days sorted most recent first. The current streak is
+// still alive if the last review was yesterday.
+func reviewStreaks(days []time.Time, now time.Time) (current, longest int, reviewedToday bool) {
+	today := notemodel.StartOfDay(now)
+	run := 0
+	var previous time.Time
+	for i, day := range days {
+		day = notemodel.StartOfDay(day)
+		if i > 0 && previous.Sub(day) == 24*time.Hour {
+			run++
+		} else {
+			run = 1
+		}
+		if run > longest {
+			longest = run
+		}
+		if i == 0 {
+			reviewedToday = day.Equal(today)
+			if reviewedToday || day.Equal(today.AddDate(0, 0, -1)) {
+				current = 1
+			}
+		} else if current == i && run == i+1 {
+			current = run
+		}
+		previous = day
+	}
+	return current, longest, reviewedToday
+}
+
+// endOfToday returns the last instant of the current UTC day.
+func endOfToday(now time.Time) time.Time {
+	return notemodel.StartOfDay(now).AddDate(0, 0, 1).Add(-time.Nanosecond)
+}
@@ -0,0 +1,44 @@
+package notebusiness
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReviewStreaks(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	// daysAgo returns review days at varying times, most recent first
+	daysAgo := func(offsets ...int) []time.Time {
+		days := make([]time.Time, 0, len(offsets))
+		for i, offset := range offsets {
+			days = append(days, time.Date(2026, 3, 10-offset, i%24, 30, 0, 0, time.UTC))
+		}
+		return days
+	}
+
+	tests := []struct {
+		name          string
+		days          []time.Time
+		current       int
+		longest       int
+		reviewedToday bool
+	}{
+		{"never reviewed", nil, 0, 0, false},
+		{"today", daysAgo(0), 1, 1, true},
+		{"yesterday keeps the streak alive", daysAgo(1), 1, 1, false},
+		{"two days ago breaks it", daysAgo(2), 0, 1, false},
+		{"consecutive days up to today", daysAgo(0, 1, 2), 3, 3, true},
+		{"longest run in the past", daysAgo(1, 2, 5, 6, 7, 8), 2, 4, false},
+		{"gap after today", daysAgo(0, 2, 3, 4), 1, 3, true},
+		{"late last night", []time.Time{time.Date(2026, 3, 9, 23, 59, 0, 0, time.UTC)}, 1, 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current, longest, reviewedToday := reviewStreaks(tt.days, now)
+			if current != tt.current || longest != tt.longest || reviewedToday != tt.reviewedToday {
+				t.Errorf("reviewStreaks = %d, %d, %v; want %d, %d, %v",
+					current, longest, reviewedToday, tt.current, tt.longest, tt.reviewedToday)
+			}
+		})
+	}
+}
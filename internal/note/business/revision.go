@@ -0,0 +1,97 @@
+package notebusiness
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	notemodel "github.com/khoaphungnguyen/go-openai/internal/note/model"
+	"github.com/khoaphungnguyen/go-openai/internal/textdiff"
+)
+
+// ErrRevisionNotFound is returned when a note has no revision with the requested version.
+var ErrRevisionNotFound = errors.New("revision not found")
+
+// FieldDiff is the unified diff of one note field between two revisions.
+type FieldDiff struct {
+	Field string `json:"field"`
+	Diff  string `json:"diff"`
+}
+
+// GetRevisions lists the revisions of a note the user can read, newest first.
+func (ns *NoteService) GetRevisions(userID, noteID uuid.UUID) ([]notemodel.NoteRevision, error) {
+	if !ns.noteRole(noteID, userID).CanRead() {
+		return nil, ErrNoteNotFound
+	}
+	return ns.notestorage.GetRevisions(noteID)
+}
+
+// GetRevision retrieves one revision of a note the user can read.
+func (ns *NoteService) GetRevision(userID, noteID uuid.UUID, version int) (*notemodel.NoteRevision, error) {
+	if !ns.noteRole(noteID, userID).CanRead() {
+		return nil, ErrNoteNotFound
+	}
+	return ns.getRevision(noteID, version)
+}
+
+// DiffRevisions returns per-field unified diffs between two revisions of a
+// note. Fields that did not change are omitted. Fields longer than
+// textdiff.MaxLines are refused with textdiff.ErrTooLarge.
+func (ns *NoteService) DiffRevisions(userID, noteID uuid.UUID, fromVersion, toVersion int) ([]FieldDiff, error) {
+	if !ns.noteRole(noteID, userID).CanRead() {
+		return nil, ErrNoteNotFound
+	}
+	from, err := ns.getRevision(noteID, fromVersion)
+	if err != nil {
+		return nil, err
+	}
+	to, err := ns.getRevision(noteID, toVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	fromFields, toFields := from.Fields(), to.Fields()
+	diffs := make([]FieldDiff, 0, len(notemodel.RevisionFields))
+	for _, field := range notemodel.RevisionFields {
+		diff, err := textdiff.Unified(
+			fmt.Sprintf("%s@v%d", field, from.Version),
+			fmt.Sprintf("%s@v%d", field, to.Version),
+			fromFields[field], toFields[field], textdiff.DefaultContext)
+		if err != nil {
+			return nil, err
+		}
+		if diff != "" {
+			diffs = append(diffs, FieldDiff{Field: field, Diff: diff})
+		}
+	}
+	return diffs, nil
+}
+
+// RestoreRevision restores a note to the content of an earlier revision. The
+// restore is itself recorded as a new revision, so history is never rewritten.
+func (ns *NoteService) RestoreRevision(userID, noteID uuid.UUID, version int) error {
+	if !ns.noteRole(noteID, userID).CanWrite() {
+		return ErrNoteNotFound
+	}
+	revision, err := ns.getRevision(noteID, version)
+	if err != nil {
+		return err
+	}
+	if err := ns.notestorage.RestoreRevision(noteID, userID, revision); err != nil {
+		return err
+	}
+	ns.index(noteID)
+	return nil
+}
+
+// getRevision loads a revision, mapping a missing one to ErrRevisionNotFound.
+func (ns *NoteService) getRevision(noteID uuid.UUID, version int) (*notemodel.NoteRevision, error) {
+	revision, err := ns.notestorage.GetRevision(noteID, version)
+	if err != nil {
+		return nil, err
+	}
+	if revision == nil {
+		return nil, ErrRevisionNotFound
+	}
+	return revision, nil
+}
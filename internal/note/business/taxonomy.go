@@ -0,0 +1,150 @@
+package notebusiness
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	notemodel "github.com/khoaphungnguyen/go-openai/internal/note/model"
+)
+
+const (
+	// DefaultSearchLimit is the page size of note searches.
+	DefaultSearchLimit = 20
+	// MaxSearchLimit bounds the page size of note searches.
+	MaxSearchLimit = 100
+	// weakAreaWindowDays is the period of reviews used to find weak areas.
+	weakAreaWindowDays = 90
+	// weakAreaMinReviews is the number of reviews a category needs to be judged.
+	weakAreaMinReviews = 3
+	// weakAreaMaxGrade is the average grade below which a category counts as weak.
+	weakAreaMaxGrade = 3.5
+	// statsMonths is how many months of note creation the stats cover.
+	statsMonths = 12
+)
+
+var (
+	// ErrInvalidCategory is returned for categories outside the canonical list.
+	ErrInvalidCategory = errors.New("unknown category")
+	// ErrTooManyTags is returned when a note would carry more than MaxTagsPerNote tags.
+	ErrTooManyTags = fmt.Errorf("a note can have at most %d tags", notemodel.MaxTagsPerNote)
+	// ErrInvalidLevel is returned for levels other than Easy, Medium and Hard.
+	ErrInvalidLevel = errors.New("level must be Easy, Medium or Hard")
+)
+
+// SetTags replaces the tags of a note the user can edit and returns the normalized tags.
+func (ns *NoteService) SetTags(userID, noteID uuid.UUID, tags []string) ([]string, error) {
+	if !ns.noteRole(noteID, userID).CanWrite() {
+		return nil, ErrNoteNotFound
+	}
+	normalized, err := normalizeTags(tags)
+	if err != nil {
+		return nil, err
+	}
+	if err := ns.notestorage.ReplaceTags(noteID, normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+// GetTags lists the tags the user has used with their note counts.
+func (ns *NoteService) GetTags(userID uuid.UUID) ([]notemodel.TagCount, error) {
+	return ns.notestorage.GetTagCounts(userID)
+}
+
+// SearchNotes finds notes visible to the user by text, level, category and tags.
+func (ns *NoteService) SearchNotes(userID uuid.UUID, filter notemodel.NoteFilter) ([]*notemodel.Note, int64, error) {
+	if filter.Level != "" && !isValidLevel(filter.Level) {
+		return nil, 0, ErrInvalidLevel
+	}
+	if filter.Category != "" && !filter.Category.IsValid() {
+		return nil, 0, ErrInvalidCategory
+	}
+	filter.Tags = notemodel.NormalizeTags(filter.Tags)
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultSearchLimit
+	}
+	if filter.Limit > MaxSearchLimit {
+		filter.Limit = MaxSearchLimit
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	return ns.notestorage.SearchNotes(userID, filter)
+}
+
+// GetNoteStats reports the user's note counts by level, category and month,
+// and the categories where review grades are weakest.
+func (ns *NoteService) GetNoteStats(userID uuid.UUID) (*notemodel.NoteStats, error) {
+	stats := &notemodel.NoteStats{}
+
+	var err error
+	if stats.Total, err = ns.notestorage.CountNotes(userID); err != nil {
+		return nil, err
+	}
+	if stats.ByLevel, err = ns.notestorage.CountNotesBy(userID, "level"); err != nil {
+		return nil, err
+	}
+	if stats.ByCategory, err = ns.notestorage.CountNotesBy(userID, "category"); err != nil {
+		return nil, err
+	}
+	byMonth, err := ns.notestorage.CountNotesBy(userID, "to_char(date_trunc('month', created_at), 'YYYY-MM')")
+	if err != nil {
+		return nil, err
+	}
+	if len(byMonth) > statsMonths {
+		byMonth = byMonth[len(byMonth)-statsMonths:]
+	}
+	stats.ByMonth = byMonth
+
+	scores, err := ns.notestorage.GetCategoryScores(userID, time.Now().AddDate(0, 0, -weakAreaWindowDays))
+	if err != nil {
+		return nil, err
+	}
+	stats.WeakAreas = make([]notemodel.CategoryScore, 0, len(scores))
+	for _, score := range scores {
+		if score.Reviews >= weakAreaMinReviews && score.AverageGrade < weakAreaMaxGrade {
+			stats.WeakAreas = append(stats.WeakAreas, score)
+		}
+	}
+	return stats, nil
+}
+
+// applyTaxonomy validates the category and tags of a new note, deriving the
+// category from the free-form type when none is given.
+func applyTaxonomy(note *notemodel.Note) error {
+	if note.Category == "" {
+		note.Category = notemodel.NormalizeCategory(note.Type)
+	} else if !note.Category.IsValid() {
+		return ErrInvalidCategory
+	}
+
+	tags := make([]string, 0, len(note.Tags))
+	for _, tag := range note.Tags {
+		tags = append(tags, tag.Tag)
+	}
+	normalized, err := normalizeTags(tags)
+	if err != nil {
+		return err
+	}
+	note.Tags = note.Tags[:0]
+	for _, tag := range normalized {
+		note.Tags = append(note.Tags, notemodel.NoteTag{Tag: tag})
+	}
+	return nil
+}
+
+// normalizeTags normalizes tags and enforces the per-note limit.
+func normalizeTags(tags []string) ([]string, error) {
+	normalized := notemodel.NormalizeTags(tags)
+	if len(normalized) > notemodel.MaxTagsPerNote {
+		return nil, ErrTooManyTags
+	}
+	return normalized, nil
+}
+
+// isValidLevel reports whether level is one of the difficulty levels allowed by the schema.
+func isValidLevel(level string) bool {
+	return level == "Easy" || level == "Medium" || level == "Hard"
+}
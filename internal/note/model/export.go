@@ -0,0 +1,110 @@
+package notemodel
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ExportFormat is a file format notes can be exported to.
+type ExportFormat string
+
+const (
+	// ExportAnkiCSV is a comma-separated Anki deck.
+	ExportAnkiCSV ExportFormat = "anki-csv"
+	// ExportAnkiTSV is a tab-separated Anki deck.
+	ExportAnkiTSV ExportFormat = "anki-tsv"
+	// ExportMarkdown is a zip of Markdown files with YAML front matter.
+	ExportMarkdown ExportFormat = "markdown"
+	// ExportJSON is a JSON array of notes.
+	ExportJSON ExportFormat = "json"
+)
+
+// IsValid reports whether f is a supported export format.
+func (f ExportFormat) IsValid() bool {
+	switch f {
+	case ExportAnkiCSV, ExportAnkiTSV, ExportMarkdown, ExportJSON:
+		return true
+	}
+	return false
+}
+
+// FileName returns the download file name of an export taken at t.
+func (f ExportFormat) FileName(t time.Time) string {
+	base := "notes-" + t.Format("20060102")
+	switch f {
+	case ExportAnkiCSV:
+		return base + ".csv"
+	case ExportAnkiTSV:
+		return base + ".tsv"
+	case ExportMarkdown:
+		return base + ".zip"
+	default:
+		return base + ".json"
+	}
+}
+
+// ContentType returns the MIME type of the export.
+func (f ExportFormat) ContentType() string {
+	switch f {
+	case ExportAnkiCSV:
+		return "text/csv; charset=utf-8"
+	case ExportAnkiTSV:
+		return "text/tab-separated-values; charset=utf-8"
+	case ExportMarkdown:
+		return "application/zip"
+	default:
+		return "application/json"
+	}
+}
+
+// ExportedNote is the portable representation of a note used by the JSON
+// export and the front matter of Markdown files.
+type ExportedNote struct {
+	ID        uuid.UUID `json:"id" yaml:"id"`
+	Title     string    `json:"title" yaml:"title"`
+	Level     string    `json:"level" yaml:"level"`
+	Type      string    `json:"type" yaml:"type"`
+	Category  Category  `json:"category" yaml:"category"`
+	Tags      []string  `json:"tags" yaml:"tags"`
+	Problem   string    `json:"problem" yaml:"-"`
+	Approach  string    `json:"approach" yaml:"-"`
+	Solution  string    `json:"solution" yaml:"-"`
+	Code      string    `json:"code" yaml:"-"`
+	CreatedAt time.Time `json:"createdAt" yaml:"created"`
+	UpdatedAt time.Time `json:"updatedAt" yaml:"updated"`
+}
+
+// NewExportedNote converts a note, with its tags loaded, to its portable form.
+func NewExportedNote(note *Note) ExportedNote {
+	tags := make([]string, 0, len(note.Tags))
+	for _, tag := range note.Tags {
+		tags = append(tags, tag.Tag)
+	}
+	return ExportedNote{
+		ID:        note.ID,
+		Title:     note.Title,
+		Level:     note.Level,
+		Type:      note.Type,
+		Category:  note.Category,
+		Tags:      tags,
+		Problem:   note.Problem,
+		Approach:  note.Approach,
+		Solution:  note.Solution,
+		Code:      note.Code,
+		CreatedAt: note.CreatedAt,
+		UpdatedAt: note.UpdatedAt,
+	}
+}
+
+// ImportResult reports the outcome of importing a batch of notes.
+type ImportResult struct {
+	Imported []uuid.UUID   `json:"imported"`
+	Failed   []ImportError `json:"failed"`
+}
+
+// ImportError describes a file that could not be imported.
+type ImportError struct {
+	File  string `json:"file"`
+	Error string `json:"error"`
+}
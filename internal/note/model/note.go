@@ -5,20 +5,27 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 // Note represents a note created by a software engineer to solve a problem.
 type Note struct {
-	ID        uuid.UUID `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
-	UserID    uuid.UUID `gorm:"type:uuid"`
-	ThreadID  uuid.UUID `gorm:"type:uuid"`
-	Title     string    `gorm:"type:varchar(255);not null"`
-	Problem   string    `gorm:"type:text;not null"`
-	Approach  string    `gorm:"type:text;not null"`
-	Solution  string    `gorm:"type:text;not null"`
-	Code      string    `gorm:"type:text"`
-	Level     string    `gorm:"type:varchar(255);not null"`
-	Type      string    `gorm:"type:varchar(255);not null"`
-	CreatedAt time.Time `gorm:"default:now()"`
-	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+	ID          uuid.UUID  `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	UserID      uuid.UUID  `gorm:"type:uuid"`
+	ThreadID    uuid.UUID  `gorm:"type:uuid"`
+	WorkspaceID *uuid.UUID `gorm:"type:uuid;index"`
+	Title       string     `gorm:"type:varchar(255);not null"`
+	Problem     string     `gorm:"type:text;not null"`
+	Approach    string     `gorm:"type:text;not null"`
+	Solution    string     `gorm:"type:text;not null"`
+	Code        string     `gorm:"type:text"`
+	Level       string     `gorm:"type:varchar(255);not null"`
+	Type        string     `gorm:"type:varchar(255);not null"`
+	Category    Category   `gorm:"type:varchar(50);not null;default:other;index"`
+	Tags        []NoteTag  `gorm:"foreignKey:NoteID"`
+	CreatedAt   time.Time  `gorm:"default:now()"`
+	UpdatedAt   time.Time  `gorm:"autoUpdateTime"`
+	// DeletedAt marks a note as moved to the trash; trashed notes are
+	// hidden from all regular queries until restored or purged.
+	DeletedAt gorm.DeletedAt `gorm:"index"`
 }
@@ -0,0 +1,118 @@
+package notemodel
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultEaseFactor is the SM-2 ease factor of a note that was never reviewed.
+	DefaultEaseFactor = 2.5
+	// MinEaseFactor is the lowest ease factor SM-2 allows.
+	MinEaseFactor = 1.3
+	// MaxGrade is the best recall grade; grades below PassingGrade reset the schedule.
+	MaxGrade     = 5
+	PassingGrade = 3
+	// MatureInterval is the interval in days from which a note counts as learned.
+	MatureInterval = 21
+)
+
+// ReviewSchedule is a user's SM-2 review state for a note.
+type ReviewSchedule struct {
+	ID             uuid.UUID  `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
+	NoteID         uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_note_review_note_user" json:"noteId"`
+	UserID         uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_note_review_note_user" json:"userId"`
+	EaseFactor     float64    `gorm:"not null;default:2.5" json:"easeFactor"`
+	IntervalDays   int        `gorm:"not null;default:0" json:"intervalDays"`
+	Repetitions    int        `gorm:"not null;default:0" json:"repetitions"`
+	DueAt          time.Time  `gorm:"not null;index" json:"dueAt"`
+	LastReviewedAt *time.Time `json:"lastReviewedAt"`
+	CreatedAt      time.Time  `gorm:"default:now()" json:"createdAt"`
+	UpdatedAt      time.Time  `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+// TableName overrides the table name used by ReviewSchedule.
+func (ReviewSchedule) TableName() string {
+	return "note_review"
+}
+
+// NewReviewSchedule returns the schedule of a note that was never reviewed.
+func NewReviewSchedule(noteID, userID uuid.UUID, now time.Time) *ReviewSchedule {
+	return &ReviewSchedule{
+		NoteID:     noteID,
+		UserID:     userID,
+		EaseFactor: DefaultEaseFactor,
+		DueAt:      now,
+	}
+}
+
+// Apply updates the schedule with a recall grade from 0 to 5 using the SM-2 algorithm.
+func (s *ReviewSchedule) Apply(grade int, now time.Time) {
+	if grade >= PassingGrade {
+		switch s.Repetitions {
+		case 0:
+			s.IntervalDays = 1
+		case 1:
+			s.IntervalDays = 6
+		default:
+			s.IntervalDays = int(math.Round(float64(s.IntervalDays) * s.EaseFactor))
+		}
+		s.Repetitions++
+	} else {
+		s.Repetitions = 0
+		s.IntervalDays = 1
+	}
+
+	q := float64(MaxGrade - grade)
+	s.EaseFactor += 0.1 - q*(0.08+q*0.02)
+	if s.EaseFactor < MinEaseFactor {
+		s.EaseFactor = MinEaseFactor
+	}
+
+	reviewedAt := now
+	s.LastReviewedAt = &reviewedAt
+	s.DueAt = StartOfDay(now).AddDate(0, 0, s.IntervalDays)
+}
+
+// ReviewLog records a single graded review of a note.
+type ReviewLog struct {
+	ID           uuid.UUID `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
+	NoteID       uuid.UUID `gorm:"type:uuid;not null;index" json:"noteId"`
+	UserID       uuid.UUID `gorm:"type:uuid;not null;index" json:"userId"`
+	Grade        int       `gorm:"not null" json:"grade"`
+	IntervalDays int       `gorm:"not null" json:"intervalDays"`
+	EaseFactor   float64   `gorm:"not null" json:"easeFactor"`
+	ReviewedAt   time.Time `gorm:"default:now()" json:"reviewedAt"`
+}
+
+// TableName overrides the table name used by ReviewLog.
+func (ReviewLog) TableName() string {
+	return "note_review_log"
+}
+
+// DueNote is a note in the review queue with its schedule, if it has one.
+type DueNote struct {
+	Note     *Note           `json:"note"`
+	Schedule *ReviewSchedule `json:"schedule"`
+}
+
+// ReviewStats summarizes a user's review activity.
+type ReviewStats struct {
+	DueToday      int64   `json:"dueToday"`
+	Scheduled     int64   `json:"scheduled"`
+	Mature        int64   `json:"mature"`
+	TotalReviews  int64   `json:"totalReviews"`
+	RecentReviews int64   `json:"recentReviews"`
+	RetentionRate float64 `json:"retentionRate"`
+	CurrentStreak int     `json:"currentStreak"`
+	LongestStreak int     `json:"longestStreak"`
+	ReviewedToday bool    `json:"reviewedToday"`
+	RetentionDays int     `json:"retentionWindowDays"`
+}
+
+// StartOfDay truncates t to midnight UTC.
+func StartOfDay(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
@@ -0,0 +1,51 @@
+package notemodel
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestReviewScheduleApply(t *testing.T) {
+	now := time.Date(2026, 3, 10, 15, 4, 5, 0, time.UTC)
+	today := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name              string
+		repetitions, days int
+		ease              float64
+		grade             int
+		wantRepetitions   int
+		wantDays          int
+		wantEase          float64
+	}{
+		{"first perfect recall", 0, 0, 2.5, 5, 1, 1, 2.6},
+		{"second recall", 1, 1, 2.5, 4, 2, 6, 2.5},
+		{"third recall grows by the old ease", 2, 6, 2.5, 3, 3, 15, 2.36},
+		{"lapse resets the interval", 3, 15, 2.36, 2, 0, 1, 2.04},
+		{"blackout keeps the minimum ease", 4, 40, MinEaseFactor, 0, 0, 1, MinEaseFactor},
+		{"mature note", 5, 100, MinEaseFactor, 5, 6, 130, MinEaseFactor + 0.1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewReviewSchedule(uuid.New(), uuid.New(), now)
+			s.Repetitions, s.IntervalDays, s.EaseFactor = tt.repetitions, tt.days, tt.ease
+			s.Apply(tt.grade, now)
+
+			if s.Repetitions != tt.wantRepetitions || s.IntervalDays != tt.wantDays {
+				t.Errorf("repetitions, interval = %d, %d; want %d, %d", s.Repetitions, s.IntervalDays, tt.wantRepetitions, tt.wantDays)
+			}
+			if math.Abs(s.EaseFactor-tt.wantEase) > 1e-9 {
+				t.Errorf("ease factor = %v, want %v", s.EaseFactor, tt.wantEase)
+			}
+			if want := today.AddDate(0, 0, tt.wantDays); !s.DueAt.Equal(want) {
+				t.Errorf("due at %v, want %v", s.DueAt, want)
+			}
+			if s.LastReviewedAt == nil || !s.LastReviewedAt.Equal(now) {
+				t.Errorf("last reviewed at %v, want %v", s.LastReviewedAt, now)
+			}
+		})
+	}
+}
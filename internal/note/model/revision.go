@@ -0,0 +1,65 @@
+package notemodel
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NoteRevision is an immutable snapshot of a note's content after a change.
+type NoteRevision struct {
+	ID           uuid.UUID  `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	NoteID       uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_note_revision_note_version"`
+	Version      int        `gorm:"not null;uniqueIndex:idx_note_revision_note_version"`
+	EditorID     *uuid.UUID `gorm:"type:uuid"`
+	RestoredFrom *int
+	Title        string    `gorm:"type:varchar(255)"`
+	Problem      string    `gorm:"type:text"`
+	Approach     string    `gorm:"type:text"`
+	Solution     string    `gorm:"type:text"`
+	Code         string    `gorm:"type:text"`
+	Level        string    `gorm:"type:varchar(50)"`
+	Type         string    `gorm:"type:text"`
+	CreatedAt    time.Time `gorm:"default:now()"`
+}
+
+// TableName overrides the table name used by NoteRevision.
+func (NoteRevision) TableName() string {
+	return "note_revision"
+}
+
+// RevisionFields lists the note fields tracked by revisions, in display order.
+var RevisionFields = []string{"title", "problem", "approach", "solution", "code", "level", "type"}
+
+// Fields returns the tracked content of the revision keyed by column name.
+func (r *NoteRevision) Fields() map[string]string {
+	return map[string]string{
+		"title":    r.Title,
+		"problem":  r.Problem,
+		"approach": r.Approach,
+		"solution": r.Solution,
+		"code":     r.Code,
+		"level":    r.Level,
+		"type":     r.Type,
+	}
+}
+
+// NewNoteRevision snapshots the content of a note.
+func NewNoteRevision(note *Note, version int, editorID uuid.UUID, restoredFrom *int) *NoteRevision {
+	revision := &NoteRevision{
+		NoteID:       note.ID,
+		Version:      version,
+		RestoredFrom: restoredFrom,
+		Title:        note.Title,
+		Problem:      note.Problem,
+		Approach:     note.Approach,
+		Solution:     note.Solution,
+		Code:         note.Code,
+		Level:        note.Level,
+		Type:         note.Type,
+	}
+	if editorID != uuid.Nil {
+		revision.EditorID = &editorID
+	}
+	return revision
+}
@@ -0,0 +1,187 @@
+package notemodel
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Category is a canonical problem topic used to group notes.
+type Category string
+
+const (
+	CategoryArrays             Category = "arrays"
+	CategoryStrings            Category = "strings"
+	CategoryHashTable          Category = "hash-table"
+	CategoryTwoPointers        Category = "two-pointers"
+	CategorySlidingWindow      Category = "sliding-window"
+	CategoryStack              Category = "stack"
+	CategoryLinkedList         Category = "linked-list"
+	CategoryTrees              Category = "trees"
+	CategoryGraphs             Category = "graphs"
+	CategoryHeap               Category = "heap"
+	CategoryBinarySearch       Category = "binary-search"
+	CategoryDynamicProgramming Category = "dynamic-programming"
+	CategoryGreedy             Category = "greedy"
+	CategoryBacktracking       Category = "backtracking"
+	CategorySorting            Category = "sorting"
+	CategoryMath               Category = "math"
+	CategoryBitManipulation    Category = "bit-manipulation"
+	CategoryDesign             Category = "design"
+	CategoryOther              Category = "other"
+)
+
+// CategoryInfo describes a canonical category for clients.
+type CategoryInfo struct {
+	Slug  Category `json:"slug"`
+	Label string   `json:"label"`
+}
+
+// Categories lists the canonical categories in display order.
+var Categories = []CategoryInfo{
+	{CategoryArrays, "Arrays"},
+	{CategoryStrings, "Strings"},
+	{CategoryHashTable, "Hash Table"},
+	{CategoryTwoPointers, "Two Pointers"},
+	{CategorySlidingWindow, "Sliding Window"},
+	{CategoryStack, "Stack & Queue"},
+	{CategoryLinkedList, "Linked List"},
+	{CategoryTrees, "Trees"},
+	{CategoryGraphs, "Graphs"},
+	{CategoryHeap, "Heap"},
+	{CategoryBinarySearch, "Binary Search"},
+	{CategoryDynamicProgramming, "Dynamic Programming"},
+	{CategoryGreedy, "Greedy"},
+	{CategoryBacktracking, "Backtracking"},
+	{CategorySorting, "Sorting"},
+	{CategoryMath, "Math"},
+	{CategoryBitManipulation, "Bit Manipulation"},
+	{CategoryDesign, "Design"},
+	{CategoryOther, "Other"},
+}
+
+// categoryAliases maps common free-form topic names to canonical categories.
+var categoryAliases = map[string]Category{
+	"array": CategoryArrays, "matrix": CategoryArrays, "prefix-sum": CategoryArrays,
+	"string": CategoryStrings,
+	"hash":   CategoryHashTable, "hashmap": CategoryHashTable, "hash-map": CategoryHashTable, "hashing": CategoryHashTable,
+	"two-pointer": CategoryTwoPointers,
+	"queue":       CategoryStack, "monotonic-stack": CategoryStack, "stacks": CategoryStack,
+	"linked-lists": CategoryLinkedList, "list": CategoryLinkedList,
+	"tree": CategoryTrees, "binary-tree": CategoryTrees, "bst": CategoryTrees, "binary-search-tree": CategoryTrees, "trie": CategoryTrees,
+	"graph": CategoryGraphs, "bfs": CategoryGraphs, "dfs": CategoryGraphs, "union-find": CategoryGraphs, "topological-sort": CategoryGraphs, "shortest-path": CategoryGraphs,
+	"heaps": CategoryHeap, "priority-queue": CategoryHeap,
+	"dp": CategoryDynamicProgramming, "dynamic-programing": CategoryDynamicProgramming, "memoization": CategoryDynamicProgramming,
+	"backtrack": CategoryBacktracking, "recursion": CategoryBacktracking,
+	"sort":          CategorySorting,
+	"number-theory": CategoryMath, "geometry": CategoryMath,
+	"bits": CategoryBitManipulation, "bitmask": CategoryBitManipulation,
+	"system-design": CategoryDesign, "object-oriented-design": CategoryDesign,
+}
+
+var slugPattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Slugify lowercases s and joins its words with dashes.
+func Slugify(s string) string {
+	return strings.Trim(slugPattern.ReplaceAllString(strings.ToLower(s), "-"), "-")
+}
+
+// IsValid reports whether c is a canonical category.
+func (c Category) IsValid() bool {
+	for _, info := range Categories {
+		if info.Slug == c {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeCategory maps a category slug, label or common alias to its
+// canonical category. Unknown topics map to CategoryOther.
+func NormalizeCategory(topic string) Category {
+	slug := Slugify(topic)
+	if c := Category(slug); c.IsValid() {
+		return c
+	}
+	if c, ok := categoryAliases[slug]; ok {
+		return c
+	}
+	for _, info := range Categories {
+		if Slugify(info.Label) == slug {
+			return info.Slug
+		}
+	}
+	return CategoryOther
+}
+
+// MaxTagsPerNote bounds how many tags a note can carry.
+const MaxTagsPerNote = 20
+
+// NoteTag is a user-defined label attached to a note.
+type NoteTag struct {
+	NoteID uuid.UUID `gorm:"primaryKey;type:uuid"`
+	Tag    string    `gorm:"primaryKey;type:varchar(50)"`
+}
+
+// TableName overrides the table name used by NoteTag.
+func (NoteTag) TableName() string {
+	return "note_tag"
+}
+
+// NormalizeTags slugifies tags, dropping empty and duplicate ones.
+func NormalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		slug := Slugify(tag)
+		if len(slug) > 50 {
+			slug = strings.Trim(slug[:50], "-")
+		}
+		if slug == "" || seen[slug] {
+			continue
+		}
+		seen[slug] = true
+		normalized = append(normalized, slug)
+	}
+	return normalized
+}
+
+// TagCount is a tag with the number of notes carrying it.
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int64  `json:"count"`
+}
+
+// NoteFilter narrows a note search. Empty fields do not filter.
+type NoteFilter struct {
+	Query    string
+	Level    string
+	Category Category
+	Tags     []string
+	Limit    int
+	Offset   int
+}
+
+// NoteStats summarizes a user's notes.
+type NoteStats struct {
+	Total      int64           `json:"total"`
+	ByLevel    []GroupCount    `json:"byLevel"`
+	ByCategory []GroupCount    `json:"byCategory"`
+	ByMonth    []GroupCount    `json:"byMonth"`
+	WeakAreas  []CategoryScore `json:"weakAreas"`
+}
+
+// GroupCount is the number of notes in a group.
+type GroupCount struct {
+	Key   string `json:"key"`
+	Count int64  `json:"count"`
+}
+
+// CategoryScore aggregates review grades of the notes in a category.
+type CategoryScore struct {
+	Category     Category `json:"category"`
+	Reviews      int64    `json:"reviews"`
+	AverageGrade float64  `json:"averageGrade"`
+	LapseRate    float64  `json:"lapseRate"`
+}
@@ -2,21 +2,32 @@ package notestorage
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	notemodel "github.com/khoaphungnguyen/go-openai/internal/note/model"
 	"gorm.io/gorm"
 )
 
-// CreateNote adds a new note to the database.
+// CreateNote adds a new note to the database together with its first revision.
 func (ns *noteStore) CreateNote(note *notemodel.Note) error {
-	return ns.db.Create(note).Error
+	return ns.db.Transaction(func(tx *gorm.DB) error {
+		create := tx
+		if note.ThreadID == uuid.Nil {
+			// Imported notes have no source thread; store NULL rather than a dangling ID
+			create = tx.Omit("ThreadID")
+		}
+		if err := create.Create(note).Error; err != nil {
+			return err
+		}
+		return tx.Create(notemodel.NewNoteRevision(note, 1, note.UserID, nil)).Error
+	})
 }
 
-// GetAllNotes retrieves all notes for a specific user.
+// GetAllNotes retrieves all notes for a specific user with their tags.
 func (ns *noteStore) GetAllNotes(userID uuid.UUID) ([]*notemodel.Note, error) {
 	var notes []*notemodel.Note
-	err := ns.db.Where("user_id = ?", userID).Order("updated_at DESC").Find(&notes).Error
+	err := ns.db.Preload("Tags").Where("user_id = ?", userID).Order("updated_at DESC").Find(&notes).Error
 	return notes, err
 }
 
@@ -30,7 +41,7 @@ func (ns *noteStore) GetNotesByUserID(userID uuid.UUID, limit, offset int) ([]*n
 // GetNoteByID retrieves a note by its ID.
 func (ns *noteStore) GetNoteByID(noteID uuid.UUID) (*notemodel.Note, error) {
 	var note notemodel.Note
-	err := ns.db.First(&note, "id = ?", noteID).Error
+	err := ns.db.Preload("Tags").First(&note, "id = ?", noteID).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, nil // Note does not exist
@@ -47,7 +58,7 @@ func (ns *noteStore) IsUserNoteOwner(noteID, userID uuid.UUID) bool {
 	return count > 0
 }
 
-// DeleteNote deletes a note.
+// DeleteNote moves a note to the trash.
 func (ns *noteStore) DeleteNote(noteID uuid.UUID, userID uuid.UUID) error {
 	return ns.db.Where("id = ? AND user_id = ?", noteID, userID).Delete(&notemodel.Note{}).Error
 }
@@ -72,7 +83,56 @@ func (ns *noteStore) CheckNoteExistsAndBelongsToUser(noteID, userID uuid.UUID) (
 	return count > 0, nil
 }
 
-// UpdateNote updates a note in the database.
-func (ns *noteStore) UpdateNoteByID(noteID uuid.UUID, note *notemodel.Note) error {
-	return ns.db.Model(&notemodel.Note{}).Where("id = ?", noteID).Updates(note).Error
+// UpdateNote updates a note in the database and records the result as a new revision.
+func (ns *noteStore) UpdateNoteByID(noteID, editorID uuid.UUID, note *notemodel.Note) error {
+	return ns.updateWithRevision(noteID, editorID, note, nil)
+}
+
+// GetNotesByWorkspaceID retrieves all notes that belong to a workspace.
+func (ns *noteStore) GetNotesByWorkspaceID(workspaceID uuid.UUID) ([]*notemodel.Note, error) {
+	var notes []*notemodel.Note
+	err := ns.db.Where("workspace_id = ?", workspaceID).Order("updated_at DESC").Find(&notes).Error
+	return notes, err
+}
+
+// GetNoteByThreadID retrieves the most recently updated note linked to a thread.
+func (ns *noteStore) GetNoteByThreadID(threadID uuid.UUID) (*notemodel.Note, error) {
+	var note notemodel.Note
+	err := ns.db.Where("thread_id = ?", threadID).Order("updated_at DESC").First(&note).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil // No note is linked to the thread
+		}
+		return nil, fmt.Errorf("failed to retrieve note: %w", err)
+	}
+	return &note, nil
+}
+
+// GetDeletedNotesByUserID retrieves the trashed notes of a user, most recently deleted first.
+func (ns *noteStore) GetDeletedNotesByUserID(userID uuid.UUID) ([]*notemodel.Note, error) {
+	var notes []*notemodel.Note
+	err := ns.db.Unscoped().Where("user_id = ? AND deleted_at IS NOT NULL", userID).Order("deleted_at DESC").Find(&notes).Error
+	return notes, err
+}
+
+// RestoreNote moves a trashed note back out of the trash. It reports whether a note was restored.
+func (ns *noteStore) RestoreNote(noteID, userID uuid.UUID) (bool, error) {
+	result := ns.db.Unscoped().Model(&notemodel.Note{}).
+		Where("id = ? AND user_id = ? AND deleted_at IS NOT NULL", noteID, userID).
+		Update("deleted_at", gorm.Expr("NULL"))
+	return result.RowsAffected > 0, result.Error
+}
+
+// PurgeNote permanently deletes a trashed note. It reports whether a note was purged.
+func (ns *noteStore) PurgeNote(noteID, userID uuid.UUID) (bool, error) {
+	result := ns.db.Unscoped().
+		Where("id = ? AND user_id = ? AND deleted_at IS NOT NULL", noteID, userID).
+		Delete(&notemodel.Note{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// PurgeNotesDeletedBefore permanently deletes all notes trashed before the cutoff.
+func (ns *noteStore) PurgeNotesDeletedBefore(cutoff time.Time) (int64, error) {
+	result := ns.db.Unscoped().Where("deleted_at < ?", cutoff).Delete(&notemodel.Note{})
+	return result.RowsAffected, result.Error
 }
@@ -0,0 +1,129 @@
+package notestorage
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	notemodel "github.com/khoaphungnguyen/go-openai/internal/note/model"
+	"gorm.io/gorm"
+)
+
+// GetReviewSchedule retrieves a user's review schedule for a note.
+func (ns *noteStore) GetReviewSchedule(noteID, userID uuid.UUID) (*notemodel.ReviewSchedule, error) {
+	var schedule notemodel.ReviewSchedule
+	err := ns.db.Where("note_id = ? AND user_id = ?", noteID, userID).First(&schedule).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil // Note was never reviewed
+		}
+		return nil, fmt.Errorf("failed to retrieve review schedule: %w", err)
+	}
+	return &schedule, nil
+}
+
+// SaveReview stores an updated review schedule together with the review that produced it.
+func (ns *noteStore) SaveReview(schedule *notemodel.ReviewSchedule, log *notemodel.ReviewLog) error {
+	return ns.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(schedule).Error; err != nil {
+			return err
+		}
+		return tx.Create(log).Error
+	})
+}
+
+// GetDueNotes retrieves the notes due for review by the user before until, most
+// overdue first. Own notes that were never reviewed are always due.
+func (ns *noteStore) GetDueNotes(userID uuid.UUID, until time.Time, limit int) ([]notemodel.DueNote, error) {
+	var notes []*notemodel.Note
+	err := ns.dueNotesQuery(userID, until).
+		Select("notes.*").
+		Order("COALESCE(r.due_at, notes.created_at) ASC").
+		Limit(limit).
+		Find(&notes).Error
+	if err != nil || len(notes) == 0 {
+		return nil, err
+	}
+
+	noteIDs := make([]uuid.UUID, 0, len(notes))
+	for _, note := range notes {
+		noteIDs = append(noteIDs, note.ID)
+	}
+	var schedules []notemodel.ReviewSchedule
+	if err := ns.db.Where("note_id IN ? AND user_id = ?", noteIDs, userID).Find(&schedules).Error; err != nil {
+		return nil, err
+	}
+	byNote := make(map[uuid.UUID]*notemodel.ReviewSchedule, len(schedules))
+	for i := range schedules {
+		byNote[schedules[i].NoteID] = &schedules[i]
+	}
+
+	due := make([]notemodel.DueNote, 0, len(notes))
+	for _, note := range notes {
+		due = append(due, notemodel.DueNote{Note: note, Schedule: byNote[note.ID]})
+	}
+	return due, nil
+}
+
+// CountDueNotes counts the notes due for review by the user before until.
+func (ns *noteStore) CountDueNotes(userID uuid.UUID, until time.Time) (int64, error) {
+	var count int64
+	err := ns.dueNotesQuery(userID, until).Count(&count).Error
+	return count, err
+}
+
+// dueNotesQuery selects the notes visible to the user, joined with their
+// schedule, that are due before until. Schedules of notes in workspaces the
+// user has left are ignored.
+func (ns *noteStore) dueNotesQuery(userID uuid.UUID, until time.Time) *gorm.DB {
+	return ns.db.Model(&notemodel.Note{}).
+		Joins("LEFT JOIN note_review r ON r.note_id = notes.id AND r.user_id = ?", userID).
+		Where("(notes.user_id = ? OR notes.workspace_id IN (SELECT workspace_id FROM workspace_member WHERE user_id = ?))", userID, userID).
+		Where("((notes.user_id = ? AND r.id IS NULL) OR (r.id IS NOT NULL AND r.due_at <= ?))", userID, until)
+}
+
+// CountReviewSchedules counts the notes the user has scheduled and how many of them are mature.
+func (ns *noteStore) CountReviewSchedules(userID uuid.UUID) (int64, int64, error) {
+	var counts struct {
+		Scheduled int64
+		Mature    int64
+	}
+	err := ns.db.Model(&notemodel.ReviewSchedule{}).
+		Select("COUNT(*) AS scheduled, COUNT(*) FILTER (WHERE interval_days >= ?) AS mature", notemodel.MatureInterval).
+		Joins("JOIN notes ON notes.id = note_review.note_id AND notes.deleted_at IS NULL").
+		Where("note_review.user_id = ?", userID).
+		Scan(&counts).Error
+	return counts.Scheduled, counts.Mature, err
+}
+
+// CountReviews counts the user's reviews since the given time and how many of them passed.
+func (ns *noteStore) CountReviews(userID uuid.UUID, since time.Time) (int64, int64, error) {
+	var counts struct {
+		Total  int64
+		Passed int64
+	}
+	err := ns.db.Model(&notemodel.ReviewLog{}).
+		Select("COUNT(*) AS total, COUNT(*) FILTER (WHERE grade >= ?) AS passed", notemodel.PassingGrade).
+		Where("user_id = ? AND reviewed_at >= ?", userID, since).
+		Scan(&counts).Error
+	return counts.Total, counts.Passed, err
+}
+
+// GetReviewDays retrieves the distinct UTC days on which the user reviewed notes, most recent first.
+func (ns *noteStore) GetReviewDays(userID uuid.UUID) ([]time.Time, error) {
+	var days []time.Time
+	err := ns.db.Model(&notemodel.ReviewLog{}).
+		Select("DISTINCT date_trunc('day', reviewed_at AT TIME ZONE 'UTC') AS day").
+		Where("user_id = ?", userID).
+		Order("day DESC").
+		Pluck("day", &days).Error
+	return days, err
+}
+
+// GetReviewLogs retrieves the most recent reviews of a note by the user.
+func (ns *noteStore) GetReviewLogs(noteID, userID uuid.UUID, limit int) ([]notemodel.ReviewLog, error) {
+	var logs []notemodel.ReviewLog
+	err := ns.db.Where("note_id = ? AND user_id = ?", noteID, userID).Order("reviewed_at DESC").Limit(limit).Find(&logs).Error
+	return logs, err
+}
@@ -0,0 +1,93 @@
+package notestorage
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	notemodel "github.com/khoaphungnguyen/go-openai/internal/note/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetRevisions retrieves the revisions of a note, newest first, without their content.
+func (ns *noteStore) GetRevisions(noteID uuid.UUID) ([]notemodel.NoteRevision, error) {
+	var revisions []notemodel.NoteRevision
+	err := ns.db.Select("id, note_id, version, editor_id, restored_from, title, created_at").
+		Where("note_id = ?", noteID).Order("version DESC").Find(&revisions).Error
+	return revisions, err
+}
+
+// GetRevision retrieves a single revision of a note.
+func (ns *noteStore) GetRevision(noteID uuid.UUID, version int) (*notemodel.NoteRevision, error) {
+	var revision notemodel.NoteRevision
+	err := ns.db.Where("note_id = ? AND version = ?", noteID, version).First(&revision).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil // Revision does not exist
+		}
+		return nil, fmt.Errorf("failed to retrieve revision: %w", err)
+	}
+	return &revision, nil
+}
+
+// RestoreRevision overwrites the note with the content of a revision, recorded as a new revision.
+func (ns *noteStore) RestoreRevision(noteID, editorID uuid.UUID, revision *notemodel.NoteRevision) error {
+	updates := map[string]interface{}{
+		"title":    revision.Title,
+		"problem":  revision.Problem,
+		"approach": revision.Approach,
+		"solution": revision.Solution,
+		"code":     revision.Code,
+		"level":    revision.Level,
+		"type":     revision.Type,
+	}
+	return ns.updateWithRevision(noteID, editorID, updates, &revision.Version)
+}
+
+// updateWithRevision applies updates to a note and snapshots the result in the
+// same transaction. Notes written before revisions existed get their original
+// content recorded as version 1 first. Updates that change nothing add no revision.
+func (ns *noteStore) updateWithRevision(noteID, editorID uuid.UUID, updates interface{}, restoredFrom *int) error {
+	return ns.db.Transaction(func(tx *gorm.DB) error {
+		var note notemodel.Note
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&note, "id = ?", noteID).Error; err != nil {
+			return err
+		}
+
+		var latest notemodel.NoteRevision
+		err := tx.Where("note_id = ?", noteID).Order("version DESC").First(&latest).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			latest = *notemodel.NewNoteRevision(&note, 1, note.UserID, nil)
+			if err := tx.Create(&latest).Error; err != nil {
+				return err
+			}
+		} else if err != nil {
+			return err
+		}
+
+		if err := tx.Model(&notemodel.Note{}).Where("id = ?", noteID).Updates(updates).Error; err != nil {
+			return err
+		}
+		if err := tx.First(&note, "id = ?", noteID).Error; err != nil {
+			return err
+		}
+
+		revision := notemodel.NewNoteRevision(&note, latest.Version+1, editorID, restoredFrom)
+		if sameContent(&latest, revision) {
+			return nil
+		}
+		return tx.Create(revision).Error
+	})
+}
+
+// sameContent reports whether two revisions hold identical note content.
+func sameContent(a, b *notemodel.NoteRevision) bool {
+	fa, fb := a.Fields(), b.Fields()
+	for _, field := range notemodel.RevisionFields {
+		if fa[field] != fb[field] {
+			return false
+		}
+	}
+	return true
+}
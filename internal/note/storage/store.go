@@ -2,6 +2,8 @@
 package notestorage
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	notemodel "github.com/khoaphungnguyen/go-openai/internal/note/model"
 	"gorm.io/gorm"
@@ -17,7 +19,34 @@ type NoteStore interface {
 	IsUserNoteOwner(noteID, userID uuid.UUID) bool
 	DeleteNote(noteID uuid.UUID, userID uuid.UUID) error
 	CheckNoteExistsAndBelongsToUser(noteID, userID uuid.UUID) (bool, error)
-	UpdateNoteByID(noteID uuid.UUID, note *notemodel.Note) error
+	UpdateNoteByID(noteID, editorID uuid.UUID, note *notemodel.Note) error
+	GetNotesByWorkspaceID(workspaceID uuid.UUID) ([]*notemodel.Note, error)
+	GetNoteByThreadID(threadID uuid.UUID) (*notemodel.Note, error)
+
+	GetDeletedNotesByUserID(userID uuid.UUID) ([]*notemodel.Note, error)
+	RestoreNote(noteID, userID uuid.UUID) (bool, error)
+	PurgeNote(noteID, userID uuid.UUID) (bool, error)
+	PurgeNotesDeletedBefore(cutoff time.Time) (int64, error)
+
+	GetReviewSchedule(noteID, userID uuid.UUID) (*notemodel.ReviewSchedule, error)
+	SaveReview(schedule *notemodel.ReviewSchedule, log *notemodel.ReviewLog) error
+	GetDueNotes(userID uuid.UUID, until time.Time, limit int) ([]notemodel.DueNote, error)
+	CountDueNotes(userID uuid.UUID, until time.Time) (int64, error)
+	CountReviewSchedules(userID uuid.UUID) (int64, int64, error)
+	CountReviews(userID uuid.UUID, since time.Time) (int64, int64, error)
+	GetReviewDays(userID uuid.UUID) ([]time.Time, error)
+	GetReviewLogs(noteID, userID uuid.UUID, limit int) ([]notemodel.ReviewLog, error)
+
+	GetRevisions(noteID uuid.UUID) ([]notemodel.NoteRevision, error)
+	GetRevision(noteID uuid.UUID, version int) (*notemodel.NoteRevision, error)
+	RestoreRevision(noteID, editorID uuid.UUID, revision *notemodel.NoteRevision) error
+
+	ReplaceTags(noteID uuid.UUID, tags []string) error
+	GetTagCounts(userID uuid.UUID) ([]notemodel.TagCount, error)
+	SearchNotes(userID uuid.UUID, filter notemodel.NoteFilter) ([]*notemodel.Note, int64, error)
+	CountNotes(userID uuid.UUID) (int64, error)
+	CountNotesBy(userID uuid.UUID, keyExpr string) ([]notemodel.GroupCount, error)
+	GetCategoryScores(userID uuid.UUID, since time.Time) ([]notemodel.CategoryScore, error)
 }
 
 // noteStore encapsulates the logic for storing and retrieving note data.
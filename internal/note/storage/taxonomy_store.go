@@ -0,0 +1,108 @@
+package notestorage
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/khoaphungnguyen/go-openai/internal/common"
+	notemodel "github.com/khoaphungnguyen/go-openai/internal/note/model"
+	"gorm.io/gorm"
+)
+
+// ReplaceTags sets the tags of a note, removing any it had before.
+func (ns *noteStore) ReplaceTags(noteID uuid.UUID, tags []string) error {
+	return ns.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("note_id = ?", noteID).Delete(&notemodel.NoteTag{}).Error; err != nil {
+			return err
+		}
+		if len(tags) == 0 {
+			return nil
+		}
+		rows := make([]notemodel.NoteTag, 0, len(tags))
+		for _, tag := range tags {
+			rows = append(rows, notemodel.NoteTag{NoteID: noteID, Tag: tag})
+		}
+		return tx.Create(&rows).Error
+	})
+}
+
+// GetTagCounts retrieves the tags used on the user's notes with their note counts.
+func (ns *noteStore) GetTagCounts(userID uuid.UUID) ([]notemodel.TagCount, error) {
+	var counts []notemodel.TagCount
+	err := ns.db.Model(&notemodel.NoteTag{}).
+		Select("note_tag.tag AS tag, COUNT(*) AS count").
+		Joins("JOIN notes ON notes.id = note_tag.note_id AND notes.deleted_at IS NULL").
+		Where("notes.user_id = ?", userID).
+		Group("note_tag.tag").
+		Order("count DESC, tag ASC").
+		Scan(&counts).Error
+	return counts, err
+}
+
+// SearchNotes retrieves the notes visible to the user that match the filter,
+// best text matches first when searching, together with the total match count.
+func (ns *noteStore) SearchNotes(userID uuid.UUID, filter notemodel.NoteFilter) ([]*notemodel.Note, int64, error) {
+	query := ns.db.Model(&notemodel.Note{}).
+		Where("(notes.user_id = ? OR notes.workspace_id IN (SELECT workspace_id FROM workspace_member WHERE user_id = ?))", userID, userID)
+	if filter.Level != "" {
+		query = query.Where("notes.level = ?", filter.Level)
+	}
+	if filter.Category != "" {
+		query = query.Where("notes.category = ?", filter.Category)
+	}
+	if len(filter.Tags) > 0 {
+		// Notes must carry every requested tag
+		query = query.Where("notes.id IN (SELECT note_id FROM note_tag WHERE tag IN ? GROUP BY note_id HAVING COUNT(*) = ?)", filter.Tags, len(filter.Tags))
+	}
+	if filter.Query != "" {
+		query = query.Where("notes.search_vector @@ websearch_to_tsquery('english', ?)", filter.Query)
+	}
+
+	total, page, err := common.Paginate(query, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	if filter.Query != "" {
+		page = page.Order(gorm.Expr("ts_rank(notes.search_vector, websearch_to_tsquery('english', ?)) DESC", filter.Query))
+	}
+	var notes []*notemodel.Note
+	err = page.Order("notes.updated_at DESC").
+		Preload("Tags").
+		Find(&notes).Error
+	return notes, total, err
+}
+
+// CountNotes counts the user's notes.
+func (ns *noteStore) CountNotes(userID uuid.UUID) (int64, error) {
+	var count int64
+	err := ns.db.Model(&notemodel.Note{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+// CountNotesBy counts the user's notes grouped by a key expression, largest groups first.
+func (ns *noteStore) CountNotesBy(userID uuid.UUID, keyExpr string) ([]notemodel.GroupCount, error) {
+	var counts []notemodel.GroupCount
+	err := ns.db.Model(&notemodel.Note{}).
+		Select(keyExpr+" AS key, COUNT(*) AS count").
+		Where("user_id = ?", userID).
+		Group("key").
+		Order("key ASC").
+		Scan(&counts).Error
+	return counts, err
+}
+
+// GetCategoryScores aggregates the user's review grades since the given time per note category.
+func (ns *noteStore) GetCategoryScores(userID uuid.UUID, since time.Time) ([]notemodel.CategoryScore, error) {
+	var scores []notemodel.CategoryScore
+	err := ns.db.Model(&notemodel.ReviewLog{}).
+		Select(`notes.category AS category,
+			COUNT(*) AS reviews,
+			AVG(note_review_log.grade) AS average_grade,
+			AVG(CASE WHEN note_review_log.grade < ? THEN 1.0 ELSE 0.0 END) AS lapse_rate`, notemodel.PassingGrade).
+		Joins("JOIN notes ON notes.id = note_review_log.note_id AND notes.deleted_at IS NULL").
+		Where("note_review_log.user_id = ? AND note_review_log.reviewed_at >= ?", userID, since).
+		Group("notes.category").
+		Order("average_grade ASC").
+		Scan(&scores).Error
+	return scores, err
+}
@@ -0,0 +1,88 @@
+package notetransport
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	auditmodel "github.com/khoaphungnguyen/go-openai/internal/audit/model"
+	audittransport "github.com/khoaphungnguyen/go-openai/internal/audit/transport"
+	"github.com/khoaphungnguyen/go-openai/internal/common"
+	notebusiness "github.com/khoaphungnguyen/go-openai/internal/note/business"
+	notemodel "github.com/khoaphungnguyen/go-openai/internal/note/model"
+)
+
+// ExportNotes streams the caller's notes as an Anki deck (format=anki-csv or
+// anki-tsv), a zip of Markdown files (format=markdown) or JSON (format=json, the default).
+func (nh *NoteHandler) ExportNotes(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	format := notemodel.ExportFormat(c.DefaultQuery("format", string(notemodel.ExportJSON)))
+	if !format.IsValid() {
+		respondWithError(c, http.StatusBadRequest, notebusiness.ErrInvalidExportFormat.Error())
+		return
+	}
+
+	nh.auditService.Record(c.Request.Context(), audittransport.NewEntry(c, auditmodel.ActionNotesExported, auditmodel.TargetUser, userID).
+		Set("format", format))
+
+	c.Header("Content-Type", format.ContentType())
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, format.FileName(time.Now())))
+	c.Status(http.StatusOK)
+	if err := nh.noteService.ExportNotes(userID, format, c.Writer); err != nil {
+		// Headers are already sent, so the partial export can only be logged
+		log.Printf("Error exporting notes: %v", err)
+	}
+}
+
+// ImportNotes handles a multipart upload of a Markdown file or a zip of
+// Markdown files in the "file" part, creating a note for each file.
+func (nh *NoteHandler) ImportNotes(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.LimitMultipartBody(c, notebusiness.MaxImportSize)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, "File is required")
+		return
+	}
+	if fileHeader.Size > notebusiness.MaxImportSize {
+		respondWithError(c, http.StatusRequestEntityTooLarge, fmt.Sprintf("Import exceeds %d bytes", notebusiness.MaxImportSize))
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, "Failed to read file")
+		return
+	}
+	defer file.Close()
+
+	result, err := nh.noteService.ImportMarkdown(userID, fileHeader.Filename, file, fileHeader.Size)
+	if err != nil {
+		if errors.Is(err, notebusiness.ErrUnsupportedImport) || errors.Is(err, notebusiness.ErrTooManyImportFiles) {
+			respondWithError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondWithError(c, http.StatusInternalServerError, "Failed to import notes")
+		return
+	}
+
+	status := http.StatusCreated
+	if len(result.Imported) == 0 {
+		status = http.StatusUnprocessableEntity
+	}
+	respondWithJSON(c, status, result)
+}
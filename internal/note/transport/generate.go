@@ -0,0 +1,79 @@
+package notetransport
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	notebusiness "github.com/khoaphungnguyen/go-openai/internal/note/business"
+)
+
+type GenerateNoteRequest struct {
+	Model string `json:"model" binding:"required"`
+}
+
+type GeneratedNoteResponse struct {
+	ID      uuid.UUID          `json:"id"`
+	Created bool               `json:"created"`
+	Note    NoteDetailResponse `json:"note"`
+}
+
+// GenerateNote handles generating a structured note from a thread. Progress is
+// streamed as server-sent events: "progress" events carry the current stage
+// and model output, followed by a final "done" or "error" event.
+func (nh *NoteHandler) GenerateNote(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	threadID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, "Invalid thread ID")
+		return
+	}
+
+	var payload GenerateNoteRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		respondWithError(c, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	c.Writer.Header().Set("Content-Type", "text/event-stream")
+	c.Writer.Header().Set("Cache-Control", "no-cache")
+	c.Writer.Header().Set("Connection", "keep-alive")
+
+	note, created, err := nh.noteGenerator.GenerateFromThread(c.Request.Context(), userID, threadID, payload.Model,
+		func(progress notebusiness.GenerateProgress) error {
+			c.SSEvent("progress", progress)
+			c.Writer.Flush()
+			return c.Request.Context().Err()
+		})
+	if err != nil {
+		c.SSEvent("error", gin.H{"error": generateErrorMessage(err)})
+		c.Writer.Flush()
+		return
+	}
+
+	response := GeneratedNoteResponse{ID: note.ID, Created: created, Note: convertToNoteResponse(note)}
+	c.SSEvent("done", response)
+	c.Writer.Flush()
+}
+
+// generateErrorMessage converts note generation errors to client-facing messages.
+func generateErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, notebusiness.ErrThreadAccessDenied):
+		return "Thread not found"
+	case errors.Is(err, notebusiness.ErrEmptyThread):
+		return "Thread has no messages"
+	case errors.Is(err, notebusiness.ErrInvalidGeneratedNote):
+		return "The model did not return a valid note, please try again"
+	case errors.Is(err, notebusiness.ErrWorkspaceAccessDenied):
+		return "Access denied"
+	default:
+		return "Failed to generate note"
+	}
+}
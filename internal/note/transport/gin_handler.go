@@ -7,14 +7,19 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	notebusiness "github.com/khoaphungnguyen/go-openai/internal/note/business"
 	notemodel "github.com/khoaphungnguyen/go-openai/internal/note/model"
 )
 
 type NoteCreateRequest struct {
-	Title    string `json:"title"`
-	Level    string `json:"level"`
-	ThreadID string `json:"threadID"`
-	Type     string `json:"type"`
+	Title       string     `json:"title"`
+	Level       string     `json:"level"`
+	ThreadID    string     `json:"threadID"`
+	Type        string     `json:"type"`
+	WorkspaceID *uuid.UUID `json:"workspaceID"`
+	// Category defaults to the canonical category matching Type.
+	Category notemodel.Category `json:"category"`
+	Tags     []string           `json:"tags"`
 }
 
 type NoteCreateResponse struct {
@@ -25,37 +30,51 @@ type NoteCreateResponse struct {
 }
 
 type NoteResponse struct {
+	ID        uuid.UUID          `json:"id"`
+	ThreadID  uuid.UUID          `json:"threadID"`
+	Title     string             `json:"title"`
+	Problem   string             `json:"problem"`
+	Type      string             `json:"type"`
+	Level     string             `json:"level"`
+	Category  notemodel.Category `json:"category"`
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+}
+
+type TrashedNoteResponse struct {
 	ID        uuid.UUID `json:"id"`
 	ThreadID  uuid.UUID `json:"threadID"`
 	Title     string    `json:"title"`
-	Problem   string    `json:"problem"`
 	Type      string    `json:"type"`
 	Level     string    `json:"level"`
 	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	DeletedAt time.Time `json:"deleted_at"`
 }
 
 type NoteDetail struct {
-	ThreadID uuid.UUID `json:"threadID"`
-	Problem  string    `json:"problem"`
-	Approach string    `json:"approach"`
-	Solution string    `json:"solution"`
-	Code     string    `json:"code"`
-	Type     string    `json:"type"`
-	Level    string    `json:"level"`
+	ThreadID uuid.UUID          `json:"threadID"`
+	Problem  string             `json:"problem"`
+	Approach string             `json:"approach"`
+	Solution string             `json:"solution"`
+	Code     string             `json:"code"`
+	Type     string             `json:"type"`
+	Level    string             `json:"level"`
+	Category notemodel.Category `json:"category"`
 }
 
 type NoteDetailResponse struct {
-	ThreadID  uuid.UUID `json:"threadID"`
-	Title     string    `json:"title"`
-	Problem   string    `json:"problem"`
-	Approach  string    `json:"approach"`
-	Solution  string    `json:"solution"`
-	Code      string    `json:"code"`
-	Type      string    `json:"type"`
-	Level     string    `json:"level"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ThreadID  uuid.UUID          `json:"threadID"`
+	Title     string             `json:"title"`
+	Problem   string             `json:"problem"`
+	Approach  string             `json:"approach"`
+	Solution  string             `json:"solution"`
+	Code      string             `json:"code"`
+	Type      string             `json:"type"`
+	Level     string             `json:"level"`
+	Category  notemodel.Category `json:"category"`
+	Tags      []string           `json:"tags"`
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
 }
 
 // CreateNote handles the creation of a new note.
@@ -77,13 +96,26 @@ func (nh *NoteHandler) CreateNote(c *gin.Context) {
 		return
 	}
 	note := &notemodel.Note{
-		Title:    payload.Title,
-		Level:    payload.Level,
-		Type:     payload.Type,
-		ThreadID: threadID,
-		UserID:   userID,
+		Title:       payload.Title,
+		Level:       payload.Level,
+		Type:        payload.Type,
+		ThreadID:    threadID,
+		UserID:      userID,
+		WorkspaceID: payload.WorkspaceID,
+		Category:    payload.Category,
+	}
+	for _, tag := range payload.Tags {
+		note.Tags = append(note.Tags, notemodel.NoteTag{Tag: tag})
 	}
 	if err := nh.noteService.CreateNote(note); err != nil {
+		if errors.Is(err, notebusiness.ErrWorkspaceAccessDenied) {
+			respondWithError(c, http.StatusForbidden, "Access denied")
+			return
+		}
+		if errors.Is(err, notebusiness.ErrInvalidCategory) || errors.Is(err, notebusiness.ErrTooManyTags) {
+			respondWithError(c, http.StatusBadRequest, err.Error())
+			return
+		}
 		respondWithError(c, http.StatusInternalServerError, "Failed to create note")
 		return
 	}
@@ -115,6 +147,49 @@ func (nh *NoteHandler) GetAllNoteByUserID(c *gin.Context) {
 			Problem:   note.Problem,
 			Type:      note.Type,
 			Level:     note.Level,
+			Category:  note.Category,
+			CreatedAt: note.CreatedAt,
+			UpdatedAt: note.UpdatedAt,
+		})
+	}
+
+	respondWithJSON(c, http.StatusOK, noteResponses)
+}
+
+// GetWorkspaceNotes handles the retrieval of all notes shared in a workspace.
+func (nh *NoteHandler) GetWorkspaceNotes(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	workspaceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, "Invalid workspace ID")
+		return
+	}
+
+	notes, err := nh.noteService.GetNotesByWorkspaceID(userID, workspaceID)
+	if err != nil {
+		if errors.Is(err, notebusiness.ErrWorkspaceAccessDenied) {
+			respondWithError(c, http.StatusNotFound, "Workspace not found")
+			return
+		}
+		respondWithError(c, http.StatusInternalServerError, "Failed to retrieve notes")
+		return
+	}
+
+	noteResponses := make([]NoteResponse, 0, len(notes))
+	for _, note := range notes {
+		noteResponses = append(noteResponses, NoteResponse{
+			ID:        note.ID,
+			ThreadID:  note.ThreadID,
+			Title:     note.Title,
+			Problem:   note.Problem,
+			Type:      note.Type,
+			Level:     note.Level,
+			Category:  note.Category,
 			CreatedAt: note.CreatedAt,
 			UpdatedAt: note.UpdatedAt,
 		})
@@ -171,7 +246,88 @@ func (nh *NoteHandler) DeleteNote(c *gin.Context) {
 		return
 	}
 
-	respondWithJSON(c, http.StatusOK, gin.H{"message": "Note deleted successfully"})
+	respondWithJSON(c, http.StatusOK, gin.H{"message": "Note moved to trash"})
+}
+
+// GetDeletedNotes handles listing the notes in the user's trash.
+func (nh *NoteHandler) GetDeletedNotes(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	notes, err := nh.noteService.GetDeletedNotes(userID)
+	if err != nil {
+		respondWithError(c, http.StatusInternalServerError, "Failed to retrieve trash")
+		return
+	}
+
+	responseNotes := make([]TrashedNoteResponse, 0, len(notes))
+	for _, note := range notes {
+		responseNotes = append(responseNotes, TrashedNoteResponse{
+			ID:        note.ID,
+			ThreadID:  note.ThreadID,
+			Title:     note.Title,
+			Type:      note.Type,
+			Level:     note.Level,
+			CreatedAt: note.CreatedAt,
+			DeletedAt: note.DeletedAt.Time,
+		})
+	}
+	respondWithJSON(c, http.StatusOK, responseNotes)
+}
+
+// RestoreNote handles moving a note out of the trash.
+func (nh *NoteHandler) RestoreNote(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	noteID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, "Invalid note ID")
+		return
+	}
+
+	if err := nh.noteService.RestoreNote(userID, noteID); err != nil {
+		if errors.Is(err, notebusiness.ErrNoteNotInTrash) {
+			respondWithError(c, http.StatusNotFound, "Note not found in trash")
+			return
+		}
+		respondWithError(c, http.StatusInternalServerError, "Failed to restore note")
+		return
+	}
+
+	respondWithJSON(c, http.StatusOK, gin.H{"message": "Note restored successfully"})
+}
+
+// PurgeNote handles permanently deleting a note from the trash.
+func (nh *NoteHandler) PurgeNote(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	noteID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, "Invalid note ID")
+		return
+	}
+
+	if err := nh.noteService.PurgeNote(userID, noteID); err != nil {
+		if errors.Is(err, notebusiness.ErrNoteNotInTrash) {
+			respondWithError(c, http.StatusNotFound, "Note not found in trash")
+			return
+		}
+		respondWithError(c, http.StatusInternalServerError, "Failed to delete note")
+		return
+	}
+
+	respondWithJSON(c, http.StatusOK, gin.H{"message": "Note deleted permanently"})
 }
 
 // UpdateNote handles the updating of a note.
@@ -201,9 +357,14 @@ func (nh *NoteHandler) UpdateNote(c *gin.Context) {
 		Code:     payload.Code,
 		Type:     payload.Type,
 		Level:    payload.Level,
+		Category: payload.Category,
 	}
 
 	if err := nh.noteService.UpdateNoteByID(userID, noteID, note); err != nil {
+		if errors.Is(err, notebusiness.ErrInvalidCategory) {
+			respondWithError(c, http.StatusBadRequest, err.Error())
+			return
+		}
 		respondWithError(c, http.StatusInternalServerError, "Failed to update note")
 		return
 	}
@@ -245,6 +406,8 @@ func convertToNoteResponse(note *notemodel.Note) NoteDetailResponse {
 		Code:      note.Code,
 		Type:      note.Type,
 		Level:     note.Level,
+		Category:  note.Category,
+		Tags:      tagNames(note.Tags),
 		CreatedAt: note.CreatedAt,
 		UpdatedAt: note.UpdatedAt,
 	}
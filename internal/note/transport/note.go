@@ -2,15 +2,18 @@
 package notetransport
 
 import (
+	auditbusiness "github.com/khoaphungnguyen/go-openai/internal/audit/business"
 	notebusiness "github.com/khoaphungnguyen/go-openai/internal/note/business"
 )
 
 // NoteHandler handles note-related HTTP requests.
 type NoteHandler struct {
-	noteService *notebusiness.NoteService
+	noteService   *notebusiness.NoteService
+	noteGenerator *notebusiness.NoteGenerator
+	auditService  *auditbusiness.AuditService
 }
 
 // NewMessageHandler creates a new ChatHandler.
-func NewNoteHandler(noteService *notebusiness.NoteService) *NoteHandler {
-	return &NoteHandler{noteService: noteService}
+func NewNoteHandler(noteService *notebusiness.NoteService, noteGenerator *notebusiness.NoteGenerator, auditService *auditbusiness.AuditService) *NoteHandler {
+	return &NoteHandler{noteService: noteService, noteGenerator: noteGenerator, auditService: auditService}
 }
@@ -0,0 +1,146 @@
+package notetransport
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	notebusiness "github.com/khoaphungnguyen/go-openai/internal/note/business"
+	notemodel "github.com/khoaphungnguyen/go-openai/internal/note/model"
+)
+
+type GradeNoteRequest struct {
+	Grade *int `json:"grade" binding:"required"`
+}
+
+type DueNoteResponse struct {
+	Note     NoteResponse              `json:"note"`
+	Schedule *notemodel.ReviewSchedule `json:"schedule"`
+}
+
+type ReviewScheduleResponse struct {
+	Schedule *notemodel.ReviewSchedule `json:"schedule"`
+	History  []notemodel.ReviewLog     `json:"history"`
+}
+
+// GetDueNotes handles listing the notes due for review today.
+func (nh *NoteHandler) GetDueNotes(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(notebusiness.DefaultDueLimit)))
+	if err != nil || limit <= 0 || limit > 100 {
+		respondWithError(c, http.StatusBadRequest, "Invalid limit")
+		return
+	}
+
+	due, err := nh.noteService.GetDueNotes(userID, limit)
+	if err != nil {
+		respondWithError(c, http.StatusInternalServerError, "Failed to retrieve review queue")
+		return
+	}
+
+	responses := make([]DueNoteResponse, 0, len(due))
+	for _, item := range due {
+		responses = append(responses, DueNoteResponse{
+			Note: NoteResponse{
+				ID:        item.Note.ID,
+				ThreadID:  item.Note.ThreadID,
+				Title:     item.Note.Title,
+				Problem:   item.Note.Problem,
+				Type:      item.Note.Type,
+				Level:     item.Note.Level,
+				CreatedAt: item.Note.CreatedAt,
+				UpdatedAt: item.Note.UpdatedAt,
+			},
+			Schedule: item.Schedule,
+		})
+	}
+	respondWithJSON(c, http.StatusOK, responses)
+}
+
+// GradeNote handles recording a review of a note with a recall grade from 0 to 5.
+func (nh *NoteHandler) GradeNote(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	noteID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, "Invalid note ID")
+		return
+	}
+
+	var payload GradeNoteRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		respondWithError(c, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	schedule, err := nh.noteService.GradeNote(userID, noteID, *payload.Grade)
+	if err != nil {
+		respondWithReviewError(c, err, "Failed to record review")
+		return
+	}
+
+	respondWithJSON(c, http.StatusOK, schedule)
+}
+
+// GetReviewSchedule handles retrieving the review schedule and history of a note.
+func (nh *NoteHandler) GetReviewSchedule(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	noteID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, "Invalid note ID")
+		return
+	}
+
+	schedule, history, err := nh.noteService.GetReviewSchedule(userID, noteID)
+	if err != nil {
+		respondWithReviewError(c, err, "Failed to retrieve review schedule")
+		return
+	}
+
+	respondWithJSON(c, http.StatusOK, ReviewScheduleResponse{Schedule: schedule, History: history})
+}
+
+// GetReviewStats handles retrieving the user's review streaks and retention.
+func (nh *NoteHandler) GetReviewStats(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	stats, err := nh.noteService.GetReviewStats(userID)
+	if err != nil {
+		respondWithError(c, http.StatusInternalServerError, "Failed to retrieve review statistics")
+		return
+	}
+
+	respondWithJSON(c, http.StatusOK, stats)
+}
+
+// respondWithReviewError maps review errors to HTTP responses.
+func respondWithReviewError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, notebusiness.ErrNoteNotFound):
+		respondWithError(c, http.StatusNotFound, "Note not found")
+	case errors.Is(err, notebusiness.ErrInvalidGrade):
+		respondWithError(c, http.StatusBadRequest, err.Error())
+	default:
+		respondWithError(c, http.StatusInternalServerError, fallback)
+	}
+}
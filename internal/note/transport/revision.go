@@ -0,0 +1,181 @@
+package notetransport
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	notebusiness "github.com/khoaphungnguyen/go-openai/internal/note/business"
+	notemodel "github.com/khoaphungnguyen/go-openai/internal/note/model"
+	"github.com/khoaphungnguyen/go-openai/internal/textdiff"
+)
+
+type RevisionSummaryResponse struct {
+	Version      int        `json:"version"`
+	EditorID     *uuid.UUID `json:"editorID"`
+	RestoredFrom *int       `json:"restoredFrom,omitempty"`
+	Title        string     `json:"title"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+type RevisionResponse struct {
+	Version      int        `json:"version"`
+	EditorID     *uuid.UUID `json:"editorID"`
+	RestoredFrom *int       `json:"restoredFrom,omitempty"`
+	Title        string     `json:"title"`
+	Problem      string     `json:"problem"`
+	Approach     string     `json:"approach"`
+	Solution     string     `json:"solution"`
+	Code         string     `json:"code"`
+	Level        string     `json:"level"`
+	Type         string     `json:"type"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+type RevisionDiffResponse struct {
+	From   int                      `json:"from"`
+	To     int                      `json:"to"`
+	Fields []notebusiness.FieldDiff `json:"fields"`
+}
+
+// GetRevisions handles listing the revisions of a note.
+func (nh *NoteHandler) GetRevisions(c *gin.Context) {
+	userID, noteID, ok := parseNoteRequest(c)
+	if !ok {
+		return
+	}
+
+	revisions, err := nh.noteService.GetRevisions(userID, noteID)
+	if err != nil {
+		respondWithRevisionError(c, err, "Failed to retrieve revisions")
+		return
+	}
+
+	responses := make([]RevisionSummaryResponse, 0, len(revisions))
+	for _, revision := range revisions {
+		responses = append(responses, RevisionSummaryResponse{
+			Version:      revision.Version,
+			EditorID:     revision.EditorID,
+			RestoredFrom: revision.RestoredFrom,
+			Title:        revision.Title,
+			CreatedAt:    revision.CreatedAt,
+		})
+	}
+	respondWithJSON(c, http.StatusOK, responses)
+}
+
+// GetRevision handles retrieving the full content of a note revision.
+func (nh *NoteHandler) GetRevision(c *gin.Context) {
+	userID, noteID, ok := parseNoteRequest(c)
+	if !ok {
+		return
+	}
+
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, "Invalid revision version")
+		return
+	}
+
+	revision, err := nh.noteService.GetRevision(userID, noteID, version)
+	if err != nil {
+		respondWithRevisionError(c, err, "Failed to retrieve revision")
+		return
+	}
+
+	respondWithJSON(c, http.StatusOK, convertToRevisionResponse(revision))
+}
+
+// DiffRevisions handles comparing two revisions given by the from and to query parameters.
+func (nh *NoteHandler) DiffRevisions(c *gin.Context) {
+	userID, noteID, ok := parseNoteRequest(c)
+	if !ok {
+		return
+	}
+
+	from, errFrom := strconv.Atoi(c.Query("from"))
+	to, errTo := strconv.Atoi(c.Query("to"))
+	if errFrom != nil || errTo != nil {
+		respondWithError(c, http.StatusBadRequest, "from and to must be revision versions")
+		return
+	}
+
+	diffs, err := nh.noteService.DiffRevisions(userID, noteID, from, to)
+	if err != nil {
+		respondWithRevisionError(c, err, "Failed to compare revisions")
+		return
+	}
+
+	respondWithJSON(c, http.StatusOK, RevisionDiffResponse{From: from, To: to, Fields: diffs})
+}
+
+// RestoreRevision handles restoring a note to an earlier revision.
+func (nh *NoteHandler) RestoreRevision(c *gin.Context) {
+	userID, noteID, ok := parseNoteRequest(c)
+	if !ok {
+		return
+	}
+
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, "Invalid revision version")
+		return
+	}
+
+	if err := nh.noteService.RestoreRevision(userID, noteID, version); err != nil {
+		respondWithRevisionError(c, err, "Failed to restore revision")
+		return
+	}
+
+	respondWithJSON(c, http.StatusOK, gin.H{"message": "Revision restored successfully"})
+}
+
+// parseNoteRequest extracts the caller and note IDs, responding on failure.
+func parseNoteRequest(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, err.Error())
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	noteID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, "Invalid note ID")
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, noteID, true
+}
+
+// respondWithRevisionError maps revision errors to HTTP responses.
+func respondWithRevisionError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, notebusiness.ErrNoteNotFound):
+		respondWithError(c, http.StatusNotFound, "Note not found")
+	case errors.Is(err, notebusiness.ErrRevisionNotFound):
+		respondWithError(c, http.StatusNotFound, "Revision not found")
+	case errors.Is(err, textdiff.ErrTooLarge):
+		respondWithError(c, http.StatusUnprocessableEntity, "Revisions are too large to compare")
+	default:
+		respondWithError(c, http.StatusInternalServerError, fallback)
+	}
+}
+
+// convertToRevisionResponse converts a revision model to a revision response.
+func convertToRevisionResponse(revision *notemodel.NoteRevision) RevisionResponse {
+	return RevisionResponse{
+		Version:      revision.Version,
+		EditorID:     revision.EditorID,
+		RestoredFrom: revision.RestoredFrom,
+		Title:        revision.Title,
+		Problem:      revision.Problem,
+		Approach:     revision.Approach,
+		Solution:     revision.Solution,
+		Code:         revision.Code,
+		Level:        revision.Level,
+		Type:         revision.Type,
+		CreatedAt:    revision.CreatedAt,
+	}
+}
@@ -0,0 +1,158 @@
+package notetransport
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	notebusiness "github.com/khoaphungnguyen/go-openai/internal/note/business"
+	notemodel "github.com/khoaphungnguyen/go-openai/internal/note/model"
+)
+
+type NoteTagsRequest struct {
+	Tags []string `json:"tags"`
+}
+
+type NoteSearchResult struct {
+	NoteResponse
+	Tags []string `json:"tags"`
+}
+
+// SearchNotes handles full-text search across the caller's notes, filtered by
+// level, category and tags. The tag parameter may be repeated; notes must carry all of them.
+func (nh *NoteHandler) SearchNotes(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(notebusiness.DefaultSearchLimit)))
+	if err != nil || limit <= 0 || limit > notebusiness.MaxSearchLimit {
+		respondWithError(c, http.StatusBadRequest, "Invalid limit")
+		return
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		respondWithError(c, http.StatusBadRequest, "Invalid offset")
+		return
+	}
+
+	filter := notemodel.NoteFilter{
+		Query:    c.Query("q"),
+		Level:    c.Query("level"),
+		Category: notemodel.Category(c.Query("category")),
+		Tags:     c.QueryArray("tag"),
+		Limit:    limit,
+		Offset:   offset,
+	}
+	notes, total, err := nh.noteService.SearchNotes(userID, filter)
+	if err != nil {
+		respondWithTaxonomyError(c, err, "Failed to search notes")
+		return
+	}
+
+	results := make([]NoteSearchResult, 0, len(notes))
+	for _, note := range notes {
+		results = append(results, NoteSearchResult{
+			NoteResponse: NoteResponse{
+				ID:        note.ID,
+				ThreadID:  note.ThreadID,
+				Title:     note.Title,
+				Problem:   note.Problem,
+				Type:      note.Type,
+				Level:     note.Level,
+				Category:  note.Category,
+				CreatedAt: note.CreatedAt,
+				UpdatedAt: note.UpdatedAt,
+			},
+			Tags: tagNames(note.Tags),
+		})
+	}
+	respondWithJSON(c, http.StatusOK, gin.H{"total": total, "notes": results})
+}
+
+// SetNoteTags handles replacing the tags of a note.
+func (nh *NoteHandler) SetNoteTags(c *gin.Context) {
+	userID, noteID, ok := parseNoteRequest(c)
+	if !ok {
+		return
+	}
+
+	var payload NoteTagsRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		respondWithError(c, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	tags, err := nh.noteService.SetTags(userID, noteID, payload.Tags)
+	if err != nil {
+		respondWithTaxonomyError(c, err, "Failed to update tags")
+		return
+	}
+	respondWithJSON(c, http.StatusOK, gin.H{"tags": tags})
+}
+
+// GetTags handles listing the caller's tags with their note counts.
+func (nh *NoteHandler) GetTags(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tags, err := nh.noteService.GetTags(userID)
+	if err != nil {
+		respondWithError(c, http.StatusInternalServerError, "Failed to retrieve tags")
+		return
+	}
+	if tags == nil {
+		tags = []notemodel.TagCount{}
+	}
+	respondWithJSON(c, http.StatusOK, tags)
+}
+
+// GetCategories handles listing the canonical note categories.
+func (nh *NoteHandler) GetCategories(c *gin.Context) {
+	respondWithJSON(c, http.StatusOK, notemodel.Categories)
+}
+
+// GetNoteStats handles the caller's note statistics dashboard.
+func (nh *NoteHandler) GetNoteStats(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	stats, err := nh.noteService.GetNoteStats(userID)
+	if err != nil {
+		respondWithError(c, http.StatusInternalServerError, "Failed to retrieve note statistics")
+		return
+	}
+	respondWithJSON(c, http.StatusOK, stats)
+}
+
+// respondWithTaxonomyError maps tagging and search errors to HTTP responses.
+func respondWithTaxonomyError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, notebusiness.ErrNoteNotFound):
+		respondWithError(c, http.StatusNotFound, "Note not found")
+	case errors.Is(err, notebusiness.ErrInvalidCategory),
+		errors.Is(err, notebusiness.ErrInvalidLevel),
+		errors.Is(err, notebusiness.ErrTooManyTags):
+		respondWithError(c, http.StatusBadRequest, err.Error())
+	default:
+		respondWithError(c, http.StatusInternalServerError, fallback)
+	}
+}
+
+// tagNames flattens note tags into their names.
+func tagNames(tags []notemodel.NoteTag) []string {
+	names := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		names = append(names, tag.Tag)
+	}
+	return names
+}
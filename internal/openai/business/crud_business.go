@@ -1,11 +1,13 @@
 package openaibusiness
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/google/uuid"
 	messagemodel "github.com/khoaphungnguyen/go-openai/internal/message/model"
 	openaimodel "github.com/khoaphungnguyen/go-openai/internal/openai/model"
+	usermodel "github.com/khoaphungnguyen/go-openai/internal/user/model"
 	"github.com/pkoukk/tiktoken-go"
 )
 
@@ -37,6 +39,7 @@ func (s *OpenAIService) CreateTransaction(userID, threadID uuid.UUID, message, m
 		ThreadID:      threadID,
 		MessageID:     chatMessage.ID,
 		Model:         model,
+		PromptVersion: openaimodel.DefaultPromptVersion,
 		Role:          role,
 		MessageLength: len(token),
 	}
@@ -44,13 +47,32 @@ func (s *OpenAIService) CreateTransaction(userID, threadID uuid.UUID, message, m
 	return s.openAIStore.CreateTransaction(transaction)
 }
 
-// UpdateTransaction updates an existing OpenAI transaction.
-func (s *OpenAIService) UpdateTransaction(transaction *openaimodel.OpenAITransaction) error {
+// ErrTransactionNotFound is returned when a transaction does not exist or the
+// caller may not access it.
+var ErrTransactionNotFound = errors.New("transaction not found")
+
+// UpdateTransaction updates a transaction of the caller. The owner, thread and
+// message of a transaction never change.
+func (s *OpenAIService) UpdateTransaction(callerID uuid.UUID, transaction *openaimodel.OpenAITransaction) error {
+	existing, err := s.transaction(transaction.ID, func(t *openaimodel.OpenAITransaction) bool {
+		return t.UserID == callerID
+	})
+	if err != nil {
+		return err
+	}
+	transaction.UserID = existing.UserID
+	transaction.ThreadID = existing.ThreadID
+	transaction.MessageID = existing.MessageID
 	return s.openAIStore.UpdateTransaction(transaction)
 }
 
-// DeleteTransaction deletes a transaction by its ID.
-func (s *OpenAIService) DeleteTransaction(transactionID uuid.UUID) error {
+// DeleteTransaction deletes a transaction of the caller.
+func (s *OpenAIService) DeleteTransaction(callerID, transactionID uuid.UUID) error {
+	if _, err := s.transaction(transactionID, func(t *openaimodel.OpenAITransaction) bool {
+		return t.UserID == callerID
+	}); err != nil {
+		return err
+	}
 	return s.openAIStore.DeleteTransaction(transactionID)
 }
 
@@ -64,9 +86,21 @@ func (s *OpenAIService) GetTransactionsByUserID(userID uuid.UUID) ([]openaimodel
 	return s.openAIStore.GetTransactionsByUserID(userID)
 }
 
-// GetTransactionsByTran retrieves all transactions for a specific user.
-func (s *OpenAIService) GetTransactionByID(transactionID uuid.UUID) (*openaimodel.OpenAITransaction, error) {
-	return s.openAIStore.GetTransactionByID(transactionID)
+// GetTransactionByID retrieves a transaction of the caller, or of any user
+// when the caller's role may read usage.
+func (s *OpenAIService) GetTransactionByID(callerID uuid.UUID, role usermodel.Role, transactionID uuid.UUID) (*openaimodel.OpenAITransaction, error) {
+	return s.transaction(transactionID, func(t *openaimodel.OpenAITransaction) bool {
+		return t.UserID == callerID || role.Can(usermodel.PermissionReadUsage)
+	})
+}
+
+// transaction loads a transaction, hiding it unless allowed accepts it.
+func (s *OpenAIService) transaction(transactionID uuid.UUID, allowed func(*openaimodel.OpenAITransaction) bool) (*openaimodel.OpenAITransaction, error) {
+	transaction, err := s.openAIStore.GetTransactionByID(transactionID)
+	if err != nil || transaction == nil || !allowed(transaction) {
+		return nil, ErrTransactionNotFound
+	}
+	return transaction, nil
 }
 
 // CountUserTransactions counts the total number of transactions for a specific user.
@@ -78,3 +112,35 @@ func (s *OpenAIService) CountUserTransactions(userID uuid.UUID) (int64, error) {
 func (s *OpenAIService) SummarizeUserUsage(userID uuid.UUID) (int64, error) {
 	return s.openAIStore.SummarizeUsage(userID)
 }
+
+// GetUserUsage totals a user's transactions overall and per model.
+func (s *OpenAIService) GetUserUsage(userID uuid.UUID) (*openaimodel.UsageSummary, error) {
+	byModel, err := s.openAIStore.SummarizeUsageByModel(userID)
+	if err != nil {
+		return nil, err
+	}
+	summary := &openaimodel.UsageSummary{UserID: userID, ByModel: byModel}
+	for _, usage := range byModel {
+		summary.Transactions += usage.Transactions
+		summary.MessageLength += usage.MessageLength
+		if usage.LastUsedAt != nil && (summary.LastUsedAt == nil || usage.LastUsedAt.After(*summary.LastUsedAt)) {
+			summary.LastUsedAt = usage.LastUsedAt
+		}
+	}
+	return summary, nil
+}
+
+// CanReadThread reports whether the user may watch the thread's messages.
+func (s *OpenAIService) CanReadThread(threadID, userID uuid.UUID) bool {
+	return s.messageService.CanReadThread(threadID, userID)
+}
+
+// CanWriteThread reports whether the user may send messages to the thread.
+func (s *OpenAIService) CanWriteThread(threadID, userID uuid.UUID) bool {
+	return s.messageService.CanWriteThread(threadID, userID)
+}
+
+// GetTransactionByMessageID retrieves the transaction recorded for a chat message.
+func (s *OpenAIService) GetTransactionByMessageID(messageID uuid.UUID) (*openaimodel.OpenAITransaction, error) {
+	return s.openAIStore.GetTransactionByMessageID(messageID)
+}
@@ -0,0 +1,82 @@
+package openaibusiness
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	openaimodel "github.com/khoaphungnguyen/go-openai/internal/openai/model"
+	openaistorage "github.com/khoaphungnguyen/go-openai/internal/openai/storage"
+	usermodel "github.com/khoaphungnguyen/go-openai/internal/user/model"
+)
+
+// memoryOpenAIStore keeps transactions in memory. The embedded interface is
+// nil, so calling any other method panics.
+type memoryOpenAIStore struct {
+	openaistorage.OpenAIStore
+	transactions map[uuid.UUID]openaimodel.OpenAITransaction
+}
+
+func (m *memoryOpenAIStore) GetTransactionByID(id uuid.UUID) (*openaimodel.OpenAITransaction, error) {
+	transaction, ok := m.transactions[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return &transaction, nil
+}
+
+func (m *memoryOpenAIStore) UpdateTransaction(transaction *openaimodel.OpenAITransaction) error {
+	m.transactions[transaction.ID] = *transaction
+	return nil
+}
+
+func (m *memoryOpenAIStore) DeleteTransaction(id uuid.UUID) error {
+	delete(m.transactions, id)
+	return nil
+}
+
+func TestTransactionAccess(t *testing.T) {
+	owner, other := uuid.New(), uuid.New()
+	transaction := openaimodel.OpenAITransaction{ID: uuid.New(), UserID: owner, ThreadID: uuid.New(), MessageLength: 10}
+	store := &memoryOpenAIStore{transactions: map[uuid.UUID]openaimodel.OpenAITransaction{transaction.ID: transaction}}
+	s := NewOpenAIService(store, nil, nil, nil)
+
+	if _, err := s.GetTransactionByID(owner, usermodel.UserRole, transaction.ID); err != nil {
+		t.Errorf("owner reading the transaction: %v", err)
+	}
+	if _, err := s.GetTransactionByID(other, usermodel.SupportRole, transaction.ID); err != nil {
+		t.Errorf("support reading the transaction: %v", err)
+	}
+	if _, err := s.GetTransactionByID(other, usermodel.UserRole, transaction.ID); !errors.Is(err, ErrTransactionNotFound) {
+		t.Errorf("another user reading the transaction = %v, want ErrTransactionNotFound", err)
+	}
+	if _, err := s.GetTransactionByID(owner, usermodel.UserRole, uuid.New()); !errors.Is(err, ErrTransactionNotFound) {
+		t.Errorf("reading a missing transaction = %v, want ErrTransactionNotFound", err)
+	}
+
+	forged := transaction
+	forged.MessageLength = 1 << 20
+	if err := s.UpdateTransaction(other, &forged); !errors.Is(err, ErrTransactionNotFound) {
+		t.Errorf("another user updating the transaction = %v, want ErrTransactionNotFound", err)
+	}
+	if err := s.DeleteTransaction(other, transaction.ID); !errors.Is(err, ErrTransactionNotFound) {
+		t.Errorf("another user deleting the transaction = %v, want ErrTransactionNotFound", err)
+	}
+	if got := store.transactions[transaction.ID]; got != transaction {
+		t.Fatalf("transaction changed to %+v by another user", got)
+	}
+
+	// Owners may update their transactions but not hand them to someone else
+	update := transaction
+	update.UserID = other
+	update.MessageLength = 20
+	if err := s.UpdateTransaction(owner, &update); err != nil {
+		t.Fatalf("owner updating the transaction: %v", err)
+	}
+	if got := store.transactions[transaction.ID]; got.UserID != owner || got.MessageLength != 20 {
+		t.Errorf("updated transaction = %+v, want owner %s and length 20", got, owner)
+	}
+	if err := s.DeleteTransaction(owner, transaction.ID); err != nil {
+		t.Errorf("owner deleting the transaction: %v", err)
+	}
+}
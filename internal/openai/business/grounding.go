@@ -0,0 +1,80 @@
+package openaibusiness
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	embeddingmodel "github.com/khoaphungnguyen/go-openai/internal/embedding/model"
+	openaimodel "github.com/khoaphungnguyen/go-openai/internal/openai/model"
+)
+
+const (
+	// RAGTopK is the number of passages retrieved to ground an answer.
+	RAGTopK = 5
+	// RAGTokenBudget bounds the tokens of the passages injected into the prompt.
+	RAGTokenBudget = 2000
+)
+
+// groundingInstructions precede the passages injected into a grounded prompt.
+const groundingInstructions = `Use the following excerpts from the user's own notes and documents when they are relevant to the question.
+Cite every excerpt you rely on with its number in square brackets, for example [1].
+If the excerpts do not contain the answer, say so and answer from general knowledge without citations.`
+
+// Retriever finds passages of the user's saved content relevant to a question.
+type Retriever interface {
+	Retrieve(ctx context.Context, userID uuid.UUID, query string, k, tokenBudget int) ([]embeddingmodel.Passage, error)
+}
+
+// Ground retrieves the user's notes and documents relevant to the question when
+// the thread has RAG enabled. It returns nil when the answer should not be grounded
+// or nothing relevant was found.
+func (s *OpenAIService) Ground(ctx context.Context, userID, threadID uuid.UUID, question string) (*openaimodel.Grounding, error) {
+	if s.retriever == nil || strings.TrimSpace(question) == "" {
+		return nil, nil
+	}
+	thread, err := s.messageService.GetThreadByID(threadID)
+	if err != nil {
+		return nil, err
+	}
+	if thread == nil || !thread.RAGEnabled {
+		return nil, nil
+	}
+
+	passages, err := s.retriever.Retrieve(ctx, userID, question, RAGTopK, RAGTokenBudget)
+	if err != nil {
+		return nil, err
+	}
+	if len(passages) == 0 {
+		return nil, nil
+	}
+	return buildGrounding(passages), nil
+}
+
+// buildGrounding numbers the sources of the passages, sharing a number between
+// passages of the same source, and formats them into a system prompt.
+func buildGrounding(passages []embeddingmodel.Passage) *openaimodel.Grounding {
+	grounding := &openaimodel.Grounding{}
+	indexes := make(map[uuid.UUID]int)
+
+	var prompt strings.Builder
+	prompt.WriteString(groundingInstructions)
+	for _, passage := range passages {
+		index, ok := indexes[passage.SourceID]
+		if !ok {
+			index = len(grounding.Citations) + 1
+			indexes[passage.SourceID] = index
+			grounding.Citations = append(grounding.Citations, openaimodel.Citation{
+				Index:    index,
+				Type:     passage.SourceType,
+				ID:       passage.SourceID,
+				ParentID: passage.ParentID,
+				Title:    passage.Title,
+			})
+		}
+		fmt.Fprintf(&prompt, "\n\n[%d] %s: %s\n%s", index, passage.SourceType, passage.Title, strings.TrimSpace(passage.Content))
+	}
+	grounding.Prompt = prompt.String()
+	return grounding
+}
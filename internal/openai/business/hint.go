@@ -0,0 +1,35 @@
+package openaibusiness
+
+import (
+	"context"
+
+	"github.com/khoaphungnguyen/go-openai/internal/provider"
+)
+
+const (
+	// hintMaxTokens bounds hints from OpenAI models.
+	hintMaxTokens = 500
+	// localHintMaxTokens bounds hints from local models, which are slower.
+	localHintMaxTokens = 200
+)
+
+// GenerateHint asks the model for a short answer to input under the given system prompt.
+func (s *OpenAIService) GenerateHint(ctx context.Context, model, system, input string) (string, error) {
+	maxTokens := hintMaxTokens
+	if !provider.IsOpenAIModel(model) {
+		maxTokens = localHintMaxTokens
+	}
+
+	var messages []provider.Message
+	if system != "" {
+		messages = append(messages, provider.Message{Role: "system", Content: system})
+	}
+	messages = append(messages, provider.Message{Role: "user", Content: input})
+
+	return s.llm.Complete(ctx, provider.Request{
+		Model:       model,
+		Messages:    messages,
+		MaxTokens:   maxTokens,
+		Temperature: 1,
+	})
+}
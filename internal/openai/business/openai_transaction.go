@@ -3,18 +3,24 @@ package openaibusiness
 import (
 	messagebusiness "github.com/khoaphungnguyen/go-openai/internal/message/business"
 	openaistorage "github.com/khoaphungnguyen/go-openai/internal/openai/storage"
+	"github.com/khoaphungnguyen/go-openai/internal/provider"
 )
 
 // OpenAIService provides business logic for OpenAI transactions.
 type OpenAIService struct {
 	openAIStore    openaistorage.OpenAIStore
 	messageService *messagebusiness.MessageService // Reference to the message business service
+	llm            provider.Provider
+	retriever      Retriever
 }
 
-// NewOpenAIService creates a new instance of OpenAIService.
-func NewOpenAIService(openAIStore openaistorage.OpenAIStore, msgService *messagebusiness.MessageService) *OpenAIService {
+// NewOpenAIService creates a new instance of OpenAIService. The retriever may
+// be nil, in which case answers are never grounded in the user's content.
+func NewOpenAIService(openAIStore openaistorage.OpenAIStore, msgService *messagebusiness.MessageService, llm provider.Provider, retriever Retriever) *OpenAIService {
 	return &OpenAIService{
 		openAIStore:    openAIStore,
 		messageService: msgService,
+		llm:            llm,
+		retriever:      retriever,
 	}
 }
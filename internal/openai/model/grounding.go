@@ -0,0 +1,52 @@
+package openaimodel
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/google/uuid"
+	embeddingmodel "github.com/khoaphungnguyen/go-openai/internal/embedding/model"
+)
+
+// citationPattern matches citation markers such as [2] in a model answer.
+var citationPattern = regexp.MustCompile(`\[(\d+)\]`)
+
+// Citation is a note or document given to the model under a citation number.
+type Citation struct {
+	Index    int                       `json:"index"`
+	Type     embeddingmodel.SourceType `json:"type"`
+	ID       uuid.UUID                 `json:"id"`
+	ParentID *uuid.UUID                `json:"parentId,omitempty"`
+	Title    string                    `json:"title"`
+}
+
+// Grounding is the context retrieved from the user's notes and documents for a chat answer.
+type Grounding struct {
+	// Prompt is the system message carrying the numbered passages.
+	Prompt    string
+	Citations []Citation
+}
+
+// Cited returns the sources the answer refers to by their citation numbers,
+// in citation order. It is safe to call on a nil Grounding.
+func (g *Grounding) Cited(answer string) []Citation {
+	cited := []Citation{}
+	if g == nil {
+		return cited
+	}
+
+	referenced := make(map[int]bool)
+	for _, match := range citationPattern.FindAllStringSubmatch(answer, -1) {
+		if index, err := strconv.Atoi(match[1]); err == nil {
+			referenced[index] = true
+		}
+	}
+	for _, citation := range g.Citations {
+		if referenced[citation.Index] {
+			cited = append(cited, citation)
+		}
+	}
+	sort.Slice(cited, func(i, j int) bool { return cited[i].Index < cited[j].Index })
+	return cited
+}
@@ -7,6 +7,10 @@ import (
 	"github.com/google/uuid"
 )
 
+// DefaultPromptVersion identifies the prompt templates currently used for chat
+// completions. Bump it whenever system prompts change so feedback can be compared.
+const DefaultPromptVersion = "v1"
+
 // OpenAITransaction represents a record of an interaction with the OpenAI API.
 type OpenAITransaction struct {
 	ID            uuid.UUID `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
@@ -14,6 +18,7 @@ type OpenAITransaction struct {
 	ThreadID      uuid.UUID `gorm:"type:uuid;index"`
 	MessageID     uuid.UUID `gorm:"type:uuid"`
 	Model         string    `gorm:"type:varchar(255)"`
+	PromptVersion string    `gorm:"type:varchar(50)"`
 	Role          string    `gorm:"type:varchar(50);not null"`
 	MessageLength int       `gorm:"type:int"` // Tracks the length of the user's message
 	ProcessTime   time.Time `gorm:"default:now()"`
@@ -0,0 +1,24 @@
+package openaimodel
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ModelUsage totals a user's transactions with one model.
+type ModelUsage struct {
+	Model         string     `json:"model"`
+	Transactions  int64      `json:"transactions"`
+	MessageLength int64      `json:"messageLength"`
+	LastUsedAt    *time.Time `json:"lastUsedAt"`
+}
+
+// UsageSummary totals a user's transactions overall and per model.
+type UsageSummary struct {
+	UserID        uuid.UUID    `json:"userId"`
+	Transactions  int64        `json:"transactions"`
+	MessageLength int64        `json:"messageLength"`
+	LastUsedAt    *time.Time   `json:"lastUsedAt"`
+	ByModel       []ModelUsage `json:"byModel"`
+}
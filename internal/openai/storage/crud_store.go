@@ -58,6 +58,28 @@ func (s *openAIStore) CountUserTransactions(userID uuid.UUID) (int64, error) {
 // SummarizeUsage calculates the total message length processed for a specific user.
 func (s *openAIStore) SummarizeUsage(userID uuid.UUID) (int64, error) {
 	var totalLength int64
-	err := s.db.Model(&openaimodel.OpenAITransaction{}).Where("user_id = ?", userID).Select("sum(message_length)").Row().Scan(&totalLength)
+	err := s.db.Model(&openaimodel.OpenAITransaction{}).Where("user_id = ?", userID).Select("COALESCE(SUM(message_length), 0)").Row().Scan(&totalLength)
 	return totalLength, err
 }
+
+// SummarizeUsageByModel totals the transactions of a specific user per model, most used first.
+func (s *openAIStore) SummarizeUsageByModel(userID uuid.UUID) ([]openaimodel.ModelUsage, error) {
+	var usage []openaimodel.ModelUsage
+	err := s.db.Model(&openaimodel.OpenAITransaction{}).
+		Select("model, COUNT(*) AS transactions, COALESCE(SUM(message_length), 0) AS message_length, MAX(process_time) AS last_used_at").
+		Where("user_id = ?", userID).
+		Group("model").
+		Order("transactions DESC").
+		Scan(&usage).Error
+	return usage, err
+}
+
+// GetTransactionByMessageID finds the OpenAI transaction recorded for a chat message.
+func (s *openAIStore) GetTransactionByMessageID(messageID uuid.UUID) (*openaimodel.OpenAITransaction, error) {
+	var transaction openaimodel.OpenAITransaction
+	err := s.db.Where("message_id = ?", messageID).First(&transaction).Error
+	if err != nil {
+		return nil, err
+	}
+	return &transaction, nil
+}
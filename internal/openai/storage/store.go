@@ -13,10 +13,12 @@ type OpenAIStore interface {
 	GetTransactionsByUserID(userID uuid.UUID) ([]openaimodel.OpenAITransaction, error)
 	GetTransactionsByThreadID(threadID uuid.UUID) ([]openaimodel.OpenAITransaction, error)
 	GetTransactionByID(transactionID uuid.UUID) (*openaimodel.OpenAITransaction, error)
+	GetTransactionByMessageID(messageID uuid.UUID) (*openaimodel.OpenAITransaction, error)
 	UpdateTransaction(transaction *openaimodel.OpenAITransaction) error
 	DeleteTransaction(id uuid.UUID) error
 	CountUserTransactions(userID uuid.UUID) (int64, error)
 	SummarizeUsage(userID uuid.UUID) (int64, error)
+	SummarizeUsageByModel(userID uuid.UUID) ([]openaimodel.ModelUsage, error)
 }
 
 // openAIStore encapsulates the logic for storing and retrieving OpenAI data.
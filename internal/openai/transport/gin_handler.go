@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -13,15 +14,20 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	attachmentbusiness "github.com/khoaphungnguyen/go-openai/internal/attachment/business"
+	attachmentmodel "github.com/khoaphungnguyen/go-openai/internal/attachment/model"
 	"github.com/khoaphungnguyen/go-openai/internal/common"
+	openaibusiness "github.com/khoaphungnguyen/go-openai/internal/openai/business"
 	openaimodel "github.com/khoaphungnguyen/go-openai/internal/openai/model"
+	usermodel "github.com/khoaphungnguyen/go-openai/internal/user/model"
 	"github.com/pkoukk/tiktoken-go"
 	"github.com/sashabaranov/go-openai"
 )
 
 type LocalMessage struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
+	Role    string   `json:"role"`
+	Content string   `json:"content"`
+	Images  []string `json:"images,omitempty"`
 }
 
 type LocalChat struct {
@@ -95,12 +101,22 @@ func (h *OpenAIHandler) createTransaction(userID uuid.UUID, inputData openaimode
 }
 
 // GetTransactionsByUserID handles fetching transactions for a specific user.
+// Users can only read their own transactions unless their role may read usage.
 func (h *OpenAIHandler) GetTransactionsByUserID(c *gin.Context) {
 	userID, err := uuid.Parse(c.Param("userID"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
 		return
 	}
+	callerID, err := common.GetUserIDFromContext(c)
+	if err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if userID != callerID && !usermodel.Role(c.GetString("role")).Can(usermodel.PermissionReadUsage) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not authorized to perform this action"})
+		return
+	}
 
 	transactions, err := h.openAIService.GetTransactionsByUserID(userID)
 	if err != nil {
@@ -111,15 +127,41 @@ func (h *OpenAIHandler) GetTransactionsByUserID(c *gin.Context) {
 	c.JSON(http.StatusOK, transactions)
 }
 
+// GetUserUsage handles summarizing a user's model usage. Admin only.
+func (h *OpenAIHandler) GetUserUsage(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("userID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	usage, err := h.openAIService.GetUserUsage(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get usage"})
+		return
+	}
+	c.JSON(http.StatusOK, usage)
+}
+
 // UpdateTransaction handles the updating of an existing OpenAI transaction.
+// Users can only update their own transactions.
 func (h *OpenAIHandler) UpdateTransaction(c *gin.Context) {
+	callerID, err := common.GetUserIDFromContext(c)
+	if err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
 	var transaction openaimodel.OpenAITransaction
 	if err := c.ShouldBindJSON(&transaction); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data"})
 		return
 	}
 
-	if err := h.openAIService.UpdateTransaction(&transaction); err != nil {
+	if err := h.openAIService.UpdateTransaction(callerID, &transaction); err != nil {
+		if errors.Is(err, openaibusiness.ErrTransactionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Transaction not found"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update transaction"})
 		return
 	}
@@ -128,14 +170,24 @@ func (h *OpenAIHandler) UpdateTransaction(c *gin.Context) {
 }
 
 // DeleteTransaction handles the deletion of an OpenAI transaction.
+// Users can only delete their own transactions.
 func (h *OpenAIHandler) DeleteTransaction(c *gin.Context) {
 	transactionID, err := uuid.Parse(c.Param("transactionID"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid transaction ID"})
 		return
 	}
+	callerID, err := common.GetUserIDFromContext(c)
+	if err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
 
-	if err := h.openAIService.DeleteTransaction(transactionID); err != nil {
+	if err := h.openAIService.DeleteTransaction(callerID, transactionID); err != nil {
+		if errors.Is(err, openaibusiness.ErrTransactionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Transaction not found"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete transaction"})
 		return
 	}
@@ -144,15 +196,25 @@ func (h *OpenAIHandler) DeleteTransaction(c *gin.Context) {
 }
 
 // GetTransactionByID handles fetching a specific transaction by its ID.
+// Users can only read their own transactions unless their role may read usage.
 func (h *OpenAIHandler) GetTransactionByID(c *gin.Context) {
 	transactionID, err := uuid.Parse(c.Param("transactionID"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid transaction ID"})
 		return
 	}
+	callerID, err := common.GetUserIDFromContext(c)
+	if err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
 
-	transaction, err := h.openAIService.GetTransactionByID(transactionID)
+	transaction, err := h.openAIService.GetTransactionByID(callerID, usermodel.Role(c.GetString("role")), transactionID)
 	if err != nil {
+		if errors.Is(err, openaibusiness.ErrTransactionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Transaction not found"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get transaction"})
 		return
 	}
@@ -253,7 +315,7 @@ func (h *OpenAIHandler) FetchSuggestion(c *gin.Context) {
 	}
 }
 
-// FetchSuggestion handles the request to fetch suggestions from OpenAI.
+// GenerateHint handles the request to generate a hint from the selected model.
 func (h *OpenAIHandler) GenerateHint(c *gin.Context) {
 	// Extract user ID from context, if required
 	_, err := common.GetUserIDFromContext(c)
@@ -262,12 +324,6 @@ func (h *OpenAIHandler) GenerateHint(c *gin.Context) {
 		return
 	}
 
-	// Retrieve the OpenAI client from the context
-	openaiClient, exists := c.MustGet("openaiClient").(*openai.Client)
-	if !exists {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "OpenAI client not available"})
-		return
-	}
 	type RequestData struct {
 		Model  string `json:"model"`
 		Input  string `json:"input"`
@@ -280,71 +336,14 @@ func (h *OpenAIHandler) GenerateHint(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data"})
 		return
 	}
-	if !strings.HasPrefix(requestData.Model, "gpt") {
-		// Construct the prompt
-		req := LocalRequest{
-			Model:  requestData.Model,
-			Prompt: requestData.Input,
-			System: requestData.System,
-			Stream: false,
-			Options: LocalOptions{
-				NumPredict: 200,
-			},
-		}
-		reqJson, _ := json.Marshal(req)
-		resp, err := http.Post("http://localhost:11434/api/generate", "application/json", bytes.NewBuffer(reqJson))
-		if err != nil {
-			// handle error
-			log.Println(err)
-		}
-		defer resp.Body.Close()
-		// Create the request payload and send the request
-		message, err := io.ReadAll(resp.Body)
-		if err != nil {
-			// handle error
-			log.Println(err)
-		}
 
-		var respData LocalResponse
-		err = json.Unmarshal(message, &respData)
-		if err != nil {
-			// handle error
-			log.Println(err)
-		}
-		// Check if the response has content and return the content
-		if len(respData.Response) > 0 {
-			c.JSON(http.StatusOK, respData.Response)
-			return
-		}
-	} else {
-		// Create the request payload and send the request to OpenAI
-		resp, err := openaiClient.CreateChatCompletion(
-			context.Background(),
-			openai.ChatCompletionRequest{
-				Model: requestData.Model,
-				Messages: []openai.ChatCompletionMessage{{
-					Role: "system", Content: requestData.System,
-				}, {Role: "user", Content: requestData.Input}},
-				Temperature:      1,
-				TopP:             1,
-				FrequencyPenalty: 0,
-				MaxTokens:        500,
-				N:                1,
-				Stream:           false,
-			},
-		)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch suggestions"})
-			return
-		}
-
-		// Check if the response has content and return the content
-		if len(resp.Choices) > 0 && len(resp.Choices[0].Message.Content) > 0 {
-			c.JSON(http.StatusOK, resp.Choices[0].Message.Content)
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "No content in response"})
+	hint, err := h.openAIService.GenerateHint(c.Request.Context(), requestData.Model, requestData.System, requestData.Input)
+	if err != nil {
+		log.Printf("GenerateHint error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch suggestions"})
+		return
 	}
+	c.JSON(http.StatusOK, hint)
 }
 
 // FetchSuggestion handles the request to fetch suggestions from OpenAI.
@@ -421,8 +420,9 @@ func (h *OpenAIHandler) FetchDrawing(c *gin.Context) {
 }
 
 type MessageInput struct {
-	Messages []LocalMessage `json:"messages"`
-	Model    string         `json:"model"`
+	Messages      []LocalMessage `json:"messages"`
+	Model         string         `json:"model"`
+	AttachmentIDs []uuid.UUID    `json:"attachmentIds"`
 }
 
 // MessageHandler handles the incoming messages.
@@ -438,6 +438,13 @@ func (h *OpenAIHandler) MessageHanlder(c *gin.Context) {
 		common.RespondWithError(c, http.StatusBadRequest, "Invalid thread ID")
 		return
 	}
+
+	// Only the thread owner and workspace editors may post to the thread
+	if !h.openAIService.CanWriteThread(threadID, userID) {
+		common.RespondWithError(c, http.StatusForbidden, "Access denied")
+		return
+	}
+
 	// Check some condition to decide whether to stop the generation
 	if c.Query("stop") == "true" {
 		err := h.StopGeneration(threadID)
@@ -461,6 +468,24 @@ func (h *OpenAIHandler) MessageHanlder(c *gin.Context) {
 		return
 	}
 
+	// Load the files sent with this message before anything is recorded
+	var attachments *attachmentmodel.PromptAttachments
+	if len(inputData.AttachmentIDs) > 0 {
+		if len(inputData.Messages) == 0 {
+			common.RespondWithError(c, http.StatusBadRequest, "Attachments must be sent with a message")
+			return
+		}
+		attachments, err = h.attachmentService.PrepareForPrompt(c.Request.Context(), userID, threadID, inputData.AttachmentIDs, inputData.Model)
+		if err != nil {
+			status := http.StatusBadRequest
+			if !errors.Is(err, attachmentbusiness.ErrInvalidAttachments) && !errors.Is(err, attachmentbusiness.ErrVisionUnsupported) {
+				status = http.StatusInternalServerError
+			}
+			common.RespondWithError(c, status, err.Error())
+			return
+		}
+	}
+
 	// add all messages to the prompt
 	var prompt strings.Builder
 	for _, message := range inputData.Messages {
@@ -495,13 +520,35 @@ func (h *OpenAIHandler) MessageHanlder(c *gin.Context) {
 		return
 	}
 
-	transaction, err := h.openAIService.GetTransactionByID(transactionID)
+	transaction, err := h.openAIService.GetTransactionByID(userID, "", transactionID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get transaction"})
 		return
 	}
 	log.Println("Tokens from the prompt	 ", transaction.MessageLength)
 
+	if attachments != nil {
+		if err := h.attachmentService.LinkToMessage(inputData.AttachmentIDs, transaction.MessageID); err != nil {
+			log.Printf("Error linking attachments: %v", err)
+		}
+		// Inline text files into the last message only after the user's own text was saved
+		last := &inputData.Messages[len(inputData.Messages)-1]
+		last.Content += attachments.Text
+		for _, image := range attachments.Images {
+			last.Images = append(last.Images, image.Base64)
+		}
+	}
+
+	// Ground the answer in the user's notes and documents when the thread opted in
+	grounding, err := h.openAIService.Ground(c.Request.Context(), userID, threadID, message)
+	if err != nil {
+		log.Printf("Error retrieving context for thread %s: %v", threadID, err)
+	}
+	if grounding != nil {
+		system := LocalMessage{Role: "system", Content: grounding.Prompt}
+		inputData.Messages = append([]LocalMessage{system}, inputData.Messages...)
+	}
+
 	// Create a new context with a cancel function
 	ctx, cancel := context.WithCancel(context.Background())
 	//Set the cancel function for the thread ID
@@ -524,15 +571,27 @@ func (h *OpenAIHandler) MessageHanlder(c *gin.Context) {
 		}
 		defer stream.Body.Close()
 		// Stream the response from OpenAI and send parts to the client via SSE
-		h.localStreamResponse(c, ctx, threadID, userID, inputData.Model, stream)
+		h.localStreamResponse(c, ctx, threadID, userID, inputData.Model, stream, grounding)
 	} else {
 		// Convert []LocalMessage to []openai.ChatCompletionMessage
 		var messages []openai.ChatCompletionMessage
-		for _, localMessage := range inputData.Messages {
-			messages = append(messages, openai.ChatCompletionMessage{
+		for i, localMessage := range inputData.Messages {
+			message := openai.ChatCompletionMessage{
 				Role:    localMessage.Role,
 				Content: localMessage.Content,
-			})
+			}
+			// Images are only ever attached to the last message
+			if attachments != nil && len(attachments.Images) > 0 && i == len(inputData.Messages)-1 {
+				message.Content = ""
+				message.MultiContent = []openai.ChatMessagePart{{Type: openai.ChatMessagePartTypeText, Text: localMessage.Content}}
+				for _, image := range attachments.Images {
+					message.MultiContent = append(message.MultiContent, openai.ChatMessagePart{
+						Type:     openai.ChatMessagePartTypeImageURL,
+						ImageURL: &openai.ChatMessageImageURL{URL: image.DataURL()},
+					})
+				}
+			}
+			messages = append(messages, message)
 		}
 
 		// Set up the chat completion request
@@ -553,14 +612,19 @@ func (h *OpenAIHandler) MessageHanlder(c *gin.Context) {
 		}
 		defer stream.Close()
 		// Stream the response from OpenAI and send parts to the client via SSE
-		h.openAIStreamResponse(c, ctx, threadID, userID, inputData.Model, stream)
+		h.openAIStreamResponse(c, ctx, threadID, userID, inputData.Model, stream, grounding)
 	}
 }
 
-func (h *OpenAIHandler) localStreamResponse(c *gin.Context, ctx context.Context, threadID uuid.UUID, userID uuid.UUID, model string, stream *http.Response) {
+func (h *OpenAIHandler) localStreamResponse(c *gin.Context, ctx context.Context, threadID uuid.UUID, userID uuid.UUID, model string, stream *http.Response, grounding *openaimodel.Grounding) {
 
 	var responseBuilder strings.Builder
 
+	// End the stream with the sources the response cited
+	defer func() {
+		h.broadcastDone(threadID, grounding.Cited(responseBuilder.String()))
+	}()
+
 	// Defer the saving logic so it always runs, even if the function returns early
 	defer func() {
 		transactionID, err := h.createTransaction(userID, openaimodel.OpenAITransactionInput{
@@ -572,7 +636,7 @@ func (h *OpenAIHandler) localStreamResponse(c *gin.Context, ctx context.Context,
 		if err != nil {
 			log.Printf("Error saving assistant transaction: %v", err)
 		}
-		transaction, err := h.openAIService.GetTransactionByID(transactionID)
+		transaction, err := h.openAIService.GetTransactionByID(userID, "", transactionID)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get transaction"})
 			return
@@ -590,12 +654,7 @@ loop:
 		select {
 		case <-ctx.Done():
 			// If the context has been cancelled, stop reading from the stream
-			ch, exists := h.ThreadSSEChannels[threadID]
-			if !exists {
-				ch = make(chan string, 100)
-				h.ThreadSSEChannels[threadID] = ch
-			}
-			ch <- ""
+			h.broadcast(threadID, "")
 			return
 		default:
 			// If the context has not been cancelled, read the next line from the stream
@@ -614,25 +673,19 @@ loop:
 			responseContent := response.Message.Content
 			responseBuilder.WriteString(responseContent)
 
-			ch, exists := h.ThreadSSEChannels[threadID]
-			if !exists {
-				ch = make(chan string, 100)
-				h.ThreadSSEChannels[threadID] = ch
-			}
-
-			select {
-			case ch <- responseContent:
-				// Successfully sent to channel
-			default:
-				log.Printf("Channel buffer full or closed. Dropping message for thread ID %s.", threadID)
-			}
+			h.broadcast(threadID, responseContent)
 		}
 	}
 }
 
-func (h *OpenAIHandler) openAIStreamResponse(c *gin.Context, ctx context.Context, threadID uuid.UUID, userID uuid.UUID, model string, stream *openai.ChatCompletionStream) {
+func (h *OpenAIHandler) openAIStreamResponse(c *gin.Context, ctx context.Context, threadID uuid.UUID, userID uuid.UUID, model string, stream *openai.ChatCompletionStream, grounding *openaimodel.Grounding) {
 	var responseBuilder strings.Builder
 
+	// End the stream with the sources the response cited
+	defer func() {
+		h.broadcastDone(threadID, grounding.Cited(responseBuilder.String()))
+	}()
+
 	// Start a goroutine to do the processing in the background
 
 loop:
@@ -640,12 +693,7 @@ loop:
 		select {
 		case <-ctx.Done():
 			// If the context has been cancelled, stop reading from the stream
-			ch, exists := h.ThreadSSEChannels[threadID]
-			if !exists {
-				ch = make(chan string, 100)
-				h.ThreadSSEChannels[threadID] = ch
-			}
-			ch <- ""
+			h.broadcast(threadID, "")
 			return
 		default:
 			// If the context has not been cancelled, read the next line from the stream
@@ -659,18 +707,7 @@ loop:
 			responseContent := response.Choices[0].Delta.Content
 			responseBuilder.WriteString(responseContent)
 
-			ch, exists := h.ThreadSSEChannels[threadID]
-			if !exists {
-				ch = make(chan string, 100)
-				h.ThreadSSEChannels[threadID] = ch
-			}
-
-			select {
-			case ch <- responseContent:
-				// Successfully sent to channel
-			default:
-				log.Printf("Channel buffer full or closed. Dropping message for thread ID %s.", threadID)
-			}
+			h.broadcast(threadID, responseContent)
 		}
 	}
 
@@ -686,7 +723,7 @@ loop:
 			log.Printf("Error saving assistant transaction: %v", err)
 		}
 
-		transaction, err := h.openAIService.GetTransactionByID(transactionID)
+		transaction, err := h.openAIService.GetTransactionByID(userID, "", transactionID)
 		if err != nil {
 			log.Printf("Failed to get transaction: %v", err)
 			return
@@ -704,7 +741,7 @@ func (h *OpenAIHandler) StopGeneration(threadID uuid.UUID) error {
 
 	if cancel, ok := h.CancelFuncsLLM[threadID]; ok {
 		cancel()
-		delete(h.CancelFuncs, threadID)
+		delete(h.CancelFuncsLLM, threadID)
 	} else {
 		return fmt.Errorf("no cancel function for thread ID %v", threadID)
 	}
@@ -712,95 +749,63 @@ func (h *OpenAIHandler) StopGeneration(threadID uuid.UUID) error {
 	return nil
 }
 
+// SSEHandler streams assistant responses of a thread to the client. Every
+// user allowed to read the thread gets their own subscription, so all
+// members watching a shared thread see the same stream.
 func (h *OpenAIHandler) SSEHandler(c *gin.Context) {
-	threadID, err := uuid.Parse(c.Param("threadID"))
+	userID, err := common.GetUserIDFromContext(c)
 	if err != nil {
-		common.RespondWithError(c, http.StatusBadRequest, "Invalid thread ID")
+		common.RespondWithError(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	// Set headers for SSE
-	c.Writer.Header().Set("Content-Type", "text/event-stream")
-	c.Writer.Header().Set("Cache-Control", "no-cache")
-	c.Writer.Header().Set("Connection", "keep-alive")
-	c.Writer.Header().Set("Transfer-Encoding", "chunked")
-
-	// Ensure the channel exists and return the channel
-	ch := h.ensureChannelExists(threadID, c.Writer)
-
-	// Just ensuring the channel and goroutine are set up correctly
-	if ch == nil {
-		common.RespondWithError(c, http.StatusInternalServerError, "Failed to set up SSE stream")
+	threadID, err := uuid.Parse(c.Param("threadID"))
+	if err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, "Invalid thread ID")
 		return
 	}
 
-	// Keep the connection open until the client closes it
-	<-c.Request.Context().Done()
-	log.Println("Client closed connection")
-
-	// Cancel the receiving goroutine when the client closes the connection
-	if cancel, exists := h.CancelFuncs[threadID]; exists {
-		cancel()
-		delete(h.CancelFuncs, threadID)
-	}
-}
-
-func (h *OpenAIHandler) ensureChannelExists(threadID uuid.UUID, w http.ResponseWriter) chan string {
-	h.Mutex.Lock()
-	defer h.Mutex.Unlock()
-
-	ch, exists := h.ThreadSSEChannels[threadID]
-	if !exists {
-		ch = make(chan string, 100)
-		h.ThreadSSEChannels[threadID] = ch
-	}
-
-	// Cancel the old goroutine if it exists
-	if cancel, exists := h.CancelFuncs[threadID]; exists {
-		cancel()
-		delete(h.CancelFuncs, threadID)
+	if !h.openAIService.CanReadThread(threadID, userID) {
+		common.RespondWithError(c, http.StatusForbidden, "Access denied")
+		return
 	}
 
-	// Create a new context with a cancel function
-	ctx, cancel := context.WithCancel(h.ctx)
-
-	// Store the cancel function
-	h.CancelFuncs[threadID] = cancel
-
-	h.startReceiving(ctx, threadID, ch, w) // Always start a new goroutine
-
-	return ch
-}
-
-func (h *OpenAIHandler) startReceiving(ctx context.Context, threadID uuid.UUID, ch chan string, w http.ResponseWriter) {
-	flusher, ok := w.(http.Flusher)
+	flusher, ok := c.Writer.(http.Flusher)
 	if !ok {
-		log.Println("Streaming not supported")
+		common.RespondWithError(c, http.StatusInternalServerError, "Failed to set up SSE stream")
 		return
 	}
 
-	go func() {
-		for {
-			select {
-			case msg, ok := <-ch:
-				if !ok {
-					return // Channel was closed, exit the goroutine.
-				}
+	// Set headers for SSE
+	c.Writer.Header().Set("Content-Type", "text/event-stream")
+	c.Writer.Header().Set("Cache-Control", "no-cache")
+	c.Writer.Header().Set("Connection", "keep-alive")
+	c.Writer.Header().Set("Transfer-Encoding", "chunked")
 
-				messageID := uuid.New().String()
-				createdAt := time.Now().Format(time.RFC3339)
-				jsonResponse := fmt.Sprintf(`{"id": %q, "content": %q, "role": "assistant", "createdAt": %q}`, messageID, msg, createdAt)
-				fmt.Fprintf(w, "data: %s\n\n", jsonResponse)
-				// Check go rountine ID
+	subscriberID, ch := h.subscribe(threadID)
+	defer h.unsubscribe(threadID, subscriberID)
 
-				//log.Println("Sent response to client: ", msg)
+	// Keep the connection open until the client closes it
+	for {
+		select {
+		case event := <-ch:
+			if event.Done {
+				// A named event, so clients only listening for messages are unaffected
+				done, _ := json.Marshal(gin.H{"citations": event.Citations})
+				fmt.Fprintf(c.Writer, "event: done\ndata: %s\n\n", done)
 				flusher.Flush()
-
-			case <-ctx.Done():
-				return // Context was cancelled, exit the goroutine.
+				continue
 			}
+			messageID := uuid.New().String()
+			createdAt := time.Now().Format(time.RFC3339)
+			jsonResponse := fmt.Sprintf(`{"id": %q, "content": %q, "role": "assistant", "createdAt": %q}`, messageID, event.Content, createdAt)
+			fmt.Fprintf(c.Writer, "data: %s\n\n", jsonResponse)
+			flusher.Flush()
+		case <-c.Request.Context().Done():
+			log.Println("Client closed connection")
+			return
 		}
-	}()
+	}
 }
 
 // func getGoroutineID() uint64 {
@@ -5,6 +5,7 @@ import (
 	"sync"
 
 	"github.com/google/uuid"
+	attachmentbusiness "github.com/khoaphungnguyen/go-openai/internal/attachment/business"
 	openaibusiness "github.com/khoaphungnguyen/go-openai/internal/openai/business"
 )
 
@@ -12,24 +13,23 @@ import (
 
 type OpenAIHandler struct {
     openAIService     *openaibusiness.OpenAIService
-    ThreadSSEChannels map[uuid.UUID]chan string
+    attachmentService *attachmentbusiness.AttachmentService
+    // ThreadSubscribers holds one channel per SSE client watching a thread,
+    // so every member of a shared thread receives the streamed response.
+    ThreadSubscribers map[uuid.UUID]map[uuid.UUID]chan streamEvent
     Mutex             *sync.RWMutex
     ctx               context.Context
-    // CancelFuncs stores the cancel functions for each thread ID
-    CancelFuncs map[uuid.UUID]context.CancelFunc
     CancelFuncsLLM map[uuid.UUID]context.CancelFunc
 }
 
 // NewOpenAIHandler creates a new instance of OpenAIHandler.
-func NewOpenAIHandler(openAIService *openaibusiness.OpenAIService) *OpenAIHandler {
-    ctx, _ := context.WithCancel(context.Background())
+func NewOpenAIHandler(openAIService *openaibusiness.OpenAIService, attachmentService *attachmentbusiness.AttachmentService) *OpenAIHandler {
     return &OpenAIHandler{
         openAIService:     openAIService,
-        ThreadSSEChannels: make(map[uuid.UUID]chan string),
+        attachmentService: attachmentService,
+        ThreadSubscribers: make(map[uuid.UUID]map[uuid.UUID]chan streamEvent),
         Mutex:             &sync.RWMutex{},
-        ctx:               ctx,
-        // Initialize the CancelFuncs map
-        CancelFuncs: make(map[uuid.UUID]context.CancelFunc),
+        ctx:               context.Background(),
         CancelFuncsLLM: make(map[uuid.UUID]context.CancelFunc),
     }
 }
@@ -0,0 +1,78 @@
+package openaitransport
+
+import (
+	"log"
+
+	"github.com/google/uuid"
+	openaimodel "github.com/khoaphungnguyen/go-openai/internal/openai/model"
+)
+
+// subscriberBufferSize is the number of chunks buffered per SSE client.
+const subscriberBufferSize = 100
+
+// streamEvent is a chunk of a streamed response, or the event that ends it.
+type streamEvent struct {
+	Content string
+	Done    bool
+	// Citations lists the sources cited by the finished response.
+	Citations []openaimodel.Citation
+}
+
+// subscribe registers a new SSE client for a thread and returns its ID and channel.
+func (h *OpenAIHandler) subscribe(threadID uuid.UUID) (uuid.UUID, chan streamEvent) {
+	h.Mutex.Lock()
+	defer h.Mutex.Unlock()
+
+	subscribers, exists := h.ThreadSubscribers[threadID]
+	if !exists {
+		subscribers = make(map[uuid.UUID]chan streamEvent)
+		h.ThreadSubscribers[threadID] = subscribers
+	}
+
+	subscriberID := uuid.New()
+	ch := make(chan streamEvent, subscriberBufferSize)
+	subscribers[subscriberID] = ch
+	return subscriberID, ch
+}
+
+// unsubscribe removes an SSE client from a thread.
+func (h *OpenAIHandler) unsubscribe(threadID, subscriberID uuid.UUID) {
+	h.Mutex.Lock()
+	defer h.Mutex.Unlock()
+
+	subscribers, exists := h.ThreadSubscribers[threadID]
+	if !exists {
+		return
+	}
+	delete(subscribers, subscriberID)
+	if len(subscribers) == 0 {
+		delete(h.ThreadSubscribers, threadID)
+	}
+}
+
+// broadcast sends a chunk to every SSE client watching the thread.
+func (h *OpenAIHandler) broadcast(threadID uuid.UUID, msg string) {
+	h.send(threadID, streamEvent{Content: msg})
+}
+
+// broadcastDone tells every SSE client watching the thread that the response
+// finished, with the sources it cited.
+func (h *OpenAIHandler) broadcastDone(threadID uuid.UUID, citations []openaimodel.Citation) {
+	h.send(threadID, streamEvent{Done: true, Citations: citations})
+}
+
+// send delivers an event to every SSE client watching the thread. Slow clients
+// whose buffers are full miss the event instead of blocking the stream.
+func (h *OpenAIHandler) send(threadID uuid.UUID, event streamEvent) {
+	h.Mutex.RLock()
+	defer h.Mutex.RUnlock()
+
+	for subscriberID, ch := range h.ThreadSubscribers[threadID] {
+		select {
+		case ch <- event:
+			// Successfully sent to channel
+		default:
+			log.Printf("Channel buffer full. Dropping message for thread ID %s, subscriber %s.", threadID, subscriberID)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// DefaultEmbeddingModel is the OpenAI model used for embeddings unless configured otherwise.
+const DefaultEmbeddingModel = "text-embedding-ada-002"
+
+// ErrUnsupportedEmbeddingModel is returned for OpenAI embedding models the client does not know.
+var ErrUnsupportedEmbeddingModel = errors.New("unsupported OpenAI embedding model")
+
+// Embedder computes embedding vectors for text.
+type Embedder interface {
+	// Embed returns one vector per input, in input order.
+	Embed(ctx context.Context, model string, inputs []string) ([][]float32, error)
+}
+
+// IsOpenAIEmbeddingModel reports whether the embedding model is served by OpenAI.
+func IsOpenAIEmbeddingModel(model string) bool {
+	return strings.HasPrefix(model, "text-")
+}
+
+// NewOpenAIEmbedder creates an Embedder backed by the OpenAI embeddings API.
+func NewOpenAIEmbedder(client *openai.Client) Embedder {
+	return &openAIProvider{client: client}
+}
+
+// NewOllamaEmbedder creates an Embedder backed by the Ollama server at baseURL.
+func NewOllamaEmbedder(baseURL string) Embedder {
+	return &ollamaProvider{baseURL: strings.TrimRight(baseURL, "/"), client: http.DefaultClient}
+}
+
+// Embed implements Embedder.
+func (r *Router) Embed(ctx context.Context, model string, inputs []string) ([][]float32, error) {
+	if IsOpenAIEmbeddingModel(model) {
+		return r.openAIEmbedder.Embed(ctx, model, inputs)
+	}
+	return r.localEmbedder.Embed(ctx, model, inputs)
+}
+
+// Embed implements Embedder.
+func (p *openAIProvider) Embed(ctx context.Context, model string, inputs []string) ([][]float32, error) {
+	var embeddingModel openai.EmbeddingModel
+	if err := embeddingModel.UnmarshalText([]byte(model)); err != nil || embeddingModel == openai.Unknown {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedEmbeddingModel, model)
+	}
+
+	resp, err := p.client.CreateEmbeddings(ctx, openai.EmbeddingRequestStrings{
+		Input: inputs,
+		Model: embeddingModel,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Data) != len(inputs) {
+		return nil, ErrEmptyResponse
+	}
+	vectors := make([][]float32, len(inputs))
+	for _, data := range resp.Data {
+		if data.Index < 0 || data.Index >= len(vectors) {
+			return nil, fmt.Errorf("embedding index %d out of range", data.Index)
+		}
+		vectors[data.Index] = data.Embedding
+	}
+	return vectors, nil
+}
+
+// ollamaEmbeddingRequest is the body of an Ollama /api/embeddings request.
+type ollamaEmbeddingRequest struct {
+	Model  string `json:"model"`
+	Prompt string `json:"prompt"`
+}
+
+// ollamaEmbeddingResponse is the body of an Ollama /api/embeddings response.
+type ollamaEmbeddingResponse struct {
+	Embedding []float32 `json:"embedding"`
+	Error     string    `json:"error"`
+}
+
+// Embed implements Embedder. The embeddings API takes one prompt per request.
+func (p *ollamaProvider) Embed(ctx context.Context, model string, inputs []string) ([][]float32, error) {
+	vectors := make([][]float32, 0, len(inputs))
+	for _, input := range inputs {
+		payload, err := json.Marshal(ollamaEmbeddingRequest{Model: model, Prompt: input})
+		if err != nil {
+			return nil, err
+		}
+		httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/api/embeddings", bytes.NewReader(payload))
+		if err != nil {
+			return nil, err
+		}
+		httpReq.Header.Set("Content-Type", "application/json")
+
+		vector, err := p.doEmbed(httpReq)
+		if err != nil {
+			return nil, err
+		}
+		vectors = append(vectors, vector)
+	}
+	return vectors, nil
+}
+
+// doEmbed sends a single embeddings request and decodes its vector.
+func (p *ollamaProvider) doEmbed(req *http.Request) ([]float32, error) {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("ollama returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	var body ollamaEmbeddingResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	if body.Error != "" {
+		return nil, errors.New(body.Error)
+	}
+	if len(body.Embedding) == 0 {
+		return nil, ErrEmptyResponse
+	}
+	return body.Embedding, nil
+}
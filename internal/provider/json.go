@@ -0,0 +1,30 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// ErrNoJSONObject is returned when a model's output contains no JSON object.
+var ErrNoJSONObject = errors.New("no JSON object in model output")
+
+// DecodeJSONObject decodes the JSON object in a model's output into v. Models
+// sometimes wrap it in prose or code fences, so anything before the first '{'
+// and after the last '}' is ignored.
+func DecodeJSONObject(output string, v interface{}) error {
+	start := strings.Index(output, "{")
+	end := strings.LastIndex(output, "}")
+	if start < 0 || end <= start {
+		return ErrNoJSONObject
+	}
+	return json.Unmarshal([]byte(output[start:end+1]), v)
+}
+
+// OrNone substitutes a placeholder for empty prompt sections.
+func OrNone(s string) string {
+	if strings.TrimSpace(s) == "" {
+		return "(none)"
+	}
+	return s
+}
@@ -0,0 +1,51 @@
+package provider
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDecodeJSONObject(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		want    string
+		wantErr error
+	}{
+		{"bare object", `{"title":"Two Sum"}`, "Two Sum", nil},
+		{"code fence", "```json\n{\"title\": \"Two Sum\"}\n```", "Two Sum", nil},
+		{"surrounding prose", `Here you go: {"title": "Two {Sum}"} Hope it helps.`, "Two {Sum}", nil},
+		{"no object", "I cannot help with that.", "", ErrNoJSONObject},
+		{"reversed braces", "} {", "", ErrNoJSONObject},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v struct {
+				Title string `json:"title"`
+			}
+			err := DecodeJSONObject(tt.output, &v)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("DecodeJSONObject = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || v.Title != tt.want {
+				t.Errorf("DecodeJSONObject = %q, %v; want %q", v.Title, err, tt.want)
+			}
+		})
+	}
+
+	var v struct{}
+	if err := DecodeJSONObject(`{"title": }`, &v); err == nil {
+		t.Error("DecodeJSONObject accepted malformed JSON")
+	}
+}
+
+func TestOrNone(t *testing.T) {
+	for input, want := range map[string]string{"": "(none)", " \n\t": "(none)", "x := 1": "x := 1"} {
+		if got := OrNone(input); got != want {
+			t.Errorf("OrNone(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
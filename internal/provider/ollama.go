@@ -0,0 +1,110 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ollamaProvider sends requests to the chat API of an Ollama server.
+type ollamaProvider struct {
+	baseURL string
+	client  *http.Client
+}
+
+// ollamaChatRequest is the body of an Ollama /api/chat request.
+type ollamaChatRequest struct {
+	Model    string        `json:"model"`
+	Messages []Message     `json:"messages"`
+	Stream   bool          `json:"stream"`
+	Format   string        `json:"format,omitempty"`
+	Options  ollamaOptions `json:"options"`
+}
+
+type ollamaOptions struct {
+	Temperature float32 `json:"temperature"`
+	NumPredict  int     `json:"num_predict,omitempty"`
+}
+
+// ollamaChatResponse is one line of an Ollama /api/chat response.
+type ollamaChatResponse struct {
+	Message Message `json:"message"`
+	Done    bool    `json:"done"`
+	Error   string  `json:"error"`
+}
+
+// NewOllamaProvider creates a Provider backed by the Ollama server at baseURL.
+func NewOllamaProvider(baseURL string) Provider {
+	return &ollamaProvider{baseURL: strings.TrimRight(baseURL, "/"), client: http.DefaultClient}
+}
+
+// Complete implements Provider.
+func (p *ollamaProvider) Complete(ctx context.Context, req Request) (string, error) {
+	return p.Stream(ctx, req, func(string) error { return nil })
+}
+
+// Stream implements Provider.
+func (p *ollamaProvider) Stream(ctx context.Context, req Request, onDelta func(delta string) error) (string, error) {
+	body := ollamaChatRequest{
+		Model:    req.Model,
+		Messages: req.Messages,
+		Stream:   true,
+		Options:  ollamaOptions{Temperature: req.Temperature, NumPredict: req.MaxTokens},
+	}
+	if req.JSON {
+		body.Format = "json"
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return "", err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/api/chat", bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("ollama returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	var b strings.Builder
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		var chunk ollamaChatResponse
+		if err := decoder.Decode(&chunk); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return b.String(), err
+		}
+		if chunk.Error != "" {
+			return b.String(), errors.New(chunk.Error)
+		}
+		if chunk.Message.Content != "" {
+			b.WriteString(chunk.Message.Content)
+			if err := onDelta(chunk.Message.Content); err != nil {
+				return b.String(), err
+			}
+		}
+		if chunk.Done {
+			break
+		}
+	}
+	if b.Len() == 0 {
+		return "", ErrEmptyResponse
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,80 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// openAIProvider sends requests to the OpenAI chat completion API.
+type openAIProvider struct {
+	client *openai.Client
+}
+
+// NewOpenAIProvider creates a Provider backed by the OpenAI API.
+func NewOpenAIProvider(client *openai.Client) Provider {
+	return &openAIProvider{client: client}
+}
+
+// Complete implements Provider.
+func (p *openAIProvider) Complete(ctx context.Context, req Request) (string, error) {
+	resp, err := p.client.CreateChatCompletion(ctx, p.request(req, false))
+	if err != nil {
+		return "", err
+	}
+	if len(resp.Choices) == 0 || resp.Choices[0].Message.Content == "" {
+		return "", ErrEmptyResponse
+	}
+	return resp.Choices[0].Message.Content, nil
+}
+
+// Stream implements Provider.
+func (p *openAIProvider) Stream(ctx context.Context, req Request, onDelta func(delta string) error) (string, error) {
+	stream, err := p.client.CreateChatCompletionStream(ctx, p.request(req, true))
+	if err != nil {
+		return "", err
+	}
+	defer stream.Close()
+
+	var b strings.Builder
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return b.String(), err
+		}
+		if len(resp.Choices) == 0 || resp.Choices[0].Delta.Content == "" {
+			continue
+		}
+		delta := resp.Choices[0].Delta.Content
+		b.WriteString(delta)
+		if err := onDelta(delta); err != nil {
+			return b.String(), err
+		}
+	}
+	if b.Len() == 0 {
+		return "", ErrEmptyResponse
+	}
+	return b.String(), nil
+}
+
+// request converts a Request to the OpenAI wire format. JSON mode is left to
+// the prompt because not every gpt model accepts a response format.
+func (p *openAIProvider) request(req Request, stream bool) openai.ChatCompletionRequest {
+	messages := make([]openai.ChatCompletionMessage, 0, len(req.Messages))
+	for _, message := range req.Messages {
+		messages = append(messages, openai.ChatCompletionMessage{Role: message.Role, Content: message.Content})
+	}
+	return openai.ChatCompletionRequest{
+		Model:       req.Model,
+		Messages:    messages,
+		MaxTokens:   req.MaxTokens,
+		Temperature: req.Temperature,
+		Stream:      stream,
+	}
+}
@@ -0,0 +1,84 @@
+// provider sends chat and embedding requests to language model backends.
+// Chat models whose name starts with "gpt" and embedding models whose name
+// starts with "text-" go to OpenAI; all others go to a local Ollama server.
+package provider
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// DefaultOllamaURL is the address of a local Ollama server.
+const DefaultOllamaURL = "http://localhost:11434"
+
+// ErrEmptyResponse is returned when the model produced no content.
+var ErrEmptyResponse = errors.New("no content in model response")
+
+// Message is a single chat message sent to a model.
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// Request describes a chat completion.
+type Request struct {
+	Model       string
+	Messages    []Message
+	MaxTokens   int
+	Temperature float32
+	// JSON asks the backend to constrain its output to a JSON object where supported.
+	JSON bool
+}
+
+// Provider sends chat requests to a model backend.
+type Provider interface {
+	// Complete returns the full response of the model.
+	Complete(ctx context.Context, req Request) (string, error)
+	// Stream calls onDelta with every chunk of the response as it arrives and
+	// returns the full response. Returning an error from onDelta aborts the stream.
+	Stream(ctx context.Context, req Request, onDelta func(delta string) error) (string, error)
+}
+
+// IsOpenAIModel reports whether the model is served by OpenAI.
+func IsOpenAIModel(model string) bool {
+	return strings.HasPrefix(model, "gpt")
+}
+
+// Router dispatches chat and embedding requests to OpenAI or Ollama based on the model name.
+type Router struct {
+	openAI         Provider
+	local          Provider
+	openAIEmbedder Embedder
+	localEmbedder  Embedder
+}
+
+// NewRouter creates a Router backed by the OpenAI client and an Ollama server at ollamaURL.
+func NewRouter(client *openai.Client, ollamaURL string) *Router {
+	return &Router{
+		openAI:         NewOpenAIProvider(client),
+		local:          NewOllamaProvider(ollamaURL),
+		openAIEmbedder: NewOpenAIEmbedder(client),
+		localEmbedder:  NewOllamaEmbedder(ollamaURL),
+	}
+}
+
+// Complete implements Provider.
+func (r *Router) Complete(ctx context.Context, req Request) (string, error) {
+	return r.backend(req.Model).Complete(ctx, req)
+}
+
+// Stream implements Provider.
+func (r *Router) Stream(ctx context.Context, req Request, onDelta func(delta string) error) (string, error) {
+	return r.backend(req.Model).Stream(ctx, req, onDelta)
+}
+
+// backend selects the provider serving the model.
+func (r *Router) backend(model string) Provider {
+	if IsOpenAIModel(model) {
+		return r.openAI
+	}
+	return r.local
+}
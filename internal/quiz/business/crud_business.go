@@ -0,0 +1,211 @@
+package quizbusiness
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/google/uuid"
+	notemodel "github.com/khoaphungnguyen/go-openai/internal/note/model"
+	"github.com/khoaphungnguyen/go-openai/internal/provider"
+	quizmodel "github.com/khoaphungnguyen/go-openai/internal/quiz/model"
+)
+
+const (
+	// gradeMaxTokens bounds the length of the model's evaluation.
+	gradeMaxTokens = 800
+	// DefaultAttemptLimit bounds the attempts returned in the history.
+	DefaultAttemptLimit = 50
+)
+
+var (
+	// ErrNoteNotFound is returned when the note does not exist or the user cannot read it.
+	ErrNoteNotFound = errors.New("note not found")
+	// ErrNothingDue is returned when the review queue is empty.
+	ErrNothingDue = errors.New("no notes are due for review")
+	// ErrEmptyAnswer is returned when an attempt has no approach.
+	ErrEmptyAnswer = errors.New("approach is required")
+	// ErrInvalidEvaluation is returned when the model output does not match the evaluation schema.
+	ErrInvalidEvaluation = errors.New("model did not return a valid evaluation")
+)
+
+const questionSystemPrompt = `You are a technical interviewer. Restate the problem below as an interview question.
+Include the inputs, expected output, constraints and one small example if they are known.
+Do not reveal the approach, the solution or any code.`
+
+const hintSystemPrompt = `You are a technical interviewer helping a candidate who is stuck.
+Give exactly one short hint (at most two sentences) that moves them toward the reference approach.
+Each later hint may be a little more specific than the previous one. Never give the full solution or code.`
+
+const gradeSystemPrompt = `You grade a candidate's answer to a coding interview problem against a reference approach and solution.
+Respond with a single JSON object and nothing else, using exactly this schema:
+{
+  "score": integer from 0 to 100,
+  "verdict": "correct" | "partial" | "incorrect",
+  "feedback": "what the candidate got right, what is missing or wrong, and the key idea they should remember"
+}
+Accept approaches that differ from the reference if they are correct and have comparable complexity.`
+
+// StartQuiz poses a problem from the given note, or from the most overdue note
+// in the user's review queue when noteID is nil.
+func (qs *QuizService) StartQuiz(ctx context.Context, userID uuid.UUID, noteID *uuid.UUID, model string) (*quizmodel.Question, error) {
+	if noteID == nil {
+		due, err := qs.notes.GetDueNotes(userID, 1)
+		if err != nil {
+			return nil, err
+		}
+		if len(due) == 0 {
+			return nil, ErrNothingDue
+		}
+		noteID = &due[0].Note.ID
+	}
+	// Check access even for queued notes, which may belong to a workspace the user left
+	note, err := qs.getNote(userID, *noteID)
+	if err != nil {
+		return nil, err
+	}
+
+	input := fmt.Sprintf("Title: %s\nDifficulty: %s\nTopic: %s\n\nProblem:\n%s", note.Title, note.Level, note.Type, note.Problem)
+	question, err := qs.hints.GenerateHint(ctx, model, questionSystemPrompt, input)
+	if err != nil {
+		return nil, err
+	}
+
+	return &quizmodel.Question{
+		NoteID:   note.ID,
+		Title:    note.Title,
+		Level:    note.Level,
+		Type:     note.Type,
+		Question: strings.TrimSpace(question),
+	}, nil
+}
+
+// GetHint returns a hint for the note's problem given the user's current attempt.
+func (qs *QuizService) GetHint(ctx context.Context, userID, noteID uuid.UUID, model, attempt string, hintsUsed int) (string, error) {
+	note, err := qs.getNote(userID, noteID)
+	if err != nil {
+		return "", err
+	}
+
+	input := fmt.Sprintf("Problem:\n%s\n\nReference approach:\n%s\n\nCandidate's current attempt:\n%s\n\nThis is hint number %d.",
+		note.Problem, note.Approach, provider.OrNone(attempt), hintsUsed+1)
+	hint, err := qs.hints.GenerateHint(ctx, model, hintSystemPrompt, input)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(hint), nil
+}
+
+// SubmitAnswer grades the user's approach against the note, records the score
+// and reschedules the note's review according to the result.
+func (qs *QuizService) SubmitAnswer(ctx context.Context, userID, noteID uuid.UUID, model, approach, code string, hintsUsed int) (*quizmodel.QuizAttempt, *notemodel.ReviewSchedule, error) {
+	if strings.TrimSpace(approach) == "" {
+		return nil, nil, ErrEmptyAnswer
+	}
+	note, err := qs.getNote(userID, noteID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	input := fmt.Sprintf("Problem:\n%s\n\nReference approach:\n%s\n\nReference solution:\n%s\n\nReference code:\n%s\n\nCandidate's approach:\n%s\n\nCandidate's code:\n%s",
+		note.Problem, note.Approach, note.Solution, provider.OrNone(note.Code), approach, provider.OrNone(code))
+	output, err := qs.llm.Complete(ctx, provider.Request{
+		Model: model,
+		Messages: []provider.Message{
+			{Role: "system", Content: gradeSystemPrompt},
+			{Role: "user", Content: input},
+		},
+		MaxTokens: gradeMaxTokens,
+		JSON:      true,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	evaluation, err := parseEvaluation(output)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if hintsUsed < 0 {
+		hintsUsed = 0
+	}
+	attempt := &quizmodel.QuizAttempt{
+		UserID:    userID,
+		NoteID:    noteID,
+		Model:     model,
+		Approach:  approach,
+		Code:      code,
+		HintsUsed: hintsUsed,
+		Score:     evaluation.Score,
+		Verdict:   evaluation.Verdict,
+		Feedback:  evaluation.Feedback,
+	}
+	if err := qs.quizStore.CreateAttempt(attempt); err != nil {
+		return nil, nil, err
+	}
+
+	schedule, err := qs.notes.GradeNote(userID, noteID, reviewGrade(evaluation.Score, hintsUsed))
+	if err != nil {
+		return nil, nil, err
+	}
+	return attempt, schedule, nil
+}
+
+// GetAttempts retrieves the user's quiz history, optionally for a single note.
+func (qs *QuizService) GetAttempts(userID uuid.UUID, noteID *uuid.UUID) ([]quizmodel.QuizAttempt, error) {
+	return qs.quizStore.GetAttempts(userID, noteID, DefaultAttemptLimit)
+}
+
+// GetNoteScores retrieves the user's quiz results aggregated per note.
+func (qs *QuizService) GetNoteScores(userID uuid.UUID) ([]quizmodel.NoteScore, error) {
+	return qs.quizStore.GetNoteScores(userID)
+}
+
+// getNote loads a note the user can read.
+func (qs *QuizService) getNote(userID, noteID uuid.UUID) (*notemodel.Note, error) {
+	note, err := qs.notes.GetNoteByID(userID, noteID)
+	if err != nil || note == nil {
+		return nil, ErrNoteNotFound
+	}
+	return note, nil
+}
+
+// reviewGrade converts a 0-100 score to an SM-2 grade, costing one grade when hints were used.
+func reviewGrade(score, hintsUsed int) int {
+	grade := int(math.Round(float64(score) / 20))
+	if hintsUsed > 0 {
+		grade--
+	}
+	if grade < 0 {
+		return 0
+	}
+	if grade > notemodel.MaxGrade {
+		return notemodel.MaxGrade
+	}
+	return grade
+}
+
+// parseEvaluation decodes the model output and validates it against the evaluation schema.
+func parseEvaluation(output string) (*quizmodel.Evaluation, error) {
+	var evaluation quizmodel.Evaluation
+	if err := provider.DecodeJSONObject(output, &evaluation); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEvaluation, err)
+	}
+	if evaluation.Score < 0 || evaluation.Score > 100 {
+		return nil, fmt.Errorf("%w: score out of range", ErrInvalidEvaluation)
+	}
+	evaluation.Verdict = quizmodel.Verdict(strings.ToLower(strings.TrimSpace(string(evaluation.Verdict))))
+	if !evaluation.Verdict.IsValid() {
+		switch {
+		case evaluation.Score >= 80:
+			evaluation.Verdict = quizmodel.VerdictCorrect
+		case evaluation.Score >= 40:
+			evaluation.Verdict = quizmodel.VerdictPartial
+		default:
+			evaluation.Verdict = quizmodel.VerdictIncorrect
+		}
+	}
+	return &evaluation, nil
+}
@@ -0,0 +1,36 @@
+// quizbusiness contains the business logic for quizzing users on their notes.
+package quizbusiness
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	notemodel "github.com/khoaphungnguyen/go-openai/internal/note/model"
+	"github.com/khoaphungnguyen/go-openai/internal/provider"
+	quizstorage "github.com/khoaphungnguyen/go-openai/internal/quiz/storage"
+)
+
+// NoteSource gives quizzes access to notes and their review schedules.
+type NoteSource interface {
+	GetNoteByID(userID, noteID uuid.UUID) (*notemodel.Note, error)
+	GetDueNotes(userID uuid.UUID, limit int) ([]notemodel.DueNote, error)
+	GradeNote(userID, noteID uuid.UUID, grade int) (*notemodel.ReviewSchedule, error)
+}
+
+// HintGenerator produces short model answers for a system prompt and input.
+type HintGenerator interface {
+	GenerateHint(ctx context.Context, model, system, input string) (string, error)
+}
+
+// QuizService provides methods for quiz operations.
+type QuizService struct {
+	quizStore quizstorage.QuizStore
+	notes     NoteSource
+	hints     HintGenerator
+	llm       provider.Provider
+}
+
+// NewQuizService creates a new QuizService.
+func NewQuizService(quizStore quizstorage.QuizStore, notes NoteSource, hints HintGenerator, llm provider.Provider) *QuizService {
+	return &QuizService{quizStore: quizStore, notes: notes, hints: hints, llm: llm}
+}
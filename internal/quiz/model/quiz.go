@@ -0,0 +1,68 @@
+// quizmodel defines the data structures used for note quizzes.
+package quizmodel
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Verdict summarizes how well an attempt matched the reference solution.
+type Verdict string
+
+const (
+	VerdictCorrect   Verdict = "correct"
+	VerdictPartial   Verdict = "partial"
+	VerdictIncorrect Verdict = "incorrect"
+)
+
+// IsValid reports whether v is a known verdict.
+func (v Verdict) IsValid() bool {
+	return v == VerdictCorrect || v == VerdictPartial || v == VerdictIncorrect
+}
+
+// QuizAttempt is a graded answer of a user to a quiz on one of their notes.
+type QuizAttempt struct {
+	ID        uuid.UUID `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index" json:"userId"`
+	NoteID    uuid.UUID `gorm:"type:uuid;not null;index" json:"noteId"`
+	Model     string    `gorm:"type:varchar(255);not null" json:"model"`
+	Approach  string    `gorm:"type:text;not null" json:"approach"`
+	Code      string    `gorm:"type:text" json:"code"`
+	HintsUsed int       `gorm:"not null;default:0" json:"hintsUsed"`
+	Score     int       `gorm:"not null" json:"score"`
+	Verdict   Verdict   `gorm:"type:varchar(20);not null" json:"verdict"`
+	Feedback  string    `gorm:"type:text" json:"feedback"`
+	CreatedAt time.Time `gorm:"default:now()" json:"createdAt"`
+}
+
+// TableName overrides the table name used by QuizAttempt.
+func (QuizAttempt) TableName() string {
+	return "quiz_attempt"
+}
+
+// Question is a problem posed to the user from one of their notes.
+type Question struct {
+	NoteID   uuid.UUID `json:"noteId"`
+	Title    string    `json:"title"`
+	Level    string    `json:"level"`
+	Type     string    `json:"type"`
+	Question string    `json:"question"`
+}
+
+// Evaluation is the model's assessment of an attempt.
+type Evaluation struct {
+	Score    int     `json:"score"`
+	Verdict  Verdict `json:"verdict"`
+	Feedback string  `json:"feedback"`
+}
+
+// NoteScore summarizes the quiz results of a note.
+type NoteScore struct {
+	NoteID        uuid.UUID `json:"noteId"`
+	Attempts      int64     `json:"attempts"`
+	AverageScore  float64   `json:"averageScore"`
+	BestScore     int       `json:"bestScore"`
+	LastScore     int       `json:"lastScore"`
+	LastAttemptAt time.Time `json:"lastAttemptAt"`
+}
@@ -0,0 +1,39 @@
+package quizstorage
+
+import (
+	"github.com/google/uuid"
+	quizmodel "github.com/khoaphungnguyen/go-openai/internal/quiz/model"
+)
+
+// CreateAttempt adds a graded quiz attempt to the database.
+func (qs *quizStore) CreateAttempt(attempt *quizmodel.QuizAttempt) error {
+	return qs.db.Create(attempt).Error
+}
+
+// GetAttempts retrieves the user's most recent attempts, optionally for a single note.
+func (qs *quizStore) GetAttempts(userID uuid.UUID, noteID *uuid.UUID, limit int) ([]quizmodel.QuizAttempt, error) {
+	query := qs.db.Where("user_id = ?", userID)
+	if noteID != nil {
+		query = query.Where("note_id = ?", *noteID)
+	}
+	var attempts []quizmodel.QuizAttempt
+	err := query.Order("created_at DESC").Limit(limit).Find(&attempts).Error
+	return attempts, err
+}
+
+// GetNoteScores aggregates the user's quiz results per note, most recently attempted first.
+func (qs *quizStore) GetNoteScores(userID uuid.UUID) ([]quizmodel.NoteScore, error) {
+	var scores []quizmodel.NoteScore
+	err := qs.db.Model(&quizmodel.QuizAttempt{}).
+		Select(`note_id,
+			COUNT(*) AS attempts,
+			AVG(score) AS average_score,
+			MAX(score) AS best_score,
+			(ARRAY_AGG(score ORDER BY created_at DESC))[1] AS last_score,
+			MAX(created_at) AS last_attempt_at`).
+		Where("user_id = ?", userID).
+		Group("note_id").
+		Order("last_attempt_at DESC").
+		Scan(&scores).Error
+	return scores, err
+}
@@ -0,0 +1,25 @@
+// quizstorage provides data persistence logic for note quizzes.
+package quizstorage
+
+import (
+	"github.com/google/uuid"
+	quizmodel "github.com/khoaphungnguyen/go-openai/internal/quiz/model"
+	"gorm.io/gorm"
+)
+
+// QuizStore provides methods for quiz operations.
+type QuizStore interface {
+	CreateAttempt(attempt *quizmodel.QuizAttempt) error
+	GetAttempts(userID uuid.UUID, noteID *uuid.UUID, limit int) ([]quizmodel.QuizAttempt, error)
+	GetNoteScores(userID uuid.UUID) ([]quizmodel.NoteScore, error)
+}
+
+// quizStore encapsulates the logic for storing and retrieving quiz attempts.
+type quizStore struct {
+	db *gorm.DB
+}
+
+// NewQuizStore creates a new instance of quizStore.
+func NewQuizStore(db *gorm.DB) QuizStore {
+	return &quizStore{db: db}
+}
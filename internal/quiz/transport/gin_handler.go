@@ -0,0 +1,181 @@
+package quiztransport
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/khoaphungnguyen/go-openai/internal/common"
+	notemodel "github.com/khoaphungnguyen/go-openai/internal/note/model"
+	quizbusiness "github.com/khoaphungnguyen/go-openai/internal/quiz/business"
+	quizmodel "github.com/khoaphungnguyen/go-openai/internal/quiz/model"
+)
+
+type StartQuizPayload struct {
+	Model  string     `json:"model" binding:"required"`
+	NoteID *uuid.UUID `json:"noteId"`
+}
+
+type HintPayload struct {
+	Model     string `json:"model" binding:"required"`
+	Attempt   string `json:"attempt"`
+	HintsUsed int    `json:"hintsUsed"`
+}
+
+type AnswerPayload struct {
+	Model     string `json:"model" binding:"required"`
+	Approach  string `json:"approach" binding:"required"`
+	Code      string `json:"code"`
+	HintsUsed int    `json:"hintsUsed"`
+}
+
+type AnswerResponse struct {
+	Attempt  *quizmodel.QuizAttempt    `json:"attempt"`
+	Schedule *notemodel.ReviewSchedule `json:"schedule"`
+}
+
+// StartQuiz handles posing a problem from a note or from the review queue.
+func (qh *QuizHandler) StartQuiz(c *gin.Context) {
+	userID, err := common.GetUserIDFromContext(c)
+	if err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var payload StartQuizPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	question, err := qh.quizService.StartQuiz(c.Request.Context(), userID, payload.NoteID, payload.Model)
+	if err != nil {
+		respondWithQuizError(c, err, "Failed to start quiz")
+		return
+	}
+
+	common.RespondWithJSON(c, http.StatusOK, question)
+}
+
+// GetHint handles generating a hint for a quiz in progress.
+func (qh *QuizHandler) GetHint(c *gin.Context) {
+	userID, noteID, ok := parseNoteRequest(c)
+	if !ok {
+		return
+	}
+
+	var payload HintPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	hint, err := qh.quizService.GetHint(c.Request.Context(), userID, noteID, payload.Model, payload.Attempt, payload.HintsUsed)
+	if err != nil {
+		respondWithQuizError(c, err, "Failed to generate hint")
+		return
+	}
+
+	common.RespondWithJSON(c, http.StatusOK, gin.H{"hint": hint})
+}
+
+// SubmitAnswer handles grading the user's attempted approach for a note.
+func (qh *QuizHandler) SubmitAnswer(c *gin.Context) {
+	userID, noteID, ok := parseNoteRequest(c)
+	if !ok {
+		return
+	}
+
+	var payload AnswerPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	attempt, schedule, err := qh.quizService.SubmitAnswer(c.Request.Context(), userID, noteID, payload.Model, payload.Approach, payload.Code, payload.HintsUsed)
+	if err != nil {
+		respondWithQuizError(c, err, "Failed to grade answer")
+		return
+	}
+
+	common.RespondWithJSON(c, http.StatusOK, AnswerResponse{Attempt: attempt, Schedule: schedule})
+}
+
+// GetAttempts handles listing the user's quiz attempts, optionally filtered by the noteId query parameter.
+func (qh *QuizHandler) GetAttempts(c *gin.Context) {
+	userID, err := common.GetUserIDFromContext(c)
+	if err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var noteID *uuid.UUID
+	if raw := c.Query("noteId"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			common.RespondWithError(c, http.StatusBadRequest, "Invalid note ID")
+			return
+		}
+		noteID = &id
+	}
+
+	attempts, err := qh.quizService.GetAttempts(userID, noteID)
+	if err != nil {
+		common.RespondWithError(c, http.StatusInternalServerError, "Failed to retrieve attempts")
+		return
+	}
+
+	common.RespondWithJSON(c, http.StatusOK, attempts)
+}
+
+// GetNoteScores handles retrieving the user's quiz scores per note.
+func (qh *QuizHandler) GetNoteScores(c *gin.Context) {
+	userID, err := common.GetUserIDFromContext(c)
+	if err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	scores, err := qh.quizService.GetNoteScores(userID)
+	if err != nil {
+		common.RespondWithError(c, http.StatusInternalServerError, "Failed to retrieve scores")
+		return
+	}
+
+	common.RespondWithJSON(c, http.StatusOK, scores)
+}
+
+// parseNoteRequest extracts the caller and note IDs, responding on failure.
+func parseNoteRequest(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userID, err := common.GetUserIDFromContext(c)
+	if err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, err.Error())
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	noteID, err := uuid.Parse(c.Param("noteID"))
+	if err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, "Invalid note ID")
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, noteID, true
+}
+
+// respondWithQuizError maps quiz errors to HTTP responses.
+func respondWithQuizError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, quizbusiness.ErrNoteNotFound):
+		common.RespondWithError(c, http.StatusNotFound, "Note not found")
+	case errors.Is(err, quizbusiness.ErrNothingDue):
+		common.RespondWithError(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, quizbusiness.ErrEmptyAnswer):
+		common.RespondWithError(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, quizbusiness.ErrInvalidEvaluation):
+		common.RespondWithError(c, http.StatusBadGateway, "The model did not return a valid evaluation, please try again")
+	default:
+		log.Printf("Quiz error: %v", err)
+		common.RespondWithError(c, http.StatusInternalServerError, fallback)
+	}
+}
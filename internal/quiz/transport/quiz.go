@@ -0,0 +1,14 @@
+// quiztransport handles HTTP requests and responses for note quizzes.
+package quiztransport
+
+import quizbusiness "github.com/khoaphungnguyen/go-openai/internal/quiz/business"
+
+// QuizHandler handles quiz-related HTTP requests.
+type QuizHandler struct {
+	quizService *quizbusiness.QuizService
+}
+
+// NewQuizHandler creates a new QuizHandler.
+func NewQuizHandler(quizService *quizbusiness.QuizService) *QuizHandler {
+	return &QuizHandler{quizService: quizService}
+}
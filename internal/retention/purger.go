@@ -0,0 +1,60 @@
+// retention periodically and permanently removes records kept past their retention period.
+package retention
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// DefaultInterval is how often the purger looks for expired records.
+const DefaultInterval = time.Hour
+
+// Task purges one kind of record older than the cutoff and returns how many were removed.
+type Task struct {
+	Name string
+	// Retention is how long records are kept; Purge gets the time that long ago as its cutoff.
+	Retention time.Duration
+	Purge     func(cutoff time.Time) (int64, error)
+}
+
+// Purger runs purge tasks periodically in the background.
+type Purger struct {
+	interval time.Duration
+	tasks    []Task
+}
+
+// NewPurger creates a Purger. Tasks run in the given order on every pass.
+func NewPurger(interval time.Duration, tasks ...Task) *Purger {
+	return &Purger{interval: interval, tasks: tasks}
+}
+
+// Start runs a purge pass immediately and then every interval until ctx is cancelled.
+func (p *Purger) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+		for {
+			p.RunOnce(time.Now())
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// RunOnce purges the records of every task older than its retention period.
+func (p *Purger) RunOnce(now time.Time) {
+	for _, task := range p.tasks {
+		purged, err := task.Purge(now.Add(-task.Retention))
+		if err != nil {
+			log.Printf("Error purging %s: %v", task.Name, err)
+			continue
+		}
+		if purged > 0 {
+			log.Printf("Purged %d %s", purged, task.Name)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package retention
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRunOnceUsesEachTaskRetention(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	cutoffs := make(map[string]time.Time)
+	task := func(name string, retention time.Duration, err error) Task {
+		return Task{Name: name, Retention: retention, Purge: func(cutoff time.Time) (int64, error) {
+			cutoffs[name] = cutoff
+			return 1, err
+		}}
+	}
+
+	NewPurger(time.Hour,
+		task("failing", time.Hour, errors.New("database is down")),
+		task("trash", 30*24*time.Hour, nil),
+		task("audit", 365*24*time.Hour, nil),
+		task("orphans", 0, nil),
+	).RunOnce(now)
+
+	want := map[string]time.Time{
+		"failing": now.Add(-time.Hour),
+		"trash":   now.Add(-30 * 24 * time.Hour),
+		"audit":   now.Add(-365 * 24 * time.Hour),
+		"orphans": now,
+	}
+	for name, cutoff := range want {
+		if got, ok := cutoffs[name]; !ok || !got.Equal(cutoff) {
+			t.Errorf("%s purged before %v, want %v", name, got, cutoff)
+		}
+	}
+}
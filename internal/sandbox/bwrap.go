@@ -0,0 +1,89 @@
+package sandbox
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// workDir and cacheDir are where the run's directory and the Go build
+	// cache appear inside the sandbox.
+	workDir  = "/sandbox"
+	cacheDir = "/cache"
+
+	// nobody is the user programs run as, both inside the sandbox and on the host.
+	nobody = 65534
+)
+
+// systemDirs are mounted read-only into every sandbox so /bin/sh and
+// dynamically linked interpreters work. Missing ones are skipped.
+var systemDirs = []string{"/usr", "/bin", "/sbin", "/lib", "/lib64", "/lib32", "/etc/ld.so.cache"}
+
+// sandboxSpec describes what a sandboxed command may see.
+type sandboxSpec struct {
+	dir            string   // Mounted at workDir
+	writable       bool     // Whether dir is mounted read-write
+	cacheDir       string   // Mounted read-write at cacheDir when set
+	toolchain      []string // Binaries whose installation is mounted read-only
+	env            []string
+	dropPrivileges bool // Run as nobody rather than the server's user
+}
+
+// command returns a command running name inside a bubblewrap sandbox with its
+// own user, mount, PID, network, IPC and UTS namespaces. The root file system
+// only holds the system directories, the toolchain and the spec's directories,
+// and /proc shows nothing but the sandbox's own processes.
+func (r *Runner) command(ctx context.Context, spec sandboxSpec, name string, arg ...string) (*exec.Cmd, error) {
+	bwrap, err := exec.LookPath(r.config.BwrapBinary)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+
+	args := []string{"--unshare-all", "--die-with-parent", "--new-session", "--cap-drop", "ALL"}
+	for _, dir := range systemDirs {
+		args = append(args, "--ro-bind-try", dir, dir)
+	}
+	for _, binary := range spec.toolchain {
+		if root := toolchainRoot(binary); root != "" {
+			args = append(args, "--ro-bind", root, root)
+		}
+	}
+	args = append(args, "--proc", "/proc", "--dev", "/dev")
+	if spec.writable {
+		args = append(args, "--bind", spec.dir, workDir)
+	} else {
+		args = append(args, "--ro-bind", spec.dir, workDir)
+	}
+	if spec.cacheDir != "" {
+		args = append(args, "--bind", spec.cacheDir, cacheDir)
+	}
+	if spec.dropPrivileges {
+		args = append(args, "--unshare-user", "--uid", fmt.Sprint(nobody), "--gid", fmt.Sprint(nobody))
+	}
+	args = append(args, "--chdir", workDir, "--", name)
+
+	cmd := exec.CommandContext(ctx, bwrap, append(args, arg...)...)
+	cmd.Env = append([]string{"PATH=/usr/local/bin:/usr/bin:/bin"}, spec.env...)
+	if err := isolate(cmd, spec.dropPrivileges); err != nil {
+		return nil, err
+	}
+	return cmd, nil
+}
+
+// toolchainRoot returns the installation directory of a toolchain binary, such
+// as GOROOT for bin/go, or "" when the binary lies under a system directory
+// already mounted into the sandbox.
+func toolchainRoot(binary string) string {
+	if resolved, err := filepath.EvalSymlinks(binary); err == nil {
+		binary = resolved
+	}
+	for _, dir := range systemDirs {
+		if strings.HasPrefix(binary, dir+"/") {
+			return ""
+		}
+	}
+	return filepath.Dir(filepath.Dir(binary))
+}
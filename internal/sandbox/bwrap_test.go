@@ -0,0 +1,85 @@
+package sandbox
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newIntegrationRunner returns a Runner for running Python programs, skipping
+// the test on hosts that cannot sandbox them.
+func newIntegrationRunner(t *testing.T, limits Limits) *Runner {
+	t.Helper()
+	for _, binary := range []string{"bwrap", "python3"} {
+		if _, err := exec.LookPath(binary); err != nil {
+			t.Skipf("%s is not installed", binary)
+		}
+	}
+	r := NewRunner(Config{Limits: limits, CacheDir: t.TempDir()})
+	report, err := r.Run(context.Background(), LanguagePython, "print(input())", []TestCase{{Input: "ok", Expected: "ok"}})
+	if errors.Is(err, ErrUnavailable) {
+		t.Skipf("sandbox unavailable: %v", err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Passed != 1 {
+		t.Fatalf("echo program failed: %+v", report.Cases[0])
+	}
+	return r
+}
+
+func TestSandboxLimits(t *testing.T) {
+	limits := DefaultLimits
+	limits.Timeout = time.Second
+	limits.OutputBytes = 1 << 10
+	r := newIntegrationRunner(t, limits)
+	ctx := context.Background()
+
+	t.Run("timeout", func(t *testing.T) {
+		start := time.Now()
+		report, err := r.Run(ctx, LanguagePython, "while True:\n    pass\n", []TestCase{{Expected: ""}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result := report.Cases[0]; !result.TimedOut || result.Passed {
+			t.Errorf("looping program = %+v, want a timeout", result)
+		}
+		if elapsed := time.Since(start); elapsed > limits.Timeout+3*time.Second {
+			t.Errorf("looping program was stopped after %v", elapsed)
+		}
+	})
+
+	t.Run("output cap", func(t *testing.T) {
+		report, err := r.Run(ctx, LanguagePython, "print('x' * 100000)", []TestCase{{Expected: ""}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		result := report.Cases[0]
+		if !result.Truncated || result.Passed || len(result.Stdout) != limits.OutputBytes {
+			t.Errorf("chatty program kept %d bytes, truncated %v; want %d bytes, truncated",
+				len(result.Stdout), result.Truncated, limits.OutputBytes)
+		}
+	})
+
+	t.Run("no network", func(t *testing.T) {
+		source := strings.Join([]string{
+			"import socket",
+			"try:",
+			"    socket.create_connection(('1.1.1.1', 53), timeout=0.5)",
+			"    print('online')",
+			"except OSError:",
+			"    print('offline')",
+		}, "\n")
+		report, err := r.Run(ctx, LanguagePython, source, []TestCase{{Expected: "offline"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result := report.Cases[0]; !result.Passed {
+			t.Errorf("network program = %+v, want offline", result)
+		}
+	})
+}
@@ -0,0 +1,34 @@
+//go:build linux
+
+package sandbox
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// isolate starts the sandbox in its own process group, killed as a whole on
+// cancellation and when the server dies. When dropPrivileges is set the
+// sandbox starts as nobody, so even files it could reach outside its mounts
+// belong to another user than the server's. That needs the server to run as
+// root; otherwise the sandbox is unavailable rather than running programs
+// with the server's own user.
+func isolate(cmd *exec.Cmd, dropPrivileges bool) error {
+	attr := &syscall.SysProcAttr{
+		Setpgid:   true,
+		Pdeathsig: syscall.SIGKILL,
+	}
+	if dropPrivileges {
+		if os.Geteuid() != 0 {
+			return fmt.Errorf("%w: the server must run as root to run programs as a separate user", ErrUnavailable)
+		}
+		attr.Credential = &syscall.Credential{Uid: nobody, Gid: nobody}
+	}
+	cmd.SysProcAttr = attr
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	return nil
+}
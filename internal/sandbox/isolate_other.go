@@ -0,0 +1,10 @@
+//go:build !linux
+
+package sandbox
+
+import "os/exec"
+
+// isolate is only implemented on Linux, which provides the namespaces bubblewrap relies on.
+func isolate(cmd *exec.Cmd, dropPrivileges bool) error {
+	return ErrUnavailable
+}
@@ -0,0 +1,389 @@
+// sandbox compiles and runs untrusted code snippets against test cases in a
+// separate, resource-limited process without network access. Isolation is
+// delegated to bubblewrap: programs see a read-only root holding only the
+// toolchain and their work directory, with a private /proc, and run as a
+// different user than the server.
+package sandbox
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Language is a programming language the sandbox can run.
+type Language string
+
+const (
+	LanguageGo     Language = "go"
+	LanguagePython Language = "python"
+)
+
+// IsValid reports whether l is a supported language.
+func (l Language) IsValid() bool {
+	return l == LanguageGo || l == LanguagePython
+}
+
+const (
+	// MaxSourceSize bounds the size of a program.
+	MaxSourceSize = 64 << 10
+	// MaxTestCases bounds the number of test cases of a run.
+	MaxTestCases = 20
+	// MaxInputSize bounds the stdin of a single test case.
+	MaxInputSize = 64 << 10
+)
+
+var (
+	// ErrUnsupportedLanguage is returned for languages other than Go and Python.
+	ErrUnsupportedLanguage = errors.New("language must be go or python")
+	// ErrInvalidProgram is returned for empty or oversized programs and test cases.
+	ErrInvalidProgram = errors.New("invalid program")
+	// ErrUnavailable is returned when the host cannot isolate child processes.
+	ErrUnavailable = errors.New("code sandbox is not available on this host")
+)
+
+// Limits bounds the resources of sandboxed processes.
+type Limits struct {
+	// Timeout is the wall-clock limit of each test case.
+	Timeout time.Duration
+	// CompileTimeout is the wall-clock limit of compiling a Go program.
+	CompileTimeout time.Duration
+	// MemoryBytes is the address space limit of each test case.
+	MemoryBytes int64
+	// FileSizeBytes is the largest file a program may write.
+	FileSizeBytes int64
+	// OutputBytes is how much of stdout and stderr is kept per test case.
+	OutputBytes int
+	// Processes is how many processes and threads a program may have at once.
+	Processes int
+	// Concurrency is how many programs may run at once.
+	Concurrency int
+}
+
+// DefaultLimits are conservative limits for interview-sized solutions.
+var DefaultLimits = Limits{
+	Timeout:        5 * time.Second,
+	CompileTimeout: 30 * time.Second,
+	MemoryBytes:    256 << 20,
+	FileSizeBytes:  1 << 20,
+	OutputBytes:    64 << 10,
+	Processes:      64,
+	Concurrency:    2,
+}
+
+// TestCase is an input fed on stdin and the stdout expected for it.
+type TestCase struct {
+	Name     string `json:"name"`
+	Input    string `json:"input"`
+	Expected string `json:"expected"`
+}
+
+// CaseResult is the outcome of running a program on one test case.
+type CaseResult struct {
+	TestCase
+	Stdout     string `json:"stdout"`
+	Stderr     string `json:"stderr"`
+	ExitCode   int    `json:"exitCode"`
+	Passed     bool   `json:"passed"`
+	TimedOut   bool   `json:"timedOut"`
+	Truncated  bool   `json:"truncated"`
+	DurationMs int64  `json:"durationMs"`
+}
+
+// Report is the outcome of a run. When compilation fails no test case runs.
+type Report struct {
+	CompileFailed bool         `json:"compileFailed"`
+	CompileOutput string       `json:"compileOutput,omitempty"`
+	Cases         []CaseResult `json:"cases"`
+	Passed        int          `json:"passed"`
+	Total         int          `json:"total"`
+}
+
+// Config locates the toolchains used by the sandbox.
+type Config struct {
+	Limits Limits
+	// GoBinary and PythonBinary default to "go" and "python3" on the PATH.
+	GoBinary     string
+	PythonBinary string
+	// BwrapBinary is the bubblewrap executable, "bwrap" on the PATH by default.
+	BwrapBinary string
+	// CacheDir holds the Go build cache shared between compilations. Programs
+	// never see it.
+	CacheDir string
+}
+
+// Runner executes programs in the sandbox.
+type Runner struct {
+	config Config
+	slots  chan struct{}
+}
+
+// NewRunner creates a Runner, filling unset configuration with defaults.
+func NewRunner(config Config) *Runner {
+	if config.Limits == (Limits{}) {
+		config.Limits = DefaultLimits
+	}
+	if config.Limits.Concurrency <= 0 {
+		config.Limits.Concurrency = 1
+	}
+	if config.GoBinary == "" {
+		config.GoBinary = "go"
+	}
+	if config.PythonBinary == "" {
+		config.PythonBinary = "python3"
+	}
+	if config.BwrapBinary == "" {
+		config.BwrapBinary = "bwrap"
+	}
+	if config.CacheDir == "" {
+		config.CacheDir = filepath.Join(os.TempDir(), "sandbox-go-build")
+	}
+	return &Runner{config: config, slots: make(chan struct{}, config.Limits.Concurrency)}
+}
+
+// DetectLanguage guesses the language of source, returning "" when unsure.
+func DetectLanguage(source string) Language {
+	switch {
+	case strings.Contains(source, "package main"):
+		return LanguageGo
+	case strings.Contains(source, "def ") || strings.Contains(source, "print(") || strings.Contains(source, "input("):
+		return LanguagePython
+	}
+	return ""
+}
+
+// Run compiles source if needed and runs it once per test case. Programs read
+// the test input on stdin and are judged on stdout, ignoring trailing whitespace.
+func (r *Runner) Run(ctx context.Context, language Language, source string, cases []TestCase) (*Report, error) {
+	if !language.IsValid() {
+		return nil, ErrUnsupportedLanguage
+	}
+	if strings.TrimSpace(source) == "" || len(source) > MaxSourceSize {
+		return nil, fmt.Errorf("%w: source must be between 1 and %d bytes", ErrInvalidProgram, MaxSourceSize)
+	}
+	if len(cases) == 0 || len(cases) > MaxTestCases {
+		return nil, fmt.Errorf("%w: between 1 and %d test cases are required", ErrInvalidProgram, MaxTestCases)
+	}
+	for _, tc := range cases {
+		if len(tc.Input) > MaxInputSize || len(tc.Expected) > MaxInputSize {
+			return nil, fmt.Errorf("%w: test cases are limited to %d bytes", ErrInvalidProgram, MaxInputSize)
+		}
+	}
+
+	select {
+	case r.slots <- struct{}{}:
+		defer func() { <-r.slots }()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	dir, err := os.MkdirTemp("", "sandbox-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	if err := os.Chmod(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	report := &Report{Total: len(cases), Cases: make([]CaseResult, 0, len(cases))}
+	var argv, toolchain []string
+	switch language {
+	case LanguageGo:
+		output, ok, err := r.compileGo(ctx, dir, source)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			report.CompileFailed = true
+			report.CompileOutput = output
+			return report, nil
+		}
+		argv = []string{workDir + "/main"}
+	case LanguagePython:
+		if err := os.WriteFile(filepath.Join(dir, "main.py"), []byte(source), 0o644); err != nil {
+			return nil, err
+		}
+		python, err := exec.LookPath(r.config.PythonBinary)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrUnavailable, err)
+		}
+		argv = []string{python, "-I", "-B", workDir + "/main.py"}
+		toolchain = []string{python}
+	}
+
+	for _, tc := range cases {
+		result, err := r.runCase(ctx, dir, argv, toolchain, tc)
+		if err != nil {
+			return nil, err
+		}
+		if result.Passed {
+			report.Passed++
+		}
+		report.Cases = append(report.Cases, *result)
+	}
+	return report, nil
+}
+
+// compileGo builds a single-file Go program into dir/main. Only the standard
+// library is available since the module proxy is disabled.
+func (r *Runner) compileGo(ctx context.Context, dir, source string) (string, bool, error) {
+	files := map[string]string{
+		"main.go": source,
+		"go.mod":  "module sandbox\n\ngo 1.21\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			return "", false, err
+		}
+	}
+
+	goBinary, err := exec.LookPath(r.config.GoBinary)
+	if err != nil {
+		return "", false, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	if err := os.MkdirAll(r.config.CacheDir, 0o700); err != nil {
+		return "", false, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.config.Limits.CompileTimeout)
+	defer cancel()
+
+	// The compiler never executes user code, so it keeps the server's user and
+	// may write to the build cache, but it is otherwise sandboxed like programs.
+	cmd, err := r.command(ctx, sandboxSpec{
+		dir:       dir,
+		writable:  true,
+		cacheDir:  r.config.CacheDir,
+		toolchain: []string{goBinary},
+		env: []string{
+			"HOME=" + workDir,
+			"GOCACHE=" + cacheDir,
+			"GOPATH=" + workDir + "/gopath",
+			"GOPROXY=off",
+			"GOFLAGS=-mod=mod",
+			"GOTOOLCHAIN=local",
+			"CGO_ENABLED=0",
+		},
+	}, goBinary, "build", "-trimpath", "-o", "main", ".")
+	if err != nil {
+		return "", false, err
+	}
+	output := newCappedBuffer(r.config.Limits.OutputBytes)
+	cmd.Stdout = output
+	cmd.Stderr = output
+
+	err = cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return "compilation timed out", false, nil
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return output.String(), false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	return "", true, nil
+}
+
+// runCase runs argv with the test input on stdin under the resource limits.
+// The toolchain lists the interpreter argv needs, if any.
+func (r *Runner) runCase(ctx context.Context, dir string, argv, toolchain []string, tc TestCase) (*CaseResult, error) {
+	limits := r.config.Limits
+	ctx, cancel := context.WithTimeout(ctx, limits.Timeout)
+	defer cancel()
+
+	// ulimit applies the limits to the shell, which then execs the program in its
+	// place. The data limit is used over the address space limit because the Go
+	// runtime reserves far more address space than it ever touches. The process
+	// limit counts within the program's own user namespace, so it stops fork
+	// bombs without touching the server's processes.
+	script := fmt.Sprintf(`ulimit -c 0 && ulimit -d %d && ulimit -f %d && ulimit -u %d && exec "$0" "$@"`,
+		limits.MemoryBytes/1024, limits.FileSizeBytes/512, limits.Processes)
+	cmd, err := r.command(ctx, sandboxSpec{
+		dir:            dir,
+		dropPrivileges: true,
+		toolchain:      toolchain,
+		env: []string{
+			"HOME=" + workDir,
+			"LANG=C.UTF-8",
+			fmt.Sprintf("GOMEMLIMIT=%d", limits.MemoryBytes*3/4),
+		},
+	}, "/bin/sh", append([]string{"-c", script}, argv...)...)
+	if err != nil {
+		return nil, err
+	}
+	cmd.Stdin = strings.NewReader(tc.Input)
+	stdout := newCappedBuffer(limits.OutputBytes)
+	stderr := newCappedBuffer(limits.OutputBytes)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	cmd.WaitDelay = time.Second
+
+	start := time.Now()
+	err = cmd.Run()
+	result := &CaseResult{
+		TestCase:   tc,
+		Stdout:     stdout.String(),
+		Stderr:     stderr.String(),
+		Truncated:  stdout.truncated || stderr.truncated,
+		DurationMs: time.Since(start).Milliseconds(),
+		TimedOut:   ctx.Err() == context.DeadlineExceeded,
+	}
+
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+	case errors.Is(err, exec.ErrWaitDelay):
+		// The program exited but something kept its output open; judge the exit status alone
+		result.ExitCode = cmd.ProcessState.ExitCode()
+	case err != nil && !result.TimedOut:
+		return nil, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	result.Passed = !result.TimedOut && result.ExitCode == 0 && !result.Truncated &&
+		normalizeOutput(result.Stdout) == normalizeOutput(tc.Expected)
+	return result, nil
+}
+
+// normalizeOutput drops trailing whitespace from every line and trailing blank lines.
+func normalizeOutput(output string) string {
+	lines := strings.Split(strings.ReplaceAll(output, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n")
+}
+
+// cappedBuffer keeps the first limit bytes written to it and discards the rest.
+type cappedBuffer struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func newCappedBuffer(limit int) *cappedBuffer {
+	return &cappedBuffer{limit: limit}
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.buf.Len(); len(p) > room {
+		b.truncated = true
+		if room > 0 {
+			b.buf.Write(p[:room])
+		}
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+func (b *cappedBuffer) String() string {
+	return b.buf.String()
+}
@@ -0,0 +1,81 @@
+package sandbox
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNormalizeOutput(t *testing.T) {
+	tests := []struct {
+		output, want string
+	}{
+		{"42", "42"},
+		{"42\n", "42"},
+		{"a  \nb\t\n\n\n", "a\nb"},
+		{"a\r\nb\r\n", "a\nb"},
+		{"  indented\n", "  indented"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := normalizeOutput(tt.output); got != tt.want {
+			t.Errorf("normalizeOutput(%q) = %q, want %q", tt.output, got, tt.want)
+		}
+	}
+}
+
+func TestCappedBuffer(t *testing.T) {
+	b := newCappedBuffer(8)
+	for _, chunk := range []string{"abc", "defgh", "ijk", "lmn"} {
+		if n, err := b.Write([]byte(chunk)); n != len(chunk) || err != nil {
+			t.Fatalf("Write(%q) = %d, %v; want %d, nil", chunk, n, err, len(chunk))
+		}
+	}
+	if got := b.String(); got != "abcdefgh" {
+		t.Errorf("String() = %q, want %q", got, "abcdefgh")
+	}
+	if !b.truncated {
+		t.Error("buffer is not marked truncated")
+	}
+
+	b = newCappedBuffer(8)
+	b.Write([]byte("abcdefgh"))
+	if b.truncated {
+		t.Error("buffer filled exactly to its limit is marked truncated")
+	}
+}
+
+func TestToolchainRoot(t *testing.T) {
+	dir := t.TempDir()
+	goBinary := filepath.Join(dir, "go", "bin", "go")
+	if err := os.MkdirAll(filepath.Dir(goBinary), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(goBinary, nil, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, "go-link")
+	if err := os.Symlink(goBinary, link); err != nil {
+		t.Fatal(err)
+	}
+	// The temporary directory may itself sit behind a symbolic link
+	resolvedDir, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		binary, want string
+	}{
+		{goBinary, filepath.Join(resolvedDir, "go")},
+		{link, filepath.Join(resolvedDir, "go")},
+		// Installations under the system directories are already mounted
+		{"/usr/lib/sandbox-test/bin/python3", ""},
+		{"/bin/sandbox-test", ""},
+	}
+	for _, tt := range tests {
+		if got := toolchainRoot(tt.binary); got != tt.want {
+			t.Errorf("toolchainRoot(%q) = %q, want %q", tt.binary, got, tt.want)
+		}
+	}
+}
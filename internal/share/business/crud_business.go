@@ -0,0 +1,142 @@
+package sharebusiness
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	messagemodel "github.com/khoaphungnguyen/go-openai/internal/message/model"
+	sharemodel "github.com/khoaphungnguyen/go-openai/internal/share/model"
+	sharestorage "github.com/khoaphungnguyen/go-openai/internal/share/storage"
+)
+
+const shareTokenLength = 24
+
+var (
+	// ErrShareNotFound is returned when a share link does not exist or was revoked.
+	ErrShareNotFound = sharestorage.ErrShareNotFound
+	// ErrShareExpired is returned when a share link is past its expiry time.
+	ErrShareExpired = errors.New("share link has expired")
+	// ErrNotThreadOwner is returned when a user manages shares of a thread they do not own.
+	ErrNotThreadOwner = errors.New("user is not the owner of the thread")
+)
+
+// CreateShare creates a share link for a thread owned by the user.
+// A zero expiresIn creates a link that never expires.
+func (ss *ShareService) CreateShare(userID, threadID uuid.UUID, expiresIn time.Duration) (*sharemodel.ThreadShare, error) {
+	if !ss.messageService.IsUserThreadOwner(threadID, userID) {
+		return nil, ErrNotThreadOwner
+	}
+	thread, err := ss.messageService.GetThreadByID(threadID)
+	if err != nil {
+		return nil, err
+	}
+	if thread == nil {
+		return nil, ErrNotThreadOwner
+	}
+
+	token, err := generateShareToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate share token: %w", err)
+	}
+
+	now := time.Now()
+	share := &sharemodel.ThreadShare{
+		ThreadID:   threadID,
+		UserID:     userID,
+		Token:      token,
+		Title:      thread.Title,
+		SnapshotAt: now,
+	}
+	if expiresIn > 0 {
+		expiresAt := now.Add(expiresIn)
+		share.ExpiresAt = &expiresAt
+	}
+
+	if err := ss.shareStore.CreateShare(share); err != nil {
+		return nil, err
+	}
+	return share, nil
+}
+
+// GetSharesByThreadID lists the share links of a thread owned by the user.
+func (ss *ShareService) GetSharesByThreadID(userID, threadID uuid.UUID) ([]sharemodel.ThreadShare, error) {
+	if !ss.messageService.IsUserThreadOwner(threadID, userID) {
+		return nil, ErrNotThreadOwner
+	}
+	return ss.shareStore.GetSharesByThreadID(threadID)
+}
+
+// RevokeShare revokes a share link owned by the user.
+func (ss *ShareService) RevokeShare(userID, shareID uuid.UUID) error {
+	return ss.shareStore.RevokeShare(shareID, userID)
+}
+
+// GetSharedThread resolves a public token into the snapshot of the shared thread.
+func (ss *ShareService) GetSharedThread(token string) (*sharemodel.SharedThreadResponse, error) {
+	share, thread, err := ss.resolveShare(token)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := ss.messageService.GetMessagesByThreadIDUntil(share.ThreadID, share.SnapshotAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sharemodel.SharedThreadResponse{
+		Title:      share.Title,
+		Model:      thread.Model,
+		SnapshotAt: share.SnapshotAt,
+		ExpiresAt:  share.ExpiresAt,
+		Messages:   messages,
+	}, nil
+}
+
+// ForkSharedThread copies the snapshot of a shared thread into a new thread owned by the user.
+func (ss *ShareService) ForkSharedThread(userID uuid.UUID, token string) (*messagemodel.ChatThread, error) {
+	share, thread, err := ss.resolveShare(token)
+	if err != nil {
+		return nil, err
+	}
+	thread.Title = share.Title
+	return ss.messageService.ForkThread(userID, thread, share.SnapshotAt)
+}
+
+// resolveShare looks up an active share link and the thread it points to.
+func (ss *ShareService) resolveShare(token string) (*sharemodel.ThreadShare, *messagemodel.ChatThread, error) {
+	if token == "" {
+		return nil, nil, ErrShareNotFound
+	}
+	share, err := ss.shareStore.GetShareByToken(token)
+	if err != nil {
+		return nil, nil, err
+	}
+	if share.RevokedAt != nil {
+		return nil, nil, ErrShareNotFound
+	}
+	if !share.IsActive(time.Now()) {
+		return nil, nil, ErrShareExpired
+	}
+
+	thread, err := ss.messageService.GetThreadByID(share.ThreadID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if thread == nil {
+		return nil, nil, ErrShareNotFound
+	}
+	return share, thread, nil
+}
+
+// generateShareToken returns a random URL-safe token.
+func generateShareToken() (string, error) {
+	buf := make([]byte, shareTokenLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
@@ -0,0 +1,21 @@
+// sharebusiness contains the business logic for thread share links.
+package sharebusiness
+
+import (
+	messagebusiness "github.com/khoaphungnguyen/go-openai/internal/message/business"
+	sharestorage "github.com/khoaphungnguyen/go-openai/internal/share/storage"
+)
+
+// ShareService provides methods for share link operations.
+type ShareService struct {
+	shareStore     sharestorage.ShareStore
+	messageService *messagebusiness.MessageService
+}
+
+// NewShareService creates a new ShareService.
+func NewShareService(shareStore sharestorage.ShareStore, messageService *messagebusiness.MessageService) *ShareService {
+	return &ShareService{
+		shareStore:     shareStore,
+		messageService: messageService,
+	}
+}
@@ -0,0 +1,69 @@
+// sharemodel defines the data structures used for public thread share links.
+package sharemodel
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	messagemodel "github.com/khoaphungnguyen/go-openai/internal/message/model"
+)
+
+// ThreadShare represents a read-only snapshot link to a chat thread.
+// Only messages created up to SnapshotAt are visible through the link.
+type ThreadShare struct {
+	ID         uuid.UUID  `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	ThreadID   uuid.UUID  `gorm:"type:uuid;index;not null"`
+	UserID     uuid.UUID  `gorm:"type:uuid;not null"`
+	Token      string     `gorm:"type:varchar(64);uniqueIndex;not null"`
+	Title      string     `gorm:"type:varchar(255)"`
+	SnapshotAt time.Time  `gorm:"not null"`
+	ExpiresAt  *time.Time `gorm:"column:expires_at"`
+	RevokedAt  *time.Time `gorm:"column:revoked_at"`
+	CreatedAt  time.Time  `gorm:"default:now()"`
+}
+
+// TableName overrides the table name used by ThreadShare.
+func (ThreadShare) TableName() string {
+	return "thread_share"
+}
+
+// IsActive reports whether the share link can still be used at the given time.
+func (s *ThreadShare) IsActive(now time.Time) bool {
+	if s.RevokedAt != nil {
+		return false
+	}
+	return s.ExpiresAt == nil || now.Before(*s.ExpiresAt)
+}
+
+// ShareResponse is the owner-facing representation of a share link.
+type ShareResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	ThreadID   uuid.UUID  `json:"threadId"`
+	Token      string     `json:"token"`
+	SnapshotAt time.Time  `json:"snapshotAt"`
+	ExpiresAt  *time.Time `json:"expiresAt"`
+	RevokedAt  *time.Time `json:"revokedAt"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+// ToShareResponse converts a ThreadShare to its owner-facing representation.
+func (s *ThreadShare) ToShareResponse() ShareResponse {
+	return ShareResponse{
+		ID:         s.ID,
+		ThreadID:   s.ThreadID,
+		Token:      s.Token,
+		SnapshotAt: s.SnapshotAt,
+		ExpiresAt:  s.ExpiresAt,
+		RevokedAt:  s.RevokedAt,
+		CreatedAt:  s.CreatedAt,
+	}
+}
+
+// SharedThreadResponse is the public, unauthenticated view of a shared thread.
+type SharedThreadResponse struct {
+	Title      string                             `json:"title"`
+	Model      string                             `json:"model"`
+	SnapshotAt time.Time                          `json:"snapshotAt"`
+	ExpiresAt  *time.Time                         `json:"expiresAt"`
+	Messages   []messagemodel.ChatMessageResponse `json:"messages"`
+}
@@ -0,0 +1,66 @@
+package sharestorage
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	sharemodel "github.com/khoaphungnguyen/go-openai/internal/share/model"
+	"gorm.io/gorm"
+)
+
+// ErrShareNotFound is the error returned when a share link cannot be found.
+var ErrShareNotFound = errors.New("share not found")
+
+// CreateShare adds a new share link to the database.
+func (ss *shareStore) CreateShare(share *sharemodel.ThreadShare) error {
+	return ss.db.Create(share).Error
+}
+
+// GetShareByID retrieves a share link by its ID.
+func (ss *shareStore) GetShareByID(shareID uuid.UUID) (*sharemodel.ThreadShare, error) {
+	var share sharemodel.ThreadShare
+	err := ss.db.First(&share, "id = ?", shareID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrShareNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve share: %w", err)
+	}
+	return &share, nil
+}
+
+// GetShareByToken retrieves a share link by its public token.
+func (ss *shareStore) GetShareByToken(token string) (*sharemodel.ThreadShare, error) {
+	var share sharemodel.ThreadShare
+	err := ss.db.First(&share, "token = ?", token).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrShareNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve share: %w", err)
+	}
+	return &share, nil
+}
+
+// GetSharesByThreadID retrieves all share links created for a thread, newest first.
+func (ss *shareStore) GetSharesByThreadID(threadID uuid.UUID) ([]sharemodel.ThreadShare, error) {
+	var shares []sharemodel.ThreadShare
+	err := ss.db.Where("thread_id = ?", threadID).Order("created_at DESC").Find(&shares).Error
+	return shares, err
+}
+
+// RevokeShare marks a share link owned by the user as revoked.
+func (ss *shareStore) RevokeShare(shareID, userID uuid.UUID) error {
+	result := ss.db.Model(&sharemodel.ThreadShare{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", shareID, userID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrShareNotFound
+	}
+	return nil
+}
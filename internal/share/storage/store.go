@@ -0,0 +1,27 @@
+// sharestorage provides data persistence logic for thread share links.
+package sharestorage
+
+import (
+	"github.com/google/uuid"
+	sharemodel "github.com/khoaphungnguyen/go-openai/internal/share/model"
+	"gorm.io/gorm"
+)
+
+// ShareStore provides methods for share link operations.
+type ShareStore interface {
+	CreateShare(share *sharemodel.ThreadShare) error
+	GetShareByID(shareID uuid.UUID) (*sharemodel.ThreadShare, error)
+	GetShareByToken(token string) (*sharemodel.ThreadShare, error)
+	GetSharesByThreadID(threadID uuid.UUID) ([]sharemodel.ThreadShare, error)
+	RevokeShare(shareID, userID uuid.UUID) error
+}
+
+// shareStore encapsulates the logic for storing and retrieving share links.
+type shareStore struct {
+	db *gorm.DB
+}
+
+// NewShareStore creates a new instance of shareStore.
+func NewShareStore(db *gorm.DB) ShareStore {
+	return &shareStore{db: db}
+}
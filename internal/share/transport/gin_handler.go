@@ -0,0 +1,161 @@
+package sharetransport
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/khoaphungnguyen/go-openai/internal/common"
+	sharebusiness "github.com/khoaphungnguyen/go-openai/internal/share/business"
+	sharemodel "github.com/khoaphungnguyen/go-openai/internal/share/model"
+)
+
+type ShareCreateRequest struct {
+	// ExpiresInHours is optional; zero or omitted creates a link that never expires.
+	ExpiresInHours int `json:"expiresInHours" binding:"min=0"`
+}
+
+type ForkResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Title     string    `json:"title"`
+	Model     string    `json:"model"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// CreateShare handles the creation of a share link for a thread owned by the caller.
+func (sh *ShareHandler) CreateShare(c *gin.Context) {
+	userID, err := common.GetUserIDFromContext(c)
+	if err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	threadID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, "Invalid thread ID")
+		return
+	}
+
+	var payload ShareCreateRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			common.RespondWithError(c, http.StatusBadRequest, "Invalid request payload")
+			return
+		}
+	}
+
+	share, err := sh.shareService.CreateShare(userID, threadID, time.Duration(payload.ExpiresInHours)*time.Hour)
+	if err != nil {
+		if errors.Is(err, sharebusiness.ErrNotThreadOwner) {
+			common.RespondWithError(c, http.StatusForbidden, "Access denied")
+			return
+		}
+		common.RespondWithError(c, http.StatusInternalServerError, "Failed to create share link")
+		return
+	}
+
+	common.RespondWithJSON(c, http.StatusCreated, share.ToShareResponse())
+}
+
+// GetThreadShares handles listing the share links of a thread owned by the caller.
+func (sh *ShareHandler) GetThreadShares(c *gin.Context) {
+	userID, err := common.GetUserIDFromContext(c)
+	if err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	threadID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, "Invalid thread ID")
+		return
+	}
+
+	shares, err := sh.shareService.GetSharesByThreadID(userID, threadID)
+	if err != nil {
+		if errors.Is(err, sharebusiness.ErrNotThreadOwner) {
+			common.RespondWithError(c, http.StatusForbidden, "Access denied")
+			return
+		}
+		common.RespondWithError(c, http.StatusInternalServerError, "Failed to retrieve share links")
+		return
+	}
+
+	responses := make([]sharemodel.ShareResponse, 0, len(shares))
+	for _, share := range shares {
+		responses = append(responses, share.ToShareResponse())
+	}
+	common.RespondWithJSON(c, http.StatusOK, responses)
+}
+
+// RevokeShare handles revoking a share link owned by the caller.
+func (sh *ShareHandler) RevokeShare(c *gin.Context) {
+	userID, err := common.GetUserIDFromContext(c)
+	if err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	shareID, err := uuid.Parse(c.Param("shareID"))
+	if err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, "Invalid share ID")
+		return
+	}
+
+	if err := sh.shareService.RevokeShare(userID, shareID); err != nil {
+		if errors.Is(err, sharebusiness.ErrShareNotFound) {
+			common.RespondWithError(c, http.StatusNotFound, "Share link not found")
+			return
+		}
+		common.RespondWithError(c, http.StatusInternalServerError, "Failed to revoke share link")
+		return
+	}
+
+	common.RespondWithJSON(c, http.StatusOK, gin.H{"message": "Share link revoked successfully"})
+}
+
+// GetSharedThread renders the snapshot of a shared thread without authentication.
+func (sh *ShareHandler) GetSharedThread(c *gin.Context) {
+	shared, err := sh.shareService.GetSharedThread(c.Param("token"))
+	if err != nil {
+		respondWithShareError(c, err)
+		return
+	}
+	common.RespondWithJSON(c, http.StatusOK, shared)
+}
+
+// ForkSharedThread copies a shared thread into the caller's account.
+func (sh *ShareHandler) ForkSharedThread(c *gin.Context) {
+	userID, err := common.GetUserIDFromContext(c)
+	if err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	thread, err := sh.shareService.ForkSharedThread(userID, c.Param("token"))
+	if err != nil {
+		respondWithShareError(c, err)
+		return
+	}
+
+	common.RespondWithJSON(c, http.StatusCreated, ForkResponse{
+		ID:        thread.ID,
+		Title:     thread.Title,
+		Model:     thread.Model,
+		CreatedAt: thread.CreatedAt,
+	})
+}
+
+// respondWithShareError maps share resolution errors to HTTP responses.
+func respondWithShareError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, sharebusiness.ErrShareNotFound):
+		common.RespondWithError(c, http.StatusNotFound, "Share link not found")
+	case errors.Is(err, sharebusiness.ErrShareExpired):
+		common.RespondWithError(c, http.StatusGone, "Share link has expired")
+	default:
+		common.RespondWithError(c, http.StatusInternalServerError, "Failed to load shared thread")
+	}
+}
@@ -0,0 +1,14 @@
+// sharetransport handles HTTP requests and responses for thread share links.
+package sharetransport
+
+import sharebusiness "github.com/khoaphungnguyen/go-openai/internal/share/business"
+
+// ShareHandler handles share-related HTTP requests.
+type ShareHandler struct {
+	shareService *sharebusiness.ShareService
+}
+
+// NewShareHandler creates a new ShareHandler.
+func NewShareHandler(shareService *sharebusiness.ShareService) *ShareHandler {
+	return &ShareHandler{shareService: shareService}
+}
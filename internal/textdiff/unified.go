@@ -0,0 +1,250 @@
+// textdiff produces line-based unified diffs of text.
+package textdiff
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DefaultContext is the number of unchanged lines shown around each change.
+const DefaultContext = 3
+
+// MaxLines bounds the number of lines of either text. Comparing takes time
+// proportional to the product of the lines that differ.
+const MaxLines = 5000
+
+// ErrTooLarge is returned when a text has more than MaxLines lines.
+var ErrTooLarge = errors.New("diff too large")
+
+// opKind identifies a line of an edit script.
+type opKind byte
+
+const (
+	opEqual  opKind = ' '
+	opDelete opKind = '-'
+	opInsert opKind = '+'
+)
+
+type op struct {
+	kind opKind
+	line string
+	// aLine and bLine are the zero-based positions of the line in each input.
+	aLine, bLine int
+}
+
+// Unified returns the unified diff turning a into b, labelled with fromName and
+// toName, or an empty string when they are equal.
+func Unified(fromName, toName, a, b string, context int) (string, error) {
+	if a == b {
+		return "", nil
+	}
+	aLines, bLines := splitLines(a), splitLines(b)
+	if len(aLines) > MaxLines || len(bLines) > MaxLines {
+		return "", ErrTooLarge
+	}
+	ops := editScript(aLines, bLines)
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromName, toName)
+	for _, hunk := range hunks(ops, context) {
+		writeHunk(&out, ops[hunk[0]:hunk[1]])
+	}
+	return out.String(), nil
+}
+
+// splitLines splits text into lines without their terminators.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// editScript computes a shortest edit script from a longest common
+// subsequence of a and b. The common prefix and suffix are matched directly,
+// and the rest is compared in linear space.
+func editScript(a, b []string) []op {
+	// Compare lines by number rather than by content
+	ids := map[string]int{}
+	intern := func(lines []string) []int {
+		result := make([]int, len(lines))
+		for i, line := range lines {
+			id, ok := ids[line]
+			if !ok {
+				id = len(ids)
+				ids[line] = id
+			}
+			result[i] = id
+		}
+		return result
+	}
+	x, y := intern(a), intern(b)
+
+	prefix := 0
+	for prefix < len(x) && prefix < len(y) && x[prefix] == y[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(x)-prefix && suffix < len(y)-prefix && x[len(x)-1-suffix] == y[len(y)-1-suffix] {
+		suffix++
+	}
+
+	matches := make([][2]int, 0, prefix+suffix)
+	for i := 0; i < prefix; i++ {
+		matches = append(matches, [2]int{i, i})
+	}
+	matches = commonSubsequence(x[prefix:len(x)-suffix], y[prefix:len(y)-suffix], prefix, prefix, matches)
+	for i := 0; i < suffix; i++ {
+		matches = append(matches, [2]int{len(x) - suffix + i, len(y) - suffix + i})
+	}
+
+	ops := make([]op, 0, len(a)+len(b)-len(matches))
+	i, j := 0, 0
+	for _, match := range append(matches, [2]int{len(a), len(b)}) {
+		for ; i < match[0]; i++ {
+			ops = append(ops, op{opDelete, a[i], i, j})
+		}
+		for ; j < match[1]; j++ {
+			ops = append(ops, op{opInsert, b[j], i, j})
+		}
+		if i < len(a) && j < len(b) {
+			ops = append(ops, op{opEqual, a[i], i, j})
+			i++
+			j++
+		}
+	}
+	return ops
+}
+
+// commonSubsequence appends the positions of a longest common subsequence of
+// x and y to matches, in order, using Hirschberg's divide and conquer so only
+// two rows of lengths are kept at a time. xOff and yOff are the positions of
+// x and y in the full inputs.
+func commonSubsequence(x, y []int, xOff, yOff int, matches [][2]int) [][2]int {
+	if len(x) == 0 || len(y) == 0 {
+		return matches
+	}
+	if len(x) == 1 {
+		for j, id := range y {
+			if id == x[0] {
+				return append(matches, [2]int{xOff, yOff + j})
+			}
+		}
+		return matches
+	}
+
+	// Split y where the best subsequences of both halves of x meet
+	mid := len(x) / 2
+	forward := prefixLengths(x[:mid], y)
+	backward := suffixLengths(x[mid:], y)
+	split, best := 0, -1
+	for j := 0; j <= len(y); j++ {
+		if total := forward[j] + backward[j]; total > best {
+			split, best = j, total
+		}
+	}
+	matches = commonSubsequence(x[:mid], y[:split], xOff, yOff, matches)
+	return commonSubsequence(x[mid:], y[split:], xOff+mid, yOff+split, matches)
+}
+
+// prefixLengths returns, for every j, the length of a longest common
+// subsequence of x and y[:j].
+func prefixLengths(x, y []int) []int {
+	prev, cur := make([]int, len(y)+1), make([]int, len(y)+1)
+	for _, id := range x {
+		for j := 1; j <= len(y); j++ {
+			switch {
+			case id == y[j-1]:
+				cur[j] = prev[j-1] + 1
+			case prev[j] >= cur[j-1]:
+				cur[j] = prev[j]
+			default:
+				cur[j] = cur[j-1]
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev
+}
+
+// suffixLengths returns, for every j, the length of a longest common
+// subsequence of x and y[j:].
+func suffixLengths(x, y []int) []int {
+	prev, cur := make([]int, len(y)+1), make([]int, len(y)+1)
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			switch {
+			case x[i] == y[j]:
+				cur[j] = prev[j+1] + 1
+			case prev[j] >= cur[j+1]:
+				cur[j] = prev[j]
+			default:
+				cur[j] = cur[j+1]
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev
+}
+
+// hunks groups changed lines with their context into [start, end) ranges of ops.
+func hunks(ops []op, context int) [][2]int {
+	var result [][2]int
+	for i := 0; i < len(ops); i++ {
+		if ops[i].kind == opEqual {
+			continue
+		}
+		start := i - context
+		if start < 0 {
+			start = 0
+		}
+		end := i + 1
+		// Extend the hunk while the next change is within two contexts.
+		for k := i + 1; k < len(ops) && k <= end-1+2*context; k++ {
+			if ops[k].kind != opEqual {
+				end = k + 1
+			}
+		}
+		end += context
+		if end > len(ops) {
+			end = len(ops)
+		}
+		if n := len(result); n > 0 && start <= result[n-1][1] {
+			result[n-1][1] = end
+		} else {
+			result = append(result, [2]int{start, end})
+		}
+		i = end - 1
+	}
+	return result
+}
+
+// writeHunk writes a hunk header followed by its lines.
+func writeHunk(out *strings.Builder, ops []op) {
+	aStart, bStart := ops[0].aLine, ops[0].bLine
+	aCount, bCount := 0, 0
+	for _, o := range ops {
+		if o.kind != opInsert {
+			aCount++
+		}
+		if o.kind != opDelete {
+			bCount++
+		}
+	}
+	fmt.Fprintf(out, "@@ -%s +%s @@\n", hunkRange(aStart, aCount), hunkRange(bStart, bCount))
+	for _, o := range ops {
+		fmt.Fprintf(out, "%c%s\n", o.kind, o.line)
+	}
+}
+
+// hunkRange formats a hunk position as used by diff -u.
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
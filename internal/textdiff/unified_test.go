@@ -0,0 +1,102 @@
+package textdiff
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestUnified(t *testing.T) {
+	a := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"
+	b := "1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n"
+	want := "--- a\n+++ b\n" +
+		"@@ -1,6 +1,6 @@\n 1\n 2\n-3\n+three\n 4\n 5\n 6\n" +
+		"@@ -10,3 +10,4 @@\n 10\n 11\n 12\n+13\n"
+	got, err := Unified("a", "b", a, b, DefaultContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("Unified() =\n%s\nwant\n%s", got, want)
+	}
+
+	if got, err := Unified("a", "b", a, a, DefaultContext); got != "" || err != nil {
+		t.Errorf("Unified() of equal texts = %q, %v; want empty", got, err)
+	}
+}
+
+func TestUnifiedTooLarge(t *testing.T) {
+	large := strings.Repeat("line\n", MaxLines+1)
+	if _, err := Unified("a", "b", large, "", DefaultContext); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("Unified() error = %v, want ErrTooLarge", err)
+	}
+	if _, err := Unified("a", "b", "", strings.Repeat("line\n", MaxLines), DefaultContext); err != nil {
+		t.Errorf("Unified() at the limit: %v", err)
+	}
+}
+
+// TestEditScript checks that random edit scripts are minimal and rebuild both inputs.
+func TestEditScript(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	words := []string{"a", "b", "c", "d"}
+	randomLines := func() []string {
+		lines := make([]string, rng.Intn(12))
+		for i := range lines {
+			lines[i] = words[rng.Intn(len(words))]
+		}
+		return lines
+	}
+
+	for n := 0; n < 500; n++ {
+		a, b := randomLines(), randomLines()
+		ops := editScript(a, b)
+
+		var gotA, gotB []string
+		equal := 0
+		for _, o := range ops {
+			if o.kind != opInsert {
+				if o.aLine != len(gotA) {
+					t.Fatalf("editScript(%q, %q): op %+v has wrong position in a", a, b, o)
+				}
+				gotA = append(gotA, o.line)
+			}
+			if o.kind != opDelete {
+				if o.bLine != len(gotB) {
+					t.Fatalf("editScript(%q, %q): op %+v has wrong position in b", a, b, o)
+				}
+				gotB = append(gotB, o.line)
+			}
+			if o.kind == opEqual {
+				equal++
+			}
+		}
+		if strings.Join(gotA, "\n") != strings.Join(a, "\n") || strings.Join(gotB, "\n") != strings.Join(b, "\n") {
+			t.Fatalf("editScript(%q, %q) does not rebuild the inputs", a, b)
+		}
+		if want := lcsLength(a, b); equal != want {
+			t.Fatalf("editScript(%q, %q) keeps %d lines, want %d", a, b, equal, want)
+		}
+	}
+}
+
+// lcsLength computes the length of a longest common subsequence with the full table.
+func lcsLength(a, b []string) int {
+	table := make([][]int, len(a)+1)
+	for i := range table {
+		table[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				table[i][j] = table[i+1][j+1] + 1
+			case table[i+1][j] >= table[i][j+1]:
+				table[i][j] = table[i+1][j]
+			default:
+				table[i][j] = table[i][j+1]
+			}
+		}
+	}
+	return table[0][0]
+}
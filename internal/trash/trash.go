@@ -0,0 +1,19 @@
+// trash permanently removes threads and notes that stayed in the trash past the retention period.
+package trash
+
+import (
+	"time"
+
+	"github.com/khoaphungnguyen/go-openai/internal/retention"
+)
+
+// DefaultRetention is how long trashed items are kept before being purged.
+const DefaultRetention = 30 * 24 * time.Hour
+
+// Tasks returns the retention tasks purging threads and notes trashed longer than period ago.
+func Tasks(period time.Duration, purgeThreads, purgeNotes func(cutoff time.Time) (int64, error)) []retention.Task {
+	return []retention.Task{
+		{Name: "trashed threads", Retention: period, Purge: purgeThreads},
+		{Name: "trashed notes", Retention: period, Purge: purgeNotes},
+	}
+}
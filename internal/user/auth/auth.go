@@ -13,6 +13,16 @@ const (
 	DefaultRefreshTokenDuration = 7 * 24 * time.Hour
 )
 
+// TokenType tells access tokens apart from refresh tokens.
+type TokenType string
+
+const (
+	AccessToken            TokenType = "access"
+	RefreshToken           TokenType = "refresh"
+	EmailVerificationToken TokenType = "email_verification"
+	MFAChallengeToken      TokenType = "mfa_challenge"
+)
+
 type JwtWrapper struct {
 	SecretKey              string        // Key used for signing the JWT token
 	Issuer                 string        // Issuer of the JWT token
@@ -21,16 +31,26 @@ type JwtWrapper struct {
 }
 
 type CustomClaims struct {
-	UserID   string `json:"userId"`
-	FullName string `json:"fullName"`
+	UserID    string    `json:"userId"`
+	FullName  string    `json:"fullName"`
+	TokenType TokenType `json:"tokenType"`
+	Role      string    `json:"role,omitempty"`      // Role of the user, set on access tokens only
+	SessionID string    `json:"sessionId,omitempty"` // Session the token was issued for
+	MFA       bool      `json:"mfa,omitempty"`       // Session was opened with a second factor
+	FamilyID  string    `json:"familyId,omitempty"`  // Refresh token family, set on refresh tokens only
+	Email     string    `json:"email,omitempty"`     // Address being verified, set on email verification tokens only
 	jwt.StandardClaims
 }
 
-// GenerateToken generates a JWT token with custom claims.
-func (j *JwtWrapper) GenerateToken(userID, fullName string) (string, error) {
+// GenerateToken generates a JWT access token with custom claims.
+func (j *JwtWrapper) GenerateToken(userID, fullName, role, sessionID string, mfa bool) (string, error) {
 	claims := &CustomClaims{
-		UserID:   userID,
-		FullName: fullName,
+		UserID:    userID,
+		FullName:  fullName,
+		TokenType: AccessToken,
+		Role:      role,
+		SessionID: sessionID,
+		MFA:       mfa,
 		StandardClaims: jwt.StandardClaims{
 			ExpiresAt: time.Now().UTC().Add(j.AccessTokenExpiration).Unix(),
 			Issuer:    j.Issuer,
@@ -41,13 +61,17 @@ func (j *JwtWrapper) GenerateToken(userID, fullName string) (string, error) {
 	return token.SignedString([]byte(j.SecretKey))
 }
 
-// RefreshToken generates a refresh JWT token with a longer lifespan.
-func (j *JwtWrapper) RefreshToken(userID, fullName string) (string, error) {
+// RefreshToken generates a refresh JWT token with a longer lifespan. The token
+// ID and family ID tie it to its server-side record.
+func (j *JwtWrapper) RefreshToken(userID, fullName, tokenID, familyID string, expiresAt time.Time) (string, error) {
 	claims := &CustomClaims{
-		UserID:   userID,
-		FullName: fullName,
+		UserID:    userID,
+		FullName:  fullName,
+		TokenType: RefreshToken,
+		FamilyID:  familyID,
 		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: time.Now().UTC().Add(j.RefreshTokenExpiration).Unix(),
+			Id:        tokenID,
+			ExpiresAt: expiresAt.UTC().Unix(),
 			Issuer:    j.Issuer,
 		},
 	}
@@ -56,8 +80,41 @@ func (j *JwtWrapper) RefreshToken(userID, fullName string) (string, error) {
 	return token.SignedString([]byte(j.SecretKey))
 }
 
-// ValidateToken validates the JWT token.
-func (j *JwtWrapper) ValidateToken(signedToken string) (*CustomClaims, error) {
+// VerificationToken generates a JWT token proving that its holder received
+// mail sent to the given address.
+func (j *JwtWrapper) VerificationToken(userID, email string, expiresAt time.Time) (string, error) {
+	claims := &CustomClaims{
+		UserID:    userID,
+		TokenType: EmailVerificationToken,
+		Email:     email,
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: expiresAt.UTC().Unix(),
+			Issuer:    j.Issuer,
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(j.SecretKey))
+}
+
+// ChallengeToken generates a short-lived JWT token proving that its holder
+// passed the password step of a login that still needs a second factor.
+func (j *JwtWrapper) ChallengeToken(userID string, expiresAt time.Time) (string, error) {
+	claims := &CustomClaims{
+		UserID:    userID,
+		TokenType: MFAChallengeToken,
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: expiresAt.UTC().Unix(),
+			Issuer:    j.Issuer,
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(j.SecretKey))
+}
+
+// ValidateToken validates the JWT token and checks that it is of the expected type.
+func (j *JwtWrapper) ValidateToken(signedToken string, tokenType TokenType) (*CustomClaims, error) {
 	token, err := jwt.ParseWithClaims(signedToken, &CustomClaims{}, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
@@ -73,6 +130,9 @@ func (j *JwtWrapper) ValidateToken(signedToken string) (*CustomClaims, error) {
 	if !ok {
 		return nil, fmt.Errorf("invalid token claims")
 	}
+	if claims.TokenType != tokenType {
+		return nil, fmt.Errorf("unexpected token type: %q", claims.TokenType)
+	}
 
 	return claims, nil
 }
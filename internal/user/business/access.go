@@ -0,0 +1,115 @@
+package userbusiness
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	storageuser "github.com/khoaphungnguyen/go-openai/internal/user/storage"
+)
+
+const (
+	// AccessCheckInterval is how long the outcome of checking an access token's
+	// session is reused. Revocations made by this server apply at once; those
+	// made by other instances apply within the interval.
+	AccessCheckInterval = 15 * time.Second
+
+	// maxAccessEntries bounds the cache before stale entries are swept out.
+	maxAccessEntries = 10000
+)
+
+// ErrSessionRevoked is returned for access tokens whose session was revoked or has expired.
+var ErrSessionRevoked = errors.New("session has ended")
+
+// accessEntry is the cached outcome of checking one session.
+type accessEntry struct {
+	userID    uuid.UUID
+	err       error
+	checkedAt time.Time
+}
+
+// accessCache remembers recent session checks so that access tokens can be
+// revoked without a database lookup on every request.
+type accessCache struct {
+	mu      sync.Mutex
+	entries map[uuid.UUID]accessEntry
+}
+
+func newAccessCache() *accessCache {
+	return &accessCache{entries: map[uuid.UUID]accessEntry{}}
+}
+
+// CheckAccess verifies that the session an access token was issued for is
+// still active and that its user is not suspended. Access tokens stop working
+// as soon as their session is revoked: on logout, password changes and resets,
+// suspension and role changes.
+func (s *UserService) CheckAccess(userID, sessionID uuid.UUID) error {
+	if entry, ok := s.access.get(sessionID); ok && entry.userID == userID {
+		return entry.err
+	}
+
+	session, err := s.userStore.GetSession(sessionID)
+	switch {
+	case errors.Is(err, storageuser.ErrSessionNotFound):
+		err = ErrSessionRevoked
+	case err != nil:
+		return err
+	case session.UserID != userID || session.RevokedAt != nil || time.Now().After(session.ExpiresAt):
+		err = ErrSessionRevoked
+	default:
+		user, lookupErr := s.userStore.GetUserByUUID(userID)
+		if lookupErr != nil {
+			return lookupErr
+		}
+		if user.SuspendedAt != nil {
+			err = ErrAccountSuspended
+		}
+	}
+	s.access.put(sessionID, accessEntry{userID: userID, err: err, checkedAt: time.Now()})
+	return err
+}
+
+// get returns the cached check of a session if it is recent enough.
+func (ac *accessCache) get(sessionID uuid.UUID) (accessEntry, bool) {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+	entry, ok := ac.entries[sessionID]
+	if !ok || time.Since(entry.checkedAt) >= AccessCheckInterval {
+		return accessEntry{}, false
+	}
+	return entry, true
+}
+
+// put caches the check of a session, sweeping out stale entries when the
+// cache is full.
+func (ac *accessCache) put(sessionID uuid.UUID, entry accessEntry) {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+	if len(ac.entries) >= maxAccessEntries {
+		for id, cached := range ac.entries {
+			if time.Since(cached.checkedAt) >= AccessCheckInterval {
+				delete(ac.entries, id)
+			}
+		}
+	}
+	ac.entries[sessionID] = entry
+}
+
+// forgetSession drops the cached check of a session.
+func (ac *accessCache) forgetSession(sessionID uuid.UUID) {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+	delete(ac.entries, sessionID)
+}
+
+// forgetUser drops the cached checks of every session of a user.
+func (ac *accessCache) forgetUser(userID uuid.UUID) {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+	for id, entry := range ac.entries {
+		if entry.userID == userID {
+			delete(ac.entries, id)
+		}
+	}
+}
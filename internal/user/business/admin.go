@@ -0,0 +1,146 @@
+package userbusiness
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	modeluser "github.com/khoaphungnguyen/go-openai/internal/user/model"
+	"github.com/khoaphungnguyen/go-openai/internal/user/utils"
+)
+
+const (
+	// DefaultUserPageSize and MaxUserPageSize bound the pages of the admin user list.
+	DefaultUserPageSize = 20
+	MaxUserPageSize     = 100
+)
+
+var (
+	// ErrInvalidRole is returned when a user is given a role that does not exist.
+	ErrInvalidRole = errors.New("invalid role")
+	// ErrInvalidUserStatus is returned when users are filtered by an unknown status.
+	ErrInvalidUserStatus = errors.New("invalid user status")
+	// ErrOwnAccount is returned when administrators try to change the role of or
+	// suspend their own account, which could lock everyone out.
+	ErrOwnAccount = errors.New("administrators cannot change their own role or suspend themselves")
+)
+
+// SearchUsers returns a page of the users matching the filter and the number of matching users.
+func (s *UserService) SearchUsers(filter modeluser.UserFilter) ([]modeluser.AdminUser, int64, error) {
+	if filter.Role != "" && !filter.Role.Valid() {
+		return nil, 0, ErrInvalidRole
+	}
+	switch filter.Status {
+	case "", modeluser.StatusActive, modeluser.StatusSuspended, modeluser.StatusDeleted:
+	default:
+		return nil, 0, ErrInvalidUserStatus
+	}
+
+	users, total, err := s.userStore.SearchUsers(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	results := make([]modeluser.AdminUser, 0, len(users))
+	for i := range users {
+		results = append(results, users[i].ToAdminUser())
+	}
+	return results, total, nil
+}
+
+// ChangeRole gives a user a new role. The user's sessions are ended, which
+// also stops the access tokens carrying the old role.
+func (s *UserService) ChangeRole(actorID, userID uuid.UUID, role modeluser.Role) (*modeluser.AdminUser, error) {
+	if !role.Valid() {
+		return nil, ErrInvalidRole
+	}
+	if actorID == userID {
+		return nil, ErrOwnAccount
+	}
+	user, err := s.userStore.GetUserByUUID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role != role {
+		if err := s.userStore.UpdateRole(userID, role); err != nil {
+			return nil, err
+		}
+		if err := s.userStore.RevokeUserSessions(userID); err != nil {
+			return nil, err
+		}
+		s.access.forgetUser(userID)
+		user.Role = role
+	}
+	result := user.ToAdminUser()
+	return &result, nil
+}
+
+// SuspendUser stops a user from logging in or using API keys and ends their
+// sessions. Their API keys work again once the suspension is lifted.
+func (s *UserService) SuspendUser(actorID, userID uuid.UUID) (*modeluser.AdminUser, error) {
+	if actorID == userID {
+		return nil, ErrOwnAccount
+	}
+	user, err := s.userStore.GetUserByUUID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.SuspendedAt == nil {
+		now := time.Now()
+		if err := s.userStore.SetSuspended(userID, &now); err != nil {
+			return nil, err
+		}
+		if err := s.userStore.RevokeUserSessions(userID); err != nil {
+			return nil, err
+		}
+		s.access.forgetUser(userID)
+		user.SuspendedAt = &now
+	}
+	result := user.ToAdminUser()
+	return &result, nil
+}
+
+// UnsuspendUser lifts the suspension of a user.
+func (s *UserService) UnsuspendUser(userID uuid.UUID) (*modeluser.AdminUser, error) {
+	user, err := s.userStore.GetUserByUUID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.SuspendedAt != nil {
+		if err := s.userStore.SetSuspended(userID, nil); err != nil {
+			return nil, err
+		}
+		user.SuspendedAt = nil
+	}
+	result := user.ToAdminUser()
+	return &result, nil
+}
+
+// ForcePasswordReset makes a user choose a new password. The current password
+// stops working, every session and API key of the user is revoked, and a
+// password reset link is mailed to them.
+func (s *UserService) ForcePasswordReset(ctx context.Context, userID uuid.UUID) error {
+	user, err := s.userStore.GetUserByUUID(userID)
+	if err != nil {
+		return err
+	}
+
+	// Nobody knows the replacement, so only the reset link can open the account
+	placeholder, err := utils.RandomToken(32)
+	if err != nil {
+		return err
+	}
+	if err := s.setPassword(userID, placeholder); err != nil {
+		return err
+	}
+	if err := s.userStore.RevokeUserAPIKeys(userID); err != nil {
+		return err
+	}
+	if err := s.userStore.RevokeUserSessions(userID); err != nil {
+		return err
+	}
+	s.access.forgetUser(userID)
+	return s.mailPasswordReset(ctx, user, "Choose a new password",
+		"An administrator reset the password of your account, and you have been logged out everywhere. Open the link below to choose a new password:",
+		"When it expires, ask for a new link from the login page.")
+}
@@ -0,0 +1,168 @@
+package userbusiness
+
+import (
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/khoaphungnguyen/go-openai/internal/common"
+	modeluser "github.com/khoaphungnguyen/go-openai/internal/user/model"
+	storageuser "github.com/khoaphungnguyen/go-openai/internal/user/storage"
+	"github.com/khoaphungnguyen/go-openai/internal/user/utils"
+)
+
+const (
+	// APIKeyPrefix starts every API key, which tells them apart from access tokens.
+	APIKeyPrefix = "sck_"
+	// MaxAPIKeys is how many active API keys a user can have at once.
+	MaxAPIKeys = 20
+	// apiKeyDisplayLength is how much of a key is kept to identify it.
+	apiKeyDisplayLength = len(APIKeyPrefix) + 8
+	// apiKeyTouchInterval limits how often the last use of a key is recorded.
+	apiKeyTouchInterval = time.Minute
+)
+
+var (
+	// ErrAPIKeyNotFound is returned when an API key does not exist or belongs to another user.
+	ErrAPIKeyNotFound = errors.New("api key not found")
+	// ErrInvalidAPIKey is returned when an API key is unknown, revoked or expired.
+	ErrInvalidAPIKey = errors.New("invalid or expired api key")
+	// ErrInvalidScope is returned when an API key is requested without scopes or with an unknown one.
+	ErrInvalidScope = errors.New("invalid api key scope")
+	// ErrTooManyAPIKeys is returned when a user already has MaxAPIKeys active keys.
+	ErrTooManyAPIKeys = errors.New("too many api keys")
+)
+
+// IsAPIKey reports whether a bearer credential is an API key rather than an access token.
+func IsAPIKey(credential string) bool {
+	return strings.HasPrefix(credential, APIKeyPrefix)
+}
+
+// CreateAPIKey creates an API key for the user. The returned response is the
+// only place the key itself appears; afterwards only its prefix is known.
+// A zero expiresIn creates a key that never expires.
+func (s *UserService) CreateAPIKey(userID uuid.UUID, name string, scopes []string, expiresIn time.Duration) (*modeluser.APIKeyResponse, error) {
+	scopes, err := normalizeScopes(scopes)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := s.userStore.GetActiveAPIKeys(userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= MaxAPIKeys {
+		return nil, ErrTooManyAPIKeys
+	}
+
+	secret, err := utils.RandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	value := APIKeyPrefix + secret
+	key := &modeluser.APIKey{
+		UserID:    userID,
+		Name:      common.Truncate(strings.TrimSpace(name), 100),
+		Prefix:    value[:apiKeyDisplayLength],
+		KeyHash:   hashToken(value),
+		Scopes:    scopes,
+		CreatedAt: time.Now(),
+	}
+	if expiresIn > 0 {
+		expiresAt := key.CreatedAt.Add(expiresIn)
+		key.ExpiresAt = &expiresAt
+	}
+	if err := s.userStore.CreateAPIKey(key); err != nil {
+		return nil, err
+	}
+
+	response := key.ToResponse()
+	response.Key = value
+	return &response, nil
+}
+
+// GetAPIKeys returns the user's active API keys.
+func (s *UserService) GetAPIKeys(userID uuid.UUID) ([]modeluser.APIKeyResponse, error) {
+	keys, err := s.userStore.GetActiveAPIKeys(userID)
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]modeluser.APIKeyResponse, 0, len(keys))
+	for i := range keys {
+		responses = append(responses, keys[i].ToResponse())
+	}
+	return responses, nil
+}
+
+// RevokeAPIKey revokes one of the user's API keys.
+func (s *UserService) RevokeAPIKey(userID, keyID uuid.UUID) error {
+	err := s.userStore.RevokeAPIKey(keyID, userID)
+	if errors.Is(err, storageuser.ErrAPIKeyNotFound) {
+		return ErrAPIKeyNotFound
+	}
+	return err
+}
+
+// AuthenticateAPIKey resolves an API key presented with a request and records
+// its use. Keys of deleted and suspended accounts are refused.
+func (s *UserService) AuthenticateAPIKey(value string) (*modeluser.APIKey, error) {
+	key, err := s.userStore.GetAPIKeyByHash(hashToken(value))
+	if errors.Is(err, storageuser.ErrAPIKeyNotFound) {
+		return nil, ErrInvalidAPIKey
+	}
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if !key.IsActive(now) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	user, err := s.userStore.GetUserByUUID(key.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user.DeletedAt != nil {
+		return nil, ErrAccountInactive
+	}
+	if user.SuspendedAt != nil {
+		return nil, ErrAccountSuspended
+	}
+
+	if err := s.userStore.TouchAPIKey(key.ID, now.Add(-apiKeyTouchInterval)); err != nil {
+		log.Println("Failed to record API key use:", err)
+	}
+	return key, nil
+}
+
+// PurgeExpiredAPIKeys permanently removes API keys that expired or were revoked before the cutoff.
+func (s *UserService) PurgeExpiredAPIKeys(cutoff time.Time) (int64, error) {
+	return s.userStore.DeleteAPIKeysExpiredBefore(cutoff)
+}
+
+// normalizeScopes checks requested scopes against the known ones and drops duplicates.
+func normalizeScopes(requested []string) ([]string, error) {
+	if len(requested) == 0 {
+		return nil, ErrInvalidScope
+	}
+	scopes := make([]string, 0, len(requested))
+	seen := make(map[string]bool, len(requested))
+	for _, scope := range requested {
+		known := false
+		for _, s := range modeluser.Scopes {
+			if scope == s {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, ErrInvalidScope
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes, nil
+}
@@ -10,10 +10,13 @@ import (
 )
 
 // CreateUser handles the creation of a new user, including password hashing.
-func (s *UserService) CreateUser(fullName, email, password string, role modeluser.Role) error {
+func (s *UserService) CreateUser(fullName, email, password string, role modeluser.Role) (*modeluser.User, error) {
+	if err := ValidatePassword(password); err != nil {
+		return nil, err
+	}
 	hashedPassword, salt, err := utils.HashPassword(password)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	user := &modeluser.User{
@@ -24,24 +27,35 @@ func (s *UserService) CreateUser(fullName, email, password string, role modeluse
 		Role:         role,
 	}
 
-	return s.userStore.Create(user)
+	if err := s.userStore.Create(user); err != nil {
+		return nil, err
+	}
+	return user, nil
 }
 
 func (s *UserService) GetAllUsers() ([]modeluser.User, error) {
 	return s.userStore.GetAllUsers()
 }
 
-// UpdateUser updates an existing user's information.
-func (s *UserService) UpdateUser(userID uuid.UUID, fullName, email string) error {
+// UpdateUser updates an existing user's information. A new email address has
+// to be verified again.
+func (s *UserService) UpdateUser(userID uuid.UUID, fullName, email string) (*modeluser.User, error) {
 	user, err := s.userStore.GetUserByUUID(userID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	if email != user.Email {
+		user.EmailVerified = false
+		user.VerificationSentAt = nil
+	}
 	user.FullName = fullName
 	user.Email = email
 
-	return s.userStore.Update(user)
+	if err := s.userStore.Update(user); err != nil {
+		return nil, err
+	}
+	return user, nil
 }
 
 // UpdateLastLogin updates only the last login time of the user
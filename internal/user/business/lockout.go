@@ -0,0 +1,247 @@
+package userbusiness
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/khoaphungnguyen/go-openai/internal/mailer"
+	modeluser "github.com/khoaphungnguyen/go-openai/internal/user/model"
+	storageuser "github.com/khoaphungnguyen/go-openai/internal/user/storage"
+	"github.com/khoaphungnguyen/go-openai/internal/user/utils"
+)
+
+const (
+	// LoginAttemptWindow is how far back failed logins count towards delays and lockouts.
+	LoginAttemptWindow = 15 * time.Minute
+	// LoginAttemptRetention is how long failed logins are kept. It covers LoginAttemptWindow.
+	LoginAttemptRetention = 24 * time.Hour
+	// AccountLockoutThreshold is how many failed logins for an email lock it.
+	AccountLockoutThreshold = 10
+	// IPLockoutThreshold is how many failed logins from an IP address lock it,
+	// whichever accounts they were for.
+	IPLockoutThreshold = 50
+	// LockoutDuration is how long a lockout blocks logins unless an administrator lifts it.
+	LockoutDuration = 15 * time.Minute
+	// DefaultLockoutPageSize and MaxLockoutPageSize bound the pages of the lockout list.
+	DefaultLockoutPageSize = 20
+	MaxLockoutPageSize     = 100
+
+	// loginDelayThreshold is how many failed logins for an email are allowed
+	// before each further attempt has to wait, doubling up to maxLoginDelay.
+	loginDelayThreshold = 3
+	maxLoginDelay       = 30 * time.Second
+)
+
+var (
+	// ErrInvalidCredentials is returned when the email or password of a login is wrong.
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	// ErrLockoutNotFound is returned when a lockout does not exist or has already ended.
+	ErrLockoutNotFound = errors.New("lockout not found")
+)
+
+// LoginThrottledError is returned when a login is refused without checking
+// the credentials because of earlier failures.
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+	Locked     bool // Refused by a lockout rather than a delay between attempts
+}
+
+func (e *LoginThrottledError) Error() string {
+	if e.Locked {
+		return fmt.Sprintf("login locked, retry in %s", e.RetryAfter.Round(time.Second))
+	}
+	return fmt.Sprintf("too many failed logins, retry in %s", e.RetryAfter.Round(time.Second))
+}
+
+// AuthenticatePassword checks the email and password of a login. Failures are
+// counted per email and IP address: after a few, further attempts have to
+// wait increasingly long, and past a threshold logins are locked for a while
+// and the account owner is told by email.
+func (s *UserService) AuthenticatePassword(ctx context.Context, email, password string, client modeluser.ClientInfo) (*modeluser.User, error) {
+	key := normalizeEmail(email)
+	if err := s.checkLoginAllowed(key, client.IPAddress); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userStore.GetUserByEmail(email)
+	if err != nil && !errors.Is(err, storageuser.ErrUserNotFound) {
+		return nil, err
+	}
+	if user == nil || utils.CheckPassword(user.PasswordHash, user.Salt, password) != nil {
+		if err := s.recordLoginFailure(ctx, key, client.IPAddress, user); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidCredentials
+	}
+
+	if err := s.userStore.ClearFailedLogins(key); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// SearchLockouts returns a page of the lockouts matching the filter and the number of matching lockouts.
+func (s *UserService) SearchLockouts(filter modeluser.LockoutFilter) ([]modeluser.LoginLockout, int64, error) {
+	filter.Email = normalizeEmail(filter.Email)
+	return s.userStore.SearchLockouts(filter)
+}
+
+// Unlock ends an active lockout on behalf of an administrator. The failed
+// logins that led to it are forgotten so the next one does not lock again.
+func (s *UserService) Unlock(actorID, lockoutID uuid.UUID) (*modeluser.LoginLockout, error) {
+	lockout, err := s.userStore.GetLockout(lockoutID)
+	if errors.Is(err, storageuser.ErrLockoutNotFound) {
+		return nil, ErrLockoutNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = s.userStore.Unlock(lockoutID, actorID)
+	if errors.Is(err, storageuser.ErrLockoutNotFound) {
+		return nil, ErrLockoutNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if lockout.Email != "" {
+		err = s.userStore.ClearFailedLogins(lockout.Email)
+	} else {
+		err = s.userStore.ClearFailedLoginsFromIP(lockout.IPAddress)
+	}
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	lockout.UnlockedAt = &now
+	lockout.UnlockedBy = &actorID
+	return lockout, nil
+}
+
+// PurgeLoginAttempts permanently removes failed logins made before the cutoff.
+func (s *UserService) PurgeLoginAttempts(cutoff time.Time) (int64, error) {
+	return s.userStore.DeleteLoginAttemptsBefore(cutoff)
+}
+
+// checkLoginAllowed refuses a login for the email from the IP address while
+// either is locked, or when it comes too soon after the last failure.
+func (s *UserService) checkLoginAllowed(email, ipAddress string) error {
+	now := time.Now()
+	lockout, err := s.userStore.GetActiveLockout(email, ipAddress, now)
+	if err == nil {
+		return &LoginThrottledError{RetryAfter: lockout.LockedUntil.Sub(now), Locked: true}
+	}
+	if !errors.Is(err, storageuser.ErrLockoutNotFound) {
+		return err
+	}
+
+	failures, err := s.userStore.CountFailedLogins(email, now.Add(-LoginAttemptWindow))
+	if err != nil {
+		return err
+	}
+	if delay := loginDelay(failures.Count); delay > 0 && failures.LastAt != nil {
+		if wait := failures.LastAt.Add(delay).Sub(now); wait > 0 {
+			return &LoginThrottledError{RetryAfter: wait}
+		}
+	}
+	return nil
+}
+
+// recordLoginFailure counts a failed login and locks the email or IP address
+// once it reaches its threshold. The user is nil for unknown emails.
+func (s *UserService) recordLoginFailure(ctx context.Context, email, ipAddress string, user *modeluser.User) error {
+	now := time.Now()
+	if err := s.userStore.RecordFailedLogin(&modeluser.LoginAttempt{
+		Email:     email,
+		IPAddress: ipAddress,
+		CreatedAt: now,
+	}); err != nil {
+		return err
+	}
+	since := now.Add(-LoginAttemptWindow)
+
+	failures, err := s.userStore.CountFailedLogins(email, since)
+	if err != nil {
+		return err
+	}
+	if failures.Count >= AccountLockoutThreshold {
+		lockout := &modeluser.LoginLockout{
+			Email:          email,
+			IPAddress:      ipAddress,
+			FailedAttempts: failures.Count,
+			LockedUntil:    now.Add(LockoutDuration),
+		}
+		if user != nil {
+			lockout.UserID = &user.ID
+		}
+		if err := s.userStore.CreateLockout(lockout); err != nil {
+			return err
+		}
+		// The lockout takes over; counting starts afresh once it ends
+		if err := s.userStore.ClearFailedLogins(email); err != nil {
+			return err
+		}
+		if user != nil {
+			if err := s.sendLockoutNotice(ctx, user, lockout); err != nil {
+				log.Println("Failed to send lockout notice:", err)
+			}
+		}
+	}
+
+	if ipAddress == "" {
+		return nil
+	}
+	fromIP, err := s.userStore.CountFailedLoginsFromIP(ipAddress, since)
+	if err != nil {
+		return err
+	}
+	if fromIP.Count >= IPLockoutThreshold {
+		if err := s.userStore.CreateLockout(&modeluser.LoginLockout{
+			IPAddress:      ipAddress,
+			FailedAttempts: fromIP.Count,
+			LockedUntil:    now.Add(LockoutDuration),
+		}); err != nil {
+			return err
+		}
+		return s.userStore.ClearFailedLoginsFromIP(ipAddress)
+	}
+	return nil
+}
+
+// sendLockoutNotice tells the owner of an account that logins to it were locked.
+func (s *UserService) sendLockoutNotice(ctx context.Context, user *modeluser.User, lockout *modeluser.LoginLockout) error {
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Logins to your account were locked",
+		Body: fmt.Sprintf("Hi %s,\n\nThere were %d failed attempts to log in to your account, the last one from %s. "+
+			"To protect the account, logins are locked until %s.\n\n"+
+			"If this was not you, someone may be guessing your password. Consider changing it and turning on two-factor authentication.\n",
+			user.FullName, lockout.FailedAttempts, lockout.IPAddress, lockout.LockedUntil.UTC().Format(time.RFC1123)),
+	})
+}
+
+// loginDelay returns how long to wait after the last of the given number of
+// failed logins before trying again.
+func loginDelay(failures int64) time.Duration {
+	if failures < loginDelayThreshold {
+		return 0
+	}
+	delay := time.Second
+	for i := int64(loginDelayThreshold); i < failures && delay < maxLoginDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxLoginDelay {
+		delay = maxLoginDelay
+	}
+	return delay
+}
+
+// normalizeEmail makes emails that differ only in case or surrounding space count as one.
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,71 @@
+package userbusiness
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	modeluser "github.com/khoaphungnguyen/go-openai/internal/user/model"
+)
+
+// seedFailedLogins records failed logins for an email long enough ago that no delay applies.
+func seedFailedLogins(store *memoryUserStore, email, ipAddress string, count int) {
+	for i := 0; i < count; i++ {
+		store.RecordFailedLogin(&modeluser.LoginAttempt{
+			Email:     email,
+			IPAddress: ipAddress,
+			CreatedAt: time.Now().Add(-time.Minute),
+		})
+	}
+}
+
+func TestLockoutNotice(t *testing.T) {
+	ctx := context.Background()
+	s, store, mail := newTestUserService()
+	if _, err := s.CreateUser("Ada Lovelace", "ada@example.com", "correct horse", modeluser.UserRole); err != nil {
+		t.Fatal(err)
+	}
+	client := modeluser.ClientInfo{IPAddress: "203.0.113.7"}
+
+	seedFailedLogins(store, "ada@example.com", client.IPAddress, AccountLockoutThreshold-1)
+	if _, err := s.AuthenticatePassword(ctx, "ada@example.com", "wrong", client); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("AuthenticatePassword = %v, want ErrInvalidCredentials", err)
+	}
+	msg, ok := mail.Last()
+	if !ok {
+		t.Fatal("no lockout notice was sent")
+	}
+	if msg.To != "ada@example.com" || msg.Subject != "Logins to your account were locked" {
+		t.Errorf("sent %q to %q, want the lockout notice to ada@example.com", msg.Subject, msg.To)
+	}
+	if !strings.Contains(msg.Body, client.IPAddress) {
+		t.Errorf("lockout notice does not name the IP address:\n%s", msg.Body)
+	}
+
+	var throttled *LoginThrottledError
+	_, err := s.AuthenticatePassword(ctx, "ada@example.com", "correct horse", client)
+	if !errors.As(err, &throttled) || !throttled.Locked {
+		t.Errorf("AuthenticatePassword while locked = %v, want a lockout", err)
+	}
+	if n := len(mail.Sent()); n != 1 {
+		t.Errorf("sent %d emails, want 1", n)
+	}
+}
+
+func TestLockoutOfUnknownEmailSendsNothing(t *testing.T) {
+	s, store, mail := newTestUserService()
+	client := modeluser.ClientInfo{IPAddress: "203.0.113.7"}
+
+	seedFailedLogins(store, "nobody@example.com", client.IPAddress, AccountLockoutThreshold-1)
+	if _, err := s.AuthenticatePassword(context.Background(), "nobody@example.com", "wrong", client); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("AuthenticatePassword = %v, want ErrInvalidCredentials", err)
+	}
+	if len(store.lockouts) != 1 {
+		t.Fatalf("created %d lockouts, want 1", len(store.lockouts))
+	}
+	if n := len(mail.Sent()); n != 0 {
+		t.Errorf("sent %d emails for an unknown address, want 0", n)
+	}
+}
@@ -0,0 +1,299 @@
+package userbusiness
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	userauth "github.com/khoaphungnguyen/go-openai/internal/user/auth"
+	modeluser "github.com/khoaphungnguyen/go-openai/internal/user/model"
+	storageuser "github.com/khoaphungnguyen/go-openai/internal/user/storage"
+	"github.com/khoaphungnguyen/go-openai/internal/user/utils"
+)
+
+const (
+	// MFAChallengeDuration is how long the second step of a login may take.
+	MFAChallengeDuration = 5 * time.Minute
+	// RecoveryCodeCount is how many recovery codes are handed out at a time.
+	RecoveryCodeCount = 10
+	// mfaIssuer names the account in authenticator apps.
+	mfaIssuer = "SmartChat"
+	// recoveryCodeLength is the number of characters in a recovery code, shown in groups of four.
+	recoveryCodeLength = 16
+	// recoveryCodeAlphabet avoids characters that are easily confused when typed.
+	recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+)
+
+var (
+	// ErrMFAAlreadyEnabled is returned when enrolling a user who already has two-factor authentication.
+	ErrMFAAlreadyEnabled = errors.New("two-factor authentication already enabled")
+	// ErrMFANotEnabled is returned when a second factor is checked for a user without one.
+	ErrMFANotEnabled = errors.New("two-factor authentication not enabled")
+	// ErrMFANotEnrolling is returned when confirming without a pending enrollment.
+	ErrMFANotEnrolling = errors.New("no two-factor enrollment to confirm")
+	// ErrMFARequired is returned when an administrator tries to turn off an enforced second factor.
+	ErrMFARequired = errors.New("two-factor authentication is required for administrators")
+	// ErrInvalidMFACode is returned when a TOTP or recovery code is wrong or was already used.
+	ErrInvalidMFACode = errors.New("invalid two-factor code")
+	// ErrInvalidMFAChallenge is returned when the token of a login's second step is invalid or expired.
+	ErrInvalidMFAChallenge = errors.New("invalid or expired mfa challenge")
+)
+
+// GetMFAStatus describes the two-factor authentication settings of the user.
+func (s *UserService) GetMFAStatus(userID uuid.UUID) (*modeluser.MFAStatus, error) {
+	user, err := s.userStore.GetUserByUUID(userID)
+	if err != nil {
+		return nil, err
+	}
+	status := &modeluser.MFAStatus{Required: s.MFARequired(user)}
+
+	mfa, err := s.getMFA(userID)
+	if err != nil {
+		return nil, err
+	}
+	if mfa.Enabled() {
+		status.Enabled = true
+		status.ConfirmedAt = mfa.ConfirmedAt
+		if status.RecoveryCodesLeft, err = s.userStore.CountRecoveryCodes(userID); err != nil {
+			return nil, err
+		}
+	}
+	return status, nil
+}
+
+// MFARequired reports whether the user must use two-factor authentication.
+func (s *UserService) MFARequired(user *modeluser.User) bool {
+	return s.MFARequiredForRole(user.Role)
+}
+
+// MFARequiredForRole reports whether users with the role must use two-factor
+// authentication. Enforcement covers every role with access to the admin API.
+func (s *UserService) MFARequiredForRole(role modeluser.Role) bool {
+	return s.requireAdminMFA && role.Privileged()
+}
+
+// IsMFAEnabled reports whether logging in as the user needs a second factor.
+func (s *UserService) IsMFAEnabled(userID uuid.UUID) (bool, error) {
+	mfa, err := s.getMFA(userID)
+	if err != nil {
+		return false, err
+	}
+	return mfa.Enabled(), nil
+}
+
+// EnrollMFA starts enrolling a TOTP authenticator with a new secret, replacing
+// any enrollment that was never confirmed.
+func (s *UserService) EnrollMFA(userID uuid.UUID) (*modeluser.MFAEnrollment, error) {
+	user, err := s.userStore.GetUserByUUID(userID)
+	if err != nil {
+		return nil, err
+	}
+	mfa, err := s.getMFA(userID)
+	if err != nil {
+		return nil, err
+	}
+	if mfa.Enabled() {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userStore.SaveMFA(&modeluser.UserMFA{UserID: userID, Secret: secret, CreatedAt: time.Now()}); err != nil {
+		return nil, err
+	}
+	return &modeluser.MFAEnrollment{
+		Secret:     secret,
+		OTPAuthURI: utils.TOTPURI(mfaIssuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmMFA turns on two-factor authentication once the user proves their
+// authenticator works. It returns the recovery codes, which are not shown again.
+func (s *UserService) ConfirmMFA(userID uuid.UUID, code string) ([]string, error) {
+	mfa, err := s.getMFA(userID)
+	if err != nil {
+		return nil, err
+	}
+	if mfa.Enabled() {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	if mfa == nil {
+		return nil, ErrMFANotEnrolling
+	}
+	step, ok := utils.ValidateTOTP(mfa.Secret, code, time.Now())
+	if !ok {
+		return nil, ErrInvalidMFACode
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	confirmed, err := s.userStore.ConfirmMFA(userID, step, hashes)
+	if err != nil {
+		return nil, err
+	}
+	if !confirmed {
+		return nil, ErrMFANotEnrolling
+	}
+	return codes, nil
+}
+
+// DisableMFA turns off two-factor authentication after checking the password
+// and a current code.
+func (s *UserService) DisableMFA(userID uuid.UUID, password, code string) error {
+	user, err := s.userStore.GetUserByUUID(userID)
+	if err != nil {
+		return err
+	}
+	if s.MFARequired(user) {
+		return ErrMFARequired
+	}
+	if utils.CheckPassword(user.PasswordHash, user.Salt, password) != nil {
+		return ErrIncorrectPassword
+	}
+	if err := s.verifySecondFactor(userID, code, true); err != nil {
+		return err
+	}
+	return s.userStore.DeleteMFA(userID)
+}
+
+// RegenerateRecoveryCodes replaces the user's recovery codes after checking a
+// current TOTP code. The new codes are not shown again.
+func (s *UserService) RegenerateRecoveryCodes(userID uuid.UUID, code string) ([]string, error) {
+	if err := s.verifySecondFactor(userID, code, false); err != nil {
+		return nil, err
+	}
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userStore.ReplaceRecoveryCodes(userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// StartMFAChallenge returns the token that lets a user who passed the password
+// step finish logging in with a second factor.
+func (s *UserService) StartMFAChallenge(user *modeluser.User) (string, error) {
+	return s.jwt.ChallengeToken(user.ID.String(), time.Now().Add(MFAChallengeDuration))
+}
+
+// CompleteMFAChallenge finishes a login with a TOTP or recovery code and
+// issues tokens for a session marked as opened with a second factor. Wrong
+// codes count as failed logins, like wrong passwords.
+func (s *UserService) CompleteMFAChallenge(ctx context.Context, challenge, code string, client modeluser.ClientInfo) (*modeluser.User, *modeluser.TokenPair, error) {
+	claims, err := s.jwt.ValidateToken(challenge, userauth.MFAChallengeToken)
+	if err != nil {
+		return nil, nil, ErrInvalidMFAChallenge
+	}
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		return nil, nil, ErrInvalidMFAChallenge
+	}
+	user, err := s.userStore.GetUserByUUID(userID)
+	if errors.Is(err, storageuser.ErrUserNotFound) {
+		return nil, nil, ErrInvalidMFAChallenge
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	key := normalizeEmail(user.Email)
+	if err := s.checkLoginAllowed(key, client.IPAddress); err != nil {
+		return nil, nil, err
+	}
+	if err := s.verifySecondFactor(userID, code, true); err != nil {
+		if errors.Is(err, ErrInvalidMFACode) {
+			if err := s.recordLoginFailure(ctx, key, client.IPAddress, user); err != nil {
+				return nil, nil, err
+			}
+		}
+		return nil, nil, err
+	}
+	if err := s.userStore.ClearFailedLogins(key); err != nil {
+		return nil, nil, err
+	}
+
+	tokens, err := s.issueTokens(user, newSession(user.ID, client, true), nil, client)
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, tokens, nil
+}
+
+// verifySecondFactor accepts a TOTP code not used before or, when allowed, an
+// unused recovery code, which is then used up.
+func (s *UserService) verifySecondFactor(userID uuid.UUID, code string, allowRecovery bool) error {
+	mfa, err := s.getMFA(userID)
+	if err != nil {
+		return err
+	}
+	if !mfa.Enabled() {
+		return ErrMFANotEnabled
+	}
+
+	if step, ok := utils.ValidateTOTP(mfa.Secret, code, time.Now()); ok {
+		fresh, err := s.userStore.UseTOTPStep(userID, step)
+		if err != nil {
+			return err
+		}
+		if !fresh {
+			return ErrInvalidMFACode
+		}
+		return nil
+	}
+
+	if !allowRecovery {
+		return ErrInvalidMFACode
+	}
+	used, err := s.userStore.UseRecoveryCode(userID, hashToken(normalizeRecoveryCode(code)))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrInvalidMFACode
+	}
+	return nil
+}
+
+// getMFA loads the user's TOTP settings, or nil when they never enrolled.
+func (s *UserService) getMFA(userID uuid.UUID) (*modeluser.UserMFA, error) {
+	mfa, err := s.userStore.GetMFA(userID)
+	if errors.Is(err, storageuser.ErrMFANotFound) {
+		return nil, nil
+	}
+	return mfa, err
+}
+
+// generateRecoveryCodes returns new recovery codes for display and the hashes to store.
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, RecoveryCodeCount)
+	hashes := make([]string, 0, RecoveryCodeCount)
+	buf := make([]byte, recoveryCodeLength)
+	for i := 0; i < RecoveryCodeCount; i++ {
+		if _, err := rand.Read(buf); err != nil {
+			return nil, nil, err
+		}
+		var code strings.Builder
+		for j, b := range buf {
+			if j > 0 && j%4 == 0 {
+				code.WriteByte('-')
+			}
+			code.WriteByte(recoveryCodeAlphabet[int(b)%len(recoveryCodeAlphabet)])
+		}
+		codes = append(codes, code.String())
+		hashes = append(hashes, hashToken(normalizeRecoveryCode(code.String())))
+	}
+	return codes, hashes, nil
+}
+
+// normalizeRecoveryCode ignores case, spaces and dashes in a typed recovery code.
+func normalizeRecoveryCode(code string) string {
+	return strings.NewReplacer("-", "", " ", "").Replace(strings.ToLower(strings.TrimSpace(code)))
+}
@@ -0,0 +1,74 @@
+package userbusiness
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	modeluser "github.com/khoaphungnguyen/go-openai/internal/user/model"
+	"github.com/khoaphungnguyen/go-openai/internal/user/utils"
+)
+
+// totpAt computes the code of a time step like an authenticator app would.
+func totpAt(t *testing.T, secret string, step int64) string {
+	t.Helper()
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(step))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	return fmt.Sprintf("%06d", (binary.BigEndian.Uint32(sum[offset:])&0x7fffffff)%1000000)
+}
+
+// enableMFA gives the user a confirmed TOTP enrollment and returns its secret.
+func enableMFA(t *testing.T, store *memoryUserStore, userID uuid.UUID) string {
+	t.Helper()
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	store.SaveMFA(&modeluser.UserMFA{UserID: userID, Secret: secret, ConfirmedAt: &now, CreatedAt: now})
+	return secret
+}
+
+func TestSecondFactorRefusesReplayedStep(t *testing.T) {
+	s, store, _ := newTestUserService()
+	userID := uuid.New()
+	secret := enableMFA(t, store, userID)
+	// Stay clear of a step boundary, where the previous step would fall out of the window
+	period := int64(utils.TOTPPeriod.Seconds())
+	if time.Now().Unix()%period >= period-2 {
+		time.Sleep(2 * time.Second)
+	}
+	current := time.Now().Unix() / period
+
+	// A code from the previous step is still accepted once
+	if err := s.verifySecondFactor(userID, totpAt(t, secret, current-1), false); err != nil {
+		t.Fatalf("verifySecondFactor with the previous step: %v", err)
+	}
+	if err := s.verifySecondFactor(userID, totpAt(t, secret, current-1), false); !errors.Is(err, ErrInvalidMFACode) {
+		t.Errorf("verifySecondFactor replaying the code = %v, want ErrInvalidMFACode", err)
+	}
+
+	if err := s.verifySecondFactor(userID, totpAt(t, secret, current), false); err != nil {
+		t.Fatalf("verifySecondFactor with the current step: %v", err)
+	}
+	if err := s.verifySecondFactor(userID, totpAt(t, secret, current), false); !errors.Is(err, ErrInvalidMFACode) {
+		t.Errorf("verifySecondFactor replaying the current code = %v, want ErrInvalidMFACode", err)
+	}
+	if err := s.verifySecondFactor(userID, totpAt(t, secret, current-1), false); !errors.Is(err, ErrInvalidMFACode) {
+		t.Errorf("verifySecondFactor with an older step after a newer one = %v, want ErrInvalidMFACode", err)
+	}
+}
@@ -0,0 +1,179 @@
+package userbusiness
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/khoaphungnguyen/go-openai/internal/mailer"
+	modeluser "github.com/khoaphungnguyen/go-openai/internal/user/model"
+	storageuser "github.com/khoaphungnguyen/go-openai/internal/user/storage"
+	"github.com/khoaphungnguyen/go-openai/internal/user/utils"
+)
+
+const (
+	// MinPasswordLength and MaxPasswordLength bound the length of passwords in characters.
+	MinPasswordLength = 8
+	MaxPasswordLength = 100
+	// PasswordResetTokenDuration is how long a password reset link stays valid.
+	PasswordResetTokenDuration = time.Hour
+	// PasswordResetInterval is the least time between two password reset emails to a user.
+	PasswordResetInterval = 2 * time.Minute
+	// resetTokenLength is the number of random bytes in a password reset token.
+	resetTokenLength = 32
+)
+
+var (
+	// ErrWeakPassword is returned when a new password does not meet the password policy.
+	ErrWeakPassword = fmt.Errorf("password must be between %d and %d characters", MinPasswordLength, MaxPasswordLength)
+	// ErrIncorrectPassword is returned when the current password given to change it is wrong.
+	ErrIncorrectPassword = errors.New("current password is incorrect")
+	// ErrInvalidResetToken is returned when a password reset token is unknown, used or expired.
+	ErrInvalidResetToken = errors.New("invalid or expired password reset token")
+)
+
+// ValidatePassword checks a new password against the password policy.
+func ValidatePassword(password string) error {
+	length := utf8.RuneCountInString(password)
+	if length < MinPasswordLength || length > MaxPasswordLength {
+		return ErrWeakPassword
+	}
+	return nil
+}
+
+// RequestPasswordReset mails a single-use password reset link to the account
+// with the given email. Unknown and inactive accounts are silently ignored so
+// the endpoint does not reveal which addresses are registered.
+func (s *UserService) RequestPasswordReset(ctx context.Context, email string) error {
+	user, err := s.userStore.GetUserByEmail(email)
+	if errors.Is(err, storageuser.ErrUserNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if user.DeletedAt != nil {
+		return nil
+	}
+	return s.sendPasswordReset(ctx, user)
+}
+
+// ResetPassword sets a new password using a token from a password reset email
+// and ends every session of the user. API keys are revoked as well, since the
+// reset may be recovering an account someone else had access to. It returns
+// the user whose password was reset.
+func (s *UserService) ResetPassword(token, newPassword string) (uuid.UUID, error) {
+	if err := ValidatePassword(newPassword); err != nil {
+		return uuid.Nil, err
+	}
+	reset, err := s.userStore.ConsumePasswordResetToken(hashToken(token))
+	if errors.Is(err, storageuser.ErrPasswordResetTokenNotFound) {
+		return uuid.Nil, ErrInvalidResetToken
+	}
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if err := s.setPassword(reset.UserID, newPassword); err != nil {
+		return uuid.Nil, err
+	}
+	if err := s.userStore.RevokeUserAPIKeys(reset.UserID); err != nil {
+		return uuid.Nil, err
+	}
+	if err := s.userStore.RevokeUserSessions(reset.UserID); err != nil {
+		return uuid.Nil, err
+	}
+	s.access.forgetUser(reset.UserID)
+	return reset.UserID, nil
+}
+
+// ChangePassword replaces the user's password after checking the current one.
+// Every other session of the user is ended; the one making the change stays
+// logged in.
+func (s *UserService) ChangePassword(ctx context.Context, userID, currentSessionID uuid.UUID, currentPassword, newPassword string) error {
+	user, err := s.userStore.GetUserByUUID(userID)
+	if err != nil {
+		return err
+	}
+	if utils.CheckPassword(user.PasswordHash, user.Salt, currentPassword) != nil {
+		return ErrIncorrectPassword
+	}
+	if err := ValidatePassword(newPassword); err != nil {
+		return err
+	}
+	if err := s.setPassword(userID, newPassword); err != nil {
+		return err
+	}
+	if err := s.userStore.RevokeOtherSessions(userID, currentSessionID); err != nil {
+		return err
+	}
+	s.access.forgetUser(userID)
+
+	// Let the owner know in case someone else changed it
+	if err := s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Your password was changed",
+		Body: fmt.Sprintf("Hi %s,\n\nThe password of your account was just changed and your other sessions were logged out.\n\n"+
+			"If you did not do this, reset your password at %s/forgot-password right away.\n", user.FullName, s.appURL),
+	}); err != nil {
+		log.Println("Failed to send password change notice:", err)
+	}
+	return nil
+}
+
+// PurgeExpiredPasswordResets permanently removes password reset tokens that expired before the cutoff.
+func (s *UserService) PurgeExpiredPasswordResets(cutoff time.Time) (int64, error) {
+	return s.userStore.DeletePasswordResetTokensExpiredBefore(cutoff)
+}
+
+// sendPasswordReset issues a password reset token and mails its link, unless
+// one was sent within PasswordResetInterval.
+func (s *UserService) sendPasswordReset(ctx context.Context, user *modeluser.User) error {
+	recent, err := s.userStore.HasRecentPasswordReset(user.ID, time.Now().Add(-PasswordResetInterval))
+	if err != nil {
+		return err
+	}
+	if recent {
+		return nil
+	}
+	return s.mailPasswordReset(ctx, user, "Reset your password",
+		"Someone asked to reset the password of your account. Open the link below to choose a new one:",
+		"If you did not ask for this, you can ignore this email.")
+}
+
+// mailPasswordReset issues a password reset token and mails its link between
+// the given introduction and closing lines.
+func (s *UserService) mailPasswordReset(ctx context.Context, user *modeluser.User, subject, intro, closing string) error {
+	token, err := utils.RandomToken(resetTokenLength)
+	if err != nil {
+		return err
+	}
+	if err := s.userStore.CreatePasswordResetToken(&modeluser.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(PasswordResetTokenDuration),
+	}); err != nil {
+		return err
+	}
+	link := s.appURL + "/reset-password?token=" + url.QueryEscape(token)
+
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: subject,
+		Body: fmt.Sprintf("Hi %s,\n\n%s\n\n%s\n\nThe link expires in %d minutes and works once. %s\n",
+			user.FullName, intro, link, int(PasswordResetTokenDuration.Minutes()), closing),
+	})
+}
+
+// setPassword hashes and stores a new password.
+func (s *UserService) setPassword(userID uuid.UUID, password string) error {
+	hashedPassword, salt, err := utils.HashPassword(password)
+	if err != nil {
+		return err
+	}
+	return s.userStore.UpdatePassword(userID, hashedPassword, salt)
+}
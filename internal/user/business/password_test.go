@@ -0,0 +1,84 @@
+package userbusiness
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	modeluser "github.com/khoaphungnguyen/go-openai/internal/user/model"
+	"github.com/khoaphungnguyen/go-openai/internal/user/utils"
+)
+
+func TestPasswordResetEmail(t *testing.T) {
+	ctx := context.Background()
+	s, store, mail := newTestUserService()
+	user, err := s.CreateUser("Ada Lovelace", "ada@example.com", "correct horse", modeluser.UserRole)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.RequestPasswordReset(ctx, "ada@example.com"); err != nil {
+		t.Fatalf("RequestPasswordReset: %v", err)
+	}
+	msg, ok := mail.Last()
+	if !ok {
+		t.Fatal("no password reset email was sent")
+	}
+	if msg.To != "ada@example.com" || msg.Subject != "Reset your password" {
+		t.Errorf("sent %q to %q, want the password reset email to ada@example.com", msg.Subject, msg.To)
+	}
+	token := linkToken(t, msg, "/reset-password")
+
+	// A second request within the interval sends nothing, and unknown addresses are ignored
+	if err := s.RequestPasswordReset(ctx, "ada@example.com"); err != nil {
+		t.Fatalf("RequestPasswordReset again: %v", err)
+	}
+	if err := s.RequestPasswordReset(ctx, "nobody@example.com"); err != nil {
+		t.Fatalf("RequestPasswordReset for an unknown address: %v", err)
+	}
+	if n := len(mail.Sent()); n != 1 {
+		t.Fatalf("sent %d emails, want 1", n)
+	}
+
+	userID, err := s.ResetPassword(token, "battery staple")
+	if err != nil {
+		t.Fatalf("ResetPassword: %v", err)
+	}
+	if userID != user.ID {
+		t.Errorf("ResetPassword reset %s, want %s", userID, user.ID)
+	}
+	stored := store.user(user.ID)
+	if utils.CheckPassword(stored.PasswordHash, stored.Salt, "battery staple") != nil {
+		t.Error("the new password does not work")
+	}
+	if store.revokedSessions[user.ID] == 0 || store.revokedAPIKeys[user.ID] == 0 {
+		t.Error("ResetPassword kept the user's sessions or API keys")
+	}
+
+	if _, err := s.ResetPassword(token, "another password"); !errors.Is(err, ErrInvalidResetToken) {
+		t.Errorf("ResetPassword with a used token = %v, want ErrInvalidResetToken", err)
+	}
+}
+
+func TestForcePasswordResetEmail(t *testing.T) {
+	s, store, mail := newTestUserService()
+	user, err := s.CreateUser("Ada Lovelace", "ada@example.com", "correct horse", modeluser.UserRole)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.ForcePasswordReset(context.Background(), user.ID); err != nil {
+		t.Fatalf("ForcePasswordReset: %v", err)
+	}
+	msg, ok := mail.Last()
+	if !ok || msg.To != "ada@example.com" || msg.Subject != "Choose a new password" {
+		t.Fatalf("sent %+v, want the forced reset email to ada@example.com", msg)
+	}
+	stored := store.user(user.ID)
+	if utils.CheckPassword(stored.PasswordHash, stored.Salt, "correct horse") == nil {
+		t.Error("the old password still works")
+	}
+	if _, err := s.ResetPassword(linkToken(t, msg, "/reset-password"), "battery staple"); err != nil {
+		t.Errorf("ResetPassword with the mailed link: %v", err)
+	}
+}
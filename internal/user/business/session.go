@@ -0,0 +1,117 @@
+package userbusiness
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/khoaphungnguyen/go-openai/internal/common"
+	modeluser "github.com/khoaphungnguyen/go-openai/internal/user/model"
+	storageuser "github.com/khoaphungnguyen/go-openai/internal/user/storage"
+)
+
+// ErrSessionNotFound is returned when a session does not exist or belongs to another user.
+var ErrSessionNotFound = errors.New("session not found")
+
+// Browsers and operating systems recognised in user agents, checked in order
+// because user agents name the engines they are compatible with as well.
+var (
+	knownBrowsers = []struct{ token, name string }{
+		{"Edg/", "Edge"},
+		{"OPR/", "Opera"},
+		{"Firefox/", "Firefox"},
+		{"Chrome/", "Chrome"},
+		{"Safari/", "Safari"},
+		{"curl/", "curl"},
+		{"PostmanRuntime/", "Postman"},
+	}
+	knownSystems = []struct{ token, name string }{
+		{"Android", "Android"},
+		{"iPhone", "iOS"},
+		{"iPad", "iPadOS"},
+		{"Windows", "Windows"},
+		{"Mac OS X", "macOS"},
+		{"CrOS", "ChromeOS"},
+		{"Linux", "Linux"},
+	}
+)
+
+// GetSessions returns the user's active sessions. The session the request was
+// made from, if any, is marked as current.
+func (s *UserService) GetSessions(userID, currentSessionID uuid.UUID) ([]modeluser.SessionResponse, error) {
+	sessions, err := s.userStore.GetActiveSessions(userID)
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]modeluser.SessionResponse, 0, len(sessions))
+	for i := range sessions {
+		responses = append(responses, sessions[i].ToResponse(sessions[i].ID == currentSessionID))
+	}
+	return responses, nil
+}
+
+// RevokeSession ends one of the user's sessions.
+func (s *UserService) RevokeSession(userID, sessionID uuid.UUID) error {
+	session, err := s.userStore.GetSession(sessionID)
+	if errors.Is(err, storageuser.ErrSessionNotFound) {
+		return ErrSessionNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if session.UserID != userID {
+		return ErrSessionNotFound
+	}
+	if err := s.userStore.RevokeSession(sessionID); err != nil {
+		return err
+	}
+	s.access.forgetSession(sessionID)
+	return nil
+}
+
+// PurgeExpiredSessions permanently removes sessions that expired before the cutoff.
+func (s *UserService) PurgeExpiredSessions(cutoff time.Time) (int64, error) {
+	return s.userStore.DeleteSessionsExpiredBefore(cutoff)
+}
+
+// newSession describes a login from the client. The session ID becomes the
+// family of its refresh tokens; the expiry is set when the first one is issued.
+func newSession(userID uuid.UUID, client modeluser.ClientInfo, mfa bool) *modeluser.Session {
+	return &modeluser.Session{
+		ID:         uuid.New(),
+		UserID:     userID,
+		Device:     describeDevice(client.UserAgent),
+		UserAgent:  common.Truncate(client.UserAgent, common.MaxUserAgentLength),
+		IPAddress:  common.Truncate(client.IPAddress, common.MaxIPAddressLength),
+		MFA:        mfa,
+		LastUsedAt: time.Now(),
+	}
+}
+
+// describeDevice summarises a user agent as "<browser> on <system>".
+func describeDevice(userAgent string) string {
+	browser, system := "", ""
+	for _, known := range knownBrowsers {
+		if strings.Contains(userAgent, known.token) {
+			browser = known.name
+			break
+		}
+	}
+	for _, known := range knownSystems {
+		if strings.Contains(userAgent, known.token) {
+			system = known.name
+			break
+		}
+	}
+	switch {
+	case browser != "" && system != "":
+		return browser + " on " + system
+	case browser != "":
+		return browser
+	case system != "":
+		return system
+	default:
+		return "Unknown device"
+	}
+}
@@ -0,0 +1,232 @@
+package userbusiness
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/khoaphungnguyen/go-openai/internal/common"
+	"github.com/khoaphungnguyen/go-openai/internal/oidc"
+	modeluser "github.com/khoaphungnguyen/go-openai/internal/user/model"
+	storageuser "github.com/khoaphungnguyen/go-openai/internal/user/storage"
+	"github.com/khoaphungnguyen/go-openai/internal/user/utils"
+)
+
+// OIDCLoginDuration is how long a user has to sign in at the identity provider.
+const OIDCLoginDuration = 10 * time.Minute
+
+var (
+	// ErrUnknownProvider is returned for an identity provider that is not configured.
+	ErrUnknownProvider = errors.New("unknown identity provider")
+	// ErrInvalidLoginState is returned when the state of an external login is unknown, used or expired.
+	ErrInvalidLoginState = errors.New("invalid or expired login state")
+	// ErrProviderEmailNotVerified is returned when an unlinked external account
+	// has no verified email to match or create a user with.
+	ErrProviderEmailNotVerified = errors.New("identity provider did not verify the email address")
+)
+
+// IdentityProvider runs the authorization code flow of an external identity provider.
+type IdentityProvider interface {
+	Name() string
+	AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error)
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*oidc.Identity, error)
+}
+
+// SSOService signs users in with external identity providers.
+type SSOService struct {
+	userService *UserService
+	providers   map[string]IdentityProvider
+}
+
+// NewSSOService creates a new SSOService for the given providers.
+func NewSSOService(userService *UserService, providers ...IdentityProvider) *SSOService {
+	byName := make(map[string]IdentityProvider, len(providers))
+	for _, provider := range providers {
+		byName[provider.Name()] = provider
+	}
+	return &SSOService{userService: userService, providers: byName}
+}
+
+// Providers returns the names of the configured identity providers.
+func (ss *SSOService) Providers() []string {
+	names := make([]string, 0, len(ss.providers))
+	for name := range ss.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// StartLogin returns the URL to send the user to for signing in at the
+// provider, and the state the provider will redirect back with. The caller
+// must tie the state to the user's browser so a login started by someone else
+// cannot be completed in it.
+func (ss *SSOService) StartLogin(ctx context.Context, providerName string) (string, string, error) {
+	provider, ok := ss.providers[providerName]
+	if !ok {
+		return "", "", ErrUnknownProvider
+	}
+
+	state, err := oidc.RandomString()
+	if err != nil {
+		return "", "", err
+	}
+	nonce, err := oidc.RandomString()
+	if err != nil {
+		return "", "", err
+	}
+	verifier, err := oidc.RandomString()
+	if err != nil {
+		return "", "", err
+	}
+
+	authURL, err := provider.AuthCodeURL(ctx, state, nonce, oidc.CodeChallenge(verifier))
+	if err != nil {
+		return "", "", err
+	}
+	if err := ss.userService.userStore.CreateLoginState(&modeluser.OIDCLoginState{
+		StateHash:    hashToken(state),
+		Provider:     providerName,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    time.Now().Add(OIDCLoginDuration),
+	}); err != nil {
+		return "", "", err
+	}
+	return authURL, state, nil
+}
+
+// CompleteLogin finishes signing in with the code the provider returned. The
+// external account is matched to the user it is linked to; an unlinked one is
+// linked to the user with the same verified email, or a new user is created.
+func (ss *SSOService) CompleteLogin(ctx context.Context, providerName, code, state string) (*modeluser.User, error) {
+	provider, ok := ss.providers[providerName]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+	pending, err := ss.userService.userStore.ConsumeLoginState(hashToken(state))
+	if errors.Is(err, storageuser.ErrLoginStateNotFound) {
+		return nil, ErrInvalidLoginState
+	}
+	if err != nil {
+		return nil, err
+	}
+	if pending.Provider != providerName || time.Now().After(pending.ExpiresAt) {
+		return nil, ErrInvalidLoginState
+	}
+
+	identity, err := provider.Exchange(ctx, code, pending.CodeVerifier, pending.Nonce)
+	if err != nil {
+		return nil, err
+	}
+	user, err := ss.resolveUser(providerName, identity)
+	if err != nil {
+		return nil, err
+	}
+	if user.DeletedAt != nil {
+		return nil, ErrAccountInactive
+	}
+	return user, nil
+}
+
+// PurgeExpiredLoginStates permanently removes pending external logins that expired before the cutoff.
+func (ss *SSOService) PurgeExpiredLoginStates(cutoff time.Time) (int64, error) {
+	return ss.userService.userStore.DeleteLoginStatesExpiredBefore(cutoff)
+}
+
+// resolveUser finds or creates the user an external account signs in as.
+func (ss *SSOService) resolveUser(providerName string, identity *oidc.Identity) (*modeluser.User, error) {
+	store := ss.userService.userStore
+
+	linked, err := store.GetIdentity(providerName, identity.Subject)
+	if err == nil {
+		return store.GetUserByUUID(linked.UserID)
+	}
+	if !errors.Is(err, storageuser.ErrIdentityNotFound) {
+		return nil, err
+	}
+
+	// Only an address the provider vouches for may take over or create an account
+	email := normalizeEmail(identity.Email)
+	if email == "" || !identity.EmailVerified {
+		return nil, ErrProviderEmailNotVerified
+	}
+	user, err := store.GetUserByEmail(email)
+	switch {
+	case errors.Is(err, storageuser.ErrUserNotFound):
+		if user, err = ss.createUser(email, identity.Name); err != nil {
+			return nil, err
+		}
+	case err != nil:
+		return nil, err
+	case !user.EmailVerified:
+		if err := ss.reclaimUser(user); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := store.CreateIdentity(&modeluser.UserIdentity{
+		UserID:   user.ID,
+		Provider: providerName,
+		Subject:  identity.Subject,
+		Email:    email,
+	}); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// reclaimUser hands an account whose email was never verified to the owner of
+// the address, who just proved it at the identity provider. Whoever signed up
+// with the address before may not be that owner, so everything they could sign
+// in with goes: the password is replaced, and sessions, API keys and two-factor
+// enrolment are removed.
+func (ss *SSOService) reclaimUser(user *modeluser.User) error {
+	store := ss.userService.userStore
+	password, err := utils.RandomToken(32)
+	if err != nil {
+		return err
+	}
+	if err := ss.userService.setPassword(user.ID, password); err != nil {
+		return err
+	}
+	if err := store.RevokeUserSessions(user.ID); err != nil {
+		return err
+	}
+	ss.userService.access.forgetUser(user.ID)
+	if err := store.RevokeUserAPIKeys(user.ID); err != nil {
+		return err
+	}
+	if err := store.DeleteMFA(user.ID); err != nil {
+		return err
+	}
+	if _, err := store.MarkEmailVerified(user.ID, user.Email); err != nil {
+		return err
+	}
+	user.EmailVerified = true
+	return nil
+}
+
+// createUser registers a user for an external account. The random password is
+// never shown; the user can set one through the forgot password flow.
+func (ss *SSOService) createUser(email, name string) (*modeluser.User, error) {
+	password, err := utils.RandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	fullName := strings.TrimSpace(name)
+	if fullName == "" {
+		fullName = strings.SplitN(email, "@", 2)[0]
+	}
+	user, err := ss.userService.CreateUser(common.Truncate(fullName, 100), email, password, modeluser.UserRole)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ss.userService.userStore.MarkEmailVerified(user.ID, user.Email); err != nil {
+		return nil, err
+	}
+	user.EmailVerified = true
+	return user, nil
+}
@@ -0,0 +1,268 @@
+package userbusiness
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/khoaphungnguyen/go-openai/internal/oidc"
+	"github.com/khoaphungnguyen/go-openai/internal/oidc/oidctest"
+	modeluser "github.com/khoaphungnguyen/go-openai/internal/user/model"
+	"github.com/khoaphungnguyen/go-openai/internal/user/utils"
+)
+
+const (
+	testProvider = "test"
+	testClientID = "test-client"
+)
+
+var testIdentity = oidctest.User{
+	Subject:       "subject-1",
+	Email:         "ada@example.com",
+	EmailVerified: true,
+	Name:          "Ada Lovelace",
+}
+
+type ssoTest struct {
+	*testing.T
+	idp   *oidctest.Server
+	sso   *SSOService
+	users *UserService
+	store *memoryUserStore
+}
+
+// newSSOTest signs users in through a mock identity provider.
+func newSSOTest(t *testing.T) *ssoTest {
+	idp, err := oidctest.NewServer(testClientID, testIdentity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(idp.Close)
+
+	users, store, _ := newTestUserService()
+	provider := oidc.NewProvider(oidc.Config{
+		Name:        testProvider,
+		IssuerURL:   idp.URL,
+		ClientID:    testClientID,
+		RedirectURL: testAppURL + "/auth/callback",
+	}, idp.Client())
+	return &ssoTest{T: t, idp: idp, sso: NewSSOService(users, provider), users: users, store: store}
+}
+
+// authorize starts a login and follows it through the provider, returning
+// the code and state the provider redirected back with.
+func (st *ssoTest) authorize() (string, string) {
+	st.Helper()
+	authURL, state, err := st.sso.StartLogin(context.Background(), testProvider)
+	if err != nil {
+		st.Fatalf("StartLogin: %v", err)
+	}
+
+	client := st.idp.Client()
+	client.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+	resp, err := client.Get(authURL)
+	if err != nil {
+		st.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusFound {
+		st.Fatalf("provider answered the authorization request with %s", resp.Status)
+	}
+	callback, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		st.Fatal(err)
+	}
+	if got := callback.Query().Get("state"); got != state {
+		st.Fatalf("provider redirected back with state %q, want the %q StartLogin returned", got, state)
+	}
+	return callback.Query().Get("code"), state
+}
+
+// login runs a whole login and returns its result.
+func (st *ssoTest) login() (*modeluser.User, error) {
+	st.Helper()
+	code, state := st.authorize()
+	return st.sso.CompleteLogin(context.Background(), testProvider, code, state)
+}
+
+func TestSSOLoginCreatesAndLinksUser(t *testing.T) {
+	st := newSSOTest(t)
+
+	user, err := st.login()
+	if err != nil {
+		t.Fatalf("CompleteLogin: %v", err)
+	}
+	if user.Email != testIdentity.Email || user.FullName != testIdentity.Name || !user.EmailVerified {
+		t.Errorf("created %+v, want a verified user for %s", user, testIdentity.Email)
+	}
+
+	// A later login finds the linked user even after the provider's email changes
+	st.idp.SetUser(oidctest.User{Subject: testIdentity.Subject, Email: "ada@example.org"})
+	again, err := st.login()
+	if err != nil {
+		t.Fatalf("CompleteLogin again: %v", err)
+	}
+	if again.ID != user.ID {
+		t.Errorf("second login signed in as %s, want %s", again.ID, user.ID)
+	}
+}
+
+func TestSSOLoginUsesPKCE(t *testing.T) {
+	st := newSSOTest(t)
+	authURL, _, err := st.sso.StartLogin(context.Background(), testProvider)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := parsed.Query()
+
+	if len(st.store.loginStates) != 1 {
+		t.Fatalf("stored %d login states, want 1", len(st.store.loginStates))
+	}
+	for _, pending := range st.store.loginStates {
+		if query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") != oidc.CodeChallenge(pending.CodeVerifier) {
+			t.Errorf("authorization request %s does not carry the S256 challenge of the stored verifier", authURL)
+		}
+		if query.Get("nonce") != pending.Nonce {
+			t.Errorf("authorization request nonce %q, want the stored %q", query.Get("nonce"), pending.Nonce)
+		}
+		if hashToken(query.Get("state")) != pending.StateHash {
+			t.Error("the stored state does not match the authorization request")
+		}
+	}
+
+	// The provider refuses the code without the matching verifier
+	code, state := st.authorize()
+	hash := hashToken(state)
+	st.store.mu.Lock()
+	pending := st.store.loginStates[hash]
+	pending.CodeVerifier = "not-the-verifier"
+	st.store.loginStates[hash] = pending
+	st.store.mu.Unlock()
+	if _, err := st.sso.CompleteLogin(context.Background(), testProvider, code, state); !errors.Is(err, oidc.ErrExchange) {
+		t.Errorf("CompleteLogin with the wrong verifier = %v, want ErrExchange", err)
+	}
+}
+
+func TestSSOLoginRejectsBadState(t *testing.T) {
+	st := newSSOTest(t)
+	ctx := context.Background()
+	code, state := st.authorize()
+
+	if _, err := st.sso.CompleteLogin(ctx, testProvider, code, "forged-state"); !errors.Is(err, ErrInvalidLoginState) {
+		t.Errorf("CompleteLogin with an unknown state = %v, want ErrInvalidLoginState", err)
+	}
+	if _, err := st.sso.CompleteLogin(ctx, "other", code, state); !errors.Is(err, ErrUnknownProvider) {
+		t.Errorf("CompleteLogin at an unknown provider = %v, want ErrUnknownProvider", err)
+	}
+	if _, err := st.sso.CompleteLogin(ctx, testProvider, code, state); err != nil {
+		t.Fatalf("CompleteLogin: %v", err)
+	}
+	if _, err := st.sso.CompleteLogin(ctx, testProvider, code, state); !errors.Is(err, ErrInvalidLoginState) {
+		t.Errorf("CompleteLogin replaying the state = %v, want ErrInvalidLoginState", err)
+	}
+}
+
+func TestSSOLoginRejectsInvalidIDToken(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims map[string]interface{}
+	}{
+		{"wrong nonce", map[string]interface{}{"nonce": "another-nonce"}},
+		{"wrong audience", map[string]interface{}{"aud": "another-client"}},
+		{"wrong issuer", map[string]interface{}{"iss": "https://idp.example.com"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := newSSOTest(t)
+			st.idp.OverrideClaims(tt.claims)
+			if _, err := st.login(); !errors.Is(err, oidc.ErrInvalidIDToken) {
+				t.Errorf("CompleteLogin = %v, want ErrInvalidIDToken", err)
+			}
+			if len(st.store.users) != 0 {
+				t.Error("a user was created from an invalid ID token")
+			}
+		})
+	}
+}
+
+func TestSSOLoginMatchesEmailCaseInsensitively(t *testing.T) {
+	st := newSSOTest(t)
+	existing, err := st.users.CreateUser("Ada Lovelace", testIdentity.Email, "correct horse", modeluser.UserRole)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st.store.updateUser(existing.ID, func(u *modeluser.User) { u.EmailVerified = true })
+	shouting := testIdentity
+	shouting.Email = " ADA@Example.COM "
+	st.idp.SetUser(shouting)
+
+	user, err := st.login()
+	if err != nil {
+		t.Fatalf("CompleteLogin: %v", err)
+	}
+	if user.ID != existing.ID {
+		t.Errorf("signed in as %s, want the existing user %s", user.ID, existing.ID)
+	}
+	if len(st.store.users) != 1 {
+		t.Errorf("%d users exist, want 1", len(st.store.users))
+	}
+	if len(st.store.identities) != 1 || st.store.identities[0].Email != testIdentity.Email {
+		t.Errorf("linked identities %+v, want one for %s", st.store.identities, testIdentity.Email)
+	}
+
+	// New users are created with the normalized address too
+	st = newSSOTest(t)
+	st.idp.SetUser(shouting)
+	if user, err = st.login(); err != nil {
+		t.Fatalf("CompleteLogin: %v", err)
+	}
+	if user.Email != testIdentity.Email {
+		t.Errorf("created user with email %q, want %q", user.Email, testIdentity.Email)
+	}
+}
+
+func TestSSOLoginRequiresVerifiedEmail(t *testing.T) {
+	st := newSSOTest(t)
+	unverified := testIdentity
+	unverified.EmailVerified = false
+	st.idp.SetUser(unverified)
+
+	if _, err := st.users.CreateUser("Ada Lovelace", testIdentity.Email, "correct horse", modeluser.UserRole); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := st.login(); !errors.Is(err, ErrProviderEmailNotVerified) {
+		t.Errorf("CompleteLogin = %v, want ErrProviderEmailNotVerified", err)
+	}
+	if len(st.store.identities) != 0 {
+		t.Error("an unverified email was linked to the existing user")
+	}
+}
+
+func TestSSOLoginReclaimsUnverifiedAccount(t *testing.T) {
+	st := newSSOTest(t)
+	squatter, err := st.users.CreateUser("Not Ada", testIdentity.Email, "correct horse", modeluser.UserRole)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := st.login()
+	if err != nil {
+		t.Fatalf("CompleteLogin: %v", err)
+	}
+	if user.ID != squatter.ID || !user.EmailVerified {
+		t.Fatalf("signed in as %+v, want the existing account verified", user)
+	}
+	stored := st.store.user(user.ID)
+	if utils.CheckPassword(stored.PasswordHash, stored.Salt, "correct horse") == nil {
+		t.Error("the earlier password still works")
+	}
+	if st.store.revokedSessions[user.ID] == 0 || st.store.revokedAPIKeys[user.ID] == 0 || st.store.deletedMFA[user.ID] == 0 {
+		t.Error("the earlier sessions, API keys or two-factor enrolment were kept")
+	}
+}
@@ -0,0 +1,344 @@
+package userbusiness
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/khoaphungnguyen/go-openai/internal/mailer"
+	userauth "github.com/khoaphungnguyen/go-openai/internal/user/auth"
+	modeluser "github.com/khoaphungnguyen/go-openai/internal/user/model"
+	storageuser "github.com/khoaphungnguyen/go-openai/internal/user/storage"
+)
+
+const testAppURL = "https://app.example.com"
+
+// newTestUserService creates a UserService backed by memory whose emails are captured.
+func newTestUserService() (*UserService, *memoryUserStore, *mailer.CapturingMailer) {
+	store := newMemoryUserStore()
+	mail := mailer.NewCapturingMailer()
+	jwt := &userauth.JwtWrapper{
+		SecretKey:              "test-secret",
+		Issuer:                 "test",
+		AccessTokenExpiration:  userauth.DefaultAccessTokenDuration,
+		RefreshTokenExpiration: userauth.DefaultRefreshTokenDuration,
+	}
+	return NewUserService(store, jwt, mail, testAppURL, false), store, mail
+}
+
+// memoryUserStore keeps the records the user service tests need in memory.
+// The embedded interface is nil, so calling any other method panics.
+type memoryUserStore struct {
+	storageuser.UserStore
+
+	mu              sync.Mutex
+	users           map[uuid.UUID]*modeluser.User
+	resetTokens     []*modeluser.PasswordResetToken
+	attempts        []modeluser.LoginAttempt
+	lockouts        []*modeluser.LoginLockout
+	mfa             map[uuid.UUID]*modeluser.UserMFA
+	identities      []modeluser.UserIdentity
+	loginStates     map[string]modeluser.OIDCLoginState
+	revokedSessions map[uuid.UUID]int
+	revokedAPIKeys  map[uuid.UUID]int
+	deletedMFA      map[uuid.UUID]int
+}
+
+func newMemoryUserStore() *memoryUserStore {
+	return &memoryUserStore{
+		users:           make(map[uuid.UUID]*modeluser.User),
+		mfa:             make(map[uuid.UUID]*modeluser.UserMFA),
+		loginStates:     make(map[string]modeluser.OIDCLoginState),
+		revokedSessions: make(map[uuid.UUID]int),
+		revokedAPIKeys:  make(map[uuid.UUID]int),
+		deletedMFA:      make(map[uuid.UUID]int),
+	}
+}
+
+// user returns a copy of the stored user.
+func (m *memoryUserStore) user(id uuid.UUID) modeluser.User {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return *m.users[id]
+}
+
+// updateUser changes the stored user.
+func (m *memoryUserStore) updateUser(id uuid.UUID, update func(*modeluser.User)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	update(m.users[id])
+}
+
+func (m *memoryUserStore) Create(user *modeluser.User) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	user.ID = uuid.New()
+	user.CreatedAt = time.Now()
+	if user.Role == "" {
+		user.Role = modeluser.UserRole
+	}
+	stored := *user
+	m.users[user.ID] = &stored
+	return nil
+}
+
+func (m *memoryUserStore) GetUserByEmail(email string) (*modeluser.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, user := range m.users {
+		if user.Email == email {
+			found := *user
+			return &found, nil
+		}
+	}
+	return nil, storageuser.ErrUserNotFound
+}
+
+func (m *memoryUserStore) GetUserByUUID(id uuid.UUID) (*modeluser.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	user, ok := m.users[id]
+	if !ok {
+		return nil, storageuser.ErrUserNotFound
+	}
+	found := *user
+	return &found, nil
+}
+
+func (m *memoryUserStore) ClaimVerificationSend(userID uuid.UUID, notBefore time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	user := m.users[userID]
+	if user.VerificationSentAt != nil && !user.VerificationSentAt.Before(notBefore) {
+		return false, nil
+	}
+	now := time.Now()
+	user.VerificationSentAt = &now
+	return true, nil
+}
+
+func (m *memoryUserStore) MarkEmailVerified(userID uuid.UUID, email string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	user, ok := m.users[userID]
+	if !ok || user.Email != email || user.DeletedAt != nil {
+		return false, nil
+	}
+	user.EmailVerified = true
+	return true, nil
+}
+
+func (m *memoryUserStore) UpdatePassword(userID uuid.UUID, passwordHash, salt string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.users[userID].PasswordHash = passwordHash
+	m.users[userID].Salt = salt
+	return nil
+}
+
+func (m *memoryUserStore) CreatePasswordResetToken(token *modeluser.PasswordResetToken) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	token.ID = uuid.New()
+	token.CreatedAt = time.Now()
+	stored := *token
+	m.resetTokens = append(m.resetTokens, &stored)
+	return nil
+}
+
+func (m *memoryUserStore) HasRecentPasswordReset(userID uuid.UUID, since time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, token := range m.resetTokens {
+		if token.UserID == userID && !token.CreatedAt.Before(since) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *memoryUserStore) ConsumePasswordResetToken(tokenHash string) (*modeluser.PasswordResetToken, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	for _, token := range m.resetTokens {
+		if token.TokenHash == tokenHash && token.UsedAt == nil && now.Before(token.ExpiresAt) {
+			token.UsedAt = &now
+			consumed := *token
+			return &consumed, nil
+		}
+	}
+	return nil, storageuser.ErrPasswordResetTokenNotFound
+}
+
+func (m *memoryUserStore) RevokeUserSessions(userID uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.revokedSessions[userID]++
+	return nil
+}
+
+func (m *memoryUserStore) RevokeUserAPIKeys(userID uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.revokedAPIKeys[userID]++
+	return nil
+}
+
+func (m *memoryUserStore) GetMFA(userID uuid.UUID) (*modeluser.UserMFA, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	mfa, ok := m.mfa[userID]
+	if !ok {
+		return nil, storageuser.ErrMFANotFound
+	}
+	found := *mfa
+	return &found, nil
+}
+
+func (m *memoryUserStore) SaveMFA(mfa *modeluser.UserMFA) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored := *mfa
+	m.mfa[mfa.UserID] = &stored
+	return nil
+}
+
+func (m *memoryUserStore) UseTOTPStep(userID uuid.UUID, step int64) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	mfa, ok := m.mfa[userID]
+	if !ok || mfa.LastUsedStep >= step {
+		return false, nil
+	}
+	mfa.LastUsedStep = step
+	return true, nil
+}
+
+func (m *memoryUserStore) DeleteMFA(userID uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deletedMFA[userID]++
+	return nil
+}
+
+func (m *memoryUserStore) RecordFailedLogin(attempt *modeluser.LoginAttempt) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.attempts = append(m.attempts, *attempt)
+	return nil
+}
+
+func (m *memoryUserStore) CountFailedLogins(email string, since time.Time) (modeluser.AttemptCount, error) {
+	return m.countFailedLogins(func(attempt modeluser.LoginAttempt) bool { return attempt.Email == email }, since), nil
+}
+
+func (m *memoryUserStore) CountFailedLoginsFromIP(ipAddress string, since time.Time) (modeluser.AttemptCount, error) {
+	return m.countFailedLogins(func(attempt modeluser.LoginAttempt) bool { return attempt.IPAddress == ipAddress }, since), nil
+}
+
+func (m *memoryUserStore) countFailedLogins(match func(modeluser.LoginAttempt) bool, since time.Time) modeluser.AttemptCount {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var count modeluser.AttemptCount
+	for _, attempt := range m.attempts {
+		if match(attempt) && !attempt.CreatedAt.Before(since) {
+			count.Count++
+			if count.LastAt == nil || attempt.CreatedAt.After(*count.LastAt) {
+				last := attempt.CreatedAt
+				count.LastAt = &last
+			}
+		}
+	}
+	return count
+}
+
+func (m *memoryUserStore) ClearFailedLogins(email string) error {
+	m.clearFailedLogins(func(attempt modeluser.LoginAttempt) bool { return attempt.Email == email })
+	return nil
+}
+
+func (m *memoryUserStore) ClearFailedLoginsFromIP(ipAddress string) error {
+	m.clearFailedLogins(func(attempt modeluser.LoginAttempt) bool { return attempt.IPAddress == ipAddress })
+	return nil
+}
+
+func (m *memoryUserStore) clearFailedLogins(match func(modeluser.LoginAttempt) bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	kept := m.attempts[:0]
+	for _, attempt := range m.attempts {
+		if !match(attempt) {
+			kept = append(kept, attempt)
+		}
+	}
+	m.attempts = kept
+}
+
+func (m *memoryUserStore) CreateLockout(lockout *modeluser.LoginLockout) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	lockout.ID = uuid.New()
+	lockout.CreatedAt = time.Now()
+	stored := *lockout
+	m.lockouts = append(m.lockouts, &stored)
+	return nil
+}
+
+func (m *memoryUserStore) GetActiveLockout(email, ipAddress string, now time.Time) (*modeluser.LoginLockout, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var active *modeluser.LoginLockout
+	for _, lockout := range m.lockouts {
+		matches := lockout.Email == email || (lockout.Email == "" && lockout.IPAddress == ipAddress)
+		if matches && lockout.UnlockedAt == nil && lockout.LockedUntil.After(now) &&
+			(active == nil || lockout.LockedUntil.After(active.LockedUntil)) {
+			active = lockout
+		}
+	}
+	if active == nil {
+		return nil, storageuser.ErrLockoutNotFound
+	}
+	found := *active
+	return &found, nil
+}
+
+func (m *memoryUserStore) GetIdentity(provider, subject string) (*modeluser.UserIdentity, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, identity := range m.identities {
+		if identity.Provider == provider && identity.Subject == subject {
+			found := identity
+			return &found, nil
+		}
+	}
+	return nil, storageuser.ErrIdentityNotFound
+}
+
+func (m *memoryUserStore) CreateIdentity(identity *modeluser.UserIdentity) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	identity.ID = uuid.New()
+	identity.CreatedAt = time.Now()
+	m.identities = append(m.identities, *identity)
+	return nil
+}
+
+func (m *memoryUserStore) CreateLoginState(state *modeluser.OIDCLoginState) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	state.CreatedAt = time.Now()
+	m.loginStates[state.StateHash] = *state
+	return nil
+}
+
+func (m *memoryUserStore) ConsumeLoginState(stateHash string) (*modeluser.OIDCLoginState, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	state, ok := m.loginStates[stateHash]
+	if !ok {
+		return nil, storageuser.ErrLoginStateNotFound
+	}
+	delete(m.loginStates, stateHash)
+	return &state, nil
+}
@@ -0,0 +1,176 @@
+package userbusiness
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	userauth "github.com/khoaphungnguyen/go-openai/internal/user/auth"
+	modeluser "github.com/khoaphungnguyen/go-openai/internal/user/model"
+	storageuser "github.com/khoaphungnguyen/go-openai/internal/user/storage"
+)
+
+// ExpiredCredentialRetention is how long refresh tokens, sessions, API keys,
+// password reset tokens and pending external logins are kept once expired.
+const ExpiredCredentialRetention = 7 * 24 * time.Hour
+
+var (
+	// ErrInvalidRefreshToken is returned when a refresh token is malformed, expired or unknown.
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	// ErrRefreshTokenReused is returned when an already rotated or revoked refresh
+	// token is presented again. Its whole family is revoked.
+	ErrRefreshTokenReused = errors.New("refresh token reuse detected")
+	// ErrAccountInactive is returned when tokens are requested for a soft-deleted account.
+	ErrAccountInactive = errors.New("account is not active")
+	// ErrAccountSuspended is returned when tokens are requested for a suspended account.
+	ErrAccountSuspended = errors.New("account is suspended")
+)
+
+// IssueTokens signs an access token and a refresh token for a user who just
+// logged in from the given client. It starts a new session, whose ID is the
+// family of the refresh token.
+func (s *UserService) IssueTokens(user *modeluser.User, client modeluser.ClientInfo) (*modeluser.TokenPair, error) {
+	return s.issueTokens(user, newSession(user.ID, client, false), nil, client)
+}
+
+// RefreshTokens exchanges a refresh token for a new token pair. The presented
+// token is rotated out and cannot be used again; presenting it a second time
+// revokes every token of its family.
+func (s *UserService) RefreshTokens(signedToken string, client modeluser.ClientInfo) (*modeluser.TokenPair, error) {
+	current, err := s.lookupRefreshToken(signedToken)
+	if err != nil {
+		return nil, err
+	}
+	if current.ReplacedBy != nil || current.RevokedAt != nil {
+		if err := s.userStore.RevokeSession(current.FamilyID); err != nil {
+			return nil, err
+		}
+		s.access.forgetSession(current.FamilyID)
+		return nil, ErrRefreshTokenReused
+	}
+
+	isSoftDeleted, err := s.userStore.IsSoftDeleted(current.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if isSoftDeleted {
+		return nil, ErrAccountInactive
+	}
+	user, err := s.userStore.GetUserByUUID(current.UserID)
+	if err != nil {
+		return nil, err
+	}
+	session, err := s.userStore.GetSession(current.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.issueTokens(user, session, &current.ID, client)
+}
+
+// Logout revokes the session of the presented refresh token and returns the
+// user it belonged to.
+func (s *UserService) Logout(signedToken string) (uuid.UUID, error) {
+	token, err := s.lookupRefreshToken(signedToken)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if err := s.userStore.RevokeSession(token.FamilyID); err != nil {
+		return uuid.Nil, err
+	}
+	s.access.forgetSession(token.FamilyID)
+	return token.UserID, nil
+}
+
+// LogoutEverywhere revokes every session of the user.
+func (s *UserService) LogoutEverywhere(userID uuid.UUID) error {
+	if err := s.userStore.RevokeUserSessions(userID); err != nil {
+		return err
+	}
+	s.access.forgetUser(userID)
+	return nil
+}
+
+// PurgeExpiredRefreshTokens permanently removes refresh tokens that expired before the cutoff.
+func (s *UserService) PurgeExpiredRefreshTokens(cutoff time.Time) (int64, error) {
+	return s.userStore.DeleteRefreshTokensExpiredBefore(cutoff)
+}
+
+// issueTokens signs a token pair for the session and records the refresh
+// token in the session's family. When replacing is set the new refresh token
+// rotates that one out; otherwise the session is new and gets saved. The
+// access token carries the user's current role.
+func (s *UserService) issueTokens(user *modeluser.User, session *modeluser.Session, replacing *uuid.UUID, client modeluser.ClientInfo) (*modeluser.TokenPair, error) {
+	if user.SuspendedAt != nil {
+		return nil, ErrAccountSuspended
+	}
+	accessToken, err := s.jwt.GenerateToken(user.ID.String(), user.FullName, string(user.Role), session.ID.String(), session.MFA)
+	if err != nil {
+		return nil, err
+	}
+
+	record := &modeluser.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		FamilyID:  session.ID,
+		ExpiresAt: time.Now().Add(s.jwt.RefreshTokenExpiration),
+	}
+	refreshToken, err := s.jwt.RefreshToken(user.ID.String(), user.FullName, record.ID.String(), session.ID.String(), record.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	record.TokenHash = hashToken(refreshToken)
+
+	if replacing == nil {
+		session.ExpiresAt = record.ExpiresAt
+		err = s.userStore.CreateSession(session, record)
+	} else {
+		var rotated bool
+		rotated, err = s.userStore.RotateRefreshToken(*replacing, record, client)
+		if err == nil && !rotated {
+			// Another request rotated the same token first
+			if err := s.userStore.RevokeSession(session.ID); err != nil {
+				return nil, err
+			}
+			s.access.forgetSession(session.ID)
+			return nil, ErrRefreshTokenReused
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &modeluser.TokenPair{
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		ExpiresIn:        time.Now().Add(s.jwt.AccessTokenExpiration).Unix(),
+		RefreshExpiresAt: record.ExpiresAt,
+	}, nil
+}
+
+// lookupRefreshToken validates a signed refresh token and loads its record.
+func (s *UserService) lookupRefreshToken(signedToken string) (*modeluser.RefreshToken, error) {
+	claims, err := s.jwt.ValidateToken(signedToken, userauth.RefreshToken)
+	if err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+	token, err := s.userStore.GetRefreshTokenByHash(hashToken(signedToken))
+	if errors.Is(err, storageuser.ErrRefreshTokenNotFound) {
+		return nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	if token.ID.String() != claims.Id || token.UserID.String() != claims.UserID {
+		return nil, ErrInvalidRefreshToken
+	}
+	return token, nil
+}
+
+// hashToken returns the hex SHA-256 of a token, which is what gets stored.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -1,13 +1,35 @@
 package userbusiness
 
-import storageuser "github.com/khoaphungnguyen/go-openai/internal/user/storage"
+import (
+	"strings"
+
+	"github.com/khoaphungnguyen/go-openai/internal/mailer"
+	userauth "github.com/khoaphungnguyen/go-openai/internal/user/auth"
+	storageuser "github.com/khoaphungnguyen/go-openai/internal/user/storage"
+)
 
 // UserService provides business logic for user operations.
 type UserService struct {
 	userStore storageuser.UserStore
+	jwt       *userauth.JwtWrapper
+	mailer    mailer.Mailer
+	appURL    string
+
+	requireAdminMFA bool
+	access          *accessCache
 }
 
-// NewUserService creates a new instance of UserService.
-func NewUserService(userStore storageuser.UserStore) *UserService {
-	return &UserService{userStore: userStore}
+// NewUserService creates a new instance of UserService. The JWT wrapper signs
+// the tokens issued to users, and links in account emails point to appURL.
+// When requireAdminMFA is set, administrators and support staff must use
+// two-factor authentication.
+func NewUserService(userStore storageuser.UserStore, jwt *userauth.JwtWrapper, mailer mailer.Mailer, appURL string, requireAdminMFA bool) *UserService {
+	return &UserService{
+		userStore:       userStore,
+		jwt:             jwt,
+		mailer:          mailer,
+		appURL:          strings.TrimRight(appURL, "/"),
+		requireAdminMFA: requireAdminMFA,
+		access:          newAccessCache(),
+	}
 }
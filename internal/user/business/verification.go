@@ -0,0 +1,99 @@
+package userbusiness
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/khoaphungnguyen/go-openai/internal/mailer"
+	userauth "github.com/khoaphungnguyen/go-openai/internal/user/auth"
+	modeluser "github.com/khoaphungnguyen/go-openai/internal/user/model"
+)
+
+const (
+	// VerificationTokenDuration is how long an email verification link stays valid.
+	VerificationTokenDuration = 24 * time.Hour
+	// VerificationResendInterval is the least time between two verification emails to a user.
+	VerificationResendInterval = 2 * time.Minute
+)
+
+var (
+	// ErrInvalidVerificationToken is returned when a verification link is malformed,
+	// expired or was sent to an address the user no longer has.
+	ErrInvalidVerificationToken = errors.New("invalid or expired verification token")
+	// ErrEmailAlreadyVerified is returned when verification is requested for a verified address.
+	ErrEmailAlreadyVerified = errors.New("email already verified")
+	// ErrVerificationThrottled is returned when a verification email was sent too recently.
+	ErrVerificationThrottled = errors.New("verification email sent too recently")
+)
+
+// SendVerificationEmail mails the user a link that verifies their current
+// address. At most one email is sent per VerificationResendInterval.
+func (s *UserService) SendVerificationEmail(ctx context.Context, user *modeluser.User) error {
+	if user.EmailVerified {
+		return ErrEmailAlreadyVerified
+	}
+	claimed, err := s.userStore.ClaimVerificationSend(user.ID, time.Now().Add(-VerificationResendInterval))
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return ErrVerificationThrottled
+	}
+
+	token, err := s.jwt.VerificationToken(user.ID.String(), user.Email, time.Now().Add(VerificationTokenDuration))
+	if err != nil {
+		return err
+	}
+	link := s.appURL + "/verify-email?token=" + url.QueryEscape(token)
+
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Hi %s,\n\nPlease confirm your email address by opening the link below:\n\n%s\n\n"+
+			"The link expires in %d hours. If you did not create an account, you can ignore this email.\n",
+			user.FullName, link, int(VerificationTokenDuration.Hours())),
+	})
+}
+
+// ResendVerificationEmail mails a new verification link to the user.
+func (s *UserService) ResendVerificationEmail(ctx context.Context, userID uuid.UUID) error {
+	user, err := s.userStore.GetUserByUUID(userID)
+	if err != nil {
+		return err
+	}
+	return s.SendVerificationEmail(ctx, user)
+}
+
+// VerifyEmail marks the address a verification token was sent to as verified
+// and returns the user it belongs to.
+func (s *UserService) VerifyEmail(token string) (uuid.UUID, error) {
+	claims, err := s.jwt.ValidateToken(token, userauth.EmailVerificationToken)
+	if err != nil {
+		return uuid.Nil, ErrInvalidVerificationToken
+	}
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		return uuid.Nil, ErrInvalidVerificationToken
+	}
+	verified, err := s.userStore.MarkEmailVerified(userID, claims.Email)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if !verified {
+		return uuid.Nil, ErrInvalidVerificationToken
+	}
+	return userID, nil
+}
+
+// IsEmailVerified reports whether the user has verified their email address.
+func (s *UserService) IsEmailVerified(userID uuid.UUID) (bool, error) {
+	user, err := s.userStore.GetUserByUUID(userID)
+	if err != nil {
+		return false, err
+	}
+	return user.EmailVerified, nil
+}
@@ -0,0 +1,119 @@
+package userbusiness
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/khoaphungnguyen/go-openai/internal/mailer"
+	modeluser "github.com/khoaphungnguyen/go-openai/internal/user/model"
+)
+
+// linkToken returns the token query parameter of the link to path in a mail body.
+func linkToken(t *testing.T, msg mailer.Message, path string) string {
+	t.Helper()
+	prefix := testAppURL + path + "?"
+	for _, line := range strings.Split(msg.Body, "\n") {
+		if strings.HasPrefix(line, prefix) {
+			link, err := url.Parse(line)
+			if err != nil {
+				t.Fatalf("mail has a malformed link %q: %v", line, err)
+			}
+			return link.Query().Get("token")
+		}
+	}
+	t.Fatalf("mail has no link to %s:\n%s", path, msg.Body)
+	return ""
+}
+
+func TestSignupVerificationEmail(t *testing.T) {
+	ctx := context.Background()
+	s, store, mail := newTestUserService()
+
+	user, err := s.CreateUser("Ada Lovelace", "ada@example.com", "correct horse", modeluser.UserRole)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SendVerificationEmail(ctx, user); err != nil {
+		t.Fatalf("SendVerificationEmail: %v", err)
+	}
+
+	msg, ok := mail.Last()
+	if !ok {
+		t.Fatal("no verification email was sent")
+	}
+	if msg.To != "ada@example.com" || msg.Subject != "Verify your email address" {
+		t.Errorf("sent %q to %q, want the verification email to ada@example.com", msg.Subject, msg.To)
+	}
+	if !strings.Contains(msg.Body, "Ada Lovelace") {
+		t.Errorf("verification email does not greet the user:\n%s", msg.Body)
+	}
+
+	userID, err := s.VerifyEmail(linkToken(t, msg, "/verify-email"))
+	if err != nil {
+		t.Fatalf("VerifyEmail: %v", err)
+	}
+	if userID != user.ID || !store.user(user.ID).EmailVerified {
+		t.Error("the link did not verify the user's email")
+	}
+
+	if err := s.ResendVerificationEmail(ctx, user.ID); !errors.Is(err, ErrEmailAlreadyVerified) {
+		t.Errorf("ResendVerificationEmail after verifying = %v, want ErrEmailAlreadyVerified", err)
+	}
+	if n := len(mail.Sent()); n != 1 {
+		t.Errorf("sent %d emails, want 1", n)
+	}
+}
+
+func TestVerificationLinkForOldAddress(t *testing.T) {
+	s, store, mail := newTestUserService()
+	user, err := s.CreateUser("Ada Lovelace", "ada@example.com", "correct horse", modeluser.UserRole)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SendVerificationEmail(context.Background(), user); err != nil {
+		t.Fatal(err)
+	}
+	msg, _ := mail.Last()
+
+	store.updateUser(user.ID, func(u *modeluser.User) { u.Email = "ada@example.org" })
+	if _, err := s.VerifyEmail(linkToken(t, msg, "/verify-email")); !errors.Is(err, ErrInvalidVerificationToken) {
+		t.Errorf("VerifyEmail for a changed address = %v, want ErrInvalidVerificationToken", err)
+	}
+	if store.user(user.ID).EmailVerified {
+		t.Error("the new address was verified by a link sent to the old one")
+	}
+}
+
+func TestResendVerificationThrottled(t *testing.T) {
+	ctx := context.Background()
+	s, store, mail := newTestUserService()
+	user, err := s.CreateUser("Ada Lovelace", "ada@example.com", "correct horse", modeluser.UserRole)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SendVerificationEmail(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.ResendVerificationEmail(ctx, user.ID); !errors.Is(err, ErrVerificationThrottled) {
+		t.Errorf("ResendVerificationEmail right away = %v, want ErrVerificationThrottled", err)
+	}
+	if n := len(mail.Sent()); n != 1 {
+		t.Fatalf("sent %d emails while throttled, want 1", n)
+	}
+
+	store.updateUser(user.ID, func(u *modeluser.User) {
+		sentAt := time.Now().Add(-VerificationResendInterval - time.Second)
+		u.VerificationSentAt = &sentAt
+	})
+	if err := s.ResendVerificationEmail(ctx, user.ID); err != nil {
+		t.Fatalf("ResendVerificationEmail after the interval: %v", err)
+	}
+	if n := len(mail.Sent()); n != 2 {
+		t.Errorf("sent %d emails, want 2", n)
+	}
+}
@@ -0,0 +1,68 @@
+package usermodel
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserStatus is the state of an account as shown to administrators.
+type UserStatus string
+
+const (
+	StatusActive    UserStatus = "active"
+	StatusSuspended UserStatus = "suspended"
+	StatusDeleted   UserStatus = "deleted"
+)
+
+// UserFilter narrows a user search. Empty fields do not filter.
+type UserFilter struct {
+	Query  string // Matches part of the name or email
+	Role   Role
+	Status UserStatus
+	Limit  int
+	Offset int
+}
+
+// AdminUser is the representation of a user in the admin API.
+type AdminUser struct {
+	ID            uuid.UUID  `json:"id"`
+	FullName      string     `json:"fullName"`
+	Email         string     `json:"email"`
+	EmailVerified bool       `json:"emailVerified"`
+	Role          Role       `json:"role"`
+	Status        UserStatus `json:"status"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	LastLogin     *time.Time `json:"lastLogin"`
+	SuspendedAt   *time.Time `json:"suspendedAt"`
+	DeletedAt     *time.Time `json:"deletedAt"`
+}
+
+// Status returns the state of the account. A deleted account counts as
+// deleted even if it was suspended before.
+func (u *User) Status() UserStatus {
+	switch {
+	case u.DeletedAt != nil:
+		return StatusDeleted
+	case u.SuspendedAt != nil:
+		return StatusSuspended
+	default:
+		return StatusActive
+	}
+}
+
+// ToAdminUser converts a User to its admin API representation.
+func (u *User) ToAdminUser() AdminUser {
+	return AdminUser{
+		ID:            u.ID,
+		FullName:      u.FullName,
+		Email:         u.Email,
+		EmailVerified: u.EmailVerified,
+		Role:          u.Role,
+		Status:        u.Status(),
+		CreatedAt:     u.CreatedAt,
+		LastLogin:     u.LastLogin,
+		SuspendedAt:   u.SuspendedAt,
+		DeletedAt:     u.DeletedAt,
+	}
+}
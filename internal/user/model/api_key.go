@@ -0,0 +1,80 @@
+package usermodel
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// API key scopes. Each one opens a group of protected routes to the keys that carry it.
+const (
+	ScopeChat       = "chat"       // Threads, messages, chat completions and attachments
+	ScopeNotes      = "notes"      // Notes, reviews, quizzes and code runs
+	ScopeDocuments  = "documents"  // Document collections
+	ScopeWorkspaces = "workspaces" // Workspaces and their members
+	ScopeSearch     = "search"     // Semantic search
+	ScopeProfile    = "profile"    // Reading the profile of the key's owner
+)
+
+// Scopes lists every scope an API key can be given.
+var Scopes = []string{ScopeChat, ScopeNotes, ScopeDocuments, ScopeWorkspaces, ScopeSearch, ScopeProfile}
+
+// APIKey is a personal key a user created for programmatic access. Only a
+// hash of the key is stored; the prefix is kept so users can tell keys apart.
+type APIKey struct {
+	ID         uuid.UUID  `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	UserID     uuid.UUID  `gorm:"column:user_id;type:uuid;not null"`
+	Name       string     `gorm:"column:name;type:varchar(100);not null"`
+	Prefix     string     `gorm:"column:prefix;type:varchar(16);not null"`
+	KeyHash    string     `gorm:"column:key_hash;type:varchar(64);unique;not null"`
+	Scopes     []string   `gorm:"column:scopes;type:jsonb;serializer:json;not null"`
+	ExpiresAt  *time.Time `gorm:"column:expires_at"` // Nil for keys that never expire
+	LastUsedAt *time.Time `gorm:"column:last_used_at"`
+	RevokedAt  *time.Time `gorm:"column:revoked_at"`
+	CreatedAt  time.Time  `gorm:"column:created_at;default:now()"`
+}
+
+func (APIKey) TableName() string {
+	return "api_key"
+}
+
+// IsActive reports whether the key is neither revoked nor expired at the given time.
+func (k *APIKey) IsActive(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+// HasScope reports whether the key grants the scope.
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKeyResponse is the API representation of an API key. Key is only set in
+// the response to creating it.
+type APIKeyResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Key        string     `json:"key,omitempty"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expiresAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+// ToResponse converts an APIKey to its API representation.
+func (k *APIKey) ToResponse() APIKeyResponse {
+	return APIKeyResponse{
+		ID:         k.ID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scopes:     k.Scopes,
+		ExpiresAt:  k.ExpiresAt,
+		LastUsedAt: k.LastUsedAt,
+		CreatedAt:  k.CreatedAt,
+	}
+}
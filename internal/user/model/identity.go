@@ -0,0 +1,36 @@
+package usermodel
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserIdentity links a user to their account at an external identity provider.
+type UserIdentity struct {
+	ID        uuid.UUID `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	UserID    uuid.UUID `gorm:"column:user_id;type:uuid;not null"`
+	Provider  string    `gorm:"column:provider;type:varchar(50);not null"`
+	Subject   string    `gorm:"column:subject;type:varchar(255);not null"`
+	Email     string    `gorm:"column:email;type:varchar(255)"`
+	CreatedAt time.Time `gorm:"column:created_at;default:now()"`
+}
+
+func (UserIdentity) TableName() string {
+	return "user_identity"
+}
+
+// OIDCLoginState remembers an external login between sending the user to the
+// provider and their return. Only a hash of the state parameter is stored.
+type OIDCLoginState struct {
+	StateHash    string    `gorm:"primaryKey;column:state_hash;type:varchar(64)"`
+	Provider     string    `gorm:"column:provider;type:varchar(50);not null"`
+	Nonce        string    `gorm:"column:nonce;type:varchar(64);not null"`
+	CodeVerifier string    `gorm:"column:code_verifier;type:varchar(128);not null"`
+	ExpiresAt    time.Time `gorm:"column:expires_at;not null"`
+	CreatedAt    time.Time `gorm:"column:created_at;default:now()"`
+}
+
+func (OIDCLoginState) TableName() string {
+	return "oidc_login_state"
+}
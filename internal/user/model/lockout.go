@@ -0,0 +1,60 @@
+package usermodel
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LoginAttempt is a failed password or two-factor login, counted towards
+// delays and lockouts of the email and IP address it came from.
+type LoginAttempt struct {
+	ID        uuid.UUID `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	Email     string    `gorm:"column:email;type:varchar(255);not null"` // Lowercased
+	IPAddress string    `gorm:"column:ip_address;type:varchar(45);not null"`
+	CreatedAt time.Time `gorm:"column:created_at;not null"`
+}
+
+func (LoginAttempt) TableName() string {
+	return "login_attempt"
+}
+
+// AttemptCount is the number of failed logins from a source and when the last one happened.
+type AttemptCount struct {
+	Count  int64
+	LastAt *time.Time
+}
+
+// LoginLockout records that logins for an email, or from an IP address when
+// Email is empty, were blocked after too many failures. Records are kept after
+// the lockout ends so lockouts can be reviewed.
+type LoginLockout struct {
+	ID             uuid.UUID  `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
+	UserID         *uuid.UUID `gorm:"column:user_id;type:uuid" json:"userId"`
+	Email          string     `gorm:"column:email;type:varchar(255);not null" json:"email"`
+	IPAddress      string     `gorm:"column:ip_address;type:varchar(45);not null" json:"ipAddress"` // Address of the last failed attempt
+	FailedAttempts int64      `gorm:"column:failed_attempts;not null" json:"failedAttempts"`
+	LockedUntil    time.Time  `gorm:"column:locked_until;not null" json:"lockedUntil"`
+	UnlockedAt     *time.Time `gorm:"column:unlocked_at" json:"unlockedAt"`
+	UnlockedBy     *uuid.UUID `gorm:"column:unlocked_by;type:uuid" json:"unlockedBy"`
+	CreatedAt      time.Time  `gorm:"column:created_at;default:now()" json:"createdAt"`
+}
+
+func (LoginLockout) TableName() string {
+	return "login_lockout"
+}
+
+// Active reports whether the lockout still blocks logins at the given time.
+func (l *LoginLockout) Active(now time.Time) bool {
+	return l.UnlockedAt == nil && now.Before(l.LockedUntil)
+}
+
+// LockoutFilter narrows a lockout search. Empty fields do not filter.
+type LockoutFilter struct {
+	UserID     *uuid.UUID
+	Email      string
+	IPAddress  string
+	ActiveOnly bool
+	Limit      int
+	Offset     int
+}
@@ -0,0 +1,54 @@
+package usermodel
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserMFA holds a user's TOTP secret. Two-factor authentication is on once the
+// enrollment has been confirmed with a first code.
+type UserMFA struct {
+	UserID       uuid.UUID  `gorm:"primaryKey;type:uuid"`
+	Secret       string     `gorm:"column:secret;type:varchar(64);not null"`
+	ConfirmedAt  *time.Time `gorm:"column:confirmed_at"`
+	LastUsedStep int64      `gorm:"column:last_used_step;not null;default:0"` // Time step of the last accepted code, to refuse replays
+	CreatedAt    time.Time  `gorm:"column:created_at;default:now()"`
+}
+
+func (UserMFA) TableName() string {
+	return "user_mfa"
+}
+
+// Enabled reports whether the enrollment was confirmed.
+func (m *UserMFA) Enabled() bool {
+	return m != nil && m.ConfirmedAt != nil
+}
+
+// RecoveryCode is a hashed one-time code that stands in for a TOTP code when
+// the user has lost their authenticator.
+type RecoveryCode struct {
+	ID        uuid.UUID  `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	UserID    uuid.UUID  `gorm:"column:user_id;type:uuid;not null"`
+	CodeHash  string     `gorm:"column:code_hash;type:varchar(64);not null"`
+	UsedAt    *time.Time `gorm:"column:used_at"`
+	CreatedAt time.Time  `gorm:"column:created_at;default:now()"`
+}
+
+func (RecoveryCode) TableName() string {
+	return "mfa_recovery_code"
+}
+
+// MFAEnrollment is what an authenticator app needs to add the account.
+type MFAEnrollment struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauthUri"`
+}
+
+// MFAStatus describes the two-factor authentication settings of a user.
+type MFAStatus struct {
+	Enabled           bool       `json:"enabled"`
+	ConfirmedAt       *time.Time `json:"confirmedAt,omitempty"`
+	RecoveryCodesLeft int64      `json:"recoveryCodesLeft"`
+	Required          bool       `json:"required"`
+}
@@ -0,0 +1,22 @@
+package usermodel
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PasswordResetToken is a single-use token emailed to reset a forgotten
+// password. Only a hash of the token is stored.
+type PasswordResetToken struct {
+	ID        uuid.UUID  `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	UserID    uuid.UUID  `gorm:"column:user_id;type:uuid;not null"`
+	TokenHash string     `gorm:"column:token_hash;type:varchar(64);unique;not null"`
+	ExpiresAt time.Time  `gorm:"column:expires_at;not null"`
+	UsedAt    *time.Time `gorm:"column:used_at"`
+	CreatedAt time.Time  `gorm:"column:created_at;default:now()"`
+}
+
+func (PasswordResetToken) TableName() string {
+	return "password_reset_token"
+}
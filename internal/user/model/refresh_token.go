@@ -0,0 +1,34 @@
+package usermodel
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshToken is the server-side record of an issued refresh token. Only a
+// hash of the token is stored. Every login starts a new family, and each
+// rotation adds a token to it, so reusing an already rotated token can revoke
+// everything issued from the same login.
+type RefreshToken struct {
+	ID         uuid.UUID  `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	UserID     uuid.UUID  `gorm:"column:user_id;type:uuid;not null"`
+	FamilyID   uuid.UUID  `gorm:"column:family_id;type:uuid;not null"`
+	TokenHash  string     `gorm:"column:token_hash;type:varchar(64);unique;not null"`
+	ExpiresAt  time.Time  `gorm:"column:expires_at;not null"`
+	ReplacedBy *uuid.UUID `gorm:"column:replaced_by;type:uuid"`
+	RevokedAt  *time.Time `gorm:"column:revoked_at"`
+	CreatedAt  time.Time  `gorm:"column:created_at;default:now()"`
+}
+
+func (RefreshToken) TableName() string {
+	return "refresh_token"
+}
+
+// TokenPair is the access and refresh token handed out at login and on refresh.
+type TokenPair struct {
+	AccessToken      string
+	RefreshToken     string
+	ExpiresIn        int64 // Unix time at which the access token expires
+	RefreshExpiresAt time.Time
+}
@@ -0,0 +1,47 @@
+package usermodel
+
+// Permission names an action on the admin API that some roles may perform.
+type Permission string
+
+const (
+	PermissionReadUsers    Permission = "users:read"    // List users and their sessions
+	PermissionManageUsers  Permission = "users:manage"  // Change roles, suspend users, force password resets and revoke sessions
+	PermissionReadUsage    Permission = "usage:read"    // View a user's model usage
+	PermissionReadFeedback Permission = "feedback:read" // View and export message feedback
+	PermissionReadAuditLog Permission = "audit:read"    // Search the security audit log
+)
+
+// Roles lists every role a user can have.
+var Roles = []Role{UserRole, SupportRole, AdminRole}
+
+// rolePermissions maps each role to the permissions it grants. Regular users
+// have none.
+var rolePermissions = map[Role][]Permission{
+	SupportRole: {PermissionReadUsers, PermissionReadUsage, PermissionReadFeedback},
+	AdminRole:   {PermissionReadUsers, PermissionManageUsers, PermissionReadUsage, PermissionReadFeedback, PermissionReadAuditLog},
+}
+
+// Valid reports whether the role is one of the known roles.
+func (r Role) Valid() bool {
+	for _, role := range Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// Can reports whether the role grants the permission.
+func (r Role) Can(permission Permission) bool {
+	for _, p := range rolePermissions[r] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// Privileged reports whether the role grants any permission on the admin API.
+func (r Role) Privileged() bool {
+	return len(rolePermissions[r]) > 0
+}
@@ -0,0 +1,59 @@
+package usermodel
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session is one login of a user on a device. Its ID is the family ID of the
+// refresh tokens issued for that login, so revoking the session revokes them.
+type Session struct {
+	ID         uuid.UUID  `gorm:"primaryKey;type:uuid"`
+	UserID     uuid.UUID  `gorm:"column:user_id;type:uuid;not null"`
+	Device     string     `gorm:"column:device;type:varchar(100);not null"`
+	UserAgent  string     `gorm:"column:user_agent;type:varchar(512);not null"`
+	IPAddress  string     `gorm:"column:ip_address;type:varchar(45);not null"`
+	MFA        bool       `gorm:"column:mfa;not null;default:false"` // Opened with a second factor
+	CreatedAt  time.Time  `gorm:"column:created_at;default:now()"`
+	LastUsedAt time.Time  `gorm:"column:last_used_at;not null"`
+	ExpiresAt  time.Time  `gorm:"column:expires_at;not null"`
+	RevokedAt  *time.Time `gorm:"column:revoked_at"`
+}
+
+func (Session) TableName() string {
+	return "user_session"
+}
+
+// ClientInfo describes the client a request came from.
+type ClientInfo struct {
+	UserAgent string
+	IPAddress string
+}
+
+// SessionResponse is the API representation of a session.
+type SessionResponse struct {
+	ID         uuid.UUID `json:"id"`
+	Device     string    `json:"device"`
+	UserAgent  string    `json:"userAgent"`
+	IPAddress  string    `json:"ipAddress"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastUsedAt time.Time `json:"lastUsedAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+	Current    bool      `json:"current"`
+}
+
+// ToResponse converts a Session to its API representation. Current marks the
+// session the request was made from.
+func (s *Session) ToResponse(current bool) SessionResponse {
+	return SessionResponse{
+		ID:         s.ID,
+		Device:     s.Device,
+		UserAgent:  s.UserAgent,
+		IPAddress:  s.IPAddress,
+		CreatedAt:  s.CreatedAt,
+		LastUsedAt: s.LastUsedAt,
+		ExpiresAt:  s.ExpiresAt,
+		Current:    current,
+	}
+}
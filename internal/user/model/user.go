@@ -10,23 +10,26 @@ import (
 type Role string
 
 const (
-	UserRole  Role = "user"
-	AdminRole Role = "admin"
+	UserRole    Role = "user"
+	SupportRole Role = "support"
+	AdminRole   Role = "admin"
 )
 
 // User represents the user entity as stored in the database.
 type User struct {
-	ID            uuid.UUID  `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
-	FullName      string     `gorm:"column:full_name;type:varchar(100);not null"`
-	Email         string     `gorm:"column:email;type:varchar(50);unique;not null"`
-	PasswordHash  string     `gorm:"column:password_hash;type:varchar(255);not null"`
-	Salt          string     `gorm:"column:salt;type:varchar(255);not null"`
-	Role          Role       `gorm:"column:role;type:varchar(10);default:user"`
-	EmailVerified bool       `gorm:"column:email_verified;default:false"`
-	LastLogin     *time.Time `gorm:"column:last_login"`
-	DeletedAt     *time.Time `gorm:"index"`
-	CreatedAt     time.Time  `gorm:"column:created_at;default:now()"`
-	UpdatedAt     time.Time  `gorm:"column:updated_at;default:now()"`
+	ID                 uuid.UUID  `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	FullName           string     `gorm:"column:full_name;type:varchar(100);not null"`
+	Email              string     `gorm:"column:email;type:varchar(50);unique;not null"`
+	PasswordHash       string     `gorm:"column:password_hash;type:varchar(255);not null"`
+	Salt               string     `gorm:"column:salt;type:varchar(255);not null"`
+	Role               Role       `gorm:"column:role;type:varchar(10);default:user"`
+	EmailVerified      bool       `gorm:"column:email_verified;default:false"`
+	VerificationSentAt *time.Time `gorm:"column:verification_sent_at"`
+	LastLogin          *time.Time `gorm:"column:last_login"`
+	SuspendedAt        *time.Time `gorm:"column:suspended_at"`
+	DeletedAt          *time.Time `gorm:"index"`
+	CreatedAt          time.Time  `gorm:"column:created_at;default:now()"`
+	UpdatedAt          time.Time  `gorm:"column:updated_at;default:now()"`
 }
 
 func (User) TableName() string {
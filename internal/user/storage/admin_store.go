@@ -0,0 +1,48 @@
+package userstorage
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/khoaphungnguyen/go-openai/internal/common"
+	modeluser "github.com/khoaphungnguyen/go-openai/internal/user/model"
+)
+
+// SearchUsers returns a page of the users matching the filter, newest first,
+// along with the number of matching users.
+func (store *userStore) SearchUsers(filter modeluser.UserFilter) ([]modeluser.User, int64, error) {
+	query := store.db.Model(&modeluser.User{})
+	if filter.Query != "" {
+		pattern := "%" + common.EscapeLike(filter.Query) + "%"
+		query = query.Where("(full_name ILIKE ? OR email ILIKE ?)", pattern, pattern)
+	}
+	if filter.Role != "" {
+		query = query.Where("role = ?", filter.Role)
+	}
+	switch filter.Status {
+	case modeluser.StatusActive:
+		query = query.Where("deleted_at IS NULL AND suspended_at IS NULL")
+	case modeluser.StatusSuspended:
+		query = query.Where("deleted_at IS NULL AND suspended_at IS NOT NULL")
+	case modeluser.StatusDeleted:
+		query = query.Where("deleted_at IS NOT NULL")
+	}
+
+	total, page, err := common.Paginate(query, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	var users []modeluser.User
+	err = page.Order("created_at DESC").Find(&users).Error
+	return users, total, err
+}
+
+// UpdateRole changes the role of a user.
+func (store *userStore) UpdateRole(userID uuid.UUID, role modeluser.Role) error {
+	return store.db.Model(&modeluser.User{}).Where("id = ?", userID).Update("role", role).Error
+}
+
+// SetSuspended suspends a user at the given time, or lifts the suspension when it is nil.
+func (store *userStore) SetSuspended(userID uuid.UUID, suspendedAt *time.Time) error {
+	return store.db.Model(&modeluser.User{}).Where("id = ?", userID).Update("suspended_at", suspendedAt).Error
+}
@@ -0,0 +1,74 @@
+package userstorage
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	modeluser "github.com/khoaphungnguyen/go-openai/internal/user/model"
+	"gorm.io/gorm"
+)
+
+// ErrAPIKeyNotFound is the error returned when an API key cannot be found.
+var ErrAPIKeyNotFound = errors.New("api key not found")
+
+// CreateAPIKey saves a new API key.
+func (store *userStore) CreateAPIKey(key *modeluser.APIKey) error {
+	return store.db.Create(key).Error
+}
+
+// GetAPIKeyByHash finds an API key by the hash of its value, including revoked and expired ones.
+func (store *userStore) GetAPIKeyByHash(keyHash string) (*modeluser.APIKey, error) {
+	var key modeluser.APIKey
+	result := store.db.Where("key_hash = ?", keyHash).First(&key)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, ErrAPIKeyNotFound
+	}
+	return &key, result.Error
+}
+
+// GetActiveAPIKeys returns the user's API keys that are neither revoked nor
+// expired, newest first.
+func (store *userStore) GetActiveAPIKeys(userID uuid.UUID) ([]modeluser.APIKey, error) {
+	var keys []modeluser.APIKey
+	err := store.db.Where("user_id = ? AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", userID, time.Now()).
+		Order("created_at DESC").
+		Find(&keys).Error
+	return keys, err
+}
+
+// RevokeAPIKey revokes an API key owned by the user.
+func (store *userStore) RevokeAPIKey(keyID, userID uuid.UUID) error {
+	result := store.db.Model(&modeluser.APIKey{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", keyID, userID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+// RevokeUserAPIKeys revokes every API key of a user.
+func (store *userStore) RevokeUserAPIKeys(userID uuid.UUID) error {
+	return store.db.Model(&modeluser.APIKey{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// TouchAPIKey records that an API key was used, unless that was already
+// recorded after notBefore, so busy keys do not write on every request.
+func (store *userStore) TouchAPIKey(keyID uuid.UUID, notBefore time.Time) error {
+	return store.db.Model(&modeluser.APIKey{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", keyID, notBefore).
+		Update("last_used_at", time.Now()).Error
+}
+
+// DeleteAPIKeysExpiredBefore permanently removes API keys that expired or were
+// revoked before the cutoff.
+func (store *userStore) DeleteAPIKeysExpiredBefore(cutoff time.Time) (int64, error) {
+	result := store.db.Where("expires_at < ? OR revoked_at < ?", cutoff, cutoff).Delete(&modeluser.APIKey{})
+	return result.RowsAffected, result.Error
+}
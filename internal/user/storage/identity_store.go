@@ -0,0 +1,59 @@
+package userstorage
+
+import (
+	"errors"
+	"time"
+
+	modeluser "github.com/khoaphungnguyen/go-openai/internal/user/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrIdentityNotFound is the error returned when no user is linked to an external account.
+	ErrIdentityNotFound = errors.New("identity not found")
+	// ErrLoginStateNotFound is the error returned when no pending external login matches a state.
+	ErrLoginStateNotFound = errors.New("login state not found")
+)
+
+// GetIdentity finds the link to an account at an identity provider.
+func (store *userStore) GetIdentity(provider, subject string) (*modeluser.UserIdentity, error) {
+	var identity modeluser.UserIdentity
+	result := store.db.Where("provider = ? AND subject = ?", provider, subject).First(&identity)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, ErrIdentityNotFound
+	}
+	return &identity, result.Error
+}
+
+// CreateIdentity links a user to an account at an identity provider.
+func (store *userStore) CreateIdentity(identity *modeluser.UserIdentity) error {
+	return store.db.Create(identity).Error
+}
+
+// CreateLoginState saves a pending external login.
+func (store *userStore) CreateLoginState(state *modeluser.OIDCLoginState) error {
+	return store.db.Create(state).Error
+}
+
+// ConsumeLoginState removes a pending external login and returns it, so each
+// state can complete at most one login.
+func (store *userStore) ConsumeLoginState(stateHash string) (*modeluser.OIDCLoginState, error) {
+	var states []modeluser.OIDCLoginState
+	result := store.db.Clauses(clause.Returning{}).
+		Where("state_hash = ?", stateHash).
+		Delete(&states)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if len(states) == 0 {
+		return nil, ErrLoginStateNotFound
+	}
+	return &states[0], nil
+}
+
+// DeleteLoginStatesExpiredBefore permanently removes pending external logins that expired before the cutoff.
+func (store *userStore) DeleteLoginStatesExpiredBefore(cutoff time.Time) (int64, error) {
+	result := store.db.Where("expires_at < ?", cutoff).Delete(&modeluser.OIDCLoginState{})
+	return result.RowsAffected, result.Error
+}
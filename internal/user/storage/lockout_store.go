@@ -0,0 +1,122 @@
+package userstorage
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/khoaphungnguyen/go-openai/internal/common"
+	modeluser "github.com/khoaphungnguyen/go-openai/internal/user/model"
+	"gorm.io/gorm"
+)
+
+// ErrLockoutNotFound is the error returned when a lockout cannot be found or is no longer active.
+var ErrLockoutNotFound = errors.New("lockout not found")
+
+// RecordFailedLogin saves a failed login attempt.
+func (store *userStore) RecordFailedLogin(attempt *modeluser.LoginAttempt) error {
+	return store.db.Create(attempt).Error
+}
+
+// CountFailedLogins counts the failed logins for an email since the given time.
+func (store *userStore) CountFailedLogins(email string, since time.Time) (modeluser.AttemptCount, error) {
+	return store.countFailedLogins(store.db.Where("email = ? AND created_at >= ?", email, since))
+}
+
+// CountFailedLoginsFromIP counts the failed logins from an IP address since the given time.
+func (store *userStore) CountFailedLoginsFromIP(ipAddress string, since time.Time) (modeluser.AttemptCount, error) {
+	return store.countFailedLogins(store.db.Where("ip_address = ? AND created_at >= ?", ipAddress, since))
+}
+
+func (store *userStore) countFailedLogins(query *gorm.DB) (modeluser.AttemptCount, error) {
+	var count modeluser.AttemptCount
+	err := query.Model(&modeluser.LoginAttempt{}).
+		Select("COUNT(*) AS count, MAX(created_at) AS last_at").
+		Scan(&count).Error
+	return count, err
+}
+
+// ClearFailedLogins forgets the failed logins for an email.
+func (store *userStore) ClearFailedLogins(email string) error {
+	return store.db.Where("email = ?", email).Delete(&modeluser.LoginAttempt{}).Error
+}
+
+// ClearFailedLoginsFromIP forgets the failed logins from an IP address.
+func (store *userStore) ClearFailedLoginsFromIP(ipAddress string) error {
+	return store.db.Where("ip_address = ?", ipAddress).Delete(&modeluser.LoginAttempt{}).Error
+}
+
+// DeleteLoginAttemptsBefore permanently removes failed logins made before the cutoff.
+func (store *userStore) DeleteLoginAttemptsBefore(cutoff time.Time) (int64, error) {
+	result := store.db.Where("created_at < ?", cutoff).Delete(&modeluser.LoginAttempt{})
+	return result.RowsAffected, result.Error
+}
+
+// CreateLockout saves a new lockout.
+func (store *userStore) CreateLockout(lockout *modeluser.LoginLockout) error {
+	return store.db.Create(lockout).Error
+}
+
+// GetActiveLockout finds the lockout that blocks logins for the email or from
+// the IP address at the given time, whichever lasts longest.
+func (store *userStore) GetActiveLockout(email, ipAddress string, now time.Time) (*modeluser.LoginLockout, error) {
+	var lockout modeluser.LoginLockout
+	result := store.db.
+		Where("(email = ? OR (email = '' AND ip_address = ?)) AND unlocked_at IS NULL AND locked_until > ?", email, ipAddress, now).
+		Order("locked_until DESC").
+		First(&lockout)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, ErrLockoutNotFound
+	}
+	return &lockout, result.Error
+}
+
+// GetLockout finds a lockout by ID.
+func (store *userStore) GetLockout(id uuid.UUID) (*modeluser.LoginLockout, error) {
+	var lockout modeluser.LoginLockout
+	result := store.db.Where("id = ?", id).First(&lockout)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, ErrLockoutNotFound
+	}
+	return &lockout, result.Error
+}
+
+// SearchLockouts returns a page of the lockouts matching the filter, newest
+// first, along with the number of matching lockouts.
+func (store *userStore) SearchLockouts(filter modeluser.LockoutFilter) ([]modeluser.LoginLockout, int64, error) {
+	query := store.db.Model(&modeluser.LoginLockout{})
+	if filter.UserID != nil {
+		query = query.Where("user_id = ?", *filter.UserID)
+	}
+	if filter.Email != "" {
+		query = query.Where("email = ?", filter.Email)
+	}
+	if filter.IPAddress != "" {
+		query = query.Where("ip_address = ?", filter.IPAddress)
+	}
+	if filter.ActiveOnly {
+		query = query.Where("unlocked_at IS NULL AND locked_until > ?", time.Now())
+	}
+
+	total, page, err := common.Paginate(query, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	var lockouts []modeluser.LoginLockout
+	err = page.Order("created_at DESC").Find(&lockouts).Error
+	return lockouts, total, err
+}
+
+// Unlock ends an active lockout early on behalf of an administrator.
+func (store *userStore) Unlock(id, unlockedBy uuid.UUID) error {
+	result := store.db.Model(&modeluser.LoginLockout{}).
+		Where("id = ? AND unlocked_at IS NULL AND locked_until > ?", id, time.Now()).
+		Updates(map[string]interface{}{"unlocked_at": time.Now(), "unlocked_by": unlockedBy})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrLockoutNotFound
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package userstorage
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	modeluser "github.com/khoaphungnguyen/go-openai/internal/user/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrMFANotFound is the error returned when a user has not started enrolling a second factor.
+var ErrMFANotFound = errors.New("mfa not found")
+
+// GetMFA finds the TOTP settings of a user.
+func (store *userStore) GetMFA(userID uuid.UUID) (*modeluser.UserMFA, error) {
+	var mfa modeluser.UserMFA
+	result := store.db.Where("user_id = ?", userID).First(&mfa)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, ErrMFANotFound
+	}
+	return &mfa, result.Error
+}
+
+// SaveMFA stores the TOTP settings of a user, replacing any earlier ones.
+func (store *userStore) SaveMFA(mfa *modeluser.UserMFA) error {
+	return store.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"secret", "confirmed_at", "last_used_step", "created_at"}),
+	}).Create(mfa).Error
+}
+
+// ConfirmMFA turns on a pending enrollment, recording the step of the code that
+// confirmed it, and replaces the user's recovery codes. It reports false when
+// there is no pending enrollment.
+func (store *userStore) ConfirmMFA(userID uuid.UUID, step int64, codeHashes []string) (bool, error) {
+	confirmed := false
+	err := store.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&modeluser.UserMFA{}).
+			Where("user_id = ? AND confirmed_at IS NULL", userID).
+			Updates(map[string]interface{}{"confirmed_at": time.Now(), "last_used_step": step})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		if err := replaceRecoveryCodes(tx, userID, codeHashes); err != nil {
+			return err
+		}
+		confirmed = true
+		return nil
+	})
+	return confirmed, err
+}
+
+// UseTOTPStep records that the code of a time step was accepted. It reports
+// false when a code of that step or a later one was already accepted.
+func (store *userStore) UseTOTPStep(userID uuid.UUID, step int64) (bool, error) {
+	result := store.db.Model(&modeluser.UserMFA{}).
+		Where("user_id = ? AND last_used_step < ?", userID, step).
+		Update("last_used_step", step)
+	return result.RowsAffected > 0, result.Error
+}
+
+// ReplaceRecoveryCodes deletes the user's recovery codes and saves new ones.
+func (store *userStore) ReplaceRecoveryCodes(userID uuid.UUID, codeHashes []string) error {
+	return store.db.Transaction(func(tx *gorm.DB) error {
+		return replaceRecoveryCodes(tx, userID, codeHashes)
+	})
+}
+
+// UseRecoveryCode marks an unused recovery code as used. It reports false when
+// the user has no such unused code.
+func (store *userStore) UseRecoveryCode(userID uuid.UUID, codeHash string) (bool, error) {
+	result := store.db.Model(&modeluser.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	return result.RowsAffected > 0, result.Error
+}
+
+// CountRecoveryCodes counts the user's unused recovery codes.
+func (store *userStore) CountRecoveryCodes(userID uuid.UUID) (int64, error) {
+	var count int64
+	err := store.db.Model(&modeluser.RecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+// DeleteMFA removes the TOTP settings and recovery codes of a user.
+func (store *userStore) DeleteMFA(userID uuid.UUID) error {
+	return store.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&modeluser.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&modeluser.UserMFA{}).Error
+	})
+}
+
+// replaceRecoveryCodes swaps the user's recovery codes within a transaction.
+func replaceRecoveryCodes(tx *gorm.DB, userID uuid.UUID, codeHashes []string) error {
+	if err := tx.Where("user_id = ?", userID).Delete(&modeluser.RecoveryCode{}).Error; err != nil {
+		return err
+	}
+	codes := make([]modeluser.RecoveryCode, 0, len(codeHashes))
+	for _, hash := range codeHashes {
+		codes = append(codes, modeluser.RecoveryCode{UserID: userID, CodeHash: hash})
+	}
+	if len(codes) == 0 {
+		return nil
+	}
+	return tx.Create(&codes).Error
+}
@@ -0,0 +1,73 @@
+package userstorage
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	modeluser "github.com/khoaphungnguyen/go-openai/internal/user/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrPasswordResetTokenNotFound is the error returned when no usable password reset token matches a hash.
+var ErrPasswordResetTokenNotFound = errors.New("password reset token not found")
+
+// CreatePasswordResetToken saves a newly issued password reset token.
+func (store *userStore) CreatePasswordResetToken(token *modeluser.PasswordResetToken) error {
+	return store.db.Create(token).Error
+}
+
+// HasRecentPasswordReset reports whether a password reset token was issued to
+// the user at or after since.
+func (store *userStore) HasRecentPasswordReset(userID uuid.UUID, since time.Time) (bool, error) {
+	var count int64
+	err := store.db.Model(&modeluser.PasswordResetToken{}).
+		Where("user_id = ? AND created_at >= ?", userID, since).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// ConsumePasswordResetToken marks an unused, unexpired token as used and
+// returns it. A token can only be consumed once.
+func (store *userStore) ConsumePasswordResetToken(tokenHash string) (*modeluser.PasswordResetToken, error) {
+	var token modeluser.PasswordResetToken
+	now := time.Now()
+	result := store.db.Model(&token).Clauses(clause.Returning{}).
+		Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", tokenHash, now).
+		Update("used_at", now)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrPasswordResetTokenNotFound
+	}
+	return &token, nil
+}
+
+// UpdatePassword replaces the user's password and invalidates every password
+// reset token still outstanding for them.
+func (store *userStore) UpdatePassword(userID uuid.UUID, passwordHash, salt string) error {
+	return store.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&modeluser.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"password_hash": passwordHash,
+			"salt":          salt,
+			"updated_at":    time.Now(),
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrUserNotFound
+		}
+		return tx.Model(&modeluser.PasswordResetToken{}).
+			Where("user_id = ? AND used_at IS NULL", userID).
+			Update("used_at", time.Now()).Error
+	})
+}
+
+// DeletePasswordResetTokensExpiredBefore permanently removes password reset tokens that expired before the cutoff.
+func (store *userStore) DeletePasswordResetTokensExpiredBefore(cutoff time.Time) (int64, error) {
+	result := store.db.Where("expires_at < ?", cutoff).Delete(&modeluser.PasswordResetToken{})
+	return result.RowsAffected, result.Error
+}
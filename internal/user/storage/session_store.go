@@ -0,0 +1,86 @@
+package userstorage
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	modeluser "github.com/khoaphungnguyen/go-openai/internal/user/model"
+	"gorm.io/gorm"
+)
+
+// ErrSessionNotFound is the error returned when a session cannot be found.
+var ErrSessionNotFound = errors.New("session not found")
+
+// CreateSession saves a new session together with its first refresh token.
+func (store *userStore) CreateSession(session *modeluser.Session, token *modeluser.RefreshToken) error {
+	return store.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(session).Error; err != nil {
+			return err
+		}
+		return tx.Create(token).Error
+	})
+}
+
+// GetSession finds a session by ID, including revoked and expired ones.
+func (store *userStore) GetSession(sessionID uuid.UUID) (*modeluser.Session, error) {
+	var session modeluser.Session
+	result := store.db.Where("id = ?", sessionID).First(&session)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, ErrSessionNotFound
+	}
+	return &session, result.Error
+}
+
+// GetActiveSessions returns the user's sessions that are neither revoked nor
+// expired, most recently used first.
+func (store *userStore) GetActiveSessions(userID uuid.UUID) ([]modeluser.Session, error) {
+	var sessions []modeluser.Session
+	err := store.db.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_used_at DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// RevokeSession revokes a session and every refresh token issued for it.
+func (store *userStore) RevokeSession(sessionID uuid.UUID) error {
+	now := time.Now()
+	return store.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&modeluser.Session{}).
+			Where("id = ? AND revoked_at IS NULL", sessionID).
+			Update("revoked_at", now).Error; err != nil {
+			return err
+		}
+		return tx.Model(&modeluser.RefreshToken{}).
+			Where("family_id = ? AND revoked_at IS NULL", sessionID).
+			Update("revoked_at", now).Error
+	})
+}
+
+// RevokeUserSessions revokes every session and refresh token of a user.
+func (store *userStore) RevokeUserSessions(userID uuid.UUID) error {
+	return store.RevokeOtherSessions(userID, uuid.Nil)
+}
+
+// RevokeOtherSessions revokes every session and refresh token of a user except
+// those of the session to keep.
+func (store *userStore) RevokeOtherSessions(userID, keepSessionID uuid.UUID) error {
+	now := time.Now()
+	return store.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&modeluser.Session{}).
+			Where("user_id = ? AND id <> ? AND revoked_at IS NULL", userID, keepSessionID).
+			Update("revoked_at", now).Error; err != nil {
+			return err
+		}
+		return tx.Model(&modeluser.RefreshToken{}).
+			Where("user_id = ? AND family_id <> ? AND revoked_at IS NULL", userID, keepSessionID).
+			Update("revoked_at", now).Error
+	})
+}
+
+// DeleteSessionsExpiredBefore permanently removes sessions that expired before
+// the cutoff, along with their refresh tokens.
+func (store *userStore) DeleteSessionsExpiredBefore(cutoff time.Time) (int64, error) {
+	result := store.db.Where("expires_at < ?", cutoff).Delete(&modeluser.Session{})
+	return result.RowsAffected, result.Error
+}
@@ -25,6 +25,66 @@ type UserStore interface {
 	UpdateOmitFields(user *modeluser.User, omitFields ...string) error
 	SoftDelete(id uuid.UUID) error
 	IsSoftDeleted(userID uuid.UUID) (bool, error)
+
+	SearchUsers(filter modeluser.UserFilter) ([]modeluser.User, int64, error)
+	UpdateRole(userID uuid.UUID, role modeluser.Role) error
+	SetSuspended(userID uuid.UUID, suspendedAt *time.Time) error
+
+	MarkEmailVerified(userID uuid.UUID, email string) (bool, error)
+	ClaimVerificationSend(userID uuid.UUID, notBefore time.Time) (bool, error)
+
+	CreatePasswordResetToken(token *modeluser.PasswordResetToken) error
+	HasRecentPasswordReset(userID uuid.UUID, since time.Time) (bool, error)
+	ConsumePasswordResetToken(tokenHash string) (*modeluser.PasswordResetToken, error)
+	UpdatePassword(userID uuid.UUID, passwordHash, salt string) error
+	DeletePasswordResetTokensExpiredBefore(cutoff time.Time) (int64, error)
+
+	GetMFA(userID uuid.UUID) (*modeluser.UserMFA, error)
+	SaveMFA(mfa *modeluser.UserMFA) error
+	ConfirmMFA(userID uuid.UUID, step int64, codeHashes []string) (bool, error)
+	UseTOTPStep(userID uuid.UUID, step int64) (bool, error)
+	ReplaceRecoveryCodes(userID uuid.UUID, codeHashes []string) error
+	UseRecoveryCode(userID uuid.UUID, codeHash string) (bool, error)
+	CountRecoveryCodes(userID uuid.UUID) (int64, error)
+	DeleteMFA(userID uuid.UUID) error
+
+	RecordFailedLogin(attempt *modeluser.LoginAttempt) error
+	CountFailedLogins(email string, since time.Time) (modeluser.AttemptCount, error)
+	CountFailedLoginsFromIP(ipAddress string, since time.Time) (modeluser.AttemptCount, error)
+	ClearFailedLogins(email string) error
+	ClearFailedLoginsFromIP(ipAddress string) error
+	DeleteLoginAttemptsBefore(cutoff time.Time) (int64, error)
+	CreateLockout(lockout *modeluser.LoginLockout) error
+	GetActiveLockout(email, ipAddress string, now time.Time) (*modeluser.LoginLockout, error)
+	GetLockout(id uuid.UUID) (*modeluser.LoginLockout, error)
+	SearchLockouts(filter modeluser.LockoutFilter) ([]modeluser.LoginLockout, int64, error)
+	Unlock(id, unlockedBy uuid.UUID) error
+
+	GetIdentity(provider, subject string) (*modeluser.UserIdentity, error)
+	CreateIdentity(identity *modeluser.UserIdentity) error
+	CreateLoginState(state *modeluser.OIDCLoginState) error
+	ConsumeLoginState(stateHash string) (*modeluser.OIDCLoginState, error)
+	DeleteLoginStatesExpiredBefore(cutoff time.Time) (int64, error)
+
+	CreateAPIKey(key *modeluser.APIKey) error
+	GetAPIKeyByHash(keyHash string) (*modeluser.APIKey, error)
+	GetActiveAPIKeys(userID uuid.UUID) ([]modeluser.APIKey, error)
+	RevokeAPIKey(keyID, userID uuid.UUID) error
+	RevokeUserAPIKeys(userID uuid.UUID) error
+	TouchAPIKey(keyID uuid.UUID, notBefore time.Time) error
+	DeleteAPIKeysExpiredBefore(cutoff time.Time) (int64, error)
+
+	GetRefreshTokenByHash(tokenHash string) (*modeluser.RefreshToken, error)
+	RotateRefreshToken(currentID uuid.UUID, next *modeluser.RefreshToken, client modeluser.ClientInfo) (bool, error)
+	DeleteRefreshTokensExpiredBefore(cutoff time.Time) (int64, error)
+
+	CreateSession(session *modeluser.Session, token *modeluser.RefreshToken) error
+	GetSession(sessionID uuid.UUID) (*modeluser.Session, error)
+	GetActiveSessions(userID uuid.UUID) ([]modeluser.Session, error)
+	RevokeSession(sessionID uuid.UUID) error
+	RevokeUserSessions(userID uuid.UUID) error
+	RevokeOtherSessions(userID, keepSessionID uuid.UUID) error
+	DeleteSessionsExpiredBefore(cutoff time.Time) (int64, error)
 }
 
 // userStore encapsulates the data storage logic for user operations.
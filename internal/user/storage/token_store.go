@@ -0,0 +1,69 @@
+package userstorage
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	modeluser "github.com/khoaphungnguyen/go-openai/internal/user/model"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrRefreshTokenNotFound is the error returned when no refresh token matches a hash.
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+	// errTokenAlreadyRotated aborts a rotation that lost to an earlier one.
+	errTokenAlreadyRotated = errors.New("refresh token already rotated")
+)
+
+// GetRefreshTokenByHash finds a refresh token by the hash of its value.
+func (store *userStore) GetRefreshTokenByHash(tokenHash string) (*modeluser.RefreshToken, error) {
+	var token modeluser.RefreshToken
+	result := store.db.Where("token_hash = ?", tokenHash).First(&token)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, ErrRefreshTokenNotFound
+	}
+	return &token, result.Error
+}
+
+// RotateRefreshToken marks the current token as replaced by next, saves next and
+// records the use on the token's session. It reports false without saving
+// anything when the current token was already replaced or revoked, which
+// happens when the same token is presented twice.
+func (store *userStore) RotateRefreshToken(currentID uuid.UUID, next *modeluser.RefreshToken, client modeluser.ClientInfo) (bool, error) {
+	rotated := false
+	err := store.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(next).Error; err != nil {
+			return err
+		}
+		result := tx.Model(&modeluser.RefreshToken{}).
+			Where("id = ? AND replaced_by IS NULL AND revoked_at IS NULL", currentID).
+			Update("replaced_by", next.ID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			// Roll back the new token
+			return errTokenAlreadyRotated
+		}
+		if err := tx.Model(&modeluser.Session{}).Where("id = ?", next.FamilyID).Updates(map[string]interface{}{
+			"ip_address":   client.IPAddress,
+			"last_used_at": time.Now(),
+			"expires_at":   next.ExpiresAt,
+		}).Error; err != nil {
+			return err
+		}
+		rotated = true
+		return nil
+	})
+	if errors.Is(err, errTokenAlreadyRotated) {
+		return false, nil
+	}
+	return rotated, err
+}
+
+// DeleteRefreshTokensExpiredBefore permanently removes refresh tokens that expired before the cutoff.
+func (store *userStore) DeleteRefreshTokensExpiredBefore(cutoff time.Time) (int64, error) {
+	result := store.db.Where("expires_at < ?", cutoff).Delete(&modeluser.RefreshToken{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,27 @@
+package userstorage
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	modeluser "github.com/khoaphungnguyen/go-openai/internal/user/model"
+)
+
+// MarkEmailVerified marks the user's email as verified, provided it is still
+// the given address. It reports whether the user was found with that address.
+func (store *userStore) MarkEmailVerified(userID uuid.UUID, email string) (bool, error) {
+	result := store.db.Model(&modeluser.User{}).
+		Where("id = ? AND email = ? AND deleted_at IS NULL", userID, email).
+		Update("email_verified", true)
+	return result.RowsAffected > 0, result.Error
+}
+
+// ClaimVerificationSend records that a verification email is being sent to the
+// user, unless one was already sent at or after notBefore. It reports whether
+// the send was claimed, so concurrent requests cannot both send.
+func (store *userStore) ClaimVerificationSend(userID uuid.UUID, notBefore time.Time) (bool, error) {
+	result := store.db.Model(&modeluser.User{}).
+		Where("id = ? AND (verification_sent_at IS NULL OR verification_sent_at < ?)", userID, notBefore).
+		Update("verification_sent_at", time.Now())
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,137 @@
+package usertransport
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	auditmodel "github.com/khoaphungnguyen/go-openai/internal/audit/model"
+	audittransport "github.com/khoaphungnguyen/go-openai/internal/audit/transport"
+	businessuser "github.com/khoaphungnguyen/go-openai/internal/user/business"
+	modeluser "github.com/khoaphungnguyen/go-openai/internal/user/model"
+)
+
+type ChangeRolePayload struct {
+	Role modeluser.Role `json:"role" binding:"required"`
+}
+
+// ListUsers searches users by name or email, role and status, a page at a time. Admin only.
+func (h *UserHandler) ListUsers(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(businessuser.DefaultUserPageSize)))
+	if err != nil || limit <= 0 || limit > businessuser.MaxUserPageSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+		return
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offset"})
+		return
+	}
+
+	users, total, err := h.userService.SearchUsers(modeluser.UserFilter{
+		Query:  c.Query("q"),
+		Role:   modeluser.Role(c.Query("role")),
+		Status: modeluser.UserStatus(c.Query("status")),
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		respondWithAdminError(c, err, "Failed to retrieve users")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"total": total, "users": users})
+}
+
+// ChangeUserRole gives a user a new role. Admin only.
+func (h *UserHandler) ChangeUserRole(c *gin.Context) {
+	actorID, err := getUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	target, ok := h.parseTargetUser(c)
+	if !ok {
+		return
+	}
+	var payload ChangeRolePayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Role required"})
+		return
+	}
+
+	user, err := h.userService.ChangeRole(actorID, target.ID, payload.Role)
+	if err != nil {
+		respondWithAdminError(c, err, "Failed to change role")
+		return
+	}
+	if target.Role != user.Role {
+		h.auditService.Record(c.Request.Context(), audittransport.NewEntry(c, auditmodel.ActionRoleChanged, auditmodel.TargetUser, user.ID).
+			Set("from", target.Role).Set("to", user.Role))
+	}
+	c.JSON(http.StatusOK, user)
+}
+
+// SuspendUser stops a user from logging in. Admin only.
+func (h *UserHandler) SuspendUser(c *gin.Context) {
+	actorID, err := getUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	target, ok := h.parseTargetUser(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.userService.SuspendUser(actorID, target.ID)
+	if err != nil {
+		respondWithAdminError(c, err, "Failed to suspend user")
+		return
+	}
+	h.auditService.Record(c.Request.Context(), audittransport.NewEntry(c, auditmodel.ActionUserSuspended, auditmodel.TargetUser, user.ID))
+	c.JSON(http.StatusOK, user)
+}
+
+// UnsuspendUser lets a suspended user log in again. Admin only.
+func (h *UserHandler) UnsuspendUser(c *gin.Context) {
+	target, ok := h.parseTargetUser(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.userService.UnsuspendUser(target.ID)
+	if err != nil {
+		respondWithAdminError(c, err, "Failed to unsuspend user")
+		return
+	}
+	h.auditService.Record(c.Request.Context(), audittransport.NewEntry(c, auditmodel.ActionUserUnsuspended, auditmodel.TargetUser, user.ID))
+	c.JSON(http.StatusOK, user)
+}
+
+// ForcePasswordReset makes a user choose a new password through a mailed link. Admin only.
+func (h *UserHandler) ForcePasswordReset(c *gin.Context) {
+	user, ok := h.parseTargetUser(c)
+	if !ok {
+		return
+	}
+
+	if err := h.userService.ForcePasswordReset(c.Request.Context(), user.ID); err != nil {
+		respondWithAdminError(c, err, "Failed to reset password")
+		return
+	}
+	h.auditService.Record(c.Request.Context(), audittransport.NewEntry(c, auditmodel.ActionPasswordResetForced, auditmodel.TargetUser, user.ID))
+	c.JSON(http.StatusOK, gin.H{"message": "Password reset. The user was logged out and emailed a reset link."})
+}
+
+// respondWithAdminError maps errors of the admin user API to HTTP responses.
+func respondWithAdminError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, businessuser.ErrInvalidRole), errors.Is(err, businessuser.ErrInvalidUserStatus):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, businessuser.ErrOwnAccount):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
@@ -0,0 +1,92 @@
+package usertransport
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	auditmodel "github.com/khoaphungnguyen/go-openai/internal/audit/model"
+	audittransport "github.com/khoaphungnguyen/go-openai/internal/audit/transport"
+	businessuser "github.com/khoaphungnguyen/go-openai/internal/user/business"
+	modeluser "github.com/khoaphungnguyen/go-openai/internal/user/model"
+)
+
+type CreateAPIKeyPayload struct {
+	Name   string   `json:"name" binding:"required,max=100"`
+	Scopes []string `json:"scopes" binding:"required"`
+	// ExpiresInDays is optional; zero or omitted creates a key that never expires.
+	ExpiresInDays int `json:"expiresInDays" binding:"min=0,max=3650"`
+}
+
+// CreateAPIKey creates a personal API key. The key is only shown in this response.
+func (h *UserHandler) CreateAPIKey(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	var payload CreateAPIKeyPayload
+	if err := c.ShouldBindJSON(&payload); err != nil || strings.TrimSpace(payload.Name) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Name and scopes required"})
+		return
+	}
+
+	key, err := h.userService.CreateAPIKey(userID, payload.Name, payload.Scopes, time.Duration(payload.ExpiresInDays)*24*time.Hour)
+	switch {
+	case errors.Is(err, businessuser.ErrInvalidScope):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Scopes must be some of: " + strings.Join(modeluser.Scopes, ", ")})
+	case errors.Is(err, businessuser.ErrTooManyAPIKeys):
+		c.JSON(http.StatusConflict, gin.H{"error": "Revoke an API key before creating another"})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create API key"})
+	default:
+		h.auditService.Record(c.Request.Context(), audittransport.NewEntry(c, auditmodel.ActionAPIKeyCreated, auditmodel.TargetAPIKey, key.ID).
+			Set("name", key.Name).Set("scopes", key.Scopes))
+		c.JSON(http.StatusCreated, key)
+	}
+}
+
+// GetAPIKeys lists the current user's active API keys.
+func (h *UserHandler) GetAPIKeys(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	keys, err := h.userService.GetAPIKeys(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve API keys"})
+		return
+	}
+	c.JSON(http.StatusOK, keys)
+}
+
+// RevokeAPIKey revokes one of the current user's API keys.
+func (h *UserHandler) RevokeAPIKey(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	keyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid API key ID"})
+		return
+	}
+
+	err = h.userService.RevokeAPIKey(userID, keyID)
+	if errors.Is(err, businessuser.ErrAPIKeyNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "API key not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke API key"})
+		return
+	}
+	h.auditService.Record(c.Request.Context(), audittransport.NewEntry(c, auditmodel.ActionAPIKeyRevoked, auditmodel.TargetAPIKey, keyID))
+	c.JSON(http.StatusOK, gin.H{"message": "API key revoked successfully"})
+}
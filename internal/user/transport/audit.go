@@ -0,0 +1,12 @@
+package usertransport
+
+import auditmodel "github.com/khoaphungnguyen/go-openai/internal/audit/model"
+
+// asUser makes the target user of an entry its actor, for events of requests
+// the user made before being authenticated, such as logging in.
+func asUser(entry *auditmodel.AuditLog) *auditmodel.AuditLog {
+	if entry.ActorID == nil {
+		entry.ActorID = entry.TargetID
+	}
+	return entry
+}
@@ -8,7 +8,9 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
-	userauth "github.com/khoaphungnguyen/go-openai/internal/user/auth"
+	auditmodel "github.com/khoaphungnguyen/go-openai/internal/audit/model"
+	audittransport "github.com/khoaphungnguyen/go-openai/internal/audit/transport"
+	userbusiness "github.com/khoaphungnguyen/go-openai/internal/user/business"
 	modeluser "github.com/khoaphungnguyen/go-openai/internal/user/model"
 )
 
@@ -43,11 +45,19 @@ func (h *UserHandler) Signup(c *gin.Context) {
 	}
 
 	// Assuming the default role is 'user'. Modify based on your application logic.
-	if err := h.userService.CreateUser(payload.FullName, payload.Email, payload.Password, modeluser.UserRole); err != nil {
+	user, err := h.userService.CreateUser(payload.FullName, payload.Email, payload.Password, modeluser.UserRole)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create user"})
 		return
 	}
-	c.JSON(http.StatusCreated, gin.H{"message": "User created successfully"})
+	h.auditService.Record(c.Request.Context(), asUser(audittransport.NewEntry(c, auditmodel.ActionSignup, auditmodel.TargetUser, user.ID)).
+		Set("email", user.Email))
+
+	// The account works without verification; the link can be resent later.
+	if err := h.userService.SendVerificationEmail(c.Request.Context(), user); err != nil {
+		log.Println("Failed to send verification email:", err)
+	}
+	c.JSON(http.StatusCreated, gin.H{"message": "User created successfully. Check your email to verify your address."})
 }
 
 // / Login handles user login.
@@ -58,20 +68,71 @@ func (h *UserHandler) Login(c *gin.Context) {
 		return
 	}
 
-	// Verify user's password.
-	authenticated, err := h.userService.VerifyUserPassword(payload.Email, payload.Password)
-	if err != nil || !authenticated {
+	// Verify user's password. Repeated failures are delayed and then locked out.
+	user, err := h.userService.AuthenticatePassword(c.Request.Context(), payload.Email, payload.Password, clientInfo(c))
+	if respondWithLoginThrottled(c, err) {
+		h.auditService.Record(c.Request.Context(), audittransport.NewEntry(c, auditmodel.ActionLoginBlocked, "", uuid.Nil).
+			Set("email", payload.Email))
+		return
+	}
+	if errors.Is(err, userbusiness.ErrInvalidCredentials) {
+		h.auditService.Record(c.Request.Context(), audittransport.NewEntry(c, auditmodel.ActionLoginFailed, "", uuid.Nil).
+			Set("email", payload.Email))
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid email or password"})
 		return
 	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve user data"})
+		return
+	}
+
+	h.completeLogin(c, user)
+}
+
+// completeLogin finishes a login of an authenticated user, either by handing
+// out tokens or, when a second factor is enabled, an MFA challenge.
+func (h *UserHandler) completeLogin(c *gin.Context, user *modeluser.User) {
+	if user.SuspendedAt != nil {
+		h.auditService.Record(c.Request.Context(), asUser(audittransport.NewEntry(c, auditmodel.ActionLoginBlocked, auditmodel.TargetUser, user.ID)).
+			Set("reason", "suspended"))
+		c.JSON(http.StatusForbidden, gin.H{"error": "Account is suspended"})
+		return
+	}
 
-	// Retrieve user details for token generation.
-	user, err := h.userService.GetUserByEmail(payload.Email)
+	// A second factor is needed before any token is issued.
+	mfaEnabled, err := h.userService.IsMFAEnabled(user.ID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve user data"})
 		return
 	}
+	if mfaEnabled {
+		challenge, err := h.userService.StartMFAChallenge(user)
+		if err != nil {
+			log.Println("Error signing MFA challenge:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error signing token"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"mfaRequired": true,
+			"mfaToken":    challenge,
+			"expiresIn":   time.Now().Add(userbusiness.MFAChallengeDuration).Unix(),
+		})
+		return
+	}
 
+	// Issue the access token and start a new refresh token family.
+	tokens, err := h.userService.IssueTokens(user, clientInfo(c))
+	if err != nil {
+		log.Println("Error issuing tokens:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error signing token"})
+		return
+	}
+	h.respondWithLogin(c, user, tokens)
+}
+
+// respondWithLogin completes a successful login by recording it and handing
+// the tokens to the client.
+func (h *UserHandler) respondWithLogin(c *gin.Context, user *modeluser.User, tokens *modeluser.TokenPair) {
 	// Handle last login time.
 	// lastLoginStr := "Never" // Default message for first-time login.
 	// if user.LastLogin != nil {
@@ -80,46 +141,13 @@ func (h *UserHandler) Login(c *gin.Context) {
 
 	// Update last login time.
 	now := time.Now()
-	err = h.userService.UpdateLastLogin(user.ID, &now)
-	if err != nil {
+	if err := h.userService.UpdateLastLogin(user.ID, &now); err != nil {
 		log.Println("Failed to update last login time:", err)
 	}
-
-	// JWT token generation.
-	jwtWrapper := userauth.JwtWrapper{
-		SecretKey:              h.JWTKey,
-		Issuer:                 "AuthService",
-		AccessTokenExpiration:  userauth.DefaultAccessTokenDuration,
-		RefreshTokenExpiration: userauth.DefaultRefreshTokenDuration,
-	}
-	signedToken, err := jwtWrapper.GenerateToken(user.ID.String(), user.FullName)
-	if err != nil {
-		log.Println("Error signing token:", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error signing token"})
-		return
-	}
-
-	// Generate refresh token.
-	signedRefreshToken, err := jwtWrapper.RefreshToken(user.ID.String(), user.FullName)
-	if err != nil {
-		log.Println("Error signing refresh token:", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error signing refresh token"})
-		return
-	}
+	h.auditService.Record(c.Request.Context(), asUser(audittransport.NewEntry(c, auditmodel.ActionLogin, auditmodel.TargetUser, user.ID)))
 
 	// Set refresh token in an HTTP-only cookie.
-	http.SetCookie(c.Writer, &http.Cookie{
-		Name:     "refreshToken",
-		Value:    signedRefreshToken,
-		HttpOnly: true,
-		Path:     "/",
-		Secure:   false, // Set to true if using HTTPS
-		SameSite: http.SameSiteStrictMode,
-		MaxAge:   int(jwtWrapper.RefreshTokenExpiration.Seconds()),
-	})
-
-	// log.Println("Refresh token:", signedRefreshToken)
-	// log.Println("userId:", user.ID.String())
+	setRefreshCookie(c, tokens)
 
 	// Prepare and send the response.
 	response := gin.H{
@@ -127,87 +155,104 @@ func (h *UserHandler) Login(c *gin.Context) {
 		"name":         user.FullName,
 		"email":        user.Email,
 		"role":         user.Role,
-		"expiresIn":    time.Now().Add(time.Second * time.Duration(jwtWrapper.AccessTokenExpiration.Seconds())).Unix(),
-		"accessToken":  signedToken,
-		"refreshToken": signedRefreshToken,
+		"expiresIn":    tokens.ExpiresIn,
+		"accessToken":  tokens.AccessToken,
+		"refreshToken": tokens.RefreshToken,
 
 		//"lastLogin":   lastLoginStr,
 	}
 	c.JSON(http.StatusOK, response)
 }
 
+// refreshTokenCookie is the name of the cookie holding the refresh token.
+const refreshTokenCookie = "refreshToken"
+
 type RefreshTokenInput struct {
 	RefreshToken string `json:"refreshToken"`
 }
 
 // RenewAccessToken handles the renewal of the access token using the refresh token.
+// The refresh token is rotated: the response carries a new one and the presented
+// one stops working.
 func (h *UserHandler) RenewAccessToken(c *gin.Context) {
-	var input RefreshTokenInput
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-	refreshToken := input.RefreshToken
-	if refreshToken == "" {
+	refreshToken, ok := readRefreshToken(c)
+	if !ok {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Refresh token required"})
 		return
 	}
-	jwtWrapper := userauth.JwtWrapper{
-		SecretKey:             h.JWTKey,
-		Issuer:                "AuthService",
-		AccessTokenExpiration: userauth.DefaultAccessTokenDuration,
-	}
-	claims, err := jwtWrapper.ValidateToken(refreshToken)
-	if err != nil {
+
+	tokens, err := h.userService.RefreshTokens(refreshToken, clientInfo(c))
+	switch {
+	case errors.Is(err, userbusiness.ErrInvalidRefreshToken), errors.Is(err, userbusiness.ErrRefreshTokenReused):
+		clearRefreshCookie(c)
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
 		return
-	}
-	userID, err := uuid.Parse(claims.UserID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
-		return
-	}
-
-	// Check if the user's account is active
-	isSoftDeleted, err := h.userService.IsSoftDeleted(userID)
-	if err != nil || isSoftDeleted {
+	case errors.Is(err, userbusiness.ErrAccountInactive):
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Account is not active. Please restore to continue."})
 		return
-	}
-
-	newAccessToken, err := jwtWrapper.GenerateToken(claims.UserID, claims.FullName)
-	if err != nil {
-		log.Println("Error signing new token:", err)
+	case errors.Is(err, userbusiness.ErrAccountSuspended):
+		clearRefreshCookie(c)
+		c.JSON(http.StatusForbidden, gin.H{"error": "Account is suspended"})
+		return
+	case err != nil:
+		log.Println("Error refreshing tokens:", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error signing new token"})
 		return
 	}
+
+	setRefreshCookie(c, tokens)
 	c.JSON(http.StatusOK, gin.H{
-		"accessToken": newAccessToken,
-		"expiresIn":   time.Now().Add(time.Second * time.Duration(jwtWrapper.AccessTokenExpiration.Seconds())).Unix(),
+		"accessToken":  tokens.AccessToken,
+		"refreshToken": tokens.RefreshToken,
+		"expiresIn":    tokens.ExpiresIn,
 	})
 }
 
-// GetAllUsers handles retrieving all users.
-func (h *UserHandler) GetAllUsers(c *gin.Context) {
+// Logout ends the session of the presented refresh token. Access tokens
+// already handed out stay valid until they expire.
+func (h *UserHandler) Logout(c *gin.Context) {
+	refreshToken, ok := readRefreshToken(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Refresh token required"})
+		return
+	}
 
-	userID, err := getUserIDFromContext(c)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+	// Logging out with an unknown or expired token has nothing left to revoke
+	userID, err := h.userService.Logout(refreshToken)
+	if err != nil && !errors.Is(err, userbusiness.ErrInvalidRefreshToken) {
+		log.Println("Error logging out:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to log out"})
 		return
 	}
+	if err == nil {
+		h.auditService.Record(c.Request.Context(), asUser(audittransport.NewEntry(c, auditmodel.ActionLogout, auditmodel.TargetUser, userID)))
+	}
 
-	// Retrieve the current user data
-	user, err := h.userService.GetUserByUUID(userID)
+	clearRefreshCookie(c)
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
+}
+
+// LogoutEverywhere revokes every refresh token of the current user, ending
+// their sessions on all devices.
+func (h *UserHandler) LogoutEverywhere(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
 		return
 	}
 
-	if user.Role != modeluser.AdminRole {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "You are not authorized to perform this action"})
+	if err := h.userService.LogoutEverywhere(userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to log out"})
 		return
 	}
+	h.auditService.Record(c.Request.Context(), audittransport.NewEntry(c, auditmodel.ActionLogoutAll, auditmodel.TargetUser, userID))
 
+	clearRefreshCookie(c)
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out of all sessions"})
+}
+
+// GetAllUsers handles retrieving all users. Access is checked by RequirePermission.
+func (h *UserHandler) GetAllUsers(c *gin.Context) {
 	users, err := h.userService.GetAllUsers()
 
 	if err != nil {
@@ -260,11 +305,26 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 	}
 
 	// Update the user data with the payload
-	if err := h.userService.UpdateUser(userID, payload.FullName, payload.Email); err != nil {
+	updated, err := h.userService.UpdateUser(userID, payload.FullName, payload.Email)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user"})
 		return
 	}
 
+	entry := audittransport.NewEntry(c, auditmodel.ActionProfileUpdated, auditmodel.TargetUser, userID)
+	if updated.FullName != user.FullName {
+		entry.Set("fullNameChanged", true)
+	}
+
+	// A changed address has to be verified again
+	if updated.Email != user.Email {
+		entry.Set("oldEmail", user.Email).Set("newEmail", updated.Email)
+		if err := h.userService.SendVerificationEmail(c.Request.Context(), updated); err != nil {
+			log.Println("Failed to send verification email:", err)
+		}
+	}
+	h.auditService.Record(c.Request.Context(), entry)
+
 	c.JSON(http.StatusOK, gin.H{"message": "User updated successfully"})
 }
 
@@ -298,6 +358,7 @@ func (h *UserHandler) DeleteProfile(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete your account"})
 		return
 	}
+	h.auditService.Record(c.Request.Context(), audittransport.NewEntry(c, auditmodel.ActionProfileDeleted, auditmodel.TargetUser, userID))
 
 	c.JSON(http.StatusOK, gin.H{"message": "Your account is deleted successfully"})
 }
@@ -332,6 +393,7 @@ func (h *UserHandler) RestoreProfile(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore your account"})
 		return
 	}
+	h.auditService.Record(c.Request.Context(), audittransport.NewEntry(c, auditmodel.ActionProfileRestored, auditmodel.TargetUser, userID))
 
 	c.JSON(http.StatusOK, gin.H{"message": "Your account is restored successfully"})
 }
@@ -354,6 +416,53 @@ func (h *UserHandler) Profile(c *gin.Context) {
 	c.JSON(http.StatusOK, publicUser)
 }
 
+// readRefreshToken takes the refresh token from the request body, falling back
+// to the refresh token cookie set at login.
+func readRefreshToken(c *gin.Context) (string, bool) {
+	var input RefreshTokenInput
+	if err := c.ShouldBindJSON(&input); err == nil && input.RefreshToken != "" {
+		return input.RefreshToken, true
+	}
+	if cookie, err := c.Cookie(refreshTokenCookie); err == nil && cookie != "" {
+		return cookie, true
+	}
+	return "", false
+}
+
+// clientInfo describes the client that made the request.
+func clientInfo(c *gin.Context) modeluser.ClientInfo {
+	return modeluser.ClientInfo{
+		UserAgent: c.Request.UserAgent(),
+		IPAddress: c.ClientIP(),
+	}
+}
+
+// setRefreshCookie stores the refresh token in an HTTP-only cookie.
+func setRefreshCookie(c *gin.Context, tokens *modeluser.TokenPair) {
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     refreshTokenCookie,
+		Value:    tokens.RefreshToken,
+		HttpOnly: true,
+		Path:     "/",
+		Secure:   false, // Set to true if using HTTPS
+		SameSite: http.SameSiteStrictMode,
+		MaxAge:   int(time.Until(tokens.RefreshExpiresAt).Seconds()),
+	})
+}
+
+// clearRefreshCookie removes the refresh token cookie.
+func clearRefreshCookie(c *gin.Context) {
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     refreshTokenCookie,
+		Value:    "",
+		HttpOnly: true,
+		Path:     "/",
+		Secure:   false, // Set to true if using HTTPS
+		SameSite: http.SameSiteStrictMode,
+		MaxAge:   -1,
+	})
+}
+
 func getUserIDFromContext(c *gin.Context) (uuid.UUID, error) {
 	userIDStr, exists := c.Get("userID")
 	if !exists {
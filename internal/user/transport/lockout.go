@@ -0,0 +1,105 @@
+package usertransport
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	auditmodel "github.com/khoaphungnguyen/go-openai/internal/audit/model"
+	audittransport "github.com/khoaphungnguyen/go-openai/internal/audit/transport"
+	businessuser "github.com/khoaphungnguyen/go-openai/internal/user/business"
+	modeluser "github.com/khoaphungnguyen/go-openai/internal/user/model"
+)
+
+// ListLockouts lists login lockouts, filtered by user, email, IP address and
+// whether they are still active. Admin only.
+func (h *UserHandler) ListLockouts(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(businessuser.DefaultLockoutPageSize)))
+	if err != nil || limit <= 0 || limit > businessuser.MaxLockoutPageSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+		return
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offset"})
+		return
+	}
+	filter := modeluser.LockoutFilter{
+		Email:     c.Query("email"),
+		IPAddress: c.Query("ip"),
+		Limit:     limit,
+		Offset:    offset,
+	}
+	if value := c.Query("userId"); value != "" {
+		userID, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+			return
+		}
+		filter.UserID = &userID
+	}
+	if value := c.Query("active"); value != "" {
+		if filter.ActiveOnly, err = strconv.ParseBool(value); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid active filter"})
+			return
+		}
+	}
+
+	lockouts, total, err := h.userService.SearchLockouts(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve lockouts"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"total": total, "lockouts": lockouts})
+}
+
+// Unlock ends a login lockout early. Admin only.
+func (h *UserHandler) Unlock(c *gin.Context) {
+	actorID, err := getUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	lockoutID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lockout ID"})
+		return
+	}
+
+	lockout, err := h.userService.Unlock(actorID, lockoutID)
+	if errors.Is(err, businessuser.ErrLockoutNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No active lockout found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unlock"})
+		return
+	}
+	entry := audittransport.NewEntry(c, auditmodel.ActionLockoutUnlocked, auditmodel.TargetLockout, lockout.ID)
+	if lockout.Email != "" {
+		entry.Set("email", lockout.Email)
+	} else {
+		entry.Set("ipAddress", lockout.IPAddress)
+	}
+	h.auditService.Record(c.Request.Context(), entry)
+	c.JSON(http.StatusOK, lockout)
+}
+
+// respondWithLoginThrottled writes the response for a login refused because
+// of earlier failures and reports whether it did.
+func respondWithLoginThrottled(c *gin.Context, err error) bool {
+	var throttled *businessuser.LoginThrottledError
+	if !errors.As(err, &throttled) {
+		return false
+	}
+	c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+	if throttled.Locked {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many failed logins. Logins are temporarily locked."})
+	} else {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many failed logins. Please wait before trying again."})
+	}
+	return true
+}
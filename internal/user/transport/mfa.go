@@ -0,0 +1,175 @@
+package usertransport
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	auditmodel "github.com/khoaphungnguyen/go-openai/internal/audit/model"
+	audittransport "github.com/khoaphungnguyen/go-openai/internal/audit/transport"
+	businessuser "github.com/khoaphungnguyen/go-openai/internal/user/business"
+)
+
+type MFACodePayload struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type DisableMFAPayload struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+type MFALoginPayload struct {
+	MFAToken string `json:"mfaToken" binding:"required"`
+	Code     string `json:"code" binding:"required"` // TOTP code or recovery code
+}
+
+// GetMFAStatus returns the two-factor authentication settings of the current user.
+func (h *UserHandler) GetMFAStatus(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	status, err := h.userService.GetMFAStatus(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve two-factor settings"})
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// EnrollMFA starts enrolling an authenticator app for the current user.
+func (h *UserHandler) EnrollMFA(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	enrollment, err := h.userService.EnrollMFA(userID)
+	if err != nil {
+		respondWithMFAError(c, err, "Failed to start two-factor enrollment")
+		return
+	}
+	c.JSON(http.StatusOK, enrollment)
+}
+
+// ConfirmMFA turns on two-factor authentication with a first code and returns
+// the recovery codes.
+func (h *UserHandler) ConfirmMFA(c *gin.Context) {
+	var payload MFACodePayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Code required"})
+		return
+	}
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	codes, err := h.userService.ConfirmMFA(userID, payload.Code)
+	if err != nil {
+		respondWithMFAError(c, err, "Failed to enable two-factor authentication")
+		return
+	}
+	h.auditService.Record(c.Request.Context(), audittransport.NewEntry(c, auditmodel.ActionMFAEnabled, auditmodel.TargetUser, userID))
+	c.JSON(http.StatusOK, gin.H{"recoveryCodes": codes})
+}
+
+// DisableMFA turns off two-factor authentication for the current user.
+func (h *UserHandler) DisableMFA(c *gin.Context) {
+	var payload DisableMFAPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Password and code required"})
+		return
+	}
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	if err := h.userService.DisableMFA(userID, payload.Password, payload.Code); err != nil {
+		respondWithMFAError(c, err, "Failed to disable two-factor authentication")
+		return
+	}
+	h.auditService.Record(c.Request.Context(), audittransport.NewEntry(c, auditmodel.ActionMFADisabled, auditmodel.TargetUser, userID))
+	c.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication disabled"})
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes of the current user.
+func (h *UserHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	var payload MFACodePayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Code required"})
+		return
+	}
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	codes, err := h.userService.RegenerateRecoveryCodes(userID, payload.Code)
+	if err != nil {
+		respondWithMFAError(c, err, "Failed to regenerate recovery codes")
+		return
+	}
+	h.auditService.Record(c.Request.Context(), audittransport.NewEntry(c, auditmodel.ActionRecoveryCodesReset, auditmodel.TargetUser, userID))
+	c.JSON(http.StatusOK, gin.H{"recoveryCodes": codes})
+}
+
+// VerifyMFALogin finishes a login that needs a second factor.
+func (h *UserHandler) VerifyMFALogin(c *gin.Context) {
+	var payload MFALoginPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid inputs"})
+		return
+	}
+
+	user, tokens, err := h.userService.CompleteMFAChallenge(c.Request.Context(), payload.MFAToken, payload.Code, clientInfo(c))
+	if respondWithLoginThrottled(c, err) {
+		h.auditService.Record(c.Request.Context(), audittransport.NewEntry(c, auditmodel.ActionLoginBlocked, "", uuid.Nil).
+			Set("factor", "mfa"))
+		return
+	}
+	if errors.Is(err, businessuser.ErrInvalidMFACode) {
+		h.auditService.Record(c.Request.Context(), audittransport.NewEntry(c, auditmodel.ActionLoginFailed, "", uuid.Nil).
+			Set("factor", "mfa"))
+	}
+	if err != nil {
+		respondWithMFAError(c, err, "Error signing token")
+		return
+	}
+	h.respondWithLogin(c, user, tokens)
+}
+
+// respondWithMFAError maps two-factor errors to responses, using message for unexpected ones.
+func respondWithMFAError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, businessuser.ErrInvalidMFACode):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid two-factor code"})
+	case errors.Is(err, businessuser.ErrInvalidMFAChallenge):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Login expired. Please log in again."})
+	case errors.Is(err, businessuser.ErrIncorrectPassword):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Current password is incorrect"})
+	case errors.Is(err, businessuser.ErrMFAAlreadyEnabled):
+		c.JSON(http.StatusConflict, gin.H{"error": "Two-factor authentication is already enabled"})
+	case errors.Is(err, businessuser.ErrMFANotEnabled):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Two-factor authentication is not enabled"})
+	case errors.Is(err, businessuser.ErrMFANotEnrolling):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Start two-factor enrollment first"})
+	case errors.Is(err, businessuser.ErrMFARequired):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Two-factor authentication is required for administrators"})
+	case errors.Is(err, businessuser.ErrAccountSuspended):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Account is suspended"})
+	default:
+		log.Println(message+":", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
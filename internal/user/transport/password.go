@@ -0,0 +1,93 @@
+package usertransport
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	auditmodel "github.com/khoaphungnguyen/go-openai/internal/audit/model"
+	audittransport "github.com/khoaphungnguyen/go-openai/internal/audit/transport"
+	businessuser "github.com/khoaphungnguyen/go-openai/internal/user/business"
+)
+
+type ForgotPasswordPayload struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResetPasswordPayload struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"newPassword" binding:"required"`
+}
+
+type ChangePasswordPayload struct {
+	CurrentPassword string `json:"currentPassword" binding:"required"`
+	NewPassword     string `json:"newPassword" binding:"required"`
+}
+
+// ForgotPassword emails a password reset link if the address belongs to an
+// account. The response is the same either way.
+func (h *UserHandler) ForgotPassword(c *gin.Context) {
+	var payload ForgotPasswordPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	if err := h.userService.RequestPasswordReset(c.Request.Context(), payload.Email); err != nil {
+		log.Println("Failed to send password reset email:", err)
+	}
+	h.auditService.Record(c.Request.Context(), audittransport.NewEntry(c, auditmodel.ActionPasswordResetRequested, "", uuid.Nil).
+		Set("email", payload.Email))
+	c.JSON(http.StatusOK, gin.H{"message": "If an account exists for this email, a password reset link has been sent."})
+}
+
+// ResetPassword sets a new password using a token from a password reset email.
+func (h *UserHandler) ResetPassword(c *gin.Context) {
+	var payload ResetPasswordPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	userID, err := h.userService.ResetPassword(payload.Token, payload.NewPassword)
+	switch {
+	case errors.Is(err, businessuser.ErrWeakPassword):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, businessuser.ErrInvalidResetToken):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired password reset link"})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reset password"})
+	default:
+		h.auditService.Record(c.Request.Context(), asUser(audittransport.NewEntry(c, auditmodel.ActionPasswordReset, auditmodel.TargetUser, userID)))
+		c.JSON(http.StatusOK, gin.H{"message": "Password reset successfully. Please log in again."})
+	}
+}
+
+// ChangePassword replaces the current user's password.
+func (h *UserHandler) ChangePassword(c *gin.Context) {
+	var payload ChangePasswordPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	err = h.userService.ChangePassword(c.Request.Context(), userID, getSessionIDFromContext(c), payload.CurrentPassword, payload.NewPassword)
+	switch {
+	case errors.Is(err, businessuser.ErrIncorrectPassword):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Current password is incorrect"})
+	case errors.Is(err, businessuser.ErrWeakPassword):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to change password"})
+	default:
+		h.auditService.Record(c.Request.Context(), audittransport.NewEntry(c, auditmodel.ActionPasswordChanged, auditmodel.TargetUser, userID))
+		c.JSON(http.StatusOK, gin.H{"message": "Password changed successfully"})
+	}
+}
@@ -0,0 +1,111 @@
+package usertransport
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	auditmodel "github.com/khoaphungnguyen/go-openai/internal/audit/model"
+	audittransport "github.com/khoaphungnguyen/go-openai/internal/audit/transport"
+	businessuser "github.com/khoaphungnguyen/go-openai/internal/user/business"
+	modeluser "github.com/khoaphungnguyen/go-openai/internal/user/model"
+)
+
+// GetSessions lists the devices the current user is logged in on.
+func (h *UserHandler) GetSessions(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	sessions, err := h.userService.GetSessions(userID, getSessionIDFromContext(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve sessions"})
+		return
+	}
+	c.JSON(http.StatusOK, sessions)
+}
+
+// RevokeSession logs the current user out of one of their sessions.
+func (h *UserHandler) RevokeSession(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		return
+	}
+
+	err = h.userService.RevokeSession(userID, sessionID)
+	if errors.Is(err, businessuser.ErrSessionNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke session"})
+		return
+	}
+	h.auditService.Record(c.Request.Context(), audittransport.NewEntry(c, auditmodel.ActionSessionRevoked, auditmodel.TargetSession, sessionID))
+	c.JSON(http.StatusOK, gin.H{"message": "Session revoked successfully"})
+}
+
+// GetUserSessions lists the active sessions of any user. Admin only.
+func (h *UserHandler) GetUserSessions(c *gin.Context) {
+	user, ok := h.parseTargetUser(c)
+	if !ok {
+		return
+	}
+
+	sessions, err := h.userService.GetSessions(user.ID, uuid.Nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve sessions"})
+		return
+	}
+	c.JSON(http.StatusOK, sessions)
+}
+
+// RevokeUserSessions logs a user out of every session. Admin only.
+func (h *UserHandler) RevokeUserSessions(c *gin.Context) {
+	user, ok := h.parseTargetUser(c)
+	if !ok {
+		return
+	}
+
+	if err := h.userService.LogoutEverywhere(user.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke sessions"})
+		return
+	}
+	h.auditService.Record(c.Request.Context(), audittransport.NewEntry(c, auditmodel.ActionUserSessionsRevoked, auditmodel.TargetUser, user.ID))
+	c.JSON(http.StatusOK, gin.H{"message": "All sessions of the user were revoked"})
+}
+
+// parseTargetUser reads the userID path parameter of admin routes and loads
+// the user, writing the error response when it does not exist.
+func (h *UserHandler) parseTargetUser(c *gin.Context) (*modeluser.User, bool) {
+	userID, err := uuid.Parse(c.Param("userID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return nil, false
+	}
+	user, err := h.userService.GetUserByUUID(userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return nil, false
+	}
+	return user, true
+}
+
+// getSessionIDFromContext returns the session of the access token the request
+// was made with, or uuid.Nil for tokens issued without one.
+func getSessionIDFromContext(c *gin.Context) uuid.UUID {
+	sessionID, err := uuid.Parse(c.GetString("sessionID"))
+	if err != nil {
+		return uuid.Nil
+	}
+	return sessionID
+}
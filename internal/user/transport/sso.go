@@ -0,0 +1,102 @@
+package usertransport
+
+import (
+	"crypto/subtle"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/khoaphungnguyen/go-openai/internal/oidc"
+	businessuser "github.com/khoaphungnguyen/go-openai/internal/user/business"
+)
+
+// oidcStateCookie ties a pending external login to the browser that started it.
+const oidcStateCookie = "oidc_state"
+
+type OIDCCallbackPayload struct {
+	Code  string `json:"code" binding:"required"`
+	State string `json:"state" binding:"required"`
+}
+
+// GetIdentityProviders lists the identity providers users can sign in with.
+func (h *UserHandler) GetIdentityProviders(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"providers": h.ssoService.Providers()})
+}
+
+// StartOIDCLogin returns the URL that starts a login at an identity provider
+// and keeps its state in a cookie the callback must present.
+func (h *UserHandler) StartOIDCLogin(c *gin.Context) {
+	authURL, state, err := h.ssoService.StartLogin(c.Request.Context(), c.Param("provider"))
+	if errors.Is(err, businessuser.ErrUnknownProvider) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Unknown identity provider"})
+		return
+	}
+	if err != nil {
+		log.Println("Error starting OIDC login:", err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Identity provider unavailable"})
+		return
+	}
+	setOIDCStateCookie(c, state, int(businessuser.OIDCLoginDuration.Seconds()))
+	c.JSON(http.StatusOK, gin.H{"authorizationUrl": authURL})
+}
+
+// CompleteOIDCLogin handles the code and state the identity provider
+// redirected back with and logs the user in. The state must match the cookie
+// set when the login started, so a victim cannot be signed in to an account
+// whose login an attacker started.
+func (h *UserHandler) CompleteOIDCLogin(c *gin.Context) {
+	var payload OIDCCallbackPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Code and state required"})
+		return
+	}
+	cookie, err := c.Cookie(oidcStateCookie)
+	setOIDCStateCookie(c, "", -1)
+	if err != nil || subtle.ConstantTimeCompare([]byte(cookie), []byte(payload.State)) != 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired login attempt"})
+		return
+	}
+
+	user, err := h.ssoService.CompleteLogin(c.Request.Context(), c.Param("provider"), payload.Code, payload.State)
+	switch {
+	case err == nil:
+	case errors.Is(err, businessuser.ErrUnknownProvider):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Unknown identity provider"})
+		return
+	case errors.Is(err, businessuser.ErrInvalidLoginState):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired login attempt"})
+		return
+	case errors.Is(err, businessuser.ErrProviderEmailNotVerified):
+		c.JSON(http.StatusForbidden, gin.H{"error": "A verified email address is required"})
+		return
+	case errors.Is(err, businessuser.ErrAccountInactive):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Account is inactive"})
+		return
+	case errors.Is(err, oidc.ErrExchange), errors.Is(err, oidc.ErrInvalidIDToken):
+		log.Println("Error completing OIDC login:", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Login with identity provider failed"})
+		return
+	default:
+		log.Println("Error completing OIDC login:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to complete login"})
+		return
+	}
+
+	h.completeLogin(c, user)
+}
+
+// setOIDCStateCookie stores the state of a pending external login in an
+// HTTP-only cookie, or removes it when maxAge is negative. Lax is enough since
+// the callback is posted by the app itself after the provider redirects back.
+func setOIDCStateCookie(c *gin.Context, state string, maxAge int) {
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    state,
+		HttpOnly: true,
+		Path:     "/",
+		Secure:   false, // Set to true if using HTTPS
+		SameSite: http.SameSiteLaxMode,
+		MaxAge:   maxAge,
+	})
+}
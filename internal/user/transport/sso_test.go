@@ -0,0 +1,54 @@
+package usertransport
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	businessuser "github.com/khoaphungnguyen/go-openai/internal/user/business"
+)
+
+func TestCompleteOIDCLoginRequiresStateCookie(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewUserHandler(nil, businessuser.NewSSOService(nil), nil)
+	router := gin.New()
+	router.POST("/oidc/:provider/callback", h.CompleteOIDCLogin)
+
+	tests := []struct {
+		name   string
+		cookie string
+		want   int
+	}{
+		{"no cookie", "", http.StatusBadRequest},
+		{"another login's state", "attacker-state", http.StatusBadRequest},
+		// A matching state reaches the provider, which is not configured here
+		{"matching state", "victim-state", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/oidc/test/callback",
+				strings.NewReader(`{"code":"code","state":"victim-state"}`))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: oidcStateCookie, Value: tt.cookie})
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+			cleared := false
+			for _, cookie := range rec.Result().Cookies() {
+				if cookie.Name == oidcStateCookie && cookie.MaxAge < 0 {
+					cleared = true
+				}
+			}
+			if !cleared {
+				t.Error("the state cookie was not cleared")
+			}
+		})
+	}
+}
@@ -1,12 +1,16 @@
 package usertransport
 
-import businessuser "github.com/khoaphungnguyen/go-openai/internal/user/business"
+import (
+	auditbusiness "github.com/khoaphungnguyen/go-openai/internal/audit/business"
+	businessuser "github.com/khoaphungnguyen/go-openai/internal/user/business"
+)
 
 type UserHandler struct {
-	userService *businessuser.UserService
-	JWTKey      string
+	userService  *businessuser.UserService
+	ssoService   *businessuser.SSOService
+	auditService *auditbusiness.AuditService
 }
 
-func NewUserHandler(userService *businessuser.UserService, JWTKey string) *UserHandler {
-	return &UserHandler{userService: userService, JWTKey: JWTKey}
+func NewUserHandler(userService *businessuser.UserService, ssoService *businessuser.SSOService, auditService *auditbusiness.AuditService) *UserHandler {
+	return &UserHandler{userService: userService, ssoService: ssoService, auditService: auditService}
 }
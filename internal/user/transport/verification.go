@@ -0,0 +1,61 @@
+package usertransport
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	auditmodel "github.com/khoaphungnguyen/go-openai/internal/audit/model"
+	audittransport "github.com/khoaphungnguyen/go-openai/internal/audit/transport"
+	businessuser "github.com/khoaphungnguyen/go-openai/internal/user/business"
+)
+
+type VerifyEmailPayload struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// VerifyEmail handles the token from an email verification link.
+func (h *UserHandler) VerifyEmail(c *gin.Context) {
+	var payload VerifyEmailPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Verification token required"})
+		return
+	}
+
+	userID, err := h.userService.VerifyEmail(payload.Token)
+	if errors.Is(err, businessuser.ErrInvalidVerificationToken) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired verification link"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify email"})
+		return
+	}
+	h.auditService.Record(c.Request.Context(), asUser(audittransport.NewEntry(c, auditmodel.ActionEmailVerified, auditmodel.TargetUser, userID)))
+	c.JSON(http.StatusOK, gin.H{"message": "Email verified successfully"})
+}
+
+// ResendVerificationEmail mails the current user a new verification link.
+func (h *UserHandler) ResendVerificationEmail(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	err = h.userService.ResendVerificationEmail(c.Request.Context(), userID)
+	switch {
+	case errors.Is(err, businessuser.ErrEmailAlreadyVerified):
+		c.JSON(http.StatusConflict, gin.H{"error": "Email is already verified"})
+	case errors.Is(err, businessuser.ErrVerificationThrottled):
+		c.Header("Retry-After", strconv.Itoa(int(businessuser.VerificationResendInterval.Seconds())))
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "A verification email was sent recently. Please try again later."})
+	case err != nil:
+		log.Println("Failed to send verification email:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send verification email"})
+	default:
+		c.JSON(http.StatusOK, gin.H{"message": "Verification email sent"})
+	}
+}
@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfc6238Key is the SHA-1 seed of the RFC 6238 test vectors.
+var rfc6238Key = []byte("12345678901234567890")
+
+// TestTOTPCodeRFC6238 checks the SHA-1 vectors of RFC 6238 Appendix B. The RFC
+// lists eight digits; six-digit codes are their last six.
+func TestTOTPCodeRFC6238(t *testing.T) {
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "94287082"},
+		{1111111109, "07081804"},
+		{1111111111, "14050471"},
+		{1234567890, "89005924"},
+		{2000000000, "69279037"},
+		{20000000000, "65353130"},
+	}
+	secret := totpEncoding.EncodeToString(rfc6238Key)
+	for _, tt := range tests {
+		want := tt.code[len(tt.code)-TOTPDigits:]
+		step := tt.unix / int64(TOTPPeriod.Seconds())
+		if got := totpCode(rfc6238Key, step); got != want {
+			t.Errorf("totpCode at %d = %s, want %s", tt.unix, got, want)
+		}
+		if got, ok := ValidateTOTP(secret, want, time.Unix(tt.unix, 0)); !ok || got != step {
+			t.Errorf("ValidateTOTP(%s) at %d = %d, %v; want step %d", want, tt.unix, got, ok, step)
+		}
+	}
+}
+
+func TestValidateTOTPSkew(t *testing.T) {
+	secret := totpEncoding.EncodeToString(rfc6238Key)
+	now := time.Unix(1234567890, 0)
+	current := now.Unix() / int64(TOTPPeriod.Seconds())
+
+	tests := []struct {
+		offset int64
+		ok     bool
+	}{
+		{-2, false},
+		{-1, true},
+		{0, true},
+		{1, true},
+		{2, false},
+	}
+	for _, tt := range tests {
+		step, ok := ValidateTOTP(secret, totpCode(rfc6238Key, current+tt.offset), now)
+		if ok != tt.ok {
+			t.Errorf("code %d steps away: accepted = %v, want %v", tt.offset, ok, tt.ok)
+		}
+		if ok && step != current+tt.offset {
+			t.Errorf("code %d steps away matched step %d, want %d", tt.offset, step, current+tt.offset)
+		}
+	}
+}
+
+func TestValidateTOTPInput(t *testing.T) {
+	secret := totpEncoding.EncodeToString(rfc6238Key)
+	now := time.Unix(59, 0)
+
+	tests := []struct {
+		name, secret, code string
+		ok                 bool
+	}{
+		{"spaced code", secret, "287 082", true},
+		{"lowercase padded secret", strings.ToLower(secret) + "====", "287082", true},
+		{"wrong code", secret, "287083", false},
+		{"short code", secret, "28708", false},
+		{"eight digits", secret, "94287082", false},
+		{"malformed secret", "not base32!", "287082", false},
+	}
+	for _, tt := range tests {
+		if _, ok := ValidateTOTP(tt.secret, tt.code, now); ok != tt.ok {
+			t.Errorf("%s: accepted = %v, want %v", tt.name, ok, tt.ok)
+		}
+	}
+}
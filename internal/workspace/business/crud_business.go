@@ -0,0 +1,167 @@
+package workspacebusiness
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	workspacemodel "github.com/khoaphungnguyen/go-openai/internal/workspace/model"
+	workspacestorage "github.com/khoaphungnguyen/go-openai/internal/workspace/storage"
+)
+
+var (
+	// ErrWorkspaceNotFound is returned when a workspace does not exist or the caller is not a member.
+	ErrWorkspaceNotFound = workspacestorage.ErrWorkspaceNotFound
+	// ErrMemberNotFound is returned when the target user is not a member of the workspace.
+	ErrMemberNotFound = workspacestorage.ErrMemberNotFound
+	// ErrPermissionDenied is returned when the caller's role does not allow the action.
+	ErrPermissionDenied = errors.New("insufficient workspace permissions")
+	// ErrInvalidRole is returned for unknown workspace roles.
+	ErrInvalidRole = errors.New("invalid workspace role")
+	// ErrOwnerImmutable is returned when trying to demote or remove the workspace creator.
+	ErrOwnerImmutable = errors.New("the workspace owner cannot be changed or removed")
+)
+
+// CreateWorkspace creates a workspace owned by the user.
+func (ws *WorkspaceService) CreateWorkspace(userID uuid.UUID, name string) (*workspacemodel.Workspace, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("workspace name cannot be empty")
+	}
+	workspace := &workspacemodel.Workspace{
+		Name:    name,
+		OwnerID: userID,
+	}
+	if err := ws.workspaceStore.CreateWorkspace(workspace); err != nil {
+		return nil, err
+	}
+	return workspace, nil
+}
+
+// GetWorkspacesByUserID lists the workspaces the user belongs to.
+func (ws *WorkspaceService) GetWorkspacesByUserID(userID uuid.UUID) ([]workspacemodel.WorkspaceSummary, error) {
+	if userID == uuid.Nil {
+		return nil, errors.New("invalid user ID")
+	}
+	return ws.workspaceStore.GetWorkspacesByUserID(userID)
+}
+
+// GetWorkspace returns a workspace and its members if the user is a member.
+func (ws *WorkspaceService) GetWorkspace(userID, workspaceID uuid.UUID) (*workspacemodel.WorkspaceDetail, error) {
+	role, err := ws.MemberRole(workspaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	workspace, err := ws.workspaceStore.GetWorkspaceByID(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	members, err := ws.workspaceStore.GetMembers(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	return &workspacemodel.WorkspaceDetail{
+		WorkspaceSummary: workspacemodel.WorkspaceSummary{
+			ID:        workspace.ID,
+			Name:      workspace.Name,
+			OwnerID:   workspace.OwnerID,
+			Role:      role,
+			CreatedAt: workspace.CreatedAt,
+			UpdatedAt: workspace.UpdatedAt,
+		},
+		Members: members,
+	}, nil
+}
+
+// DeleteWorkspace deletes a workspace the user owns.
+func (ws *WorkspaceService) DeleteWorkspace(userID, workspaceID uuid.UUID) error {
+	if err := ws.requireRole(workspaceID, userID, workspacemodel.Role.CanManage); err != nil {
+		return err
+	}
+	return ws.workspaceStore.DeleteWorkspace(workspaceID)
+}
+
+// AddMember invites an existing user, identified by email, into the workspace.
+func (ws *WorkspaceService) AddMember(actorID, workspaceID uuid.UUID, email string, role workspacemodel.Role) (*workspacemodel.WorkspaceMember, error) {
+	if !role.IsValid() {
+		return nil, ErrInvalidRole
+	}
+	if err := ws.requireRole(workspaceID, actorID, workspacemodel.Role.CanManage); err != nil {
+		return nil, err
+	}
+	user, err := ws.userService.GetUserByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	member := &workspacemodel.WorkspaceMember{
+		WorkspaceID: workspaceID,
+		UserID:      user.ID,
+		Role:        role,
+	}
+	if err := ws.workspaceStore.AddMember(member); err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+// UpdateMemberRole changes the role of a member; only owners may do this.
+func (ws *WorkspaceService) UpdateMemberRole(actorID, workspaceID, memberID uuid.UUID, role workspacemodel.Role) error {
+	if !role.IsValid() {
+		return ErrInvalidRole
+	}
+	if err := ws.requireRole(workspaceID, actorID, workspacemodel.Role.CanManage); err != nil {
+		return err
+	}
+	if err := ws.checkNotCreator(workspaceID, memberID); err != nil {
+		return err
+	}
+	return ws.workspaceStore.UpdateMemberRole(workspaceID, memberID, role)
+}
+
+// RemoveMember removes a member from the workspace. Owners may remove anyone
+// except the creator, and every member may remove themselves.
+func (ws *WorkspaceService) RemoveMember(actorID, workspaceID, memberID uuid.UUID) error {
+	if actorID != memberID {
+		if err := ws.requireRole(workspaceID, actorID, workspacemodel.Role.CanManage); err != nil {
+			return err
+		}
+	}
+	if err := ws.checkNotCreator(workspaceID, memberID); err != nil {
+		return err
+	}
+	return ws.workspaceStore.RemoveMember(workspaceID, memberID)
+}
+
+// MemberRole returns the role of the user in the workspace, or ErrWorkspaceNotFound
+// if the user is not a member so that non-members cannot probe workspace IDs.
+func (ws *WorkspaceService) MemberRole(workspaceID, userID uuid.UUID) (workspacemodel.Role, error) {
+	role, err := ws.workspaceStore.GetMemberRole(workspaceID, userID)
+	if errors.Is(err, workspacestorage.ErrMemberNotFound) {
+		return "", ErrWorkspaceNotFound
+	}
+	return role, err
+}
+
+// requireRole checks that the user's role in the workspace satisfies allowed.
+func (ws *WorkspaceService) requireRole(workspaceID, userID uuid.UUID, allowed func(workspacemodel.Role) bool) error {
+	role, err := ws.MemberRole(workspaceID, userID)
+	if err != nil {
+		return err
+	}
+	if !allowed(role) {
+		return ErrPermissionDenied
+	}
+	return nil
+}
+
+// checkNotCreator rejects changes to the membership of the workspace creator.
+func (ws *WorkspaceService) checkNotCreator(workspaceID, memberID uuid.UUID) error {
+	workspace, err := ws.workspaceStore.GetWorkspaceByID(workspaceID)
+	if err != nil {
+		return err
+	}
+	if workspace.OwnerID == memberID {
+		return ErrOwnerImmutable
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+// workspacebusiness contains the business logic for shared team workspaces.
+package workspacebusiness
+
+import (
+	userbusiness "github.com/khoaphungnguyen/go-openai/internal/user/business"
+	workspacestorage "github.com/khoaphungnguyen/go-openai/internal/workspace/storage"
+)
+
+// WorkspaceService provides methods for workspace operations.
+type WorkspaceService struct {
+	workspaceStore workspacestorage.WorkspaceStore
+	userService    *userbusiness.UserService
+}
+
+// NewWorkspaceService creates a new WorkspaceService.
+func NewWorkspaceService(workspaceStore workspacestorage.WorkspaceStore, userService *userbusiness.UserService) *WorkspaceService {
+	return &WorkspaceService{
+		workspaceStore: workspaceStore,
+		userService:    userService,
+	}
+}
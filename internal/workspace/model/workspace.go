@@ -0,0 +1,89 @@
+// workspacemodel defines the data structures used for shared team workspaces.
+package workspacemodel
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Role defines the permission level of a workspace member.
+type Role string
+
+const (
+	OwnerRole  Role = "owner"
+	EditorRole Role = "editor"
+	ViewerRole Role = "viewer"
+)
+
+// IsValid reports whether the role is one of the known workspace roles.
+func (r Role) IsValid() bool {
+	return r == OwnerRole || r == EditorRole || r == ViewerRole
+}
+
+// CanRead reports whether the role may view workspace threads and notes.
+func (r Role) CanRead() bool {
+	return r.IsValid()
+}
+
+// CanWrite reports whether the role may post messages and edit notes.
+func (r Role) CanWrite() bool {
+	return r == OwnerRole || r == EditorRole
+}
+
+// CanManage reports whether the role may manage members and delete the workspace.
+func (r Role) CanManage() bool {
+	return r == OwnerRole
+}
+
+// Workspace represents a team space whose members share threads and notes.
+type Workspace struct {
+	ID        uuid.UUID `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	Name      string    `gorm:"type:varchar(255);not null"`
+	OwnerID   uuid.UUID `gorm:"type:uuid;not null"`
+	CreatedAt time.Time `gorm:"default:now()"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+// TableName overrides the table name used by Workspace.
+func (Workspace) TableName() string {
+	return "workspace"
+}
+
+// WorkspaceMember links a user to a workspace with a role.
+type WorkspaceMember struct {
+	WorkspaceID uuid.UUID `gorm:"primaryKey;type:uuid"`
+	UserID      uuid.UUID `gorm:"primaryKey;type:uuid"`
+	Role        Role      `gorm:"type:varchar(20);not null"`
+	CreatedAt   time.Time `gorm:"default:now()"`
+}
+
+// TableName overrides the table name used by WorkspaceMember.
+func (WorkspaceMember) TableName() string {
+	return "workspace_member"
+}
+
+// MemberDetail is a workspace member joined with the user's public profile.
+type MemberDetail struct {
+	UserID    uuid.UUID `json:"userId"`
+	FullName  string    `json:"fullName"`
+	Email     string    `json:"email"`
+	Role      Role      `json:"role"`
+	CreatedAt time.Time `json:"joinedAt"`
+}
+
+// WorkspaceSummary is a workspace together with the caller's role in it.
+type WorkspaceSummary struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	OwnerID   uuid.UUID `json:"ownerId"`
+	Role      Role      `json:"role"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// WorkspaceDetail is a workspace together with its members.
+type WorkspaceDetail struct {
+	WorkspaceSummary
+	Members []MemberDetail `json:"members"`
+}
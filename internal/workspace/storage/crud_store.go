@@ -0,0 +1,117 @@
+package workspacestorage
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	workspacemodel "github.com/khoaphungnguyen/go-openai/internal/workspace/model"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrWorkspaceNotFound is the error returned when a workspace cannot be found.
+	ErrWorkspaceNotFound = errors.New("workspace not found")
+	// ErrMemberNotFound is the error returned when a user is not a member of a workspace.
+	ErrMemberNotFound = errors.New("workspace member not found")
+)
+
+// CreateWorkspace adds a new workspace and registers its owner as a member.
+func (ws *workspaceStore) CreateWorkspace(workspace *workspacemodel.Workspace) error {
+	return ws.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(workspace).Error; err != nil {
+			return err
+		}
+		return tx.Create(&workspacemodel.WorkspaceMember{
+			WorkspaceID: workspace.ID,
+			UserID:      workspace.OwnerID,
+			Role:        workspacemodel.OwnerRole,
+		}).Error
+	})
+}
+
+// GetWorkspaceByID retrieves a workspace by its ID.
+func (ws *workspaceStore) GetWorkspaceByID(workspaceID uuid.UUID) (*workspacemodel.Workspace, error) {
+	var workspace workspacemodel.Workspace
+	err := ws.db.First(&workspace, "id = ?", workspaceID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrWorkspaceNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve workspace: %w", err)
+	}
+	return &workspace, nil
+}
+
+// GetWorkspacesByUserID retrieves all workspaces the user is a member of.
+func (ws *workspaceStore) GetWorkspacesByUserID(userID uuid.UUID) ([]workspacemodel.WorkspaceSummary, error) {
+	var workspaces []workspacemodel.WorkspaceSummary
+	err := ws.db.Table("workspace AS w").
+		Select("w.id, w.name, w.owner_id, m.role, w.created_at, w.updated_at").
+		Joins("JOIN workspace_member AS m ON m.workspace_id = w.id").
+		Where("m.user_id = ?", userID).
+		Order("w.updated_at DESC").
+		Scan(&workspaces).Error
+	return workspaces, err
+}
+
+// DeleteWorkspace deletes a workspace; its threads and notes fall back to their creators.
+func (ws *workspaceStore) DeleteWorkspace(workspaceID uuid.UUID) error {
+	return ws.db.Where("id = ?", workspaceID).Delete(&workspacemodel.Workspace{}).Error
+}
+
+// AddMember adds a user to a workspace.
+func (ws *workspaceStore) AddMember(member *workspacemodel.WorkspaceMember) error {
+	return ws.db.Create(member).Error
+}
+
+// GetMemberRole returns the role of a user in a workspace.
+func (ws *workspaceStore) GetMemberRole(workspaceID, userID uuid.UUID) (workspacemodel.Role, error) {
+	var member workspacemodel.WorkspaceMember
+	err := ws.db.Select("role").Where("workspace_id = ? AND user_id = ?", workspaceID, userID).First(&member).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", ErrMemberNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to retrieve workspace member: %w", err)
+	}
+	return member.Role, nil
+}
+
+// GetMembers retrieves the members of a workspace with their profile details.
+func (ws *workspaceStore) GetMembers(workspaceID uuid.UUID) ([]workspacemodel.MemberDetail, error) {
+	var members []workspacemodel.MemberDetail
+	err := ws.db.Table("workspace_member AS m").
+		Select("m.user_id, u.full_name, u.email, m.role, m.created_at").
+		Joins("JOIN users AS u ON u.id = m.user_id").
+		Where("m.workspace_id = ?", workspaceID).
+		Order("m.created_at ASC").
+		Scan(&members).Error
+	return members, err
+}
+
+// UpdateMemberRole changes the role of a workspace member.
+func (ws *workspaceStore) UpdateMemberRole(workspaceID, userID uuid.UUID, role workspacemodel.Role) error {
+	result := ws.db.Model(&workspacemodel.WorkspaceMember{}).
+		Where("workspace_id = ? AND user_id = ?", workspaceID, userID).
+		Update("role", role)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrMemberNotFound
+	}
+	return nil
+}
+
+// RemoveMember removes a user from a workspace.
+func (ws *workspaceStore) RemoveMember(workspaceID, userID uuid.UUID) error {
+	result := ws.db.Where("workspace_id = ? AND user_id = ?", workspaceID, userID).Delete(&workspacemodel.WorkspaceMember{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrMemberNotFound
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+// workspacestorage provides data persistence logic for workspaces and their members.
+package workspacestorage
+
+import (
+	"github.com/google/uuid"
+	workspacemodel "github.com/khoaphungnguyen/go-openai/internal/workspace/model"
+	"gorm.io/gorm"
+)
+
+// WorkspaceStore provides methods for workspace operations.
+type WorkspaceStore interface {
+	CreateWorkspace(workspace *workspacemodel.Workspace) error
+	GetWorkspaceByID(workspaceID uuid.UUID) (*workspacemodel.Workspace, error)
+	GetWorkspacesByUserID(userID uuid.UUID) ([]workspacemodel.WorkspaceSummary, error)
+	DeleteWorkspace(workspaceID uuid.UUID) error
+
+	AddMember(member *workspacemodel.WorkspaceMember) error
+	GetMemberRole(workspaceID, userID uuid.UUID) (workspacemodel.Role, error)
+	GetMembers(workspaceID uuid.UUID) ([]workspacemodel.MemberDetail, error)
+	UpdateMemberRole(workspaceID, userID uuid.UUID, role workspacemodel.Role) error
+	RemoveMember(workspaceID, userID uuid.UUID) error
+}
+
+// workspaceStore encapsulates the logic for storing and retrieving workspace data.
+type workspaceStore struct {
+	db *gorm.DB
+}
+
+// NewWorkspaceStore creates a new instance of workspaceStore.
+func NewWorkspaceStore(db *gorm.DB) WorkspaceStore {
+	return &workspaceStore{db: db}
+}
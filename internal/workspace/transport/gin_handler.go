@@ -0,0 +1,210 @@
+package workspacetransport
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/khoaphungnguyen/go-openai/internal/common"
+	userstorage "github.com/khoaphungnguyen/go-openai/internal/user/storage"
+	workspacebusiness "github.com/khoaphungnguyen/go-openai/internal/workspace/business"
+	workspacemodel "github.com/khoaphungnguyen/go-openai/internal/workspace/model"
+)
+
+type WorkspacePayload struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type MemberPayload struct {
+	Email string              `json:"email" binding:"required,email"`
+	Role  workspacemodel.Role `json:"role" binding:"required"`
+}
+
+type MemberRolePayload struct {
+	Role workspacemodel.Role `json:"role" binding:"required"`
+}
+
+// CreateWorkspace handles the creation of a new workspace owned by the caller.
+func (wh *WorkspaceHandler) CreateWorkspace(c *gin.Context) {
+	userID, err := common.GetUserIDFromContext(c)
+	if err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var payload WorkspacePayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	workspace, err := wh.workspaceService.CreateWorkspace(userID, payload.Name)
+	if err != nil {
+		common.RespondWithError(c, http.StatusInternalServerError, "Failed to create workspace")
+		return
+	}
+
+	common.RespondWithJSON(c, http.StatusCreated, workspacemodel.WorkspaceSummary{
+		ID:        workspace.ID,
+		Name:      workspace.Name,
+		OwnerID:   workspace.OwnerID,
+		Role:      workspacemodel.OwnerRole,
+		CreatedAt: workspace.CreatedAt,
+		UpdatedAt: workspace.UpdatedAt,
+	})
+}
+
+// GetWorkspaces handles listing the workspaces the caller belongs to.
+func (wh *WorkspaceHandler) GetWorkspaces(c *gin.Context) {
+	userID, err := common.GetUserIDFromContext(c)
+	if err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	workspaces, err := wh.workspaceService.GetWorkspacesByUserID(userID)
+	if err != nil {
+		common.RespondWithError(c, http.StatusInternalServerError, "Failed to retrieve workspaces")
+		return
+	}
+
+	common.RespondWithJSON(c, http.StatusOK, workspaces)
+}
+
+// GetWorkspace handles retrieving a workspace and its members.
+func (wh *WorkspaceHandler) GetWorkspace(c *gin.Context) {
+	userID, workspaceID, ok := parseWorkspaceRequest(c)
+	if !ok {
+		return
+	}
+
+	workspace, err := wh.workspaceService.GetWorkspace(userID, workspaceID)
+	if err != nil {
+		respondWithWorkspaceError(c, err, "Failed to retrieve workspace")
+		return
+	}
+
+	common.RespondWithJSON(c, http.StatusOK, workspace)
+}
+
+// DeleteWorkspace handles deleting a workspace owned by the caller.
+func (wh *WorkspaceHandler) DeleteWorkspace(c *gin.Context) {
+	userID, workspaceID, ok := parseWorkspaceRequest(c)
+	if !ok {
+		return
+	}
+
+	if err := wh.workspaceService.DeleteWorkspace(userID, workspaceID); err != nil {
+		respondWithWorkspaceError(c, err, "Failed to delete workspace")
+		return
+	}
+
+	common.RespondWithJSON(c, http.StatusOK, gin.H{"message": "Workspace deleted successfully"})
+}
+
+// AddMember handles adding an existing user to a workspace.
+func (wh *WorkspaceHandler) AddMember(c *gin.Context) {
+	userID, workspaceID, ok := parseWorkspaceRequest(c)
+	if !ok {
+		return
+	}
+
+	var payload MemberPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	member, err := wh.workspaceService.AddMember(userID, workspaceID, payload.Email, payload.Role)
+	if err != nil {
+		respondWithWorkspaceError(c, err, "Failed to add member")
+		return
+	}
+
+	common.RespondWithJSON(c, http.StatusCreated, gin.H{
+		"workspaceId": member.WorkspaceID,
+		"userId":      member.UserID,
+		"role":        member.Role,
+	})
+}
+
+// UpdateMemberRole handles changing the role of a workspace member.
+func (wh *WorkspaceHandler) UpdateMemberRole(c *gin.Context) {
+	userID, workspaceID, ok := parseWorkspaceRequest(c)
+	if !ok {
+		return
+	}
+
+	memberID, err := uuid.Parse(c.Param("userID"))
+	if err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	var payload MemberRolePayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	if err := wh.workspaceService.UpdateMemberRole(userID, workspaceID, memberID, payload.Role); err != nil {
+		respondWithWorkspaceError(c, err, "Failed to update member role")
+		return
+	}
+
+	common.RespondWithJSON(c, http.StatusOK, gin.H{"message": "Member role updated successfully"})
+}
+
+// RemoveMember handles removing a member from a workspace, including leaving it.
+func (wh *WorkspaceHandler) RemoveMember(c *gin.Context) {
+	userID, workspaceID, ok := parseWorkspaceRequest(c)
+	if !ok {
+		return
+	}
+
+	memberID, err := uuid.Parse(c.Param("userID"))
+	if err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	if err := wh.workspaceService.RemoveMember(userID, workspaceID, memberID); err != nil {
+		respondWithWorkspaceError(c, err, "Failed to remove member")
+		return
+	}
+
+	common.RespondWithJSON(c, http.StatusOK, gin.H{"message": "Member removed successfully"})
+}
+
+// parseWorkspaceRequest extracts the caller and workspace IDs, responding on failure.
+func parseWorkspaceRequest(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userID, err := common.GetUserIDFromContext(c)
+	if err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, err.Error())
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	workspaceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		common.RespondWithError(c, http.StatusBadRequest, "Invalid workspace ID")
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, workspaceID, true
+}
+
+// respondWithWorkspaceError maps workspace errors to HTTP responses.
+func respondWithWorkspaceError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, workspacebusiness.ErrWorkspaceNotFound):
+		common.RespondWithError(c, http.StatusNotFound, "Workspace not found")
+	case errors.Is(err, workspacebusiness.ErrMemberNotFound), errors.Is(err, userstorage.ErrUserNotFound):
+		common.RespondWithError(c, http.StatusNotFound, "User not found")
+	case errors.Is(err, workspacebusiness.ErrPermissionDenied), errors.Is(err, workspacebusiness.ErrOwnerImmutable):
+		common.RespondWithError(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, workspacebusiness.ErrInvalidRole):
+		common.RespondWithError(c, http.StatusBadRequest, err.Error())
+	default:
+		common.RespondWithError(c, http.StatusInternalServerError, fallback)
+	}
+}
@@ -0,0 +1,14 @@
+// workspacetransport handles HTTP requests and responses for workspace operations.
+package workspacetransport
+
+import workspacebusiness "github.com/khoaphungnguyen/go-openai/internal/workspace/business"
+
+// WorkspaceHandler handles workspace-related HTTP requests.
+type WorkspaceHandler struct {
+	workspaceService *workspacebusiness.WorkspaceService
+}
+
+// NewWorkspaceHandler creates a new WorkspaceHandler.
+func NewWorkspaceHandler(workspaceService *workspacebusiness.WorkspaceService) *WorkspaceHandler {
+	return &WorkspaceHandler{workspaceService: workspaceService}
+}
@@ -0,0 +1,6 @@
+-- Drop note taxonomy: full-text search, tags and categories
+DROP INDEX IF EXISTS idx_notes_search_vector;
+ALTER TABLE notes DROP COLUMN IF EXISTS search_vector;
+DROP TABLE IF EXISTS note_tag;
+DROP INDEX IF EXISTS idx_notes_category;
+ALTER TABLE notes DROP COLUMN IF EXISTS category;
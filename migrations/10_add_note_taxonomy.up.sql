@@ -0,0 +1,85 @@
+-- Canonical topic category of a note, derived from the free-form type for existing notes
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS category VARCHAR(50) NOT NULL DEFAULT 'other';
+
+UPDATE notes SET category = aliases.category
+FROM (VALUES
+  ('array', 'arrays'),
+  ('arrays', 'arrays'),
+  ('backtrack', 'backtracking'),
+  ('backtracking', 'backtracking'),
+  ('bfs', 'graphs'),
+  ('binary-search', 'binary-search'),
+  ('binary-search-tree', 'trees'),
+  ('binary-tree', 'trees'),
+  ('bit-manipulation', 'bit-manipulation'),
+  ('bitmask', 'bit-manipulation'),
+  ('bits', 'bit-manipulation'),
+  ('bst', 'trees'),
+  ('design', 'design'),
+  ('dfs', 'graphs'),
+  ('dp', 'dynamic-programming'),
+  ('dynamic-programing', 'dynamic-programming'),
+  ('dynamic-programming', 'dynamic-programming'),
+  ('geometry', 'math'),
+  ('graph', 'graphs'),
+  ('graphs', 'graphs'),
+  ('greedy', 'greedy'),
+  ('hash', 'hash-table'),
+  ('hash-map', 'hash-table'),
+  ('hash-table', 'hash-table'),
+  ('hashing', 'hash-table'),
+  ('hashmap', 'hash-table'),
+  ('heap', 'heap'),
+  ('heaps', 'heap'),
+  ('linked-list', 'linked-list'),
+  ('linked-lists', 'linked-list'),
+  ('list', 'linked-list'),
+  ('math', 'math'),
+  ('matrix', 'arrays'),
+  ('memoization', 'dynamic-programming'),
+  ('monotonic-stack', 'stack'),
+  ('number-theory', 'math'),
+  ('object-oriented-design', 'design'),
+  ('prefix-sum', 'arrays'),
+  ('priority-queue', 'heap'),
+  ('queue', 'stack'),
+  ('recursion', 'backtracking'),
+  ('shortest-path', 'graphs'),
+  ('sliding-window', 'sliding-window'),
+  ('sort', 'sorting'),
+  ('sorting', 'sorting'),
+  ('stack', 'stack'),
+  ('stack-queue', 'stack'),
+  ('stacks', 'stack'),
+  ('string', 'strings'),
+  ('strings', 'strings'),
+  ('system-design', 'design'),
+  ('topological-sort', 'graphs'),
+  ('tree', 'trees'),
+  ('trees', 'trees'),
+  ('trie', 'trees'),
+  ('two-pointer', 'two-pointers'),
+  ('two-pointers', 'two-pointers'),
+  ('union-find', 'graphs')
+) AS aliases(slug, category)
+WHERE aliases.slug = trim(both '-' from regexp_replace(lower(notes.type), '[^a-z0-9]+', '-', 'g'));
+
+CREATE INDEX IF NOT EXISTS idx_notes_category ON notes(category);
+
+-- Note Tag Table: user-defined tags attached to notes
+CREATE TABLE IF NOT EXISTS note_tag (
+  note_id UUID NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+  tag VARCHAR(50) NOT NULL,
+  PRIMARY KEY (note_id, tag)
+);
+
+CREATE INDEX IF NOT EXISTS idx_note_tag_tag ON note_tag(tag);
+
+-- Full-text search over note content
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+  setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+  setweight(to_tsvector('english', coalesce(type, '') || ' ' || coalesce(problem, '')), 'B') ||
+  setweight(to_tsvector('english', coalesce(approach, '') || ' ' || coalesce(solution, '') || ' ' || coalesce(code, '')), 'C')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_notes_search_vector ON notes USING GIN(search_vector);
@@ -0,0 +1,2 @@
+-- Drop table Note Code Run
+DROP TABLE IF EXISTS "note_code_run";
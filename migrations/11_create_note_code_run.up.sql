@@ -0,0 +1,16 @@
+-- Note Code Run Table: results of running note code against test cases in the sandbox
+CREATE TABLE IF NOT EXISTS note_code_run (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  note_id UUID NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  language VARCHAR(20) NOT NULL CHECK (language IN ('go', 'python')),
+  code TEXT NOT NULL,
+  status VARCHAR(20) NOT NULL CHECK (status IN ('passed', 'failed', 'compile_error')),
+  passed INTEGER NOT NULL,
+  total INTEGER NOT NULL,
+  compile_output TEXT,
+  results JSONB NOT NULL DEFAULT '[]',
+  created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_note_code_run_note_created_at ON note_code_run(note_id, created_at);
@@ -0,0 +1,2 @@
+-- Drop table Embedding
+DROP TABLE IF EXISTS "embedding";
@@ -0,0 +1,24 @@
+-- pgvector speeds up similarity search when available; without it the
+-- server ranks vectors in process
+DO $$
+BEGIN
+  CREATE EXTENSION IF NOT EXISTS vector;
+EXCEPTION WHEN OTHERS THEN
+  RAISE NOTICE 'pgvector is not available, similarity search will run in process';
+END
+$$;
+
+-- Embedding Table: vectors of notes and messages for semantic search
+CREATE TABLE IF NOT EXISTS embedding (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  source_type VARCHAR(20) NOT NULL CHECK (source_type IN ('note', 'message')),
+  source_id UUID NOT NULL,
+  model VARCHAR(255) NOT NULL,
+  content_hash VARCHAR(64) NOT NULL,
+  vector REAL[] NOT NULL,
+  updated_at TIMESTAMPTZ DEFAULT NOW(),
+  UNIQUE (source_type, source_id, model)
+);
+
+CREATE INDEX IF NOT EXISTS idx_embedding_user_model ON embedding(user_id, model);
@@ -0,0 +1,9 @@
+-- Drop thread RAG and chunked embeddings
+DELETE FROM embedding WHERE source_type = 'attachment' OR chunk_index > 0;
+ALTER TABLE embedding DROP CONSTRAINT IF EXISTS embedding_source_type_check;
+ALTER TABLE embedding ADD CONSTRAINT embedding_source_type_check CHECK (source_type IN ('note', 'message'));
+ALTER TABLE embedding DROP CONSTRAINT IF EXISTS embedding_source_chunk_key;
+ALTER TABLE embedding ADD CONSTRAINT embedding_source_type_source_id_model_key UNIQUE (source_type, source_id, model);
+ALTER TABLE embedding DROP COLUMN IF EXISTS content;
+ALTER TABLE embedding DROP COLUMN IF EXISTS chunk_index;
+ALTER TABLE chat_thread DROP COLUMN IF EXISTS rag_enabled;
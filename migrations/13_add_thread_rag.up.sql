@@ -0,0 +1,15 @@
+-- Opt-in retrieval-augmented answers per thread
+ALTER TABLE chat_thread ADD COLUMN IF NOT EXISTS rag_enabled BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Embeddings are computed per chunk of content and keep the chunk text for prompts
+ALTER TABLE embedding ADD COLUMN IF NOT EXISTS chunk_index INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE embedding ADD COLUMN IF NOT EXISTS content TEXT NOT NULL DEFAULT '';
+ALTER TABLE embedding DROP CONSTRAINT IF EXISTS embedding_source_type_source_id_model_key;
+ALTER TABLE embedding ADD CONSTRAINT embedding_source_chunk_key UNIQUE (source_type, source_id, model, chunk_index);
+
+-- Text extracted from uploaded files is embedded too
+ALTER TABLE embedding DROP CONSTRAINT IF EXISTS embedding_source_type_check;
+ALTER TABLE embedding ADD CONSTRAINT embedding_source_type_check CHECK (source_type IN ('note', 'message', 'attachment'));
+
+-- Mark existing embeddings stale so the background worker rebuilds them by chunk
+UPDATE embedding SET updated_at = 'epoch';
@@ -0,0 +1,6 @@
+-- Drop tables Document and Document Collection
+DELETE FROM embedding WHERE source_type = 'document';
+ALTER TABLE embedding DROP CONSTRAINT IF EXISTS embedding_source_type_check;
+ALTER TABLE embedding ADD CONSTRAINT embedding_source_type_check CHECK (source_type IN ('note', 'message', 'attachment'));
+DROP TABLE IF EXISTS "document";
+DROP TABLE IF EXISTS "document_collection";
@@ -0,0 +1,40 @@
+-- Document Collection Table: personal collections of reference material
+CREATE TABLE IF NOT EXISTS document_collection (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  name VARCHAR(255) NOT NULL,
+  description TEXT,
+  chunk_size INTEGER NOT NULL CHECK (chunk_size > 0),
+  chunk_overlap INTEGER NOT NULL CHECK (chunk_overlap >= 0 AND chunk_overlap < chunk_size),
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_document_collection_user_name ON document_collection(user_id, lower(name));
+
+-- Document Table: uploaded files, their extracted text and ingestion status
+CREATE TABLE IF NOT EXISTS document (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  collection_id UUID NOT NULL REFERENCES document_collection(id) ON DELETE CASCADE,
+  file_name VARCHAR(255) NOT NULL,
+  content_type VARCHAR(255) NOT NULL,
+  kind VARCHAR(20) NOT NULL,
+  size BIGINT NOT NULL,
+  storage_key VARCHAR(512) NOT NULL,
+  content TEXT,
+  status VARCHAR(20) NOT NULL CHECK (status IN ('pending', 'processing', 'ready', 'failed')),
+  error TEXT,
+  tokens INTEGER NOT NULL DEFAULT 0,
+  chunk_count BIGINT NOT NULL DEFAULT 0,
+  indexed_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_document_collection_id ON document(collection_id);
+CREATE INDEX IF NOT EXISTS idx_document_status ON document(status);
+
+-- Document chunks are embedded alongside notes, messages and attachments
+ALTER TABLE embedding DROP CONSTRAINT IF EXISTS embedding_source_type_check;
+ALTER TABLE embedding ADD CONSTRAINT embedding_source_type_check CHECK (source_type IN ('note', 'message', 'attachment', 'document'));
@@ -0,0 +1,2 @@
+-- Drop table Refresh Token
+DROP TABLE IF EXISTS "refresh_token";
@@ -0,0 +1,24 @@
+-- User Session Table: one row per login, identified by its refresh token family
+CREATE TABLE IF NOT EXISTS user_session (
+  id UUID PRIMARY KEY,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  device VARCHAR(100) NOT NULL,
+  user_agent VARCHAR(512) NOT NULL,
+  ip_address VARCHAR(45) NOT NULL,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  last_used_at TIMESTAMPTZ NOT NULL,
+  expires_at TIMESTAMPTZ NOT NULL,
+  revoked_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_session_user_id ON user_session(user_id);
+
+-- Token families issued before sessions were tracked become sessions of an unknown device
+INSERT INTO user_session (id, user_id, device, user_agent, ip_address, created_at, last_used_at, expires_at, revoked_at)
+SELECT family_id, user_id, 'Unknown device', '', '', MIN(created_at), MAX(created_at), MAX(expires_at),
+  CASE WHEN BOOL_AND(revoked_at IS NOT NULL) THEN MAX(revoked_at) END
+FROM refresh_token
+GROUP BY family_id, user_id
+ON CONFLICT (id) DO NOTHING;
+
+ALTER TABLE refresh_token ADD CONSTRAINT refresh_token_family_id_fkey FOREIGN KEY (family_id) REFERENCES user_session(id) ON DELETE CASCADE;
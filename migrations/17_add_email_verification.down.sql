@@ -0,0 +1,2 @@
+-- Drop column Verification Sent At
+ALTER TABLE users DROP COLUMN IF EXISTS verification_sent_at;
@@ -0,0 +1,2 @@
+-- Remember when the last verification email was sent to throttle resends
+ALTER TABLE users ADD COLUMN IF NOT EXISTS verification_sent_at TIMESTAMPTZ;
@@ -0,0 +1,2 @@
+-- Drop table Password Reset Token
+DROP TABLE IF EXISTS "password_reset_token";
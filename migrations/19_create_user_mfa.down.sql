@@ -0,0 +1,4 @@
+-- Drop tables User MFA and MFA Recovery Code
+ALTER TABLE user_session DROP COLUMN IF EXISTS mfa;
+DROP TABLE IF EXISTS "mfa_recovery_code";
+DROP TABLE IF EXISTS "user_mfa";
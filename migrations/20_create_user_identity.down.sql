@@ -0,0 +1,3 @@
+-- Drop tables User Identity and OIDC Login State
+DROP TABLE IF EXISTS "oidc_login_state";
+DROP TABLE IF EXISTS "user_identity";
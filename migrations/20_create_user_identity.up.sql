@@ -0,0 +1,22 @@
+-- User Identity Table: accounts at external identity providers linked to users
+CREATE TABLE IF NOT EXISTS user_identity (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  provider VARCHAR(50) NOT NULL,
+  subject VARCHAR(255) NOT NULL,
+  email VARCHAR(255),
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  UNIQUE (provider, subject)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_identity_user_id ON user_identity(user_id);
+
+-- OIDC Login State Table: pending external logins awaiting the provider's callback
+CREATE TABLE IF NOT EXISTS oidc_login_state (
+  state_hash VARCHAR(64) PRIMARY KEY,
+  provider VARCHAR(50) NOT NULL,
+  nonce VARCHAR(64) NOT NULL,
+  code_verifier VARCHAR(128) NOT NULL,
+  expires_at TIMESTAMPTZ NOT NULL,
+  created_at TIMESTAMPTZ DEFAULT NOW()
+);
@@ -0,0 +1,2 @@
+-- Drop table API Key
+DROP TABLE IF EXISTS "api_key";
@@ -0,0 +1,15 @@
+-- API Key Table: hashed personal API keys for programmatic access
+CREATE TABLE IF NOT EXISTS api_key (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  name VARCHAR(100) NOT NULL,
+  prefix VARCHAR(16) NOT NULL,
+  key_hash VARCHAR(64) UNIQUE NOT NULL,
+  scopes JSONB NOT NULL DEFAULT '[]',
+  expires_at TIMESTAMPTZ,
+  last_used_at TIMESTAMPTZ,
+  revoked_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_key_user_id ON api_key(user_id);
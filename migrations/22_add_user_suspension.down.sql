@@ -0,0 +1,2 @@
+-- Drop column Suspended At
+ALTER TABLE users DROP COLUMN IF EXISTS suspended_at;
@@ -0,0 +1,2 @@
+-- Users: accounts suspended by an administrator cannot log in
+ALTER TABLE users ADD COLUMN IF NOT EXISTS suspended_at TIMESTAMPTZ;
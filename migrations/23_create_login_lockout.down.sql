@@ -0,0 +1,3 @@
+-- Drop tables Login Lockout and Login Attempt
+DROP TABLE IF EXISTS "login_lockout";
+DROP TABLE IF EXISTS "login_attempt";
@@ -0,0 +1,27 @@
+-- Login Attempt Table: recent failed logins by email and IP address
+CREATE TABLE IF NOT EXISTS login_attempt (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  email VARCHAR(255) NOT NULL,
+  ip_address VARCHAR(45) NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_login_attempt_email_created_at ON login_attempt(email, created_at);
+CREATE INDEX IF NOT EXISTS idx_login_attempt_ip_address_created_at ON login_attempt(ip_address, created_at);
+
+-- Login Lockout Table: lockouts of emails and IP addresses, kept for review
+CREATE TABLE IF NOT EXISTS login_lockout (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+  email VARCHAR(255) NOT NULL,
+  ip_address VARCHAR(45) NOT NULL,
+  failed_attempts BIGINT NOT NULL,
+  locked_until TIMESTAMPTZ NOT NULL,
+  unlocked_at TIMESTAMPTZ,
+  unlocked_by UUID REFERENCES users(id) ON DELETE SET NULL,
+  created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_login_lockout_email ON login_lockout(email, locked_until);
+CREATE INDEX IF NOT EXISTS idx_login_lockout_ip_address ON login_lockout(ip_address, locked_until);
+CREATE INDEX IF NOT EXISTS idx_login_lockout_user_id ON login_lockout(user_id);
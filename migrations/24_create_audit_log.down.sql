@@ -0,0 +1,3 @@
+-- Drop table Audit Log
+DROP TABLE IF EXISTS "audit_log";
+DROP FUNCTION IF EXISTS prevent_audit_log_update();
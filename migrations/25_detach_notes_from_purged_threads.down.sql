@@ -0,0 +1,4 @@
+-- Restore deleting notes together with their thread
+ALTER TABLE notes DROP CONSTRAINT IF EXISTS notes_thread_id_fkey;
+ALTER TABLE notes ADD CONSTRAINT notes_thread_id_fkey
+  FOREIGN KEY (thread_id) REFERENCES chat_thread(id) ON DELETE CASCADE;
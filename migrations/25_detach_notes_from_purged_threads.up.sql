@@ -0,0 +1,4 @@
+-- Keep notes when the thread they were taken from is purged; they have their own trash
+ALTER TABLE notes DROP CONSTRAINT IF EXISTS notes_thread_id_fkey;
+ALTER TABLE notes ADD CONSTRAINT notes_thread_id_fkey
+  FOREIGN KEY (thread_id) REFERENCES chat_thread(id) ON DELETE SET NULL;
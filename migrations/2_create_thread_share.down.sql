@@ -0,0 +1,2 @@
+-- Drop table Thread Share
+DROP TABLE IF EXISTS "thread_share";
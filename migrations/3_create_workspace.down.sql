@@ -0,0 +1,9 @@
+-- Remove workspace columns
+ALTER TABLE notes DROP COLUMN IF EXISTS workspace_id;
+ALTER TABLE chat_thread DROP COLUMN IF EXISTS workspace_id;
+
+-- Drop table Workspace Member
+DROP TABLE IF EXISTS "workspace_member";
+
+-- Drop table Workspace
+DROP TABLE IF EXISTS "workspace";
@@ -0,0 +1,26 @@
+-- Workspace Table
+CREATE TABLE IF NOT EXISTS workspace (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  name VARCHAR(255) NOT NULL,
+  owner_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- Workspace Member Table
+CREATE TABLE IF NOT EXISTS workspace_member (
+  workspace_id UUID NOT NULL REFERENCES workspace(id) ON DELETE CASCADE,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  role VARCHAR(20) NOT NULL CHECK (role IN ('owner', 'editor', 'viewer')),
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  PRIMARY KEY (workspace_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_workspace_member_user_id ON workspace_member(user_id);
+
+-- Threads and notes can belong to a workspace; they fall back to their creator when it is deleted
+ALTER TABLE chat_thread ADD COLUMN IF NOT EXISTS workspace_id UUID REFERENCES workspace(id) ON DELETE SET NULL;
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS workspace_id UUID REFERENCES workspace(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_chat_thread_workspace_id ON chat_thread(workspace_id);
+CREATE INDEX IF NOT EXISTS idx_notes_workspace_id ON notes(workspace_id);
@@ -0,0 +1,5 @@
+-- Drop table Message Feedback
+DROP TABLE IF EXISTS "message_feedback";
+
+DROP INDEX IF EXISTS idx_openai_transaction_message_id;
+ALTER TABLE openai_transaction DROP COLUMN IF EXISTS prompt_version;
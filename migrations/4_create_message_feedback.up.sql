@@ -0,0 +1,23 @@
+-- Track which prompt templates produced each transaction
+ALTER TABLE openai_transaction ADD COLUMN IF NOT EXISTS prompt_version VARCHAR(50);
+
+-- Message Feedback Table
+CREATE TABLE IF NOT EXISTS message_feedback (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  message_id UUID NOT NULL REFERENCES chat_message(id) ON DELETE CASCADE,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  thread_id UUID REFERENCES chat_thread(id) ON DELETE SET NULL,
+  transaction_id UUID REFERENCES openai_transaction(id) ON DELETE SET NULL,
+  model VARCHAR(255),
+  prompt_version VARCHAR(50),
+  rating VARCHAR(10) NOT NULL CHECK (rating IN ('up', 'down')),
+  reasons JSONB NOT NULL DEFAULT '[]',
+  comment TEXT,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW(),
+  UNIQUE (message_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_message_feedback_created_at ON message_feedback(created_at);
+CREATE INDEX IF NOT EXISTS idx_message_feedback_model ON message_feedback(model);
+CREATE INDEX IF NOT EXISTS idx_openai_transaction_message_id ON openai_transaction(message_id);
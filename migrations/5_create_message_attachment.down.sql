@@ -0,0 +1,2 @@
+-- Drop table Message Attachment
+DROP TABLE IF EXISTS "message_attachment";
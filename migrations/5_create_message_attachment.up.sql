@@ -0,0 +1,17 @@
+-- Message Attachment Table
+CREATE TABLE IF NOT EXISTS message_attachment (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  thread_id UUID NOT NULL REFERENCES chat_thread(id) ON DELETE CASCADE,
+  message_id UUID REFERENCES chat_message(id) ON DELETE SET NULL,
+  file_name VARCHAR(255) NOT NULL,
+  content_type VARCHAR(255) NOT NULL,
+  kind VARCHAR(20) NOT NULL CHECK (kind IN ('image', 'text', 'pdf')),
+  size BIGINT NOT NULL,
+  storage_key VARCHAR(512) NOT NULL UNIQUE,
+  extracted_text TEXT,
+  created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_message_attachment_thread_id ON message_attachment(thread_id);
+CREATE INDEX IF NOT EXISTS idx_message_attachment_message_id ON message_attachment(message_id);
@@ -0,0 +1,14 @@
+DELETE FROM message_attachment WHERE thread_id IS NULL;
+ALTER TABLE message_attachment DROP CONSTRAINT IF EXISTS message_attachment_thread_id_fkey;
+ALTER TABLE message_attachment ADD CONSTRAINT message_attachment_thread_id_fkey
+  FOREIGN KEY (thread_id) REFERENCES chat_thread(id) ON DELETE CASCADE;
+ALTER TABLE message_attachment ALTER COLUMN thread_id SET NOT NULL;
+
+ALTER TABLE openai_transaction DROP CONSTRAINT IF EXISTS openai_transaction_thread_id_fkey;
+ALTER TABLE openai_transaction ADD CONSTRAINT openai_transaction_thread_id_fkey
+  FOREIGN KEY (thread_id) REFERENCES chat_thread(id) ON DELETE CASCADE;
+
+DROP INDEX IF EXISTS idx_notes_deleted_at;
+DROP INDEX IF EXISTS idx_chat_thread_deleted_at;
+ALTER TABLE notes DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE chat_thread DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,17 @@
+-- Move threads and notes to a trash instead of deleting them
+ALTER TABLE chat_thread ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_chat_thread_deleted_at ON chat_thread(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_notes_deleted_at ON notes(deleted_at);
+
+-- Keep usage history when a thread is purged
+ALTER TABLE openai_transaction DROP CONSTRAINT IF EXISTS openai_transaction_thread_id_fkey;
+ALTER TABLE openai_transaction ADD CONSTRAINT openai_transaction_thread_id_fkey
+  FOREIGN KEY (thread_id) REFERENCES chat_thread(id) ON DELETE SET NULL;
+
+-- Detach attachments from purged threads so their files can be cleaned up
+ALTER TABLE message_attachment ALTER COLUMN thread_id DROP NOT NULL;
+ALTER TABLE message_attachment DROP CONSTRAINT IF EXISTS message_attachment_thread_id_fkey;
+ALTER TABLE message_attachment ADD CONSTRAINT message_attachment_thread_id_fkey
+  FOREIGN KEY (thread_id) REFERENCES chat_thread(id) ON DELETE SET NULL;
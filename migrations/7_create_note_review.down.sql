@@ -0,0 +1,3 @@
+-- Drop tables Note Review Log and Note Review
+DROP TABLE IF EXISTS "note_review_log";
+DROP TABLE IF EXISTS "note_review";
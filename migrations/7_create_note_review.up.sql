@@ -0,0 +1,30 @@
+-- Note Review Table: one SM-2 schedule per user and note
+CREATE TABLE IF NOT EXISTS note_review (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  note_id UUID NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  ease_factor DOUBLE PRECISION NOT NULL DEFAULT 2.5,
+  interval_days INTEGER NOT NULL DEFAULT 0,
+  repetitions INTEGER NOT NULL DEFAULT 0,
+  due_at TIMESTAMPTZ NOT NULL,
+  last_reviewed_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW(),
+  UNIQUE (note_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_note_review_user_due ON note_review(user_id, due_at);
+
+-- Note Review Log Table: every graded review
+CREATE TABLE IF NOT EXISTS note_review_log (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  note_id UUID NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  grade SMALLINT NOT NULL CHECK (grade BETWEEN 0 AND 5),
+  interval_days INTEGER NOT NULL,
+  ease_factor DOUBLE PRECISION NOT NULL,
+  reviewed_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_note_review_log_user_reviewed_at ON note_review_log(user_id, reviewed_at);
+CREATE INDEX IF NOT EXISTS idx_note_review_log_note_id ON note_review_log(note_id);
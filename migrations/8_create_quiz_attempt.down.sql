@@ -0,0 +1,2 @@
+-- Drop table Quiz Attempt
+DROP TABLE IF EXISTS "quiz_attempt";
@@ -0,0 +1,17 @@
+-- Quiz Attempt Table
+CREATE TABLE IF NOT EXISTS quiz_attempt (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  note_id UUID NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+  model VARCHAR(255) NOT NULL,
+  approach TEXT NOT NULL,
+  code TEXT,
+  hints_used INTEGER NOT NULL DEFAULT 0,
+  score INTEGER NOT NULL CHECK (score BETWEEN 0 AND 100),
+  verdict VARCHAR(20) NOT NULL CHECK (verdict IN ('correct', 'partial', 'incorrect')),
+  feedback TEXT,
+  created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_quiz_attempt_user_created_at ON quiz_attempt(user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_quiz_attempt_note_id ON quiz_attempt(note_id);
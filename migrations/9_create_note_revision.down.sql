@@ -0,0 +1,2 @@
+-- Drop table Note Revision
+DROP TABLE IF EXISTS "note_revision";
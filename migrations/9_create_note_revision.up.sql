@@ -0,0 +1,17 @@
+-- Note Revision Table: immutable snapshots of note content
+CREATE TABLE IF NOT EXISTS note_revision (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  note_id UUID NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+  version INTEGER NOT NULL,
+  editor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+  restored_from INTEGER,
+  title VARCHAR(255),
+  problem TEXT,
+  approach TEXT,
+  solution TEXT,
+  code TEXT,
+  level VARCHAR(50),
+  type TEXT,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  UNIQUE (note_id, version)
+);